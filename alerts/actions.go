@@ -0,0 +1,174 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const (
+	defaultActionTimeout = 30 * time.Second
+	journalSocket        = "/run/systemd/journal/socket"
+)
+
+// Event is delivered to actions whenever an alert starts firing or resolves.
+type Event struct {
+	Event models.AlertState `json:"event"`
+	Host  string            `json:"host"`
+	Alert models.Alert      `json:"alert"`
+}
+
+func (e Event) Summary() string {
+	return fmt.Sprintf("%s %s: %s (value %.2f)", e.Alert.Name, e.Event, e.Alert.Expr, e.Alert.Value)
+}
+
+// Action is something to do when an alert changes state.
+type Action interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+type actionEntry struct {
+	action  Action
+	timeout time.Duration
+}
+
+func newAction(name string, cfg models.AlertActionConfig) (actionEntry, error) {
+	timeout := defaultActionTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return actionEntry{}, fmt.Errorf("action %q: invalid timeout %q", name, cfg.Timeout)
+		}
+		timeout = d
+	}
+
+	switch cfg.Type {
+	case "command":
+		if cfg.Command == "" {
+			return actionEntry{}, fmt.Errorf("action %q: command is required", name)
+		}
+		return actionEntry{&CommandAction{Command: cfg.Command}, timeout}, nil
+	case "webhook":
+		if cfg.URL == "" {
+			return actionEntry{}, fmt.Errorf("action %q: url is required", name)
+		}
+		return actionEntry{&WebhookAction{URL: cfg.URL, Headers: cfg.Headers}, timeout}, nil
+	case "journal":
+		return actionEntry{&JournalAction{}, timeout}, nil
+	default:
+		return actionEntry{}, fmt.Errorf("action %q: unknown type %q (expected command, webhook or journal)", name, cfg.Type)
+	}
+}
+
+// CommandAction runs a shell command with the alert exposed as DGOP_ALERT_*
+// environment variables.
+type CommandAction struct {
+	Command string
+}
+
+func (a *CommandAction) Notify(ctx context.Context, event Event) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", a.Command)
+	cmd.Env = append(os.Environ(),
+		"DGOP_ALERT_NAME="+event.Alert.Name,
+		"DGOP_ALERT_STATE="+string(event.Event),
+		"DGOP_ALERT_SEVERITY="+event.Alert.Severity,
+		"DGOP_ALERT_EXPR="+event.Alert.Expr,
+		"DGOP_ALERT_VALUE="+strconv.FormatFloat(event.Alert.Value, 'f', -1, 64),
+		"DGOP_ALERT_THRESHOLD="+strconv.FormatFloat(event.Alert.Threshold, 'f', -1, 64),
+		"DGOP_ALERT_HOST="+event.Host,
+		"DGOP_ALERT_SUMMARY="+event.Summary(),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// WebhookAction POSTs the event as JSON.
+type WebhookAction struct {
+	URL     string
+	Headers map[string]string
+}
+
+func (a *WebhookAction) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range a.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// JournalAction writes the event to the systemd journal using its native
+// datagram protocol, so the alert fields are queryable with journalctl.
+type JournalAction struct{}
+
+func (a *JournalAction) Notify(ctx context.Context, event Event) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unixgram", journalSocket)
+	if err != nil {
+		return fmt.Errorf("journal unavailable: %w", err)
+	}
+	defer conn.Close()
+
+	var msg strings.Builder
+	writeJournalField(&msg, "MESSAGE", event.Summary())
+	writeJournalField(&msg, "PRIORITY", journalPriority(event))
+	writeJournalField(&msg, "SYSLOG_IDENTIFIER", "dgop")
+	writeJournalField(&msg, "DGOP_ALERT", event.Alert.Name)
+	writeJournalField(&msg, "DGOP_ALERT_STATE", string(event.Event))
+	writeJournalField(&msg, "DGOP_ALERT_SEVERITY", event.Alert.Severity)
+	writeJournalField(&msg, "DGOP_ALERT_VALUE", strconv.FormatFloat(event.Alert.Value, 'f', -1, 64))
+
+	_, err = conn.Write([]byte(msg.String()))
+	return err
+}
+
+func writeJournalField(b *strings.Builder, key, value string) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(strings.ReplaceAll(value, "\n", " "))
+	b.WriteByte('\n')
+}
+
+func journalPriority(event Event) string {
+	if event.Event == models.AlertResolved {
+		return "6"
+	}
+	switch event.Alert.Severity {
+	case "critical":
+		return "2"
+	case "info":
+		return "6"
+	default:
+		return "4"
+	}
+}
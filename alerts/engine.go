@@ -0,0 +1,288 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const defaultInterval = 10 * time.Second
+
+// Transition records an alert moving from one state to another.
+type Transition struct {
+	From  models.AlertState
+	Alert models.Alert
+}
+
+type Engine struct {
+	gops     *gops.GopsUtil
+	interval time.Duration
+	rules    []*rule
+	actions  map[string]actionEntry
+	dispatch bool
+	hostname string
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	lastEval time.Time
+
+	// tickMu serializes collection so concurrent callers don't race on the
+	// cursor carried between samples.
+	tickMu     sync.Mutex
+	metaCursor string
+}
+
+type Option func(*Engine)
+
+// WithoutActions evaluates rules without running their actions, for
+// consumers such as the TUI that only display alert state.
+func WithoutActions() Option {
+	return func(e *Engine) {
+		e.dispatch = false
+	}
+}
+
+func NewEngine(gopsUtil *gops.GopsUtil, cfg *models.AlertsConfig, opts ...Option) (*Engine, error) {
+	e := &Engine{
+		gops:     gopsUtil,
+		interval: defaultInterval,
+		actions:  make(map[string]actionEntry),
+		dispatch: true,
+		now:      time.Now,
+	}
+	e.hostname, _ = os.Hostname()
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if cfg == nil {
+		return e, nil
+	}
+
+	if cfg.Interval != "" {
+		d, err := time.ParseDuration(cfg.Interval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", cfg.Interval)
+		}
+		e.interval = d
+	}
+
+	for name, actionCfg := range cfg.Actions {
+		entry, err := newAction(name, actionCfg)
+		if err != nil {
+			return nil, err
+		}
+		e.actions[name] = entry
+	}
+
+	seen := make(map[string]struct{})
+	for _, ruleCfg := range cfg.Rules {
+		r, err := newRule(ruleCfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := seen[r.name]; dup {
+			return nil, fmt.Errorf("rule %q: duplicate name", r.name)
+		}
+		seen[r.name] = struct{}{}
+		for _, a := range r.actions {
+			if _, ok := e.actions[a]; !ok {
+				return nil, fmt.Errorf("rule %q: unknown action %q", r.name, a)
+			}
+		}
+		e.rules = append(e.rules, r)
+	}
+
+	return e, nil
+}
+
+func (e *Engine) Empty() bool {
+	return len(e.rules) == 0
+}
+
+func (e *Engine) Interval() time.Duration {
+	return e.interval
+}
+
+// Run evaluates the rules every interval until ctx is cancelled, calling
+// onTransition (if set) for every state change.
+func (e *Engine) Run(ctx context.Context, onTransition func(Transition)) error {
+	if e.Empty() {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		transitions, err := e.Tick(ctx)
+		if err != nil {
+			log.Warn("alert evaluation failed", "error", err)
+		}
+		if onTransition != nil {
+			for _, t := range transitions {
+				onTransition(t)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick collects the modules the rules need and evaluates them once.
+func (e *Engine) Tick(ctx context.Context) ([]Transition, error) {
+	if e.Empty() {
+		return nil, nil
+	}
+
+	e.tickMu.Lock()
+	defer e.tickMu.Unlock()
+
+	modules, pciIds := e.requirements()
+	params := gops.MetaParams{
+		SortBy:     gops.SortByCPU,
+		EnableCPU:  true,
+		GPUPciIds:  pciIds,
+		MetaCursor: e.metaCursor,
+	}
+
+	meta, err := e.gops.GetMeta(ctx, modules, params)
+	if gops.IsCursorError(err) {
+		// The last tick was too long ago, e.g. before a suspend.
+		params.MetaCursor = ""
+		meta, err = e.gops.GetMeta(ctx, modules, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.metaCursor = meta.MetaCursor
+
+	transitions := e.Evaluate(meta)
+	if e.dispatch {
+		e.notify(transitions)
+	}
+	return transitions, nil
+}
+
+// Evaluate steps every rule against meta and returns the state changes.
+func (e *Engine) Evaluate(meta *models.MetaInfo) []Transition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	e.lastEval = now
+
+	var transitions []Transition
+	for _, r := range e.rules {
+		value, ok := r.metric.resolve(meta)
+		prev := r.step(value, ok, now)
+		if prev != r.state {
+			transitions = append(transitions, Transition{From: prev, Alert: r.snapshot()})
+		}
+	}
+	return transitions
+}
+
+func (e *Engine) requirements() ([]string, []string) {
+	seen := make(map[string]struct{})
+	var modules, pciIds []string
+	for _, r := range e.rules {
+		mod := r.metric.module()
+		if _, ok := seen[mod]; !ok {
+			seen[mod] = struct{}{}
+			modules = append(modules, mod)
+		}
+		if r.metric.kind == "gpu" {
+			pciIds = append(pciIds, r.metric.key)
+		}
+	}
+	return modules, pciIds
+}
+
+func (e *Engine) notify(transitions []Transition) {
+	for _, t := range transitions {
+		switch t.Alert.State {
+		case models.AlertFiring, models.AlertResolved:
+		default:
+			continue
+		}
+
+		event := Event{Event: t.Alert.State, Host: e.hostname, Alert: t.Alert}
+		for _, name := range e.ruleActions(t.Alert.Name) {
+			entry := e.actions[name]
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), entry.timeout)
+				defer cancel()
+				if err := entry.action.Notify(ctx, event); err != nil {
+					log.Warn("alert action failed", "alert", event.Alert.Name, "action", name, "error", err)
+				}
+			}()
+		}
+	}
+}
+
+func (e *Engine) ruleActions(name string) []string {
+	for _, r := range e.rules {
+		if r.name == name {
+			return r.actions
+		}
+	}
+	return nil
+}
+
+// Alerts returns a snapshot of every rule's current state.
+func (e *Engine) Alerts() *models.AlertsInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	info := &models.AlertsInfo{
+		Alerts:     make([]models.Alert, 0, len(e.rules)),
+		LastEvalAt: unixMilli(e.lastEval),
+	}
+	for _, r := range e.rules {
+		info.Alerts = append(info.Alerts, r.snapshot())
+		if r.state == models.AlertFiring {
+			info.FiringCount++
+		}
+	}
+	return info
+}
+
+// Firing returns the currently firing alerts, most severe first.
+func (e *Engine) Firing() []models.Alert {
+	var firing []models.Alert
+	for _, a := range e.Alerts().Alerts {
+		if a.State == models.AlertFiring {
+			firing = append(firing, a)
+		}
+	}
+	sort.SliceStable(firing, func(i, j int) bool {
+		return severityRank(firing[i].Severity) > severityRank(firing[j].Severity)
+	})
+	return firing
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 2
+	case "warning":
+		return 1
+	default:
+		return 0
+	}
+}
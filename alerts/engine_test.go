@@ -0,0 +1,175 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		wantErr   bool
+		metric    string
+		op        string
+		threshold float64
+		forDur    time.Duration
+	}{
+		{name: "simple", expr: "cpu.usage > 90", metric: "cpu.usage", op: ">", threshold: 90},
+		{name: "with for", expr: "memory.usedPercent >= 85.5 for 2m", metric: "memory.usedPercent", op: ">=", threshold: 85.5, forDur: 2 * time.Minute},
+		{name: "mount", expr: "mount./home.percent > 95", metric: "mount./home.percent", op: ">", threshold: 95},
+		{name: "process selector", expr: "process[name=ffmpeg,user=root].cpu > 200 for 30s", metric: "process[name=ffmpeg,user=root].cpu", op: ">", threshold: 200, forDur: 30 * time.Second},
+		{name: "missing threshold", expr: "cpu.usage >", wantErr: true},
+		{name: "bad operator", expr: "cpu.usage => 90", wantErr: true},
+		{name: "bad threshold", expr: "cpu.usage > high", wantErr: true},
+		{name: "bad for keyword", expr: "cpu.usage > 90 during 1m", wantErr: true},
+		{name: "bad duration", expr: "cpu.usage > 90 for soon", wantErr: true},
+		{name: "unknown module", expr: "fan.rpm > 100", wantErr: true},
+		{name: "unknown field", expr: "cpu.idle > 100", wantErr: true},
+		{name: "unknown selector key", expr: "process[cmd=x].cpu > 1", wantErr: true},
+		{name: "non-numeric pid", expr: "process[pid=abc].cpu > 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, op, threshold, forDur, err := parseExpr(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.metric, metric.String())
+			assert.Equal(t, tt.op, op)
+			assert.Equal(t, tt.threshold, threshold)
+			assert.Equal(t, tt.forDur, forDur)
+		})
+	}
+}
+
+func TestNewEngineValidation(t *testing.T) {
+	_, err := NewEngine(nil, &models.AlertsConfig{
+		Rules: []models.AlertRuleConfig{{Name: "a", When: "cpu.usage > 1", Actions: []string{"missing"}}},
+	})
+	assert.ErrorContains(t, err, "unknown action")
+
+	_, err = NewEngine(nil, &models.AlertsConfig{
+		Rules: []models.AlertRuleConfig{
+			{Name: "a", When: "cpu.usage > 1"},
+			{Name: "a", When: "cpu.usage > 2"},
+		},
+	})
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = NewEngine(nil, &models.AlertsConfig{
+		Actions: map[string]models.AlertActionConfig{"x": {Type: "email"}},
+	})
+	assert.ErrorContains(t, err, "unknown type")
+
+	engine, err := NewEngine(nil, nil)
+	require.NoError(t, err)
+	assert.True(t, engine.Empty())
+}
+
+func newTestEngine(t *testing.T, rules ...models.AlertRuleConfig) (*Engine, *time.Time) {
+	t.Helper()
+	engine, err := NewEngine(nil, &models.AlertsConfig{Rules: rules}, WithoutActions())
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+	return engine, &now
+}
+
+func cpuMeta(usage float64) *models.MetaInfo {
+	return &models.MetaInfo{CPU: &models.CPUInfo{Usage: usage}}
+}
+
+func TestEngineForDuration(t *testing.T) {
+	engine, now := newTestEngine(t, models.AlertRuleConfig{Name: "cpu-hot", When: "cpu.usage > 90 for 1m"})
+
+	transitions := engine.Evaluate(cpuMeta(95))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertInactive, transitions[0].From)
+	assert.Equal(t, models.AlertPending, transitions[0].Alert.State)
+
+	*now = now.Add(30 * time.Second)
+	assert.Empty(t, engine.Evaluate(cpuMeta(95)))
+
+	*now = now.Add(30 * time.Second)
+	transitions = engine.Evaluate(cpuMeta(95))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertFiring, transitions[0].Alert.State)
+	assert.Equal(t, now.UnixMilli(), transitions[0].Alert.FiredAt)
+
+	firing := engine.Firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "cpu-hot", firing[0].Name)
+	assert.Equal(t, 1, engine.Alerts().FiringCount)
+}
+
+func TestEnginePendingResetsWhenConditionClears(t *testing.T) {
+	engine, now := newTestEngine(t, models.AlertRuleConfig{Name: "cpu-hot", When: "cpu.usage > 90 for 1m"})
+
+	engine.Evaluate(cpuMeta(95))
+	*now = now.Add(30 * time.Second)
+	transitions := engine.Evaluate(cpuMeta(50))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertInactive, transitions[0].Alert.State)
+
+	*now = now.Add(45 * time.Second)
+	transitions = engine.Evaluate(cpuMeta(95))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertPending, transitions[0].Alert.State, "the for window must restart")
+}
+
+func TestEngineHysteresis(t *testing.T) {
+	engine, _ := newTestEngine(t, models.AlertRuleConfig{Name: "mem", When: "memory.usedPercent > 80", Hysteresis: 5})
+	mem := func(pct float64) *models.MetaInfo {
+		return &models.MetaInfo{Memory: &models.MemoryInfo{UsedPercent: pct}}
+	}
+
+	transitions := engine.Evaluate(mem(81))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertFiring, transitions[0].Alert.State)
+
+	assert.Empty(t, engine.Evaluate(mem(78)), "inside the hysteresis band stays firing")
+	assert.Empty(t, engine.Evaluate(mem(75.5)))
+
+	transitions = engine.Evaluate(mem(74))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertFiring, transitions[0].From)
+	assert.Equal(t, models.AlertResolved, transitions[0].Alert.State)
+
+	transitions = engine.Evaluate(mem(82))
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertFiring, transitions[0].Alert.State)
+}
+
+func TestEngineProcessSelector(t *testing.T) {
+	engine, _ := newTestEngine(t, models.AlertRuleConfig{Name: "ffmpeg", When: "process[name=ffmpeg].cpu > 150", Severity: "critical"})
+	meta := &models.MetaInfo{Processes: []*models.ProcessInfo{
+		{PID: 1, Command: "ffmpeg", CPU: 80},
+		{PID: 2, Command: "ffmpeg", CPU: 90},
+		{PID: 3, Command: "firefox", CPU: 300},
+	}}
+
+	transitions := engine.Evaluate(meta)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertFiring, transitions[0].Alert.State)
+	assert.InDelta(t, 170, transitions[0].Alert.Value, 0.001)
+	assert.Equal(t, "critical", transitions[0].Alert.Severity)
+}
+
+func TestEngineMissingMetricResolves(t *testing.T) {
+	engine, _ := newTestEngine(t, models.AlertRuleConfig{Name: "root", When: "mount./.percent > 90"})
+
+	engine.Evaluate(&models.MetaInfo{DiskMounts: []*models.DiskMountInfo{{Mount: "/", Percent: "95%"}}})
+	assert.Len(t, engine.Firing(), 1)
+
+	transitions := engine.Evaluate(&models.MetaInfo{})
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.AlertResolved, transitions[0].Alert.State)
+}
@@ -0,0 +1,295 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// metricRef identifies a single numeric value inside a MetaInfo snapshot,
+// e.g. "memory.usedPercent", "mount./home.percent" or "process[name=ffmpeg].cpu".
+type metricRef struct {
+	kind  string
+	key   string
+	field string
+}
+
+var metricFields = map[string][]string{
+	"cpu":     {"usage", "temperature", "frequency"},
+	"memory":  {"usedPercent", "used", "available", "swapUsedPercent"},
+	"mount":   {"percent"},
+	"load":    {"1", "5", "15"},
+	"process": {"cpu", "memory", "memoryKB", "count"},
+	"net":     {"rx", "tx"},
+	"disk":    {"read", "write"},
+	"gpu":     {"temperature"},
+}
+
+var processSelectorKeys = []string{"name", "user", "pid", "exe"}
+
+func parseMetric(path string) (metricRef, error) {
+	var ref metricRef
+
+	switch {
+	case strings.HasPrefix(path, "process["), strings.HasPrefix(path, "gpu["):
+		open := strings.Index(path, "[")
+		end := strings.Index(path, "].")
+		if end < open {
+			return ref, fmt.Errorf("metric %q: expected %s[...].field", path, path[:open])
+		}
+		ref.kind = path[:open]
+		ref.key = path[open+1 : end]
+		ref.field = path[end+2:]
+		if ref.key == "" {
+			return ref, fmt.Errorf("metric %q: empty selector", path)
+		}
+		if ref.kind == "process" {
+			if err := validateProcessSelector(ref.key); err != nil {
+				return ref, fmt.Errorf("metric %q: %w", path, err)
+			}
+		}
+	default:
+		head, rest, found := strings.Cut(path, ".")
+		if !found || rest == "" {
+			return ref, fmt.Errorf("metric %q: expected <module>.<field>", path)
+		}
+		ref.kind = head
+		switch head {
+		case "mount", "net", "disk":
+			dot := strings.LastIndex(rest, ".")
+			if dot <= 0 {
+				return ref, fmt.Errorf("metric %q: expected %s.<name>.<field>", path, head)
+			}
+			ref.key, ref.field = rest[:dot], rest[dot+1:]
+		default:
+			ref.field = rest
+		}
+	}
+
+	fields, known := metricFields[ref.kind]
+	if !known {
+		return ref, fmt.Errorf("metric %q: unknown module %q", path, ref.kind)
+	}
+	for _, f := range fields {
+		if f == ref.field {
+			return ref, nil
+		}
+	}
+	return ref, fmt.Errorf("metric %q: unknown field %q (expected one of %s)", path, ref.field, strings.Join(fields, ", "))
+}
+
+func validateProcessSelector(selector string) error {
+	for _, part := range strings.Split(selector, ",") {
+		key, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return fmt.Errorf("selector %q: expected key=value", part)
+		}
+		known := false
+		for _, k := range processSelectorKeys {
+			if k == strings.TrimSpace(key) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("selector %q: unknown key (expected one of %s)", part, strings.Join(processSelectorKeys, ", "))
+		}
+		if strings.TrimSpace(key) == "pid" {
+			if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("selector %q: pid must be a number", part)
+			}
+		}
+	}
+	return nil
+}
+
+// module returns the meta module that has to be collected to resolve ref.
+func (ref metricRef) module() string {
+	switch ref.kind {
+	case "mount":
+		return "diskmounts"
+	case "load":
+		return "system"
+	case "net":
+		return "net-rate"
+	case "disk":
+		return "disk-rate"
+	case "gpu":
+		return "gpu-temp"
+	case "process":
+		return "processes"
+	default:
+		return ref.kind
+	}
+}
+
+func (ref metricRef) String() string {
+	switch ref.kind {
+	case "process", "gpu":
+		return fmt.Sprintf("%s[%s].%s", ref.kind, ref.key, ref.field)
+	case "mount", "net", "disk":
+		return fmt.Sprintf("%s.%s.%s", ref.kind, ref.key, ref.field)
+	default:
+		return ref.kind + "." + ref.field
+	}
+}
+
+// resolve extracts the referenced value from meta. ok is false when the
+// module wasn't collected or the named mount/interface/device doesn't exist.
+func (ref metricRef) resolve(meta *models.MetaInfo) (value float64, ok bool) {
+	if meta == nil {
+		return 0, false
+	}
+
+	switch ref.kind {
+	case "cpu":
+		if meta.CPU == nil {
+			return 0, false
+		}
+		switch ref.field {
+		case "usage":
+			return meta.CPU.Usage, true
+		case "temperature":
+			return meta.CPU.Temperature, true
+		case "frequency":
+			return meta.CPU.Frequency, true
+		}
+	case "memory":
+		if meta.Memory == nil {
+			return 0, false
+		}
+		switch ref.field {
+		case "usedPercent":
+			return meta.Memory.UsedPercent, true
+		case "used":
+			return float64(meta.Memory.Used), true
+		case "available":
+			return float64(meta.Memory.Available), true
+		case "swapUsedPercent":
+			if meta.Memory.SwapTotal == 0 {
+				return 0, true
+			}
+			used := meta.Memory.SwapTotal - meta.Memory.SwapFree
+			return float64(used) / float64(meta.Memory.SwapTotal) * 100, true
+		}
+	case "mount":
+		for _, m := range meta.DiskMounts {
+			if m.Mount != ref.key {
+				continue
+			}
+			pct, err := strconv.ParseFloat(strings.TrimSuffix(m.Percent, "%"), 64)
+			return pct, err == nil
+		}
+	case "load":
+		if meta.System == nil {
+			return 0, false
+		}
+		fields := strings.Fields(meta.System.LoadAvg)
+		idx := map[string]int{"1": 0, "5": 1, "15": 2}[ref.field]
+		if idx >= len(fields) {
+			return 0, false
+		}
+		load, err := strconv.ParseFloat(fields[idx], 64)
+		return load, err == nil
+	case "process":
+		if meta.Processes == nil {
+			return 0, false
+		}
+		return resolveProcessMetric(meta.Processes, ref.key, ref.field), true
+	case "net":
+		if meta.NetRate == nil {
+			return 0, false
+		}
+		for _, iface := range meta.NetRate.Interfaces {
+			if ref.key != "*" && iface.Interface != ref.key {
+				continue
+			}
+			ok = true
+			switch ref.field {
+			case "rx":
+				value += iface.RxRate
+			case "tx":
+				value += iface.TxRate
+			}
+		}
+		return value, ok
+	case "disk":
+		if meta.DiskRate == nil {
+			return 0, false
+		}
+		for _, d := range meta.DiskRate.Disks {
+			if ref.key != "*" && d.Device != ref.key {
+				continue
+			}
+			ok = true
+			switch ref.field {
+			case "read":
+				value += d.ReadRate
+			case "write":
+				value += d.WriteRate
+			}
+		}
+		return value, ok
+	case "gpu":
+		if meta.GPU == nil {
+			return 0, false
+		}
+		for _, g := range meta.GPU.GPUs {
+			if g.PciId == ref.key {
+				return g.Temperature, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// resolveProcessMetric sums field over every process matching selector, so
+// "process[name=chrome].memory" reports the whole app rather than one tab.
+func resolveProcessMetric(procs []*models.ProcessInfo, selector, field string) float64 {
+	var total float64
+	for _, p := range procs {
+		if !matchesProcessSelector(p, selector) {
+			continue
+		}
+		switch field {
+		case "cpu":
+			total += p.CPU
+		case "memory":
+			total += float64(p.MemoryPercent)
+		case "memoryKB":
+			total += float64(p.MemoryKB)
+		case "count":
+			total++
+		}
+	}
+	return total
+}
+
+func matchesProcessSelector(p *models.ProcessInfo, selector string) bool {
+	for _, part := range strings.Split(selector, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			if p.Command != value {
+				return false
+			}
+		case "user":
+			if p.Username != value {
+				return false
+			}
+		case "pid":
+			if strconv.Itoa(int(p.PID)) != value {
+				return false
+			}
+		case "exe":
+			if p.ExecutablePath != value {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+type rule struct {
+	name       string
+	expr       string
+	severity   string
+	metric     metricRef
+	op         string
+	threshold  float64
+	forDur     time.Duration
+	hysteresis float64
+	actions    []string
+
+	state      models.AlertState
+	value      float64
+	activeAt   time.Time
+	firedAt    time.Time
+	resolvedAt time.Time
+}
+
+// parseExpr parses "<metric> <op> <threshold> [for <duration>]".
+func parseExpr(expr string) (metricRef, string, float64, time.Duration, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 3 && len(fields) != 5 {
+		return metricRef{}, "", 0, 0, fmt.Errorf("expression %q: expected \"<metric> <op> <value> [for <duration>]\"", expr)
+	}
+
+	metric, err := parseMetric(fields[0])
+	if err != nil {
+		return metricRef{}, "", 0, 0, err
+	}
+
+	op := fields[1]
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		return metricRef{}, "", 0, 0, fmt.Errorf("expression %q: unknown operator %q", expr, op)
+	}
+
+	threshold, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return metricRef{}, "", 0, 0, fmt.Errorf("expression %q: invalid threshold %q", expr, fields[2])
+	}
+
+	var forDur time.Duration
+	if len(fields) == 5 {
+		if fields[3] != "for" {
+			return metricRef{}, "", 0, 0, fmt.Errorf("expression %q: expected \"for\", got %q", expr, fields[3])
+		}
+		if forDur, err = time.ParseDuration(fields[4]); err != nil || forDur < 0 {
+			return metricRef{}, "", 0, 0, fmt.Errorf("expression %q: invalid duration %q", expr, fields[4])
+		}
+	}
+
+	return metric, op, threshold, forDur, nil
+}
+
+func newRule(cfg models.AlertRuleConfig) (*rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("rule %q: name is required", cfg.When)
+	}
+	if cfg.Hysteresis < 0 {
+		return nil, fmt.Errorf("rule %q: hysteresis must not be negative", cfg.Name)
+	}
+
+	metric, op, threshold, forDur, err := parseExpr(cfg.When)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", cfg.Name, err)
+	}
+
+	severity := cfg.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	return &rule{
+		name:       cfg.Name,
+		expr:       cfg.When,
+		severity:   severity,
+		metric:     metric,
+		op:         op,
+		threshold:  threshold,
+		forDur:     forDur,
+		hysteresis: cfg.Hysteresis,
+		actions:    cfg.Actions,
+		state:      models.AlertInactive,
+	}, nil
+}
+
+func compare(op string, value, threshold float64) bool {
+	switch op {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	case "==":
+		return value == threshold
+	case "!=":
+		return value != threshold
+	}
+	return false
+}
+
+// cleared reports whether a firing rule may resolve. The value has to move
+// past the threshold by the hysteresis margin, so a metric hovering around
+// the threshold doesn't flap between firing and resolved every sample.
+func (r *rule) cleared(value float64, ok bool) bool {
+	if !ok {
+		return true
+	}
+	threshold := r.threshold
+	switch r.op {
+	case ">", ">=":
+		threshold -= r.hysteresis
+	case "<", "<=":
+		threshold += r.hysteresis
+	}
+	return !compare(r.op, value, threshold)
+}
+
+// step advances the rule's state machine with a new sample and returns the
+// previous state.
+func (r *rule) step(value float64, ok bool, now time.Time) models.AlertState {
+	prev := r.state
+	if ok {
+		r.value = value
+	}
+	active := ok && compare(r.op, value, r.threshold)
+
+	switch r.state {
+	case models.AlertInactive, models.AlertResolved:
+		if !active {
+			break
+		}
+		r.activeAt = now
+		r.state = models.AlertPending
+		if r.forDur == 0 {
+			r.state = models.AlertFiring
+			r.firedAt = now
+		}
+	case models.AlertPending:
+		switch {
+		case !active:
+			r.state = models.AlertInactive
+			r.activeAt = time.Time{}
+		case now.Sub(r.activeAt) >= r.forDur:
+			r.state = models.AlertFiring
+			r.firedAt = now
+		}
+	case models.AlertFiring:
+		if r.cleared(value, ok) {
+			r.state = models.AlertResolved
+			r.resolvedAt = now
+			r.activeAt = time.Time{}
+		}
+	}
+
+	return prev
+}
+
+func (r *rule) snapshot() models.Alert {
+	return models.Alert{
+		Name:       r.name,
+		Expr:       r.expr,
+		Severity:   r.severity,
+		State:      r.state,
+		Value:      r.value,
+		Threshold:  r.threshold,
+		ActiveAt:   unixMilli(r.activeAt),
+		FiredAt:    unixMilli(r.firedAt),
+		ResolvedAt: unixMilli(r.resolvedAt),
+	}
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
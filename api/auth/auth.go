@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+type Scope string
+
+const (
+	// ScopeRead allows GET requests: metrics, processes, alerts.
+	ScopeRead Scope = "read"
+	// ScopeAction allows requests that change state, such as signalling
+	// processes.
+	ScopeAction Scope = "action"
+)
+
+var allScopes = []Scope{ScopeRead, ScopeAction}
+
+type contextKey int
+
+const (
+	scopesKey contextKey = iota
+	unixConnKey
+)
+
+type token struct {
+	digest [sha256.Size]byte
+	scopes []Scope
+}
+
+// Authenticator checks bearer tokens and TLS client certificates and attaches
+// the caller's scopes to the request context.
+type Authenticator struct {
+	tokens     []token
+	certScopes map[string][]Scope
+	mtls       bool
+}
+
+// New builds an Authenticator from cfg. mtls is set when the TLS listener
+// verifies client certificates; verified certificates not listed in cfg get
+// read access.
+func New(cfg *models.AuthConfig, mtls bool) (*Authenticator, error) {
+	a := &Authenticator{
+		certScopes: make(map[string][]Scope),
+		mtls:       mtls,
+	}
+	if cfg == nil {
+		return a, nil
+	}
+
+	for i, spec := range cfg.Tokens {
+		name := spec.Name
+		if name == "" {
+			name = fmt.Sprintf("token #%d", i+1)
+		}
+
+		var t token
+		switch {
+		case spec.Token != "" && spec.SHA256 != "":
+			return nil, fmt.Errorf("%s: set either token or sha256, not both", name)
+		case spec.Token != "":
+			t.digest = sha256.Sum256([]byte(spec.Token))
+		case spec.SHA256 != "":
+			raw, err := hex.DecodeString(spec.SHA256)
+			if err != nil || len(raw) != sha256.Size {
+				return nil, fmt.Errorf("%s: sha256 must be %d hex characters", name, sha256.Size*2)
+			}
+			copy(t.digest[:], raw)
+		default:
+			return nil, fmt.Errorf("%s: token or sha256 is required", name)
+		}
+
+		scopes, err := parseScopes(spec.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		t.scopes = scopes
+		a.tokens = append(a.tokens, t)
+	}
+
+	for _, client := range cfg.Clients {
+		if client.CommonName == "" {
+			return nil, fmt.Errorf("client certificate entry without cn")
+		}
+		scopes, err := parseScopes(client.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("client %q: %w", client.CommonName, err)
+		}
+		a.certScopes[client.CommonName] = scopes
+	}
+
+	return a, nil
+}
+
+// parseScopes defaults to read-only so a token has to be explicitly granted
+// actions.
+func parseScopes(raw []string) ([]Scope, error) {
+	if len(raw) == 0 {
+		return []Scope{ScopeRead}, nil
+	}
+	var scopes []Scope
+	for _, s := range raw {
+		scope := Scope(strings.ToLower(strings.TrimSpace(s)))
+		if !slices.Contains(allScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q (expected read or action)", s)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// Enabled reports whether any credentials are configured. Without them every
+// caller gets every scope, matching the behaviour of older versions.
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0 || a.mtls
+}
+
+// Middleware rejects requests without the scope their method needs: read for
+// GET and HEAD, action for everything else.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scopes, authenticated := a.scopesFor(r)
+		if !authenticated {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dgop"`)
+			writeProblem(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		required := RequiredScope(r)
+		if !slices.Contains(scopes, required) {
+			writeProblem(w, http.StatusForbidden, fmt.Sprintf("missing %q scope", required))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), scopesKey, scopes)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (a *Authenticator) scopesFor(r *http.Request) ([]Scope, bool) {
+	// Access to the unix socket is controlled by its file mode.
+	if !a.Enabled() || isUnixConn(r.Context()) {
+		return allScopes, true
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, value, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return nil, false
+		}
+		digest := sha256.Sum256([]byte(strings.TrimSpace(value)))
+		for _, t := range a.tokens {
+			if subtle.ConstantTimeCompare(digest[:], t.digest[:]) == 1 {
+				return t.scopes, true
+			}
+		}
+		return nil, false
+	}
+
+	if a.mtls && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if scopes, ok := a.certScopes[cn]; ok {
+			return scopes, true
+		}
+		return []Scope{ScopeRead}, true
+	}
+
+	return nil, false
+}
+
+func RequiredScope(r *http.Request) Scope {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeAction
+	}
+}
+
+// HasScope reports whether the request behind ctx was granted scope.
+func HasScope(ctx context.Context, scope Scope) bool {
+	scopes, _ := ctx.Value(scopesKey).([]Scope)
+	return slices.Contains(scopes, scope)
+}
+
+// ConnContext marks connections accepted on a unix socket. Set it as the
+// http.Server's ConnContext.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	if _, ok := c.(*net.UnixConn); ok {
+		return context.WithValue(ctx, unixConnKey, true)
+	}
+	return ctx
+}
+
+func isUnixConn(ctx context.Context) bool {
+	unix, _ := ctx.Value(unixConnKey).(bool)
+	return unix
+}
+
+func writeProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": status,
+		"title":  http.StatusText(status),
+		"detail": detail,
+	})
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, a *Authenticator, method, token string, ctx context.Context) (int, bool) {
+	t.Helper()
+	var actionScope bool
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actionScope = HasScope(r.Context(), ScopeAction)
+	}))
+
+	req := httptest.NewRequest(method, "/gops/meta", nil)
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, actionScope
+}
+
+func TestDisabledAllowsEverything(t *testing.T) {
+	a, err := New(nil, false)
+	require.NoError(t, err)
+	assert.False(t, a.Enabled())
+
+	code, action := serve(t, a, http.MethodPost, "", nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, action)
+}
+
+func TestTokenScopes(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed"))
+	a, err := New(&models.AuthConfig{Tokens: []models.TokenConfig{
+		{Name: "reader", Token: "read-token"},
+		{Name: "admin", Token: "action-token", Scopes: []string{"read", "action"}},
+		{Name: "hashed", SHA256: hex.EncodeToString(digest[:]), Scopes: []string{"Action"}},
+	}}, false)
+	require.NoError(t, err)
+	require.True(t, a.Enabled())
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "nope", http.StatusUnauthorized},
+		{"read token GET", http.MethodGet, "read-token", http.StatusOK},
+		{"read token POST", http.MethodPost, "read-token", http.StatusForbidden},
+		{"action token POST", http.MethodPost, "action-token", http.StatusOK},
+		{"hashed token POST", http.MethodPost, "hashed", http.StatusOK},
+		{"hashed token without read", http.MethodGet, "hashed", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := serve(t, a, tt.method, tt.token, nil)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+}
+
+func TestUnauthorizedHeader(t *testing.T) {
+	a, err := New(&models.AuthConfig{Tokens: []models.TokenConfig{{Token: "secret"}}}, false)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	a.Middleware(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+}
+
+func TestUnixSocketIsTrusted(t *testing.T) {
+	a, err := New(&models.AuthConfig{Tokens: []models.TokenConfig{{Token: "secret"}}}, false)
+	require.NoError(t, err)
+
+	ln, err := net.Listen("unix", t.TempDir()+"/dgop.sock")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		if c, err := net.Dial("unix", ln.Addr().String()); err == nil {
+			c.Close()
+		}
+	}()
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	code, action := serve(t, a, http.MethodPost, "", ConnContext(context.Background(), conn))
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, action)
+}
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  models.AuthConfig
+	}{
+		{"missing token", models.AuthConfig{Tokens: []models.TokenConfig{{Name: "x"}}}},
+		{"token and sha256", models.AuthConfig{Tokens: []models.TokenConfig{{Token: "a", SHA256: "b"}}}},
+		{"bad sha256", models.AuthConfig{Tokens: []models.TokenConfig{{SHA256: "abc"}}}},
+		{"unknown scope", models.AuthConfig{Tokens: []models.TokenConfig{{Token: "a", Scopes: []string{"admin"}}}}},
+		{"client without cn", models.AuthConfig{Clients: []models.ClientCertConfig{{Scopes: []string{"read"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.cfg, false)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// APIError is returned when the server answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("server returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Client talks to a dgop API server started with `dgop server`.
+type Client struct {
+	baseURL    string
+	socketPath string
+	token      string
+	tlsConfig  *tls.Config
+	http       *http.Client
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithToken sends token as a bearer token with every request.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithTLSConfig sets the TLS configuration for https servers, e.g. a private
+// CA or a client certificate for mTLS. See LoadTLSConfig.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// New creates a client for baseURL, which is either an http(s) URL or
+// unix:///path/to/socket for a server listening on a unix socket.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %q", baseURL)
+	}
+
+	c := &Client{}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid server URL %q", baseURL)
+		}
+		c.baseURL = strings.TrimRight(u.String(), "/")
+	case "unix":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid server URL %q: missing socket path", baseURL)
+		}
+		c.socketPath = u.Path
+		c.baseURL = "http://unix"
+	default:
+		return nil, fmt.Errorf("invalid server URL %q: scheme must be http, https or unix", baseURL)
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.http == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = c.tlsConfig
+		if c.socketPath != "" {
+			var d net.Dialer
+			transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", c.socketPath)
+			}
+		}
+		c.http = &http.Client{Timeout: defaultTimeout, Transport: transport}
+	}
+	return c, nil
+}
+
+// LoadTLSConfig builds a client TLS configuration. caFile adds a CA to trust
+// besides the system roots; certFile and keyFile present a client
+// certificate. Empty arguments are skipped.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+func (c *Client) BaseURL() string {
+	if c.socketPath != "" {
+		return "unix://" + c.socketPath
+	}
+	return c.baseURL
+}
+
+// Health checks that the server is reachable.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil, nil)
+}
+
+// IsCursorError reports whether the server rejected a cursor, e.g. because
+// it has rebooted since issuing it or the cursor is too old.
+func IsCursorError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest &&
+		strings.HasPrefix(apiErr.Message, "invalid ") && strings.Contains(apiErr.Message, " cursor: ")
+}
+
+// Meta mirrors GopsUtil.GetMeta. Cursors in params are passed through and
+// the returned MetaInfo carries the next ones, same as a local call. When
+// the server rejects the cursors the call is retried without them, which
+// starts a new baseline.
+func (c *Client) Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error) {
+	meta, err := c.meta(ctx, metaQuery(modules, params))
+	if IsCursorError(err) {
+		params.CPUCursor, params.ProcCursor, params.NetRateCursor, params.DiskRateCursor = "", "", "", ""
+		params.UnitsCursor, params.ProcEventsCursor, params.AnomaliesCursor = "", "", ""
+		params.MetaCursor = ""
+		return c.meta(ctx, metaQuery(modules, params))
+	}
+	return meta, err
+}
+
+// MetaSession is Meta with the cursors kept on the server. Pass "new" to
+// start a session and then the Session of the previous result; the server
+// starts a new one if it has dropped the old.
+func (c *Client) MetaSession(ctx context.Context, modules []string, params gops.MetaParams, session string) (*models.MetaInfo, error) {
+	q := metaQuery(modules, params)
+	q.Set("session", session)
+	return c.meta(ctx, q)
+}
+
+func metaQuery(modules []string, params gops.MetaParams) url.Values {
+	q := url.Values{}
+	q.Set("modules", strings.Join(modules, ","))
+	if params.SortBy != "" {
+		q.Set("sort_by", string(params.SortBy))
+	}
+	if params.ProcLimit > 0 {
+		q.Set("limit", strconv.Itoa(params.ProcLimit))
+	}
+	q.Set("disable_proc_cpu", strconv.FormatBool(!params.EnableCPU))
+	q.Set("merge_children", strconv.FormatBool(params.MergeChildren))
+	setIfNotEmpty(q, "group_by", string(params.GroupBy))
+	if params.AccurateMemory {
+		q.Set("accurate_memory", "true")
+	}
+	setIfNotEmpty(q, "filter", params.ProcFilter.String())
+	if len(params.GPUPciIds) > 0 {
+		q.Set("gpu_pci_ids", strings.Join(params.GPUPciIds, ","))
+	}
+	setIfNotEmpty(q, "cpu_cursor", params.CPUCursor)
+	setIfNotEmpty(q, "proc_cursor", params.ProcCursor)
+	setIfNotEmpty(q, "net_rate_cursor", params.NetRateCursor)
+	setIfNotEmpty(q, "disk_rate_cursor", params.DiskRateCursor)
+	setIfNotEmpty(q, "units_cursor", params.UnitsCursor)
+	setIfNotEmpty(q, "proc_events_cursor", params.ProcEventsCursor)
+	setIfNotEmpty(q, "anomalies_cursor", params.AnomaliesCursor)
+	setIfNotEmpty(q, "meta_cursor", params.MetaCursor)
+	return q
+}
+
+func (c *Client) meta(ctx context.Context, q url.Values) (*models.MetaInfo, error) {
+	var meta models.MetaInfo
+	if err := c.do(ctx, http.MethodGet, "/gops/meta", q, nil, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// NetworkRates retries without the cursor when the server rejects it, like Meta.
+func (c *Client) NetworkRates(ctx context.Context, cursor string) (*models.NetworkRateResponse, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "cursor", cursor)
+
+	var rates models.NetworkRateResponse
+	err := c.do(ctx, http.MethodGet, "/gops/net-rate", q, nil, &rates)
+	if IsCursorError(err) && cursor != "" {
+		return c.NetworkRates(ctx, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// DiskRates retries without the cursor when the server rejects it, like Meta.
+func (c *Client) DiskRates(ctx context.Context, cursor string) (*models.DiskRateResponse, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "cursor", cursor)
+
+	var rates models.DiskRateResponse
+	err := c.do(ctx, http.MethodGet, "/gops/disk-rate", q, nil, &rates)
+	if IsCursorError(err) && cursor != "" {
+		return c.DiskRates(ctx, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+func (c *Client) DiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error) {
+	var resp struct {
+		Data []*models.DiskMountInfo `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/gops/disk/mounts", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *Client) Hardware(ctx context.Context) (*models.SystemHardware, error) {
+	var hw models.SystemHardware
+	if err := c.do(ctx, http.MethodGet, "/gops/hardware", nil, nil, &hw); err != nil {
+		return nil, err
+	}
+	return &hw, nil
+}
+
+func (c *Client) Temperatures(ctx context.Context) ([]models.TemperatureSensor, error) {
+	var resp struct {
+		Data []models.TemperatureSensor `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/gops/temperatures", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *Client) Alerts(ctx context.Context) (*models.AlertsInfo, error) {
+	var info models.AlertsInfo
+	if err := c.do(ctx, http.MethodGet, "/gops/alerts", nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Fleet fetches /gops/fleet/meta from a server running with --peers.
+func (c *Client) Fleet(ctx context.Context, limit int) (*models.FleetInfo, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+
+	var info models.FleetInfo
+	if err := c.do(ctx, http.MethodGet, "/gops/fleet/meta", q, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ProcessDetails fetches /gops/processes/{pid}. includeEnv needs a
+// credential with the action scope.
+func (c *Client) ProcessDetails(ctx context.Context, pid int32, includeEnv bool) (*models.ProcessDetails, error) {
+	q := url.Values{}
+	if includeEnv {
+		q.Set("env", "true")
+	}
+
+	var details models.ProcessDetails
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/gops/processes/%d", pid), q, nil, &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// Threads retries without the cursor when the server rejects it, like Meta.
+func (c *Client) Threads(ctx context.Context, pid int32, cursor string) (*models.ThreadListResponse, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "cursor", cursor)
+
+	var threads models.ThreadListResponse
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/gops/processes/%d/threads", pid), q, nil, &threads)
+	if IsCursorError(err) && cursor != "" {
+		return c.Threads(ctx, pid, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &threads, nil
+}
+
+// Signal asks the server to send signal (e.g. "TERM") to pid. Servers
+// refuse this with 403 unless they were started with --allow-actions.
+func (c *Client) Signal(ctx context.Context, pid int32, signal string) error {
+	body := map[string]string{"signal": signal}
+	path := fmt.Sprintf("/gops/processes/%d/signal", pid)
+	return c.do(ctx, http.MethodPost, path, nil, body, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	var problem struct {
+		Title   string `json:"title"`
+		Detail  string `json:"detail"`
+		Message string `json:"message"`
+	}
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if json.Unmarshal(data, &problem) == nil {
+		switch {
+		case problem.Detail != "":
+			apiErr.Message = problem.Detail
+		case problem.Message != "":
+			apiErr.Message = problem.Message
+		default:
+			apiErr.Message = problem.Title
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+func setIfNotEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRejectsInvalidURLs(t *testing.T) {
+	for _, raw := range []string{"", "localhost:63484", "ftp://host", "http://", "unix://"} {
+		_, err := New(raw)
+		assert.Error(t, err, raw)
+	}
+
+	c, err := New("http://host:63484/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://host:63484", c.BaseURL())
+
+	c, err = New("unix:///run/dgop.sock")
+	require.NoError(t, err)
+	assert.Equal(t, "unix:///run/dgop.sock", c.BaseURL())
+}
+
+func TestMetaPassesCursors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gops/meta", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "cpu,processes", q.Get("modules"))
+		assert.Equal(t, "memory", q.Get("sort_by"))
+		assert.Equal(t, "5", q.Get("limit"))
+		assert.Equal(t, "false", q.Get("disable_proc_cpu"))
+		assert.Equal(t, "cpu-cursor", q.Get("cpu_cursor"))
+		assert.Equal(t, "proc-cursor", q.Get("proc_cursor"))
+		assert.False(t, q.Has("net_rate_cursor"))
+		assert.Equal(t, "meta-cursor", q.Get("meta_cursor"))
+
+		json.NewEncoder(w).Encode(models.MetaInfo{
+			CPU:    &models.CPUInfo{Usage: 42, Cursor: "next-cpu"},
+			Cursor: "next-proc",
+		})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	meta, err := c.Meta(t.Context(), []string{"cpu", "processes"}, gops.MetaParams{
+		SortBy:     gops.SortByMemory,
+		ProcLimit:  5,
+		EnableCPU:  true,
+		CPUCursor:  "cpu-cursor",
+		ProcCursor: "proc-cursor",
+		MetaCursor: "meta-cursor",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, meta.CPU.Usage)
+	assert.Equal(t, "next-cpu", meta.CPU.Cursor)
+	assert.Equal(t, "next-proc", meta.Cursor)
+}
+
+func TestMetaRetriesWithoutRejectedCursors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Has("cpu_cursor") {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":400,"detail":"invalid cpu cursor: cursor is from a previous boot"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(models.MetaInfo{CPU: &models.CPUInfo{Cursor: "fresh"}})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	meta, err := c.Meta(t.Context(), []string{"cpu"}, gops.MetaParams{CPUCursor: "stale"})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", meta.CPU.Cursor)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMetaSession(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "new", r.URL.Query().Get("session"))
+		json.NewEncoder(w).Encode(models.MetaInfo{Session: "abc123"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	meta, err := c.MetaSession(t.Context(), []string{"cpu"}, gops.MetaParams{}, "new")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", meta.Session)
+}
+
+func TestSignalReturnsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/gops/processes/1234/signal", r.URL.Path)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "KILL", body["signal"])
+
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"status":403,"detail":"process actions are disabled"}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	err = c.Signal(t.Context(), 1234, "KILL")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "process actions are disabled", apiErr.Message)
+}
@@ -0,0 +1,20 @@
+package gops_handler
+
+import (
+	"context"
+
+	"github.com/AvengeMedia/dankgo/httpapi"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+type AlertsResponse struct {
+	Body *models.AlertsInfo
+}
+
+// GET /alerts
+func (self *HandlerGroup) Alerts(ctx context.Context, _ *httpapi.EmptyInput) (*AlertsResponse, error) {
+	if self.srv.Alerts == nil {
+		return &AlertsResponse{Body: &models.AlertsInfo{Alerts: []models.Alert{}}}, nil
+	}
+	return &AlertsResponse{Body: self.srv.Alerts.Alerts()}, nil
+}
@@ -14,6 +14,7 @@ type AllInput struct {
 	Limit          int             `query:"ps_limit"`
 	DisableProcCPU bool            `query:"disable_proc_cpu" default:"false"`
 	MergeChildren  bool            `query:"merge_children" default:"true"`
+	Strict         bool            `query:"strict" default:"false" doc:"Fail the request when any module couldn't be collected, instead of listing it under errors"`
 }
 
 type AllResponse struct {
@@ -25,11 +26,16 @@ type AllResponse struct {
 // GET /all
 func (self *HandlerGroup) All(ctx context.Context, input *AllInput) (*AllResponse, error) {
 	enableCPU := !input.DisableProcCPU
-	all, err := self.srv.Gops.GetAllMetrics(input.SortBy, input.Limit, enableCPU, input.MergeChildren)
+	all, err := self.srv.Gops.GetAllMetrics(ctx, input.SortBy, input.Limit, enableCPU, input.MergeChildren)
 	if err != nil {
 		log.Error("Error getting all metrics")
 		return nil, huma.Error500InternalServerError("Unable to retrieve all metrics")
 	}
+	if input.Strict {
+		if err := gops.CheckComplete(all.Errors); err != nil {
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
 
 	resp := &AllResponse{}
 	resp.Body.Data = all
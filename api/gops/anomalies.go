@@ -0,0 +1,46 @@
+package gops_handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type AnomaliesInput struct {
+	Cursor       string  `query:"cursor" doc:"Cursor from the previous request"`
+	StuckSamples int     `query:"stuck_samples" default:"3" minimum:"1" doc:"Samples in a row a process must spend in uninterruptible sleep to be flagged"`
+	ForkRate     float64 `query:"fork_rate" default:"20" minimum:"0" doc:"New processes per second under one ancestor that count as a fork bomb"`
+	GrowthWindow string  `query:"growth_window" default:"10m" doc:"Span memory growth is judged over, as a Go duration"`
+}
+
+type AnomaliesResponse struct {
+	Body *models.AnomaliesResponse
+}
+
+// GET /anomalies
+func (self *HandlerGroup) Anomalies(ctx context.Context, input *AnomaliesInput) (*AnomaliesResponse, error) {
+	window, err := time.ParseDuration(input.GrowthWindow)
+	if err != nil || window <= 0 {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid growth_window %q", input.GrowthWindow))
+	}
+
+	anomalies, err := self.srv.Gops.GetAnomalies(ctx, input.Cursor, gops.AnomalyOptions{
+		StuckSamples: input.StuckSamples,
+		ForkRate:     input.ForkRate,
+		GrowthWindow: window,
+	})
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err != nil {
+		log.Error("Error getting anomalies")
+		return nil, huma.Error500InternalServerError("Unable to retrieve anomalies")
+	}
+
+	return &AnomaliesResponse{Body: anomalies}, nil
+}
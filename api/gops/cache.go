@@ -0,0 +1,21 @@
+package gops_handler
+
+import (
+	"context"
+
+	"github.com/AvengeMedia/dankgo/httpapi"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+type CacheResponse struct {
+	Body *models.CacheStats
+}
+
+// GET /cache
+func (self *HandlerGroup) Cache(ctx context.Context, _ *httpapi.EmptyInput) (*CacheResponse, error) {
+	stats := self.srv.Gops.CacheStats()
+	if stats == nil {
+		stats = &models.CacheStats{Modules: map[string]models.ModuleCacheStats{}}
+	}
+	return &CacheResponse{Body: stats}, nil
+}
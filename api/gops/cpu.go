@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -20,7 +21,10 @@ type CpuResponse struct {
 
 // GET /cpu
 func (self *HandlerGroup) Cpu(ctx context.Context, input *CpuInput) (*CpuResponse, error) {
-	cpuInfo, err := self.srv.Gops.GetCPUInfoWithCursor(input.Cursor)
+	cpuInfo, err := self.srv.Gops.GetCPUInfoWithCursor(ctx, input.Cursor)
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		log.Error("Error getting CPU info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve CPU info")
@@ -17,7 +17,7 @@ type DiskResponse struct {
 
 // GET /disk
 func (self *HandlerGroup) Disk(ctx context.Context, _ *httpapi.EmptyInput) (*DiskResponse, error) {
-	diskInfo, err := self.srv.Gops.GetDiskInfo()
+	diskInfo, err := self.srv.Gops.GetDiskInfo(ctx)
 	if err != nil {
 		log.Error("Error getting Disk info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve Disk info")
@@ -36,7 +36,7 @@ type DiskMountsResponse struct {
 }
 
 func (self *HandlerGroup) DiskMounts(ctx context.Context, _ *httpapi.EmptyInput) (*DiskMountsResponse, error) {
-	diskMountsInfo, err := self.srv.Gops.GetDiskMounts()
+	diskMountsInfo, err := self.srv.Gops.GetDiskMounts(ctx)
 	if err != nil {
 		log.Error("Error getting Disk Mounts info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve Disk Mounts info")
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -18,7 +19,10 @@ type DiskRateResponse struct {
 
 // GET /disk-rate
 func (self *HandlerGroup) DiskRate(ctx context.Context, input *DiskRateInput) (*DiskRateResponse, error) {
-	diskRateInfo, err := self.srv.Gops.GetDiskRates(input.Cursor)
+	diskRateInfo, err := self.srv.Gops.GetDiskRates(ctx, input.Cursor)
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		log.Error("Error getting disk rates")
 		return nil, huma.Error500InternalServerError("Unable to retrieve disk rates")
@@ -0,0 +1,24 @@
+package gops_handler
+
+import (
+	"context"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type FleetInput struct {
+	Limit int `query:"limit" default:"10" minimum:"0" doc:"Maximum entries in each summary list, 0 for all"`
+}
+
+type FleetResponse struct {
+	Body *models.FleetInfo
+}
+
+// GET /fleet/meta
+func (self *HandlerGroup) FleetMeta(ctx context.Context, input *FleetInput) (*FleetResponse, error) {
+	if self.srv.Fleet == nil {
+		return nil, huma.Error404NotFound("fleet mode is not enabled (start the server with --peers)")
+	}
+	return &FleetResponse{Body: self.srv.Fleet.Info(input.Limit)}, nil
+}
@@ -112,6 +112,78 @@ func RegisterHandlers(server *server.Server, grp *huma.Group) {
 		handlers.Processes,
 	)
 
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "process-details",
+			Summary:     "Get Process Details",
+			Description: "Get everything known about one process: state, threads with their CPU usage, open files, limits, namespaces, cgroup, OOM score, context switches, I/O counters and a memory breakdown",
+			Path:        "/processes/{pid}",
+			Method:      http.MethodGet,
+		},
+		handlers.ProcessDetails,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "process-threads",
+			Summary:     "Get Process Threads",
+			Description: "Get the threads of a process, busiest first, with cursor-based sampling for per-thread CPU usage",
+			Path:        "/processes/{pid}/threads",
+			Method:      http.MethodGet,
+		},
+		handlers.Threads,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "proc-events",
+			Summary:     "Get Process Events",
+			Description: "Get the processes that started and exited since the cursor, with the name, runtime and last CPU and memory of those that exited. Uses the kernel's proc connector when dgop may, and otherwise compares the processes running at each call",
+			Path:        "/proc-events",
+			Method:      http.MethodGet,
+		},
+		handlers.ProcEvents,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "anomalies",
+			Summary:     "Get Process Anomalies",
+			Description: "Flag parents leaving zombies, processes stuck in uninterruptible sleep, fork bombs and processes whose memory keeps growing. Everything but zombies is judged across calls, so poll with the cursor",
+			Path:        "/anomalies",
+			Method:      http.MethodGet,
+		},
+		handlers.Anomalies,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "units",
+			Summary:     "Get Systemd Units",
+			Description: "Get the CPU, memory, I/O and task usage of running systemd services and scopes, with cursor-based sampling for CPU and I/O rates",
+			Path:        "/units",
+			Method:      http.MethodGet,
+		},
+		handlers.Units,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "process-signal",
+			Summary:     "Signal Process",
+			Description: "Send a signal to a process. Requires the server to be started with --allow-actions",
+			Path:        "/processes/{pid}/signal",
+			Method:      http.MethodPost,
+		},
+		handlers.Signal,
+	)
+
 	huma.Register(
 		grp,
 		huma.Operation{
@@ -172,6 +244,18 @@ func RegisterHandlers(server *server.Server, grp *huma.Group) {
 		handlers.GPUTemp,
 	)
 
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "temperatures",
+			Summary:     "Get Temperatures",
+			Description: "Get readings from all temperature sensors",
+			Path:        "/temperatures",
+			Method:      http.MethodGet,
+		},
+		handlers.Temperatures,
+	)
+
 	huma.Register(
 		grp,
 		huma.Operation{
@@ -195,4 +279,40 @@ func RegisterHandlers(server *server.Server, grp *huma.Group) {
 		},
 		handlers.Modules,
 	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "fleet-meta",
+			Summary:     "Get Fleet Metrics",
+			Description: "Get the latest metrics of every federated host, fleet-wide summaries and peer health",
+			Path:        "/fleet/meta",
+			Method:      http.MethodGet,
+		},
+		handlers.FleetMeta,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "alerts",
+			Summary:     "Get Alerts",
+			Description: "Get the state of every configured alert rule",
+			Path:        "/alerts",
+			Method:      http.MethodGet,
+		},
+		handlers.Alerts,
+	)
+
+	huma.Register(
+		grp,
+		huma.Operation{
+			OperationID: "cache",
+			Summary:     "Get Cache Statistics",
+			Description: "Get the result cache TTL and hit, miss and coalesced counts of each module",
+			Path:        "/cache",
+			Method:      http.MethodGet,
+		},
+		handlers.Cache,
+	)
 }
@@ -24,9 +24,15 @@ type GPUTempResponse struct {
 	Body *models.GPUTempInfo
 }
 
+type TemperaturesResponse struct {
+	Body struct {
+		Data []models.TemperatureSensor `json:"data"`
+	}
+}
+
 // GET /hardware
 func (self *HandlerGroup) SystemHardware(ctx context.Context, input *struct{}) (*SystemHardwareResponse, error) {
-	systemInfo, err := self.srv.Gops.GetSystemHardware()
+	systemInfo, err := self.srv.Gops.GetSystemHardware(ctx)
 	if err != nil {
 		log.Error("Error getting system hardware info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve system hardware info")
@@ -37,7 +43,7 @@ func (self *HandlerGroup) SystemHardware(ctx context.Context, input *struct{}) (
 
 // GET /gpu
 func (self *HandlerGroup) GPU(ctx context.Context, input *struct{}) (*GPUResponse, error) {
-	gpuInfo, err := self.srv.Gops.GetGPUInfo()
+	gpuInfo, err := self.srv.Gops.GetGPUInfo(ctx)
 	if err != nil {
 		log.Error("Error getting GPU info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve GPU info")
@@ -48,7 +54,7 @@ func (self *HandlerGroup) GPU(ctx context.Context, input *struct{}) (*GPURespons
 
 // GET /gpu/temp
 func (self *HandlerGroup) GPUTemp(ctx context.Context, input *GPUTempInput) (*GPUTempResponse, error) {
-	gpuTempInfo, err := self.srv.Gops.GetGPUTemp(input.PciId)
+	gpuTempInfo, err := self.srv.Gops.GetGPUTemp(ctx, input.PciId)
 	if err != nil {
 		log.Error("Error getting GPU temperature")
 		return nil, huma.Error400BadRequest(err.Error())
@@ -56,3 +62,16 @@ func (self *HandlerGroup) GPUTemp(ctx context.Context, input *GPUTempInput) (*GP
 
 	return &GPUTempResponse{Body: gpuTempInfo}, nil
 }
+
+// GET /temperatures
+func (self *HandlerGroup) Temperatures(ctx context.Context, input *struct{}) (*TemperaturesResponse, error) {
+	temps, err := self.srv.Gops.GetSystemTemperatures(ctx)
+	if err != nil {
+		log.Error("Error getting temperatures")
+		return nil, huma.Error500InternalServerError("Unable to retrieve temperatures")
+	}
+
+	resp := &TemperaturesResponse{}
+	resp.Body.Data = temps
+	return resp, nil
+}
@@ -18,7 +18,7 @@ type MemoryResponse struct {
 // GET /memory
 func (self *HandlerGroup) Memory(ctx context.Context, _ *httpapi.EmptyInput) (*MemoryResponse, error) {
 
-	memoryInfo, err := self.srv.Gops.GetMemoryInfo()
+	memoryInfo, err := self.srv.Gops.GetMemoryInfo(ctx)
 	if err != nil {
 		log.Error("Error getting memory info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve memory info")
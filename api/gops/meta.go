@@ -11,18 +11,27 @@ import (
 )
 
 type MetaInput struct {
-	Modules        []string        `query:"modules" required:"true" example:"cpu,memory,network"`
-	SortBy         gops.ProcSortBy `query:"sort_by" default:"cpu"`
-	Limit          int             `query:"limit" default:"0"`
-	DisableProcCPU bool            `query:"disable_proc_cpu" default:"false"`
-	MergeChildren  bool            `query:"merge_children" default:"true"`
+	Modules        []string         `query:"modules" required:"true" example:"cpu,memory,network"`
+	SortBy         gops.ProcSortBy  `query:"sort_by" default:"cpu"`
+	Limit          int              `query:"limit" default:"0"`
+	DisableProcCPU bool             `query:"disable_proc_cpu" default:"false"`
+	MergeChildren  bool             `query:"merge_children" default:"true"`
+	GroupBy        gops.ProcGroupBy `query:"group_by" default:"process" doc:"'app' lists each desktop application once, with its processes' usage added together"`
+	AccurateMemory bool             `query:"accurate_memory" default:"false" doc:"Read every process's smaps_rollup for PSS, USS and swap, and report memory as PSS. Slower; sort_by=pss turns it on"`
+	Filter         string           `query:"filter" example:"user=alice name~^firefox kthreads=false" doc:"Only list matching processes: space-separated terms that must all hold, from user=a,b name~re cmd~re pid=1,2 tree=PID cgroup~re state=R,D cpu>=N mem>=N kthreads=false"`
 
 	// Module-specific parameters
-	GPUPciIds      []string `query:"gpu_pci_ids" example:"10de:2684,1002:164e" doc:"PCI IDs for GPU temperatures (when gpu module is requested)"`
-	CPUCursor      string   `query:"cpu_cursor" doc:"CPU cursor from previous request"`
-	ProcCursor     string   `query:"proc_cursor" doc:"Process cursor from previous request"`
-	NetRateCursor  string   `query:"net_rate_cursor" doc:"Network rate cursor from previous request"`
-	DiskRateCursor string   `query:"disk_rate_cursor" doc:"Disk rate cursor from previous request"`
+	GPUPciIds        []string `query:"gpu_pci_ids" example:"10de:2684,1002:164e" doc:"PCI IDs for GPU temperatures (when gpu module is requested)"`
+	CPUCursor        string   `query:"cpu_cursor" doc:"CPU cursor from previous request"`
+	ProcCursor       string   `query:"proc_cursor" doc:"Process cursor from previous request"`
+	NetRateCursor    string   `query:"net_rate_cursor" doc:"Network rate cursor from previous request"`
+	DiskRateCursor   string   `query:"disk_rate_cursor" doc:"Disk rate cursor from previous request"`
+	UnitsCursor      string   `query:"units_cursor" doc:"Units cursor from previous request"`
+	ProcEventsCursor string   `query:"proc_events_cursor" doc:"Process events cursor from previous request"`
+	AnomaliesCursor  string   `query:"anomalies_cursor" doc:"Anomalies cursor from previous request"`
+	MetaCursor       string   `query:"meta_cursor" doc:"Composite cursor (metaCursor) from previous request, covering every module; explicit module cursors take precedence"`
+	Session          string   `query:"session" doc:"Keep cursors on the server: 'new' to start a session, then the session ID from the previous response. Responses carry the session ID instead of cursors"`
+	Strict           bool     `query:"strict" default:"false" doc:"Fail the request when any module couldn't be collected, instead of listing it under errors"`
 }
 
 type MetaResponse struct {
@@ -47,24 +56,68 @@ func (self *HandlerGroup) Meta(ctx context.Context, input *MetaInput) (*MetaResp
 		modules = input.Modules
 	}
 
+	filter, err := gops.ParseProcFilter(input.Filter)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
 	params := gops.MetaParams{
-		SortBy:         input.SortBy,
-		ProcLimit:      input.Limit,
-		EnableCPU:      !input.DisableProcCPU,
-		MergeChildren:  input.MergeChildren,
-		GPUPciIds:      input.GPUPciIds,
-		CPUCursor:      input.CPUCursor,
-		ProcCursor:     input.ProcCursor,
-		NetRateCursor:  input.NetRateCursor,
-		DiskRateCursor: input.DiskRateCursor,
+		SortBy:           input.SortBy,
+		ProcLimit:        input.Limit,
+		EnableCPU:        !input.DisableProcCPU,
+		MergeChildren:    input.MergeChildren,
+		GroupBy:          input.GroupBy,
+		AccurateMemory:   input.AccurateMemory,
+		ProcFilter:       filter,
+		GPUPciIds:        input.GPUPciIds,
+		CPUCursor:        input.CPUCursor,
+		ProcCursor:       input.ProcCursor,
+		NetRateCursor:    input.NetRateCursor,
+		DiskRateCursor:   input.DiskRateCursor,
+		UnitsCursor:      input.UnitsCursor,
+		ProcEventsCursor: input.ProcEventsCursor,
+		AnomaliesCursor:  input.AnomaliesCursor,
+		MetaCursor:       input.MetaCursor,
+	}
+
+	var session string
+	var fromSession bool
+	if input.Session != "" {
+		var cursor string
+		session, cursor = self.srv.Sessions.Resume(input.Session, "meta")
+		if params.MetaCursor == "" && cursor != "" {
+			params.MetaCursor = cursor
+			fromSession = true
+		}
 	}
 
 	metaInfo, err := self.srv.Gops.GetMeta(ctx, modules, params)
+	if gops.IsCursorError(err) && session != "" {
+		// The client never sees the session's cursor, so it can't drop it;
+		// forget it here and start again, as client.Meta does.
+		self.srv.Sessions.Save(session, "meta", "")
+		if fromSession {
+			params.MetaCursor = ""
+			metaInfo, err = self.srv.Gops.GetMeta(ctx, modules, params)
+		}
+	}
 	if err != nil {
 		log.Error("Error getting meta info")
 		return nil, huma.Error400BadRequest(err.Error())
 	}
 
+	if session != "" {
+		self.srv.Sessions.Save(session, "meta", metaInfo.MetaCursor)
+		stripCursors(metaInfo)
+		metaInfo.Session = session
+	}
+
+	if input.Strict {
+		if err := gops.CheckComplete(metaInfo.Errors); err != nil {
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
 	return &MetaResponse{Body: metaInfo}, nil
 }
 
@@ -78,3 +131,28 @@ func (self *HandlerGroup) Modules(ctx context.Context, input *struct{}) (*Module
 
 	return &ModulesResponse{Body: modulesInfo}, nil
 }
+
+// stripCursors drops the cursors a session keeps on the server, so they
+// don't travel with the response.
+func stripCursors(meta *models.MetaInfo) {
+	meta.Cursor = ""
+	meta.MetaCursor = ""
+	if meta.CPU != nil {
+		meta.CPU.Cursor = ""
+	}
+	if meta.NetRate != nil {
+		meta.NetRate.Cursor = ""
+	}
+	if meta.DiskRate != nil {
+		meta.DiskRate.Cursor = ""
+	}
+	if meta.Units != nil {
+		meta.Units.Cursor = ""
+	}
+	if meta.ProcEvents != nil {
+		meta.ProcEvents.Cursor = ""
+	}
+	if meta.Anomalies != nil {
+		meta.Anomalies.Cursor = ""
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -18,7 +19,10 @@ type NetRateResponse struct {
 
 // GET /net-rate
 func (self *HandlerGroup) NetRate(ctx context.Context, input *NetRateInput) (*NetRateResponse, error) {
-	netRateInfo, err := self.srv.Gops.GetNetworkRates(input.Cursor)
+	netRateInfo, err := self.srv.Gops.GetNetworkRates(ctx, input.Cursor)
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		log.Error("Error getting network rates")
 		return nil, huma.Error500InternalServerError("Unable to retrieve network rates")
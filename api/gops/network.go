@@ -18,7 +18,7 @@ type NetworkResponse struct {
 // GET /network
 func (self *HandlerGroup) Network(ctx context.Context, _ *httpapi.EmptyInput) (*NetworkResponse, error) {
 
-	networkInfo, err := self.srv.Gops.GetNetworkInfo()
+	networkInfo, err := self.srv.Gops.GetNetworkInfo(ctx)
 	if err != nil {
 		log.Error("Error getting Network info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve Network info")
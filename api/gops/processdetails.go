@@ -0,0 +1,41 @@
+package gops_handler
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/api/auth"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type ProcessDetailsInput struct {
+	PID int32 `path:"pid" minimum:"1"`
+	Env bool  `query:"env" default:"false" doc:"Include the environment, which can hold secrets. Needs the action scope"`
+}
+
+type ProcessDetailsResponse struct {
+	Body *models.ProcessDetails
+}
+
+// GET /processes/{pid}
+func (self *HandlerGroup) ProcessDetails(ctx context.Context, input *ProcessDetailsInput) (*ProcessDetailsResponse, error) {
+	if input.Env && !auth.HasScope(ctx, auth.ScopeAction) {
+		return nil, huma.Error403Forbidden("reading the environment needs the action scope")
+	}
+
+	details, err := self.srv.Gops.GetProcessDetails(ctx, input.PID, input.Env)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, huma.Error404NotFound("no such process")
+	case errors.Is(err, fs.ErrPermission):
+		return nil, huma.Error403Forbidden("not permitted to read this process")
+	case err != nil:
+		log.Error("Error getting process details")
+		return nil, huma.Error500InternalServerError("Unable to retrieve process details")
+	}
+
+	return &ProcessDetailsResponse{Body: details}, nil
+}
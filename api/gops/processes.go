@@ -10,25 +10,67 @@ import (
 )
 
 type ProcessInput struct {
-	SortBy         gops.ProcSortBy `query:"sort_by" required:"true" default:"cpu"`
-	Limit          int             `query:"limit"`
-	DisableProcCPU bool            `query:"disable_proc_cpu" default:"false"`
-	Cursor         string          `query:"cursor" required:"false"`
-	MergeChildren  bool            `query:"merge_children" default:"true"`
+	SortBy         gops.ProcSortBy  `query:"sort_by" required:"true" default:"cpu"`
+	Limit          int              `query:"limit"`
+	DisableProcCPU bool             `query:"disable_proc_cpu" default:"false"`
+	Cursor         string           `query:"cursor" required:"false"`
+	Session        string           `query:"session" required:"false" doc:"Keep the cursor on the server: 'new' to start a session, then the session ID from the previous response"`
+	MergeChildren  bool             `query:"merge_children" default:"true"`
+	GroupBy        gops.ProcGroupBy `query:"group_by" default:"process" doc:"'app' lists each desktop application once, with its processes' usage added together"`
+	AccurateMemory bool             `query:"accurate_memory" default:"false" doc:"Read every process's smaps_rollup for PSS, USS and swap, and report memory as PSS. Slower; sort_by=pss turns it on"`
+	Filter         string           `query:"filter" example:"user=alice name~^firefox kthreads=false" doc:"Only list matching processes: space-separated terms that must all hold, from user=a,b name~re cmd~re pid=1,2 tree=PID cgroup~re state=R,D cpu>=N mem>=N kthreads=false"`
 }
 
 type ProcessResponse struct {
 	Body struct {
-		Data   []*models.ProcessInfo `json:"data"`
-		Cursor string                `json:"cursor,omitempty"`
+		Data    []*models.ProcessInfo `json:"data"`
+		Cursor  string                `json:"cursor,omitempty"`
+		Session string                `json:"session,omitempty"`
 	}
 }
 
 // GET /processes
 func (self *HandlerGroup) Processes(ctx context.Context, input *ProcessInput) (*ProcessResponse, error) {
 	enableCPU := !input.DisableProcCPU
+	filter, err := gops.ParseProcFilter(input.Filter)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 
-	result, err := self.srv.Gops.GetProcessesWithCursor(input.SortBy, input.Limit, enableCPU, input.Cursor, input.MergeChildren)
+	params := gops.ProcessParams{
+		SortBy:         input.SortBy,
+		Limit:          input.Limit,
+		EnableCPU:      enableCPU,
+		Cursor:         input.Cursor,
+		MergeChildren:  input.MergeChildren,
+		GroupBy:        input.GroupBy,
+		AccurateMemory: input.AccurateMemory,
+		Filter:         filter,
+	}
+
+	var session string
+	var fromSession bool
+	if input.Session != "" {
+		var saved string
+		session, saved = self.srv.Sessions.Resume(input.Session, "processes")
+		if params.Cursor == "" && saved != "" {
+			params.Cursor = saved
+			fromSession = true
+		}
+	}
+
+	result, err := self.srv.Gops.GetProcessesWithCursor(ctx, params)
+	if gops.IsCursorError(err) && session != "" {
+		// As in Meta: the client can't drop a cursor it never saw.
+		self.srv.Sessions.Save(session, "processes", "")
+		if fromSession {
+			params.Cursor = ""
+			result, err = self.srv.Gops.GetProcessesWithCursor(ctx, params)
+		}
+	}
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		log.Error("Error getting process info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve process info")
@@ -36,6 +78,11 @@ func (self *HandlerGroup) Processes(ctx context.Context, input *ProcessInput) (*
 
 	resp := &ProcessResponse{}
 	resp.Body.Data = result.Processes
-	resp.Body.Cursor = result.Cursor
+	if session != "" {
+		self.srv.Sessions.Save(session, "processes", result.Cursor)
+		resp.Body.Session = session
+	} else {
+		resp.Body.Cursor = result.Cursor
+	}
 	return resp, nil
 }
@@ -0,0 +1,75 @@
+package gops_handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/api/server"
+	"github.com/AvengeMedia/dgop/api/session"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorHeader decodes the clear header of a cursor, which is
+// header.payload.mac.
+func cursorHeader(t *testing.T, cursor string) map[string]any {
+	t.Helper()
+	encoded, _, _ := strings.Cut(cursor, ".")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	var header map[string]any
+	require.NoError(t, json.Unmarshal(data, &header))
+	return header
+}
+
+// backdate re-signs cursor with key as if it were issued age ago.
+func backdate(t *testing.T, key []byte, cursor string, age time.Duration) string {
+	t.Helper()
+	header := cursorHeader(t, cursor)
+	header["t"] = time.Now().Add(-age).UnixMilli()
+	data, err := json.Marshal(header)
+	require.NoError(t, err)
+
+	rest := cursor[strings.IndexByte(cursor, '.')+1:]
+	payload := rest[:strings.LastIndexByte(rest, '.')]
+	signed := base64.RawURLEncoding.EncodeToString(data) + "." + payload
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func TestProcessesSessionDropsExpiredCursor(t *testing.T) {
+	key := gops.NewCursorKey()
+	g := gops.NewGopsUtil()
+	require.NoError(t, g.SetCursorKey(key))
+	srv := &server.Server{Gops: g, Sessions: session.NewStore(0, 0)}
+	_, api := humatest.New(t)
+	RegisterHandlers(srv, huma.NewGroup(api, "/gops"))
+
+	resp := api.Get("/gops/processes?session=new&disable_proc_cpu=true&limit=1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var body struct {
+		Session string `json:"session"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	_, saved := srv.Sessions.Resume(body.Session, "processes")
+	require.NotEmpty(t, saved)
+
+	srv.Sessions.Save(body.Session, "processes", backdate(t, key, saved, 2*gops.CursorMaxAge))
+	resp = api.Get("/gops/processes?session=" + body.Session + "&disable_proc_cpu=true&limit=1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	_, fresh := srv.Sessions.Resume(body.Session, "processes")
+	require.NotEmpty(t, fresh)
+	issued := time.UnixMilli(int64(cursorHeader(t, fresh)["t"].(float64)))
+	assert.WithinDuration(t, time.Now(), issued, time.Minute, "the session holds a new cursor")
+}
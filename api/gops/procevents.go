@@ -0,0 +1,32 @@
+package gops_handler
+
+import (
+	"context"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type ProcEventsInput struct {
+	Cursor string `query:"cursor" doc:"Cursor from the previous request; the first request only returns one"`
+}
+
+type ProcEventsResponse struct {
+	Body *models.ProcessEventsResponse
+}
+
+// GET /proc-events
+func (self *HandlerGroup) ProcEvents(ctx context.Context, input *ProcEventsInput) (*ProcEventsResponse, error) {
+	events, err := self.srv.Gops.GetProcessEvents(ctx, input.Cursor)
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err != nil {
+		log.Error("Error getting process events")
+		return nil, huma.Error500InternalServerError("Unable to retrieve process events")
+	}
+
+	return &ProcEventsResponse{Body: events}, nil
+}
@@ -0,0 +1,60 @@
+package gops_handler
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/api/auth"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type SignalInput struct {
+	PID  int32 `path:"pid" minimum:"1"`
+	Body struct {
+		Signal string `json:"signal" required:"false" default:"TERM" example:"TERM" doc:"Signal name, e.g. TERM, KILL, HUP"`
+	}
+}
+
+type SignalResponse struct {
+	Body struct {
+		PID    int32  `json:"pid"`
+		Signal string `json:"signal"`
+	}
+}
+
+// POST /processes/{pid}/signal
+func (self *HandlerGroup) Signal(ctx context.Context, input *SignalInput) (*SignalResponse, error) {
+	if !self.srv.Cfg.AllowActions {
+		return nil, huma.Error403Forbidden("process actions are disabled on this server (start it with --allow-actions)")
+	}
+	if !auth.HasScope(ctx, auth.ScopeAction) {
+		return nil, huma.Error403Forbidden("this credential lacks the action scope")
+	}
+
+	sig, err := gops.ParseSignal(input.Body.Signal)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	if err := self.srv.Gops.SignalProcess(input.PID, sig); err != nil {
+		switch {
+		case errors.Is(err, syscall.ESRCH):
+			return nil, huma.Error404NotFound("no such process")
+		case errors.Is(err, syscall.EPERM):
+			return nil, huma.Error403Forbidden("not permitted to signal this process")
+		}
+		log.Error("Error signalling process", "pid", input.PID, "error", err)
+		return nil, huma.Error500InternalServerError("Unable to signal process")
+	}
+
+	name := gops.SignalName(sig)
+	log.Infof("Sent SIG%s to PID %d", name, input.PID)
+
+	resp := &SignalResponse{}
+	resp.Body.PID = input.PID
+	resp.Body.Signal = name
+	return resp, nil
+}
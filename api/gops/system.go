@@ -18,7 +18,7 @@ type SystemResponse struct {
 // GET /system
 func (self *HandlerGroup) System(ctx context.Context, _ *httpapi.EmptyInput) (*SystemResponse, error) {
 
-	systemInfo, err := self.srv.Gops.GetSystemInfo()
+	systemInfo, err := self.srv.Gops.GetSystemInfo(ctx)
 	if err != nil {
 		log.Error("Error getting system info")
 		return nil, huma.Error500InternalServerError("Unable to retrieve system info")
@@ -0,0 +1,40 @@
+package gops_handler
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type ThreadsInput struct {
+	PID    int32  `path:"pid" minimum:"1"`
+	Limit  int    `query:"limit" default:"0"`
+	Cursor string `query:"cursor" doc:"Base64 cursor for per-thread CPU calculation"`
+}
+
+type ThreadsResponse struct {
+	Body *models.ThreadListResponse
+}
+
+// GET /processes/{pid}/threads
+func (self *HandlerGroup) Threads(ctx context.Context, input *ThreadsInput) (*ThreadsResponse, error) {
+	threads, err := self.srv.Gops.GetThreads(ctx, input.PID, input.Limit, input.Cursor)
+	switch {
+	case gops.IsCursorError(err):
+		return nil, huma.Error400BadRequest(err.Error())
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, huma.Error404NotFound("no such process")
+	case errors.Is(err, errors.ErrUnsupported):
+		return nil, huma.Error501NotImplemented(err.Error())
+	case err != nil:
+		log.Error("Error getting threads")
+		return nil, huma.Error500InternalServerError("Unable to retrieve threads")
+	}
+
+	return &ThreadsResponse{Body: threads}, nil
+}
@@ -0,0 +1,38 @@
+package gops_handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type UnitsInput struct {
+	SortBy gops.UnitSortBy `query:"sort_by" default:"memory"`
+	Limit  int             `query:"limit" default:"0"`
+	Cursor string          `query:"cursor" doc:"Base64 cursor for CPU and I/O rate calculation"`
+}
+
+type UnitsResponse struct {
+	Body *models.UnitsResponse
+}
+
+// GET /units
+func (self *HandlerGroup) Units(ctx context.Context, input *UnitsInput) (*UnitsResponse, error) {
+	units, err := self.srv.Gops.GetUnits(ctx, input.SortBy, input.Limit, input.Cursor)
+	if gops.IsCursorError(err) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, huma.Error501NotImplemented(err.Error())
+	}
+	if err != nil {
+		log.Error("Error getting units")
+		return nil, huma.Error500InternalServerError("Unable to retrieve units")
+	}
+
+	return &UnitsResponse{Body: units}, nil
+}
@@ -1,11 +1,20 @@
 package server
 
 import (
+	"github.com/AvengeMedia/dgop/alerts"
+	"github.com/AvengeMedia/dgop/api/session"
 	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/fleet"
 	"github.com/AvengeMedia/dgop/gops"
 )
 
 type Server struct {
-	Cfg  *config.Config
-	Gops *gops.GopsUtil
+	Cfg    *config.Config
+	Gops   *gops.GopsUtil
+	Alerts *alerts.Engine
+	Fleet  *fleet.Fleet
+
+	// Sessions holds cursors for clients using ?session= instead of
+	// passing them back.
+	Sessions *session.Store
 }
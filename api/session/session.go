@@ -0,0 +1,165 @@
+// Package session keeps cursors on the server for clients that would rather
+// not send them back on every request.
+//
+// A client opts in by asking for a new session, then passes the short ID it
+// gets back where it would have passed a cursor. Sessions expire after a
+// period without use, and the least recently used are dropped first once the
+// stored cursors exceed a memory cap. A client whose session was dropped
+// simply gets a fresh one, the same as sending no cursor.
+package session
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// New asks for a fresh session instead of resuming one.
+const New = "new"
+
+const (
+	DefaultTTL       = 5 * time.Minute
+	DefaultMaxMemory = 32 << 20
+
+	// idBytes of randomness give a 16 character ID.
+	idBytes = 12
+	// entryOverhead roughly accounts for the map, list and struct memory of a
+	// session on top of its cursors.
+	entryOverhead = 256
+)
+
+type entry struct {
+	id       string
+	cursors  map[string]string
+	size     int
+	lastUsed time.Time
+}
+
+// Store holds the latest cursors of each session. It is safe for concurrent
+// use.
+type Store struct {
+	ttl       time.Duration
+	maxMemory int
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru has the most recently used session at the front.
+	lru  *list.List
+	size int
+}
+
+// NewStore creates a store whose sessions expire after ttl without use and
+// whose cursors take at most maxMemory bytes. Zero values pick the defaults.
+func NewStore(ttl time.Duration, maxMemory int) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if maxMemory <= 0 {
+		maxMemory = DefaultMaxMemory
+	}
+	return &Store{
+		ttl:       ttl,
+		maxMemory: maxMemory,
+		now:       time.Now,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// Resume returns the session for id and the cursor it last saved under key.
+// For New, or an ID that expired or was never issued, it starts a new
+// session with no cursor.
+func (s *Store) Resume(id, key string) (string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	if id != New {
+		if el, ok := s.entries[id]; ok {
+			e := el.Value.(*entry)
+			e.lastUsed = now
+			s.lru.MoveToFront(el)
+			return id, e.cursors[key]
+		}
+	}
+
+	e := &entry{id: newID(), cursors: map[string]string{}, size: entryOverhead, lastUsed: now}
+	s.entries[e.id] = s.lru.PushFront(e)
+	s.size += e.size
+	s.evict()
+	return e.id, ""
+}
+
+// Save stores cursor under key for the session. Sessions that have been
+// dropped since Resume are ignored.
+func (s *Store) Save(id, key, cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[id]
+	if !ok {
+		return
+	}
+	e := el.Value.(*entry)
+	delta := len(cursor) - len(e.cursors[key])
+	if _, had := e.cursors[key]; !had {
+		delta += len(key)
+	}
+	e.cursors[key] = cursor
+	e.size += delta
+	s.size += delta
+	e.lastUsed = s.now()
+	s.lru.MoveToFront(el)
+	s.evict()
+}
+
+// Len returns the number of live sessions.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(s.now())
+	return len(s.entries)
+}
+
+// Size returns the approximate memory held by the sessions in bytes.
+func (s *Store) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// expire drops sessions unused for longer than the TTL. They sit at the back
+// of the list, so this stops at the first live one.
+func (s *Store) expire(now time.Time) {
+	for el := s.lru.Back(); el != nil; el = s.lru.Back() {
+		if now.Sub(el.Value.(*entry).lastUsed) <= s.ttl {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+// evict drops the least recently used sessions until the store fits its
+// memory cap, always keeping the most recent one.
+func (s *Store) evict() {
+	for s.size > s.maxMemory && s.lru.Len() > 1 {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *Store) remove(el *list.Element) {
+	e := s.lru.Remove(el).(*entry)
+	delete(s.entries, e.id)
+	s.size -= e.size
+}
+
+func newID() string {
+	b := make([]byte, idBytes)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeAndSave(t *testing.T) {
+	s := NewStore(0, 0)
+
+	id, cursor := s.Resume(New, "meta")
+	require.Len(t, id, 16)
+	assert.Empty(t, cursor)
+
+	s.Save(id, "meta", "meta-cursor")
+	s.Save(id, "processes", "proc-cursor")
+
+	got, cursor := s.Resume(id, "meta")
+	assert.Equal(t, id, got)
+	assert.Equal(t, "meta-cursor", cursor)
+	_, cursor = s.Resume(id, "processes")
+	assert.Equal(t, "proc-cursor", cursor)
+}
+
+func TestUnknownSessionStartsFresh(t *testing.T) {
+	s := NewStore(0, 0)
+	id, cursor := s.Resume("not-a-session", "meta")
+	assert.NotEqual(t, "not-a-session", id)
+	assert.Empty(t, cursor)
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestSessionsExpire(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewStore(time.Minute, 0)
+	s.now = func() time.Time { return now }
+
+	old, _ := s.Resume(New, "meta")
+	s.Save(old, "meta", "old")
+	now = now.Add(50 * time.Second)
+	kept, _ := s.Resume(New, "meta")
+
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, 1, s.Len())
+	id, cursor := s.Resume(old, "meta")
+	assert.NotEqual(t, old, id, "expired sessions are not resumed")
+	assert.Empty(t, cursor)
+
+	got, _ := s.Resume(kept, "meta")
+	assert.Equal(t, kept, got)
+}
+
+func TestMemoryCapEvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewStore(0, 3*entryOverhead+2000)
+	big := strings.Repeat("x", 900)
+
+	a, _ := s.Resume(New, "meta")
+	s.Save(a, "meta", big)
+	b, _ := s.Resume(New, "meta")
+	s.Save(b, "meta", big)
+	s.Resume(a, "meta")
+	c, _ := s.Resume(New, "meta")
+	s.Save(c, "meta", big)
+
+	assert.Equal(t, 2, s.Len())
+	assert.LessOrEqual(t, s.Size(), 3*entryOverhead+2000)
+	got, _ := s.Resume(b, "meta")
+	assert.NotEqual(t, b, got, "b was the least recently used")
+	_, cursor := s.Resume(a, "meta")
+	assert.Equal(t, big, cursor)
+}
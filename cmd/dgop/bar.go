@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/cmd/dgop/bar"
+	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	barProtocol  string
+	barModules   []string
+	barInterval  time.Duration
+	barSeparator string
+)
+
+var barCmd = &cobra.Command{
+	Use:   "bar",
+	Short: "Stream readings for a status bar",
+	Long: `Print cpu, memory and other readings continuously in the native format of
+i3bar/swaybar, waybar, polybar or the tmux status line. Modules are colored
+and classed low, medium or high with the same thresholds and colors as the TUI.`,
+}
+
+func runBarCommand(cmd *cobra.Command, gopsUtil *gops.GopsUtil) error {
+	protocol, err := bar.ParseProtocol(barProtocol)
+	if err != nil {
+		return err
+	}
+	modules, err := bar.ValidateModules(barModules)
+	if err != nil {
+		return err
+	}
+	if barInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	palette := models.DefaultColorPalette
+	if colors, err := config.NewColorManager(); err != nil {
+		log.Warnf("Using the default colors: %v", err)
+	} else {
+		defer colors.Close()
+		palette = colors.GetPalette
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	out := bar.NewWriter(os.Stdout, protocol, barSeparator)
+	ticker := time.NewTicker(barInterval)
+	defer ticker.Stop()
+
+	var params gops.MetaParams
+	for {
+		meta, err := gopsUtil.GetMeta(ctx, modules, params)
+		if gops.IsCursorError(err) {
+			// Resumed from a suspend longer than a cursor lives.
+			params.MetaCursor = ""
+			meta, err = gopsUtil.GetMeta(ctx, modules, params)
+		}
+		if err != nil {
+			return err
+		}
+		// Carry the cursor so usage and rates cover the last interval.
+		params.MetaCursor = meta.MetaCursor
+
+		if err := out.Write(bar.Segments(meta, modules, palette())); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
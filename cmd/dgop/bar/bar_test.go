@@ -0,0 +1,98 @@
+package bar
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleMeta() *models.MetaInfo {
+	return &models.MetaInfo{
+		CPU:    &models.CPUInfo{Model: "Test CPU", Count: 4, Usage: 85},
+		Memory: &models.MemoryInfo{Total: 8 << 20, Used: 5 << 20, UsedPercent: 62.5},
+		NetRate: &models.NetworkRateResponse{Interfaces: []*models.NetworkRateInfo{
+			{Interface: "eth0", RxRate: 1536, TxRate: 100},
+			{Interface: "wlan0", RxRate: 512, TxRate: 0},
+		}},
+	}
+}
+
+func TestSegmentsUsePaletteThresholds(t *testing.T) {
+	palette := models.DefaultColorPalette()
+	segs := Segments(sampleMeta(), DefaultModules, palette)
+	require.Len(t, segs, 3)
+
+	assert.Equal(t, "CPU 85%", segs[0].Text)
+	assert.Equal(t, models.LevelHigh, segs[0].Level)
+	assert.Equal(t, palette.ProgressBars.CPUHigh, segs[0].Color)
+
+	assert.Equal(t, models.LevelMedium, segs[1].Level)
+	assert.Equal(t, palette.ProgressBars.MemoryMedium, segs[1].Color)
+	assert.Equal(t, "5.0 GB / 8.0 GB used", segs[1].Tooltip)
+
+	assert.Equal(t, "↓2.0K ↑100", segs[2].Text)
+	assert.Equal(t, -1.0, segs[2].Percentage)
+}
+
+func TestValidateModules(t *testing.T) {
+	mods, err := ValidateModules(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultModules, mods)
+
+	mods, err = ValidateModules([]string{"Memory", "cpu", "memory"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memory", "cpu"}, mods)
+
+	_, err = ValidateModules([]string{"gpu"})
+	assert.Error(t, err)
+}
+
+func TestI3barStream(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, ProtocolI3bar, "")
+	segs := []Segment{{Name: "cpu", Text: "CPU 85%", Color: "#ff0000", Level: models.LevelHigh}}
+	require.NoError(t, w.Write(segs))
+	require.NoError(t, w.Write(segs))
+
+	want := "{\"version\":1}\n[\n" +
+		`[{"name":"cpu","full_text":"CPU 85%","color":"#ff0000","urgent":true}]` + "\n" +
+		`,[{"name":"cpu","full_text":"CPU 85%","color":"#ff0000","urgent":true}]` + "\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWaybarFoldsSegments(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, ProtocolWaybar, "")
+	require.NoError(t, w.Write(Segments(sampleMeta(), DefaultModules, models.DefaultColorPalette())))
+
+	var out struct {
+		Text       string   `json:"text"`
+		Tooltip    string   `json:"tooltip"`
+		Class      []string `json:"class"`
+		Percentage int      `json:"percentage"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, "CPU 85%  MEM 62%  ↓2.0K ↑100", out.Text)
+	assert.Equal(t, []string{"high", "cpu-high", "memory-medium", "net-rate-low"}, out.Class)
+	assert.Equal(t, 85, out.Percentage)
+	assert.Contains(t, out.Tooltip, "eth0 ↓1.5 KB/s")
+}
+
+func TestTextProtocols(t *testing.T) {
+	segs := []Segment{
+		{Name: "cpu", Text: "CPU 5%", Color: "#00ff00"},
+		{Name: "system", Text: "LOAD 0.10"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf, ProtocolPolybar, " | ").Write(segs))
+	assert.Equal(t, "%{F#00ff00}CPU 5%%{F-} | LOAD 0.10\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, NewWriter(&buf, ProtocolTmux, " ").Write(segs))
+	assert.Equal(t, "#[fg=#00ff00]CPU 5%#[default] LOAD 0.10\n", buf.String())
+}
@@ -0,0 +1,170 @@
+package bar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+type Protocol string
+
+const (
+	// ProtocolI3bar is the i3bar JSON protocol, also spoken by swaybar: a
+	// header followed by an endless array of block arrays.
+	ProtocolI3bar Protocol = "i3bar"
+	// ProtocolWaybar is one JSON object per line, for a custom module with
+	// "return-type": "json".
+	ProtocolWaybar Protocol = "waybar"
+	// ProtocolPolybar is one line per update with %{F} color tags, for a
+	// script module with tail = true.
+	ProtocolPolybar Protocol = "polybar"
+	// ProtocolTmux is one line per update with #[fg] styles, for
+	// #(dgop bar --protocol tmux) in status-right.
+	ProtocolTmux Protocol = "tmux"
+)
+
+var protocols = []Protocol{ProtocolI3bar, ProtocolWaybar, ProtocolPolybar, ProtocolTmux}
+
+func ParseProtocol(s string) (Protocol, error) {
+	p := Protocol(strings.ToLower(s))
+	if !slices.Contains(protocols, p) {
+		return "", fmt.Errorf("unknown protocol %q (expected i3bar, waybar, polybar or tmux)", s)
+	}
+	return p, nil
+}
+
+// Writer streams bar updates in one protocol.
+type Writer struct {
+	out       io.Writer
+	protocol  Protocol
+	separator string
+	updates   int
+}
+
+// NewWriter returns a Writer that joins the segments of text protocols with
+// separator. i3bar and waybar ignore it.
+func NewWriter(out io.Writer, protocol Protocol, separator string) *Writer {
+	return &Writer{out: out, protocol: protocol, separator: separator}
+}
+
+type i3barBlock struct {
+	Name     string `json:"name"`
+	FullText string `json:"full_text"`
+	Color    string `json:"color,omitempty"`
+	Urgent   bool   `json:"urgent,omitempty"`
+}
+
+type waybarOutput struct {
+	Text       string   `json:"text"`
+	Tooltip    string   `json:"tooltip,omitempty"`
+	Class      []string `json:"class"`
+	Percentage *int     `json:"percentage,omitempty"`
+}
+
+// Write prints one update.
+func (w *Writer) Write(segments []Segment) error {
+	defer func() { w.updates++ }()
+
+	switch w.protocol {
+	case ProtocolI3bar:
+		if w.updates == 0 {
+			if _, err := io.WriteString(w.out, "{\"version\":1}\n[\n"); err != nil {
+				return err
+			}
+		}
+		blocks := make([]i3barBlock, len(segments))
+		for i, seg := range segments {
+			blocks[i] = i3barBlock{
+				Name:     seg.Name,
+				FullText: seg.Text,
+				Color:    seg.Color,
+				Urgent:   seg.Level == models.LevelHigh,
+			}
+		}
+		data, err := json.Marshal(blocks)
+		if err != nil {
+			return err
+		}
+		prefix := ""
+		if w.updates > 0 {
+			prefix = ","
+		}
+		_, err = fmt.Fprintf(w.out, "%s%s\n", prefix, data)
+		return err
+	case ProtocolWaybar:
+		data, err := json.Marshal(waybarUpdate(segments))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w.out, "%s\n", data)
+		return err
+	case ProtocolPolybar, ProtocolTmux:
+		parts := make([]string, len(segments))
+		for i, seg := range segments {
+			parts[i] = w.colorize(seg)
+		}
+		_, err := fmt.Fprintln(w.out, strings.Join(parts, w.separator))
+		return err
+	}
+	return fmt.Errorf("unsupported protocol %q", w.protocol)
+}
+
+func (w *Writer) colorize(seg Segment) string {
+	if seg.Color == "" {
+		return seg.Text
+	}
+	if w.protocol == ProtocolTmux {
+		return fmt.Sprintf("#[fg=%s]%s#[default]", seg.Color, seg.Text)
+	}
+	return fmt.Sprintf("%%{F%s}%s%%{F-}", seg.Color, seg.Text)
+}
+
+// waybarUpdate folds all segments into the single object a custom module
+// reads. The classes are the worst level overall followed by each module's
+// own, e.g. ["high", "cpu-high", "memory-low"], so CSS can style either.
+func waybarUpdate(segments []Segment) waybarOutput {
+	texts := make([]string, 0, len(segments))
+	tooltips := make([]string, 0, len(segments))
+	classes := []string{string(models.LevelLow)}
+	worst := 0
+	percentage := -1.0
+	for _, seg := range segments {
+		texts = append(texts, seg.Text)
+		if seg.Tooltip != "" {
+			tooltips = append(tooltips, seg.Tooltip)
+		}
+		classes = append(classes, seg.Name+"-"+string(seg.Level))
+		if rank := levelRank(seg.Level); rank > worst {
+			worst = rank
+			classes[0] = string(seg.Level)
+		}
+		percentage = math.Max(percentage, seg.Percentage)
+	}
+
+	out := waybarOutput{
+		Text:    strings.Join(texts, "  "),
+		Tooltip: strings.Join(tooltips, "\n\n"),
+		Class:   classes,
+	}
+	if percentage >= 0 {
+		p := int(math.Round(percentage))
+		out.Percentage = &p
+	}
+	return out
+}
+
+func levelRank(level models.Level) int {
+	switch level {
+	case models.LevelHigh:
+		return 2
+	case models.LevelMedium:
+		return 1
+	default:
+		return 0
+	}
+}
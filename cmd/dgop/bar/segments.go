@@ -0,0 +1,200 @@
+// Package bar renders system readings for status bars such as i3bar, waybar,
+// polybar and the tmux status line.
+package bar
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// Modules lists the meta modules a bar can show, in their default order.
+var Modules = []string{"cpu", "memory", "net-rate", "disk-rate", "diskmounts", "system"}
+
+// DefaultModules is what a bar shows when no modules are given.
+var DefaultModules = []string{"cpu", "memory", "net-rate"}
+
+// ValidateModules checks modules against Modules. "all" expands to every
+// module.
+func ValidateModules(modules []string) ([]string, error) {
+	var out []string
+	for _, m := range modules {
+		m = strings.ToLower(strings.TrimSpace(m))
+		switch {
+		case m == "all":
+			return Modules, nil
+		case !slices.Contains(Modules, m):
+			return nil, fmt.Errorf("unknown bar module %q (available: %s)", m, strings.Join(Modules, ", "))
+		case !slices.Contains(out, m):
+			out = append(out, m)
+		}
+	}
+	if len(out) == 0 {
+		return DefaultModules, nil
+	}
+	return out, nil
+}
+
+// Segment is one module's entry on the bar.
+type Segment struct {
+	Name    string
+	Text    string
+	Tooltip string
+	// Percentage is the reading the level was derived from, or -1 for
+	// modules without one, such as rates.
+	Percentage float64
+	Level      models.Level
+	Color      string
+}
+
+// Segments builds a segment per module from meta, in the order of modules.
+// Modules missing from meta, because collecting them failed, are left out.
+func Segments(meta *models.MetaInfo, modules []string, palette *models.ColorPalette) []Segment {
+	var out []Segment
+	for _, module := range modules {
+		if seg, ok := segment(meta, module, palette); ok {
+			out = append(out, seg)
+		}
+	}
+	return out
+}
+
+func segment(meta *models.MetaInfo, module string, palette *models.ColorPalette) (Segment, bool) {
+	switch module {
+	case "cpu":
+		if meta.CPU == nil {
+			return Segment{}, false
+		}
+		return usageSegment("cpu", "cpu", fmt.Sprintf("CPU %.0f%%", meta.CPU.Usage), cpuTooltip(meta.CPU), meta.CPU.Usage, palette), true
+	case "memory":
+		if meta.Memory == nil {
+			return Segment{}, false
+		}
+		// Memory is reported in KiB.
+		m := meta.Memory
+		tooltip := fmt.Sprintf("%s / %s used", humanBytes(float64(m.Used)*1024), humanBytes(float64(m.Total)*1024))
+		if m.SwapTotal > 0 {
+			tooltip += fmt.Sprintf("\nSwap %s / %s", humanBytes(float64(m.SwapTotal-m.SwapFree)*1024), humanBytes(float64(m.SwapTotal)*1024))
+		}
+		return usageSegment("memory", "memory", fmt.Sprintf("MEM %.0f%%", m.UsedPercent), tooltip, m.UsedPercent, palette), true
+	case "net-rate":
+		if meta.NetRate == nil {
+			return Segment{}, false
+		}
+		var rx, tx float64
+		var lines []string
+		for _, iface := range meta.NetRate.Interfaces {
+			rx += iface.RxRate
+			tx += iface.TxRate
+			lines = append(lines, fmt.Sprintf("%s ↓%s/s ↑%s/s", iface.Interface, humanBytes(iface.RxRate), humanBytes(iface.TxRate)))
+		}
+		return Segment{
+			Name:       "net-rate",
+			Text:       fmt.Sprintf("↓%s ↑%s", compactBytes(rx), compactBytes(tx)),
+			Tooltip:    strings.Join(lines, "\n"),
+			Percentage: -1,
+			Level:      models.LevelLow,
+			Color:      palette.Charts.NetworkDownload,
+		}, true
+	case "disk-rate":
+		if meta.DiskRate == nil {
+			return Segment{}, false
+		}
+		var read, write float64
+		var lines []string
+		for _, disk := range meta.DiskRate.Disks {
+			read += disk.ReadRate
+			write += disk.WriteRate
+			lines = append(lines, fmt.Sprintf("%s R %s/s W %s/s", disk.Device, humanBytes(disk.ReadRate), humanBytes(disk.WriteRate)))
+		}
+		return Segment{
+			Name:       "disk-rate",
+			Text:       fmt.Sprintf("R %s W %s", compactBytes(read), compactBytes(write)),
+			Tooltip:    strings.Join(lines, "\n"),
+			Percentage: -1,
+			Level:      models.LevelLow,
+			Color:      palette.Charts.DiskRead,
+		}, true
+	case "diskmounts":
+		// The bar shows the root filesystem, the tooltip every mount.
+		var root *models.DiskMountInfo
+		var lines []string
+		for _, mount := range meta.DiskMounts {
+			if mount.Mount == "/" {
+				root = mount
+			}
+			lines = append(lines, fmt.Sprintf("%s %s / %s (%s)", mount.Mount, mount.Used, mount.Size, mount.Percent))
+		}
+		if root == nil {
+			return Segment{}, false
+		}
+		percent, _ := strconv.ParseFloat(strings.TrimSuffix(root.Percent, "%"), 64)
+		return usageSegment("diskmounts", "disk", fmt.Sprintf("/ %.0f%%", percent), strings.Join(lines, "\n"), percent, palette), true
+	case "system":
+		if meta.System == nil {
+			return Segment{}, false
+		}
+		s := meta.System
+		return Segment{
+			Name:       "system",
+			Text:       "LOAD " + s.LoadAvg,
+			Tooltip:    fmt.Sprintf("%d processes, %d threads", s.Processes, s.Threads),
+			Percentage: -1,
+			Level:      models.LevelLow,
+			Color:      palette.UI.TextPrimary,
+		}, true
+	}
+	return Segment{}, false
+}
+
+// usageSegment levels a percentage with the same thresholds and palette
+// colors as the TUI's progress bars for kind.
+func usageSegment(name, kind, text, tooltip string, percent float64, palette *models.ColorPalette) Segment {
+	level := models.UsageLevel(kind, percent)
+	return Segment{
+		Name:       name,
+		Text:       text,
+		Tooltip:    tooltip,
+		Percentage: percent,
+		Level:      level,
+		Color:      palette.UsageColor(kind, level),
+	}
+}
+
+func cpuTooltip(cpu *models.CPUInfo) string {
+	tooltip := fmt.Sprintf("%s\n%d cores @ %.0f MHz", cpu.Model, cpu.Count, cpu.Frequency)
+	if cpu.Temperature > 0 {
+		tooltip += fmt.Sprintf("\n%.0f°C", cpu.Temperature)
+	}
+	return tooltip
+}
+
+func humanBytes(bytes float64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%.0f B", bytes)
+	}
+	div, exp := float64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 5; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", bytes/div, "KMGTPE"[exp])
+}
+
+// compactBytes is humanBytes without the space and "B", to keep bars short.
+func compactBytes(bytes float64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%.0f", bytes)
+	}
+	div, exp := float64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 5; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", bytes/div, "KMGTPE"[exp])
+}
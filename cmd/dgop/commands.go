@@ -2,10 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/AvengeMedia/dgop/api/client"
+	"github.com/AvengeMedia/dgop/cmd/dgop/tui"
+	"github.com/AvengeMedia/dgop/fleet"
 	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/spf13/cobra"
@@ -68,6 +77,31 @@ var processesCmd = &cobra.Command{
 	Long:  "Display information about running processes with sorting and filtering options.",
 }
 
+var unitsCmd = &cobra.Command{
+	Use:   "units",
+	Short: "Get systemd unit resource usage",
+	Long:  "Display the CPU, memory, I/O and task usage of running systemd services and scopes, read from their cgroups.",
+}
+
+var procEventsCmd = &cobra.Command{
+	Use:   "proc-events",
+	Short: "Get processes that started and exited",
+	Long:  "Display the processes that started and exited since the cursor, with the runtime, CPU and memory of those that exited. Without a cursor, watches for --interval first. The kernel's proc connector catches even the shortest-lived processes when dgop may use it; otherwise running processes are compared between calls.",
+}
+
+var anomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Find zombies, stuck processes, fork bombs and leaks",
+	Long:  "Flag parents leaving zombies unreaped, processes stuck in uninterruptible sleep (often a hung NFS mount), fork bombs and processes whose memory keeps growing. All but zombies are judged across calls, so pass the cursor back; without one, samples twice --interval apart.",
+}
+
+var procCmd = &cobra.Command{
+	Use:   "proc <pid>",
+	Short: "Get detailed information about one process",
+	Long:  "Display everything dgop can read about a process: state, threads with their CPU usage, open files, limits, namespaces, cgroup, OOM score, context switches, I/O counters and a memory breakdown.",
+	Args:  cobra.ExactArgs(1),
+}
+
 var systemCmd = &cobra.Command{
 	Use:   "system",
 	Short: "Get general system information",
@@ -107,34 +141,40 @@ var modulesCmd = &cobra.Command{
 var topCmd = &cobra.Command{
 	Use:   "top",
 	Short: "Launch interactive system monitor",
-	Long:  "Launch an interactive system monitor for real-time system monitoring.",
+	Long:  "Launch an interactive system monitor for real-time system monitoring. Use --remote to attach to a dgop API server on another machine.",
 }
 
 func runAllCommand(gopsUtil *gops.GopsUtil) error {
 	enableCPU := !disableProcCPU
 	sortBy := parseProcessSortBy(procSortBy, disableProcCPU)
 
-	metrics, err := gopsUtil.GetAllMetricsWithCursors(sortBy, procLimit, enableCPU, cpuCursor, procCursor, mergeChildren)
+	metrics, err := gopsUtil.GetAllMetricsWithCursors(context.Background(), sortBy, procLimit, enableCPU, cpuCursor, procCursor, mergeChildren)
 	if err != nil {
 		return fmt.Errorf("failed to get system metrics: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(metrics)
+	if structuredOutput() {
+		if err := writeOutput(metrics, nil); err != nil {
+			return err
+		}
+	} else {
+		displayAllMetrics(metrics)
 	}
 
-	displayAllMetrics(metrics)
+	if strict {
+		return gops.CheckComplete(metrics.Errors)
+	}
 	return nil
 }
 
 func runCpuCommand(gopsUtil *gops.GopsUtil) error {
-	cpuInfo, err := gopsUtil.GetCPUInfoWithCursor(cpuCursor)
+	cpuInfo, err := gopsUtil.GetCPUInfoWithCursor(context.Background(), cpuCursor)
 	if err != nil {
 		return fmt.Errorf("failed to get CPU info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(cpuInfo)
+	if structuredOutput() {
+		return writeOutput(cpuInfo, nil)
 	}
 
 	displayCPUInfo(cpuInfo)
@@ -142,13 +182,13 @@ func runCpuCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runMemoryCommand(gopsUtil *gops.GopsUtil) error {
-	memInfo, err := gopsUtil.GetMemoryInfo()
+	memInfo, err := gopsUtil.GetMemoryInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get memory info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(memInfo)
+	if structuredOutput() {
+		return writeOutput(memInfo, nil)
 	}
 
 	displayMemoryInfo(memInfo)
@@ -156,13 +196,13 @@ func runMemoryCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runNetworkCommand(gopsUtil *gops.GopsUtil) error {
-	networkInfo, err := gopsUtil.GetNetworkInfo()
+	networkInfo, err := gopsUtil.GetNetworkInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get network info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(networkInfo)
+	if structuredOutput() {
+		return writeOutput(networkInfo, networkInfo)
 	}
 
 	displayNetworkInfo(networkInfo)
@@ -170,17 +210,17 @@ func runNetworkCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runDiskCommand(gopsUtil *gops.GopsUtil) error {
-	diskInfo, err := gopsUtil.GetDiskInfo()
+	diskInfo, err := gopsUtil.GetDiskInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get disk info: %w", err)
 	}
 
-	diskMounts, err := gopsUtil.GetDiskMounts()
+	diskMounts, err := gopsUtil.GetDiskMounts(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get disk mounts: %w", err)
 	}
 
-	if jsonOutput {
+	if structuredOutput() {
 		data := struct {
 			Disk   []*models.DiskInfo      `json:"disk"`
 			Mounts []*models.DiskMountInfo `json:"mounts"`
@@ -188,7 +228,7 @@ func runDiskCommand(gopsUtil *gops.GopsUtil) error {
 			Disk:   diskInfo,
 			Mounts: diskMounts,
 		}
-		return outputJSON(data)
+		return writeOutput(data, diskMounts)
 	}
 
 	displayDiskInfo(diskInfo, diskMounts)
@@ -196,30 +236,163 @@ func runDiskCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runProcessesCommand(gopsUtil *gops.GopsUtil) error {
+	if showThreads {
+		return runThreadsCommand(gopsUtil)
+	}
+
 	enableCPU := !disableProcCPU
 	sortBy := parseProcessSortBy(procSortBy, disableProcCPU)
+	group, err := gops.ParseProcGroupBy(groupBy)
+	if err != nil {
+		return err
+	}
+	filter, err := gops.ParseProcFilter(procFilter)
+	if err != nil {
+		return err
+	}
 
-	result, err := gopsUtil.GetProcessesWithCursor(sortBy, procLimit, enableCPU, procCursor, mergeChildren)
+	result, err := gopsUtil.GetProcessesWithCursor(context.Background(), gops.ProcessParams{
+		SortBy:         sortBy,
+		Limit:          procLimit,
+		EnableCPU:      enableCPU,
+		Cursor:         procCursor,
+		MergeChildren:  mergeChildren,
+		GroupBy:        group,
+		AccurateMemory: accurateMemory,
+		Filter:         filter,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(result)
+	if structuredOutput() {
+		return writeOutput(result, result.Processes)
 	}
 
 	displayProcesses(result.Processes)
 	return nil
 }
 
+func runThreadsCommand(gopsUtil *gops.GopsUtil) error {
+	if threadsPID < 0 {
+		return fmt.Errorf("invalid pid %d", threadsPID)
+	}
+
+	result, err := gopsUtil.GetThreads(context.Background(), threadsPID, procLimit, procCursor)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no process with pid %d", threadsPID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get threads: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(result, result.Threads)
+	}
+
+	displayThreads(result)
+	return nil
+}
+
+func runUnitsCommand(gopsUtil *gops.GopsUtil) error {
+	sortBy, err := gops.ParseUnitSortBy(unitSortBy)
+	if err != nil {
+		return err
+	}
+
+	result, err := gopsUtil.GetUnits(context.Background(), sortBy, procLimit, unitsCursor)
+	if err != nil {
+		return fmt.Errorf("failed to get units: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(result, result.Units)
+	}
+
+	displayUnits(result)
+	return nil
+}
+
+func runProcEventsCommand(gopsUtil *gops.GopsUtil) error {
+	ctx := context.Background()
+	cursor := procEventsCursor
+	if cursor == "" {
+		baseline, err := gopsUtil.GetProcessEvents(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed to get process events: %w", err)
+		}
+		cursor = baseline.Cursor
+		time.Sleep(procEventsInterval)
+	}
+
+	result, err := gopsUtil.GetProcessEvents(ctx, cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get process events: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(result, result.Events)
+	}
+
+	displayProcessEvents(result)
+	return nil
+}
+
+func runAnomaliesCommand(gopsUtil *gops.GopsUtil) error {
+	ctx := context.Background()
+	cursor := anomaliesCursor
+	if cursor == "" {
+		baseline, err := gopsUtil.GetAnomalies(ctx, "", anomalyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to get anomalies: %w", err)
+		}
+		cursor = baseline.Cursor
+		time.Sleep(anomaliesInterval)
+	}
+
+	result, err := gopsUtil.GetAnomalies(ctx, cursor, anomalyOpts)
+	if err != nil {
+		return fmt.Errorf("failed to get anomalies: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(result, result.Anomalies)
+	}
+
+	displayAnomalies(result)
+	return nil
+}
+
+func runProcCommand(gopsUtil *gops.GopsUtil, arg string) error {
+	pid, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil || pid <= 0 {
+		return fmt.Errorf("invalid pid %q", arg)
+	}
+
+	details, err := gopsUtil.GetProcessDetails(context.Background(), int32(pid), procEnv)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no process with pid %d", pid)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get process details: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(details, details.Threads)
+	}
+
+	displayProcessDetails(details)
+	return nil
+}
+
 func runSystemCommand(gopsUtil *gops.GopsUtil) error {
-	systemInfo, err := gopsUtil.GetSystemInfo()
+	systemInfo, err := gopsUtil.GetSystemInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get system info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(systemInfo)
+	if structuredOutput() {
+		return writeOutput(systemInfo, nil)
 	}
 
 	displaySystemInfo(systemInfo)
@@ -227,13 +400,13 @@ func runSystemCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runHardwareCommand(gopsUtil *gops.GopsUtil) error {
-	hardwareInfo, err := gopsUtil.GetSystemHardware()
+	hardwareInfo, err := gopsUtil.GetSystemHardware(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get hardware info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(hardwareInfo)
+	if structuredOutput() {
+		return writeOutput(hardwareInfo, nil)
 	}
 
 	displayHardwareInfo(hardwareInfo)
@@ -241,13 +414,13 @@ func runHardwareCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runGPUCommand(gopsUtil *gops.GopsUtil) error {
-	gpuInfo, err := gopsUtil.GetGPUInfo()
+	gpuInfo, err := gopsUtil.GetGPUInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get GPU info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(gpuInfo)
+	if structuredOutput() {
+		return writeOutput(gpuInfo, gpuInfo.GPUs)
 	}
 
 	displayGPUInfo(gpuInfo)
@@ -255,13 +428,13 @@ func runGPUCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runGPUTempCommand(gopsUtil *gops.GopsUtil) error {
-	gpuTempInfo, err := gopsUtil.GetGPUTemp(gpuPciId)
+	gpuTempInfo, err := gopsUtil.GetGPUTemp(context.Background(), gpuPciId)
 	if err != nil {
 		return fmt.Errorf("failed to get GPU temperature: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(gpuTempInfo)
+	if structuredOutput() {
+		return writeOutput(gpuTempInfo, nil)
 	}
 
 	displayGPUTempInfo(gpuTempInfo)
@@ -269,16 +442,31 @@ func runGPUTempCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runMetaCommand(gopsUtil *gops.GopsUtil) error {
+	group, err := gops.ParseProcGroupBy(groupBy)
+	if err != nil {
+		return err
+	}
+	filter, err := gops.ParseProcFilter(procFilter)
+	if err != nil {
+		return err
+	}
 	params := gops.MetaParams{
-		SortBy:         parseProcessSortBy(procSortBy, disableProcCPU),
-		ProcLimit:      procLimit,
-		EnableCPU:      !disableProcCPU,
-		MergeChildren:  mergeChildren,
-		GPUPciIds:      metaGPUPciIds,
-		CPUCursor:      cpuCursor,
-		ProcCursor:     procCursor,
-		NetRateCursor:  netRateCursor,
-		DiskRateCursor: diskRateCursor,
+		SortBy:           parseProcessSortBy(procSortBy, disableProcCPU),
+		ProcLimit:        procLimit,
+		EnableCPU:        !disableProcCPU,
+		MergeChildren:    mergeChildren,
+		GroupBy:          group,
+		AccurateMemory:   accurateMemory,
+		ProcFilter:       filter,
+		GPUPciIds:        metaGPUPciIds,
+		CPUCursor:        cpuCursor,
+		ProcCursor:       procCursor,
+		NetRateCursor:    netRateCursor,
+		DiskRateCursor:   diskRateCursor,
+		ProcEventsCursor: procEventsCursor,
+		AnomaliesCursor:  anomaliesCursor,
+		UnitsCursor:      unitsCursor,
+		MetaCursor:       metaCursor,
 	}
 
 	metaInfo, err := gopsUtil.GetMeta(context.Background(), metaModules, params)
@@ -286,11 +474,17 @@ func runMetaCommand(gopsUtil *gops.GopsUtil) error {
 		return fmt.Errorf("failed to get meta info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(metaInfo)
+	if structuredOutput() {
+		if err := writeOutput(metaInfo, nil); err != nil {
+			return err
+		}
+	} else {
+		displayMetaInfo(metaInfo)
 	}
 
-	displayMetaInfo(metaInfo)
+	if strict {
+		return gops.CheckComplete(metaInfo.Errors)
+	}
 	return nil
 }
 
@@ -300,8 +494,12 @@ func runModulesCommand(gopsUtil *gops.GopsUtil) error {
 		return fmt.Errorf("failed to get modules info: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(modulesInfo)
+	if structuredOutput() {
+		rows := make([]map[string]string, len(modulesInfo.Available))
+		for i, name := range modulesInfo.Available {
+			rows[i] = map[string]string{"name": name}
+		}
+		return writeOutput(modulesInfo, rows)
 	}
 
 	displayModulesInfo(modulesInfo)
@@ -314,6 +512,11 @@ func displayAllMetrics(metrics *models.SystemMetrics) {
 	fmt.Println(titleStyle.Render("SYSTEM METRICS"))
 	fmt.Println()
 
+	if len(metrics.Errors) > 0 {
+		displayModuleErrors(metrics.Errors)
+		fmt.Println()
+	}
+
 	if metrics.System != nil {
 		displaySystemInfo(metrics.System)
 		fmt.Println()
@@ -462,6 +665,11 @@ func displayDiskInfo(disks []*models.DiskInfo, mounts []*models.DiskMountInfo) {
 func displayProcesses(processes []*models.ProcessInfo) {
 	fmt.Println(titleStyle.Render(fmt.Sprintf("PROCESSES (%d)", len(processes))))
 
+	if accurateMemory || procSortBy == string(gops.SortByPSS) {
+		displayProcessesMemory(processes)
+		return
+	}
+
 	// Header
 	header := fmt.Sprintf("%-8s %-8s %-20s %-8s %-8s %s",
 		"PID", "PPID", "COMMAND", "CPU%", "MEM%", "FULL COMMAND")
@@ -472,7 +680,7 @@ func displayProcesses(processes []*models.ProcessInfo) {
 		row := fmt.Sprintf("%-8d %-8d %-20s %-8.1f %-8.1f %s",
 			proc.PID,
 			proc.PPID,
-			truncateString(proc.Command, 20),
+			truncateString(processDisplayName(proc), 20),
 			proc.CPU,
 			proc.MemoryPercent,
 			truncateString(proc.FullCommand, 30))
@@ -480,6 +688,219 @@ func displayProcesses(processes []*models.ProcessInfo) {
 	}
 }
 
+// displayProcessesMemory lists the smaps_rollup breakdown read in accurate
+// memory mode.
+func displayProcessesMemory(processes []*models.ProcessInfo) {
+	header := fmt.Sprintf("%-8s %-20s %-7s %-11s %-11s %-11s %-11s %-11s %s",
+		"PID", "COMMAND", "CPU%", "RSS", "PSS", "USS", "SWAP", "ANON", "SHMEM")
+	fmt.Println(keyStyle.Render(header))
+	fmt.Println(strings.Repeat("─", 110))
+
+	for _, proc := range processes {
+		row := fmt.Sprintf("%-8d %-20s %-7.1f %-11s %-11s %-11s %-11s %-11s %s",
+			proc.PID,
+			truncateString(processDisplayName(proc), 20),
+			proc.CPU,
+			formatBytes(proc.RSSKB*1024),
+			formatBytes(proc.PSSKB*1024),
+			formatBytes(proc.USSKB*1024),
+			formatBytes(proc.SwapKB*1024),
+			formatBytes(proc.PSSAnonKB*1024),
+			formatBytes(proc.PSSShmemKB*1024))
+		fmt.Println(valueStyle.Render(row))
+	}
+}
+
+func processDisplayName(proc *models.ProcessInfo) string {
+	if groupBy == string(gops.GroupByApp) && proc.AppName != "" {
+		return proc.AppName
+	}
+	return proc.Command
+}
+
+func displayThreads(threads *models.ThreadListResponse) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("THREADS (%d)", len(threads.Threads))))
+
+	header := fmt.Sprintf("%-8s %-8s %-6s %-7s %-5s %s", "PID", "TID", "STATE", "CPU%", "CPU#", "NAME")
+	fmt.Println(keyStyle.Render(header))
+	fmt.Println(strings.Repeat("─", 60))
+
+	for _, t := range threads.Threads {
+		row := fmt.Sprintf("%-8d %-8d %-6s %-7.1f %-5d %s", t.PID, t.TID, t.State, t.CPU, t.LastCPU, t.Name)
+		fmt.Println(valueStyle.Render(row))
+	}
+
+	fmt.Printf("\nCursor: %s\n", threads.Cursor)
+}
+
+func displayUnits(units *models.UnitsResponse) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("UNITS (%d)", len(units.Units))))
+
+	header := fmt.Sprintf("%-32s %-10s %-7s %-11s %-11s %-11s %-6s %s",
+		"UNIT", "STATE", "CPU%", "MEMORY", "READ", "WRITE", "TASKS", "RESTARTS")
+	fmt.Println(keyStyle.Render(header))
+	fmt.Println(strings.Repeat("─", 100))
+
+	for _, u := range units.Units {
+		row := fmt.Sprintf("%-32s %-10s %-7.1f %-11s %-11s %-11s %-6d %d",
+			truncateString(u.Name, 32),
+			truncateString(u.ActiveState, 10),
+			u.CPU,
+			formatBytes(u.MemoryBytes),
+			formatRate(u.IOReadRate),
+			formatRate(u.IOWriteRate),
+			u.Tasks,
+			u.Restarts)
+		fmt.Println(valueStyle.Render(row))
+	}
+
+	fmt.Printf("\nCursor: %s\n", units.Cursor)
+}
+
+func displayProcessEvents(events *models.ProcessEventsResponse) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("PROCESS EVENTS (%d, from %s)", len(events.Events), events.Source)))
+
+	header := fmt.Sprintf("%-8s %-8s %-8s %-8s %-20s %-9s %-7s %-11s %s",
+		"TIME", "EVENT", "PID", "PPID", "COMMAND", "RUNTIME", "CPU%", "MEMORY", "EXIT")
+	fmt.Println(keyStyle.Render(header))
+	fmt.Println(strings.Repeat("─", 100))
+
+	for _, e := range events.Events {
+		var runtime, cpu, memory, exit string
+		if e.Type == models.ProcessExited {
+			runtime = fmt.Sprintf("%.1fs", e.Runtime)
+			cpu = fmt.Sprintf("%.1f", e.CPU)
+			switch {
+			case e.Signal != 0:
+				exit = fmt.Sprintf("signal %d", e.Signal)
+			case e.ExitCode != nil:
+				exit = fmt.Sprintf("code %d", *e.ExitCode)
+			}
+		}
+		if e.MemoryKB > 0 {
+			memory = formatBytes(e.MemoryKB * 1024)
+		}
+		row := fmt.Sprintf("%-8s %-8s %-8d %-8d %-20s %-9s %-7s %-11s %s",
+			time.UnixMilli(e.Time).Format("15:04:05"),
+			e.Type,
+			e.PID,
+			e.PPID,
+			truncateString(e.Command, 20),
+			runtime,
+			cpu,
+			memory,
+			exit)
+		fmt.Println(valueStyle.Render(row))
+	}
+
+	fmt.Printf("\nCursor: %s\n", events.Cursor)
+}
+
+func displayAnomalies(anomalies *models.AnomaliesResponse) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("ANOMALIES (%d)", len(anomalies.Anomalies))))
+
+	header := fmt.Sprintf("%-16s %-8s %s", "KIND", "PID", "DETAILS")
+	fmt.Println(keyStyle.Render(header))
+	fmt.Println(strings.Repeat("─", 100))
+
+	for _, a := range anomalies.Anomalies {
+		row := fmt.Sprintf("%-16s %-8d %s", a.Kind, a.PID, a.Summary)
+		fmt.Println(valueStyle.Render(row))
+	}
+	if len(anomalies.Anomalies) == 0 {
+		fmt.Println(valueStyle.Render("Nothing unusual"))
+	}
+
+	fmt.Printf("\nCursor: %s\n", anomalies.Cursor)
+}
+
+func displayProcessDetails(d *models.ProcessDetails) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("PROCESS %d", d.PID)))
+	rows := [][]string{
+		{"Command:", d.Command},
+		{"Full Command:", d.FullCommand},
+		{"Executable:", d.ExecutablePath},
+		{"User:", d.Username},
+		{"PPID:", fmt.Sprintf("%d", d.PPID)},
+		{"State:", d.State},
+		{"Nice / Priority:", fmt.Sprintf("%d / %d", d.Nice, d.Priority)},
+		{"Started:", fmt.Sprintf("%s (%.0fs ago)", d.StartTime, d.AgeSeconds)},
+		{"CPU:", fmt.Sprintf("%.1f%% (%.2fs total)", d.CPU, d.PTicks)},
+		{"Memory:", formatBytes(d.MemoryKB * 1024)},
+		{"Cwd:", d.Cwd},
+		{"Cgroup:", d.CGroup},
+		{"OOM Score:", fmt.Sprintf("%d (adj %d)", d.OOMScore, d.OOMScoreAdj)},
+		{"Context Switches:", fmt.Sprintf("%d voluntary, %d involuntary", d.VoluntaryCtxSwitches, d.InvoluntaryCtxSwitches)},
+		{"Open Files:", fmt.Sprintf("%d", d.NumFDs)},
+	}
+	printTable(rows)
+
+	if d.Memory != nil {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("MEMORY (smaps_rollup)"))
+		r := d.Memory
+		printTable([][]string{
+			{"RSS / PSS:", fmt.Sprintf("%s / %s", formatBytes(r.RSS*1024), formatBytes(r.PSS*1024))},
+			{"PSS Anon / File / Shmem:", fmt.Sprintf("%s / %s / %s", formatBytes(r.PSSAnon*1024), formatBytes(r.PSSFile*1024), formatBytes(r.PSSShmem*1024))},
+			{"Private Clean / Dirty:", fmt.Sprintf("%s / %s", formatBytes(r.PrivateClean*1024), formatBytes(r.PrivateDirty*1024))},
+			{"Shared Clean / Dirty:", fmt.Sprintf("%s / %s", formatBytes(r.SharedClean*1024), formatBytes(r.SharedDirty*1024))},
+			{"Anonymous:", formatBytes(r.Anonymous * 1024)},
+			{"Swap / SwapPSS:", fmt.Sprintf("%s / %s", formatBytes(r.Swap*1024), formatBytes(r.SwapPSS*1024))},
+			{"Locked:", formatBytes(r.Locked * 1024)},
+		})
+	}
+
+	if d.IO != nil {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("I/O"))
+		printTable([][]string{
+			{"Read / Write:", fmt.Sprintf("%s / %s", formatBytes(d.IO.ReadBytes), formatBytes(d.IO.WriteBytes))},
+			{"Chars Read / Written:", fmt.Sprintf("%s / %s", formatBytes(d.IO.ReadChars), formatBytes(d.IO.WriteChars))},
+			{"Syscalls Read / Write:", fmt.Sprintf("%d / %d", d.IO.ReadSyscalls, d.IO.WriteSyscalls)},
+		})
+	}
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render(fmt.Sprintf("THREADS (%d)", len(d.Threads))))
+	fmt.Println(keyStyle.Render(fmt.Sprintf("%-8s %-6s %-7s %-5s %s", "TID", "STATE", "CPU%", "CPU#", "NAME")))
+	for _, t := range d.Threads {
+		fmt.Println(valueStyle.Render(fmt.Sprintf("%-8d %-6s %-7.1f %-5d %s", t.TID, t.State, t.CPU, t.LastCPU, t.Name)))
+	}
+
+	if len(d.FDs) > 0 {
+		fmt.Println()
+		fmt.Println(titleStyle.Render(fmt.Sprintf("OPEN FILES (%d)", d.NumFDs)))
+		for _, fd := range d.FDs {
+			fmt.Printf("  %s %s\n", keyStyle.Render(fmt.Sprintf("%-5d", fd.FD)), valueStyle.Render(fd.Target))
+		}
+	}
+
+	if len(d.Limits) > 0 {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("LIMITS"))
+		fmt.Println(keyStyle.Render(fmt.Sprintf("%-26s %-20s %-20s %s", "LIMIT", "SOFT", "HARD", "UNITS")))
+		for _, l := range d.Limits {
+			fmt.Println(valueStyle.Render(fmt.Sprintf("%-26s %-20s %-20s %s", l.Name, l.Soft, l.Hard, l.Unit)))
+		}
+	}
+
+	if len(d.Namespaces) > 0 {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("NAMESPACES"))
+		for _, name := range slices.Sorted(maps.Keys(d.Namespaces)) {
+			fmt.Printf("  %s %s\n", keyStyle.Render(fmt.Sprintf("%-18s", name)), valueStyle.Render(d.Namespaces[name]))
+		}
+	}
+
+	if len(d.Environ) > 0 {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("ENVIRONMENT"))
+		for _, kv := range d.Environ {
+			fmt.Println(valueStyle.Render("  " + kv))
+		}
+	}
+}
+
 // Helper functions
 
 func printTable(rows [][]string) {
@@ -560,6 +981,11 @@ func displayMetaInfo(meta *models.MetaInfo) {
 	fmt.Println(titleStyle.Render("META METRICS"))
 	fmt.Println()
 
+	if len(meta.Errors) > 0 {
+		displayModuleErrors(meta.Errors)
+		fmt.Println()
+	}
+
 	if meta.CPU != nil {
 		displayCPUInfo(meta.CPU)
 		fmt.Println()
@@ -605,11 +1031,37 @@ func displayMetaInfo(meta *models.MetaInfo) {
 		fmt.Println()
 	}
 
+	if meta.Units != nil {
+		displayUnits(meta.Units)
+		fmt.Println()
+	}
+
+	if meta.ProcEvents != nil {
+		displayProcessEvents(meta.ProcEvents)
+		fmt.Println()
+	}
+
+	if meta.Anomalies != nil {
+		displayAnomalies(meta.Anomalies)
+		fmt.Println()
+	}
+
 	if len(meta.Processes) > 0 {
 		displayProcesses(meta.Processes)
 	}
 }
 
+func displayModuleErrors(errs map[string]models.ModuleError) {
+	fmt.Println(titleStyle.Render("ERRORS"))
+
+	var rows [][]string
+	for _, module := range slices.Sorted(maps.Keys(errs)) {
+		e := errs[module]
+		rows = append(rows, []string{module + ":", fmt.Sprintf("%s (%s)", e.Code, e.Message)})
+	}
+	printTable(rows)
+}
+
 func displayModulesInfo(modules *models.ModulesInfo) {
 	fmt.Println(titleStyle.Render("AVAILABLE MODULES"))
 
@@ -710,13 +1162,13 @@ func formatBytesFloat(bytes float64) string {
 }
 
 func runNetRateCommand(gopsUtil *gops.GopsUtil) error {
-	netRateInfo, err := gopsUtil.GetNetworkRates(netRateCursor)
+	netRateInfo, err := gopsUtil.GetNetworkRates(context.Background(), netRateCursor)
 	if err != nil {
 		return fmt.Errorf("failed to get network rates: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(netRateInfo)
+	if structuredOutput() {
+		return writeOutput(netRateInfo, netRateInfo.Interfaces)
 	}
 
 	displayNetworkRates(netRateInfo)
@@ -724,19 +1176,90 @@ func runNetRateCommand(gopsUtil *gops.GopsUtil) error {
 }
 
 func runDiskRateCommand(gopsUtil *gops.GopsUtil) error {
-	diskRateInfo, err := gopsUtil.GetDiskRates(diskRateCursor)
+	diskRateInfo, err := gopsUtil.GetDiskRates(context.Background(), diskRateCursor)
 	if err != nil {
 		return fmt.Errorf("failed to get disk rates: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(diskRateInfo)
+	if structuredOutput() {
+		return writeOutput(diskRateInfo, diskRateInfo.Disks)
 	}
 
 	displayDiskRates(diskRateInfo)
 	return nil
 }
 
-func runTopCommand(gopsUtil *gops.GopsUtil) error {
-	return runTUIWithOptions(gopsUtil, hideCPUCores, summarizeCores)
+func runTopCommand(cmd *cobra.Command, gopsUtil *gops.GopsUtil) error {
+	settings, err := newConfigManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer settings.Close()
+
+	if remoteURL == "" {
+		gopsUtil.EnableProcessTable()
+		source := tui.NewLocalSource(gopsUtil)
+		if len(peers) > 0 {
+			clientOpts, err := clientAuthOptions()
+			if err != nil {
+				return err
+			}
+			f, err := fleet.New(peers, fleet.WithLocal(gopsUtil, ""), fleet.WithClientOptions(clientOpts...))
+			if err != nil {
+				return err
+			}
+			go f.Run(cmd.Context())
+			source.SetFleet(f)
+		}
+		return runTUIWithSource(source, settings)
+	}
+
+	clientOpts, err := clientAuthOptions()
+	if err != nil {
+		return err
+	}
+	apiClient, err := client.New(remoteURL, clientOpts...)
+	if err != nil {
+		return err
+	}
+	if err := apiClient.Health(cmd.Context()); err != nil {
+		return fmt.Errorf("cannot reach %s: %w", apiClient.BaseURL(), err)
+	}
+	return runTUIWithSource(tui.NewRemoteSource(apiClient), settings)
+}
+
+func addClientAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&clientToken, "token", "", "Bearer token for remote dgop servers (env DGOP_TOKEN)")
+	cmd.Flags().StringVar(&clientCA, "ca", "", "CA for verifying remote dgop servers (env DGOP_CA)")
+	cmd.Flags().StringVar(&clientCert, "cert", "", "Client certificate for remote dgop servers (env DGOP_CERT)")
+	cmd.Flags().StringVar(&clientKey, "key", "", "Client key for remote dgop servers (env DGOP_KEY)")
+}
+
+// clientAuthOptions builds API client options from --token, --ca, --cert and
+// --key, falling back to their DGOP_* environment variables.
+func clientAuthOptions() ([]client.Option, error) {
+	token := flagOrEnv(clientToken, "DGOP_TOKEN")
+	ca := flagOrEnv(clientCA, "DGOP_CA")
+	cert := flagOrEnv(clientCert, "DGOP_CERT")
+	key := flagOrEnv(clientKey, "DGOP_KEY")
+
+	var opts []client.Option
+	if token != "" {
+		opts = append(opts, client.WithToken(token))
+	}
+	if ca != "" || cert != "" || key != "" {
+		tlsCfg, err := client.LoadTLSConfig(ca, cert, key)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithTLSConfig(tlsCfg))
+	}
+	return opts, nil
+}
+
+func flagOrEnv(value, env string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(env)
 }
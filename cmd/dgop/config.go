@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long:  "Show or check the effective configuration from ~/.config/dgop/config.toml, environment variables and flags.",
+	// The subcommands report config errors themselves.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective configuration as TOML",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		data, err := cfg.Encode()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check a config file for errors",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := resolvedConfigPath()
+		if len(args) == 1 {
+			path = args[0]
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		if path == "" {
+			path, _ = config.ConfigFilePath()
+		}
+		fmt.Printf("%s: ok\n", path)
+		return nil
+	},
+}
+
+func resolvedConfigPath() string {
+	return flagOrEnv(configPath, "DGOP_CONFIG")
+}
+
+// loadConfig reads the effective configuration for cmd, with its changed
+// flags applied on top.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := config.Load(resolvedConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	flagOverrides(cmd)(cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// newConfigManager is loadConfig with hot reload, for long-running commands.
+func newConfigManager(cmd *cobra.Command) (*config.Manager, error) {
+	return config.NewManager(resolvedConfigPath(), flagOverrides(cmd))
+}
+
+// flagOverrides copies the flags set on cmd's command line into a config so
+// they win over the file and the environment.
+func flagOverrides(cmd *cobra.Command) func(*config.Config) {
+	flags := cmd.Flags()
+	changed := flags.Changed
+	return func(cfg *config.Config) {
+		if changed("allow-actions") {
+			cfg.AllowActions = allowActions
+		}
+		if changed("bind") {
+			cfg.ApiBind = apiBind
+		}
+		if changed("no-tcp") {
+			cfg.ApiNoTCP = apiNoTCP
+		}
+		if changed("socket") {
+			cfg.ApiSocket = apiSocket
+		}
+		if changed("socket-mode") {
+			cfg.ApiSocketMode = apiSocketMode
+		}
+		if changed("tls-cert") {
+			cfg.TLSCert = tlsCert
+		}
+		if changed("tls-key") {
+			cfg.TLSKey = tlsKey
+		}
+		if changed("tls-client-ca") {
+			cfg.TLSClientCA = tlsClientCA
+		}
+		if changed("auth-file") {
+			cfg.AuthFile = authFile
+		}
+
+		// dgop bar's --modules picks bar modules, not the meta defaults.
+		if changed("modules") && cmd != barCmd {
+			cfg.Defaults.Modules = metaModules
+		}
+		if changed("sort") {
+			cfg.Defaults.Sort = procSortBy
+		}
+		if changed("limit") {
+			cfg.Defaults.Limit = procLimit
+		}
+		if changed("merge-children") {
+			cfg.Defaults.MergeChildren = mergeChildren
+		}
+		if changed("no-cpu") {
+			cfg.Defaults.NoCPU = disableProcCPU
+		}
+		if changed("gpu-pci-ids") {
+			cfg.Defaults.GPUPciIds = metaGPUPciIds
+		}
+
+		if changed("hide-cpu-cores") {
+			cfg.TUI.HideCPUCores = hideCPUCores
+		}
+		if changed("summarize-cores") {
+			cfg.TUI.SummarizeCores = summarizeCores
+		}
+	}
+}
+
+// applyConfig loads the configuration before every command and makes it the
+// default for flags that weren't given.
+func applyConfig(cmd *cobra.Command, gopsUtil *gops.GopsUtil) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	metaModules = cfg.Defaults.Modules
+	procSortBy = cfg.Defaults.Sort
+	procLimit = cfg.Defaults.Limit
+	mergeChildren = cfg.Defaults.MergeChildren
+	disableProcCPU = cfg.Defaults.NoCPU
+	metaGPUPciIds = cfg.Defaults.GPUPciIds
+	hideCPUCores = cfg.TUI.HideCPUCores
+	summarizeCores = cfg.TUI.SummarizeCores
+
+	gopsUtil.SetDeviceFilter(cfg.Devices)
+	return gopsUtil.SetModuleTimeouts(cfg.Timeouts)
+}
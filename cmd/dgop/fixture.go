@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AvengeMedia/dgop/fixture"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixtureOutput string
+	fixtureRedact []string
+)
+
+var captureFixtureCmd = &cobra.Command{
+	Use:   "capture-fixture",
+	Short: "Snapshot the /proc and /sys files dgop reads for a bug report",
+	Long: `Copy the /proc and /sys files dgop's collectors read (meminfo, stat,
+diskstats, hwmon, drm, dmi, power_supply, thermal and per-process files) into
+a tarball. Extract it and pass the directory to --sysroot to replay it.
+
+Use --redact all, or any of hostnames, usernames, serials and cmdlines, to
+scrub the capture before sharing it.`,
+	Args: cobra.NoArgs,
+}
+
+func runCaptureFixtureCommand() error {
+	redact, err := fixture.ParseRedactions(fixtureRedact)
+	if err != nil {
+		return err
+	}
+
+	path := fixtureOutput
+	if path == "" {
+		path = fmt.Sprintf("dgop-fixture-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	report, err := fixture.Capture(f, fixture.Options{
+		Root:    flagOrEnv(sysroot, "DGOP_SYSROOT"),
+		Redact:  redact,
+		Version: Version,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to capture fixture: %w", err)
+	}
+
+	if structuredOutput() {
+		return writeOutput(struct {
+			Path string `json:"path"`
+			*fixture.Report
+		}{path, report}, nil)
+	}
+
+	fmt.Printf("Wrote %s: %d files, %d links", path, report.Files, report.Links)
+	if report.Redacted > 0 {
+		fmt.Printf(", %d redacted", report.Redacted)
+	}
+	if report.Skipped > 0 {
+		fmt.Printf(", %d unreadable skipped", report.Skipped)
+	}
+	fmt.Println()
+	fmt.Printf("Replay with: mkdir fixture && tar xzf %s -C fixture && dgop --sysroot fixture\n", path)
+	return nil
+}
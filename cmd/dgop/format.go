@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AvengeMedia/dgop/cmd/dgop/output"
+	"github.com/spf13/cobra"
+)
+
+var formatter *output.Writer
+
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&outputFormat, "format", "", "Output format: text, json, yaml, csv, tsv, table or template")
+	cmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Go template for --format template, e.g. '{{.CPU.Usage}}%'")
+	cmd.PersistentFlags().StringSliceVar(&outputFields, "fields", nil, "Columns to show for list output, e.g. pid,cpu,command")
+}
+
+// setupOutput resolves --format, --json and --template into the formatter.
+func setupOutput() error {
+	format := output.FormatText
+	if outputFormat != "" {
+		f, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+		format = f
+	}
+	if outputTemplate != "" && outputFormat == "" {
+		format = output.FormatTemplate
+	}
+
+	if jsonOutput {
+		if outputFormat != "" && format != output.FormatJSON {
+			return fmt.Errorf("--json conflicts with --format %s", format)
+		}
+		format = output.FormatJSON
+	}
+	// Commands that only know --json, like watch, still honour --format json.
+	jsonOutput = format == output.FormatJSON
+
+	w, err := output.New(output.Options{
+		Format:   format,
+		Template: outputTemplate,
+		Fields:   outputFields,
+	})
+	if err != nil {
+		return err
+	}
+	formatter = w
+	return nil
+}
+
+// structuredOutput reports whether a format other than the styled text was
+// requested.
+func structuredOutput() bool {
+	return formatter != nil && !formatter.Text()
+}
+
+// writeOutput renders data in the requested format. rows is the list that
+// tabular formats and --fields apply to, or nil to treat data as one row.
+func writeOutput(data any, rows any) error {
+	return formatter.Write(os.Stdout, data, rows)
+}
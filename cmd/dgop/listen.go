@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/config"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// serverTLSConfig returns nil when no certificate is configured. With a
+// client CA, certificates are required unless bearer tokens are also
+// configured, in which case either is accepted.
+func serverTLSConfig(cfg *config.Config, hasTokens bool) (*tls.Config, error) {
+	if cfg.TLSCert == "" && cfg.TLSKey == "" {
+		if cfg.TLSClientCA != "" {
+			return nil, fmt.Errorf("a client CA requires a TLS certificate and key")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if cfg.TLSClientCA != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSClientCA)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		if hasTokens {
+			tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsCfg, nil
+}
+
+// apiListeners opens the TCP listener (wrapped in TLS when configured) and
+// the unix socket, whichever are enabled.
+func apiListeners(cfg *config.Config, tlsCfg *tls.Config) ([]net.Listener, error) {
+	var listeners []net.Listener
+	closeAll := func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}
+
+	if !cfg.ApiNoTCP {
+		ln, err := net.Listen("tcp", cfg.ListenAddr())
+		if err != nil {
+			return nil, err
+		}
+		if tlsCfg != nil {
+			ln = tls.NewListener(ln, tlsCfg)
+		}
+		listeners = append(listeners, ln)
+	}
+
+	if cfg.ApiSocket != "" {
+		ln, err := listenUnix(cfg.ApiSocket, cfg.ApiSocketMode)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		listeners = append(listeners, ln)
+	}
+
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("nothing to listen on: TCP is disabled and no socket is configured")
+	}
+	return listeners, nil
+}
+
+func listenUnix(path, modeStr string) (net.Listener, error) {
+	mode, err := strconv.ParseUint(modeStr, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socket mode %q", modeStr)
+	}
+
+	// Clear a socket left behind by a previous run, but never anything else.
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// serveListeners serves srv on every listener until ctx is cancelled or one
+// of them fails, then shuts the server down gracefully.
+func serveListeners(ctx context.Context, srv *http.Server, listeners []net.Listener) error {
+	errCh := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		go func() {
+			errCh <- srv.Serve(ln)
+		}()
+	}
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-errCh:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("API server shutdown: %v", err)
+	}
+
+	if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return nil
+}
+
+func listenerURL(ln net.Listener, secure bool) string {
+	if ln.Addr().Network() == "unix" {
+		return "unix://" + ln.Addr().String()
+	}
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port))
+}
+
+func isLoopbackListener(ln net.Listener) bool {
+	if ln.Addr().Network() == "unix" {
+		return true
+	}
+	host, _, _ := net.SplitHostPort(ln.Addr().String())
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -4,29 +4,69 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/cmd/dgop/bar"
+	"github.com/AvengeMedia/dgop/config"
 	"github.com/AvengeMedia/dgop/gops"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
 var (
-	Version        = "dev"
-	jsonOutput     bool
-	procSortBy     string
-	procLimit      int
-	disableProcCPU bool
-	mergeChildren  bool
-	metaModules    []string
-	gpuPciId       string
-	metaGPUPciIds  []string
-	cpuCursor      string
-	procCursor     string
-	netRateCursor  string
-	diskRateCursor string
-	hideCPUCores   bool
-	summarizeCores bool
+	Version            = "dev"
+	jsonOutput         bool
+	procSortBy         string
+	procLimit          int
+	disableProcCPU     bool
+	mergeChildren      bool
+	metaModules        []string
+	gpuPciId           string
+	metaGPUPciIds      []string
+	cpuCursor          string
+	procCursor         string
+	netRateCursor      string
+	diskRateCursor     string
+	unitsCursor        string
+	unitSortBy         string
+	procEventsCursor   string
+	procEventsInterval time.Duration
+	anomaliesCursor    string
+	anomaliesInterval  time.Duration
+	anomalyOpts        gops.AnomalyOptions
+	procEnv            bool
+	metaCursor         string
+	strict             bool
+	groupBy            string
+	accurateMemory     bool
+	procFilter         string
+	showThreads        bool
+	threadsPID         int32
+	hideCPUCores       bool
+	summarizeCores     bool
+	alertsFile         string
+	remoteURL          string
+	allowActions       bool
+	peers              []string
+	peersFile          string
+	apiBind            string
+	apiSocket          string
+	apiSocketMode      string
+	apiNoTCP           bool
+	tlsCert            string
+	tlsKey             string
+	tlsClientCA        string
+	authFile           string
+	clientToken        string
+	clientCA           string
+	clientCert         string
+	clientKey          string
+	configPath         string
+	sysroot            string
+	outputFormat       string
+	outputTemplate     string
+	outputFields       []string
 )
 
 var titleStyle = lipgloss.NewStyle().
@@ -61,12 +101,16 @@ func printHeader() {
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	rootCmd.PersistentFlags().BoolVar(&disableProcCPU, "no-cpu", false, "Disable CPU calculation for faster process listing")
+	addOutputFlags(rootCmd)
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default ~/.config/dgop/config.toml, env DGOP_CONFIG)")
+	rootCmd.PersistentFlags().StringVar(&sysroot, "sysroot", "", "Read /proc, /sys and /etc from a captured tree instead of this system (env DGOP_SYSROOT)")
 
-	allCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid)")
+	allCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid, pss)")
 	allCmd.Flags().IntVar(&procLimit, "limit", 0, "Limit number of processes (0 = no limit)")
 	allCmd.Flags().StringVar(&cpuCursor, "cpu-cursor", "", "CPU cursor from previous request")
 	allCmd.Flags().StringVar(&procCursor, "proc-cursor", "", "Process cursor from previous request")
 	allCmd.Flags().BoolVar(&mergeChildren, "merge-children", true, "Merge child processes with same executable")
+	allCmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error when any module couldn't be collected")
 
 	cpuCmd.Flags().StringVar(&cpuCursor, "cursor", "", "Cursor from previous CPU request")
 
@@ -74,36 +118,84 @@ func init() {
 
 	diskRateCmd.Flags().StringVar(&diskRateCursor, "cursor", "", "Cursor from previous disk rate request")
 
-	processesCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid)")
+	processesCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid, pss)")
 	processesCmd.Flags().IntVar(&procLimit, "limit", 0, "Limit number of processes (0 = no limit)")
 	processesCmd.Flags().StringVar(&procCursor, "cursor", "", "Cursor from previous process request")
 	processesCmd.Flags().BoolVar(&mergeChildren, "merge-children", true, "Merge child processes with same executable")
+	processesCmd.Flags().StringVar(&groupBy, "group-by", "process", "Group processes by process or app (desktop application)")
+	processesCmd.Flags().BoolVar(&accurateMemory, "accurate-memory", false, "Read smaps_rollup for PSS, USS and swap of every process (slower)")
+	processesCmd.Flags().StringVar(&procFilter, "filter", "", "Only list matching processes, e.g. \"user=alice name~^firefox kthreads=false\" (terms: user= name~ cmd~ pid= tree= cgroup~ state= cpu>= mem>= kthreads=)")
+	processesCmd.Flags().BoolVar(&showThreads, "threads", false, "List threads instead of processes, busiest first; --cursor takes the threads cursor")
+	processesCmd.Flags().Int32Var(&threadsPID, "pid", 0, "With --threads, only list the threads of this process (0 = all)")
+
+	unitsCmd.Flags().StringVar(&unitSortBy, "sort", "memory", "Sort units by (cpu, memory, io, name)")
+	unitsCmd.Flags().IntVar(&procLimit, "limit", 0, "Limit number of units (0 = no limit)")
+	unitsCmd.Flags().StringVar(&unitsCursor, "cursor", "", "Cursor from previous units request")
+
+	procEventsCmd.Flags().StringVar(&procEventsCursor, "cursor", "", "Cursor from previous proc-events request")
+	procEventsCmd.Flags().DurationVar(&procEventsInterval, "interval", 2*time.Second, "Without --cursor, how long to watch before reporting")
+
+	anomaliesCmd.Flags().StringVar(&anomaliesCursor, "cursor", "", "Cursor from previous anomalies request")
+	anomaliesCmd.Flags().DurationVar(&anomaliesInterval, "interval", 2*time.Second, "Without --cursor, how long to wait between the two samples")
+	anomaliesCmd.Flags().IntVar(&anomalyOpts.StuckSamples, "stuck-samples", 3, "Samples in a row a process must spend in uninterruptible sleep")
+	anomaliesCmd.Flags().Float64Var(&anomalyOpts.ForkRate, "fork-rate", 20, "New processes per second under one ancestor that count as a fork bomb")
+	anomaliesCmd.Flags().DurationVar(&anomalyOpts.GrowthWindow, "growth-window", 10*time.Minute, "Span memory growth is judged over")
+
+	procCmd.Flags().BoolVar(&procEnv, "env", false, "Include the environment, which can hold secrets")
 
 	metaCmd.Flags().StringSliceVar(&metaModules, "modules", []string{"all"}, "Modules to include (cpu,memory,network,etc)")
-	metaCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid)")
+	metaCmd.Flags().StringVar(&procSortBy, "sort", "cpu", "Sort processes by (cpu, memory, name, pid, pss)")
 	metaCmd.Flags().IntVar(&procLimit, "limit", 0, "Limit number of processes (0 = no limit)")
 	metaCmd.Flags().StringSliceVar(&metaGPUPciIds, "gpu-pci-ids", []string{}, "PCI IDs for GPU temperatures (e.g., 10de:2684,1002:164e)")
 	metaCmd.Flags().StringVar(&cpuCursor, "cpu-cursor", "", "CPU cursor from previous request")
 	metaCmd.Flags().StringVar(&procCursor, "proc-cursor", "", "Process cursor from previous request")
 	metaCmd.Flags().StringVar(&netRateCursor, "net-rate-cursor", "", "Network rate cursor from previous request")
 	metaCmd.Flags().StringVar(&diskRateCursor, "disk-rate-cursor", "", "Disk rate cursor from previous request")
+	metaCmd.Flags().StringVar(&unitsCursor, "units-cursor", "", "Units cursor from previous request")
+	metaCmd.Flags().StringVar(&procEventsCursor, "proc-events-cursor", "", "Process events cursor from previous request")
+	metaCmd.Flags().StringVar(&anomaliesCursor, "anomalies-cursor", "", "Anomalies cursor from previous request")
+	metaCmd.Flags().StringVar(&metaCursor, "meta-cursor", "", "Composite cursor from previous request, covering every module")
 	metaCmd.Flags().BoolVar(&mergeChildren, "merge-children", true, "Merge child processes with same executable")
+	metaCmd.Flags().StringVar(&groupBy, "group-by", "process", "Group processes by process or app (desktop application)")
+	metaCmd.Flags().BoolVar(&accurateMemory, "accurate-memory", false, "Read smaps_rollup for PSS, USS and swap of every process (slower)")
+	metaCmd.Flags().StringVar(&procFilter, "filter", "", "Only list matching processes, e.g. \"user=alice name~^firefox kthreads=false\" (terms: user= name~ cmd~ pid= tree= cgroup~ state= cpu>= mem>= kthreads=)")
+	metaCmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error when any module couldn't be collected")
 
 	gpuTempCmd.Flags().StringVar(&gpuPciId, "pci-id", "", "PCI ID of GPU to get temperature (e.g., 10de:2684)")
 	gpuTempCmd.MarkFlagRequired("pci-id")
 
 	topCmd.Flags().BoolVar(&hideCPUCores, "hide-cpu-cores", false, "Hide individual CPU core display in TUI")
 	topCmd.Flags().BoolVar(&summarizeCores, "summarize-cores", false, "Show summarized CPU core groups instead of individual cores")
+	topCmd.Flags().StringVar(&remoteURL, "remote", "", "Attach to a dgop API server, e.g. http://host:63484")
+	topCmd.Flags().StringSliceVar(&peers, "peers", nil, "Poll other dgop servers for the fleet view, e.g. host1,host2")
+	addClientAuthFlags(topCmd)
+
+	serverCmd.Flags().BoolVar(&allowActions, "allow-actions", false, "Allow clients to signal processes through the API")
+	serverCmd.Flags().StringVar(&alertsFile, "alerts", "", "Alert rules file (default ~/.config/dgop/alerts.toml)")
+	serverCmd.Flags().StringSliceVar(&peers, "peers", nil, "Federate other dgop servers, e.g. host1,host2:63484,https://host3")
+	serverCmd.Flags().StringVar(&peersFile, "peers-file", "", "Peers file (default ~/.config/dgop/peers.toml)")
+	serverCmd.Flags().StringVar(&apiBind, "bind", "", "Address to listen on (default all interfaces)")
+	serverCmd.Flags().BoolVar(&apiNoTCP, "no-tcp", false, "Only listen on the unix socket")
+	serverCmd.Flags().StringVar(&apiSocket, "socket", "", "Also listen on this unix socket")
+	serverCmd.Flags().StringVar(&apiSocketMode, "socket-mode", "", "File mode for the unix socket (default 0660)")
+	serverCmd.Flags().StringVar(&tlsCert, "tls-cert", "", "TLS certificate file")
+	serverCmd.Flags().StringVar(&tlsKey, "tls-key", "", "TLS private key file")
+	serverCmd.Flags().StringVar(&tlsClientCA, "tls-client-ca", "", "CA for verifying client certificates (enables mTLS)")
+	serverCmd.Flags().StringVar(&authFile, "auth-file", "", "Auth tokens file (default ~/.config/dgop/auth.toml)")
+	addClientAuthFlags(serverCmd)
+	watchCmd.Flags().StringVar(&alertsFile, "alerts", "", "Alert rules file (default ~/.config/dgop/alerts.toml)")
+
+	barCmd.Flags().StringVar(&barProtocol, "protocol", "i3bar", "Output protocol (i3bar, waybar, polybar, tmux)")
+	barCmd.Flags().StringSliceVar(&barModules, "modules", bar.DefaultModules, "Modules to show (cpu,memory,net-rate,disk-rate,diskmounts,system or all)")
+	barCmd.Flags().DurationVar(&barInterval, "interval", 2*time.Second, "Time between updates")
+	captureFixtureCmd.Flags().StringVarP(&fixtureOutput, "output", "o", "", "Tarball to write (default dgop-fixture-<time>.tar.gz)")
+	captureFixtureCmd.Flags().StringSliceVar(&fixtureRedact, "redact", nil, "Scrub hostnames, usernames, serials, cmdlines or all")
+
+	barCmd.Flags().StringVar(&barSeparator, "separator", "  ", "Text between modules for polybar and tmux")
 }
 
 var rootCmd = &cobra.Command{
 	Use: "dgop",
-	Run: func(cmd *cobra.Command, args []string) {
-		gopsUtil := gops.NewGopsUtil()
-		if err := runTUIWithOptions(gopsUtil, hideCPUCores, summarizeCores); err != nil {
-			log.Fatal(err)
-		}
-	},
 }
 
 func main() {
@@ -111,8 +203,16 @@ func main() {
 
 	gopsUtil := gops.NewGopsUtil()
 
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		cmd.SetContext(cmd.Context())
+		if err := setupOutput(); err != nil {
+			return err
+		}
+		if err := gopsUtil.UseSysroot(flagOrEnv(sysroot, "DGOP_SYSROOT")); err != nil {
+			return err
+		}
+		useCursorKey(gopsUtil)
+		return applyConfig(cmd, gopsUtil)
 	}
 
 	setupCommands(gopsUtil)
@@ -123,6 +223,18 @@ func main() {
 	}
 }
 
+// useCursorKey signs cursors with the per-install key. Without it cursors
+// still work within one process, such as the server or TUI, just not
+// across CLI runs.
+func useCursorKey(gopsUtil *gops.GopsUtil) {
+	key, err := config.CursorKey()
+	if err != nil {
+		log.Debug("cursors won't carry over between runs", "error", err)
+		return
+	}
+	_ = gopsUtil.SetCursorKey(key)
+}
+
 func setupCommands(gopsUtil *gops.GopsUtil) {
 	rootCmd.AddCommand(helpCmd)
 	rootCmd.AddCommand(versionCmd)
@@ -132,6 +244,10 @@ func setupCommands(gopsUtil *gops.GopsUtil) {
 	rootCmd.AddCommand(networkCmd)
 	rootCmd.AddCommand(diskCmd)
 	rootCmd.AddCommand(processesCmd)
+	rootCmd.AddCommand(unitsCmd)
+	rootCmd.AddCommand(procEventsCmd)
+	rootCmd.AddCommand(anomaliesCmd)
+	rootCmd.AddCommand(procCmd)
 	rootCmd.AddCommand(systemCmd)
 	rootCmd.AddCommand(hardwareCmd)
 	rootCmd.AddCommand(gpuCmd)
@@ -142,6 +258,16 @@ func setupCommands(gopsUtil *gops.GopsUtil) {
 	rootCmd.AddCommand(diskRateCmd)
 	rootCmd.AddCommand(topCmd)
 	rootCmd.AddCommand(serverCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(barCmd)
+	rootCmd.AddCommand(captureFixtureCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPrintCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runTopCommand(cmd, gopsUtil)
+	}
 
 	// Set gopsUtil for all commands
 	allCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -176,6 +302,22 @@ func setupCommands(gopsUtil *gops.GopsUtil) {
 		return runProcessesCommand(gopsUtil)
 	}
 
+	unitsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runUnitsCommand(gopsUtil)
+	}
+
+	procEventsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runProcEventsCommand(gopsUtil)
+	}
+
+	anomaliesCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runAnomaliesCommand(gopsUtil)
+	}
+
+	procCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runProcCommand(gopsUtil, args[0])
+	}
+
 	systemCmd.RunE = func(cmd *cobra.Command, args []string) error {
 		return runSystemCommand(gopsUtil)
 	}
@@ -201,7 +343,19 @@ func setupCommands(gopsUtil *gops.GopsUtil) {
 	}
 
 	topCmd.RunE = func(cmd *cobra.Command, args []string) error {
-		return runTopCommand(gopsUtil)
+		return runTopCommand(cmd, gopsUtil)
+	}
+
+	watchCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runWatchCommand(cmd, gopsUtil)
+	}
+
+	barCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runBarCommand(cmd, gopsUtil)
+	}
+
+	captureFixtureCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runCaptureFixtureCommand()
 	}
 
 	helpCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -233,6 +387,8 @@ func parseProcessSortBy(sortBy string, cpuDisabled bool) gops.ProcSortBy {
 		return gops.SortByName
 	case "pid":
 		return gops.SortByPID
+	case "pss":
+		return gops.SortByPSS
 	default:
 		// Default behavior: CPU if enabled, memory if CPU disabled
 		if cpuDisabled {
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// namedColors maps the names accepted by the color helper to ANSI colors.
+var namedColors = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// Funcs returns the helpers available to --template:
+//
+//	humanBytes  1536 -> "1.50 KB"
+//	round       round 3.14159 1 -> 3.1
+//	bar         bar 42 10 -> "████░░░░░░"
+//	color       color "red" "text", or a hex or ANSI color number
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"humanBytes": humanBytes,
+		"round":      round,
+		"bar":        bar,
+		"color":      color,
+	}
+}
+
+func humanBytes(v any) (string, error) {
+	bytes, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	const unit = 1024
+	if math.Abs(bytes) < unit {
+		return fmt.Sprintf("%.0f B", bytes), nil
+	}
+	div, exp := float64(unit), 0
+	for n := math.Abs(bytes) / unit; n >= unit && exp < 5; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", bytes/div, "KMGTPE"[exp]), nil
+}
+
+// round rounds to the given number of decimal places, zero by default.
+func round(v any, places ...int) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	p := 0
+	if len(places) > 0 {
+		p = places[0]
+	}
+	scale := math.Pow(10, float64(p))
+	return math.Round(f*scale) / scale, nil
+}
+
+// bar draws a percentage (0-100) as a bar of the given width.
+func bar(percent any, width int) (string, error) {
+	p, err := toFloat(percent)
+	if err != nil {
+		return "", err
+	}
+	if width <= 0 {
+		return "", nil
+	}
+	p = math.Max(0, math.Min(100, p))
+	filled := int(math.Round(p / 100 * float64(width)))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled), nil
+}
+
+// color styles s with a foreground color. Like the rest of the styled output
+// it is plain text when stdout isn't a terminal.
+func color(c string, s any) string {
+	if ansi, ok := namedColors[strings.ToLower(c)]; ok {
+		c = ansi
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Render(fmt.Sprint(s))
+}
+
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case string:
+		return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(n), "%"), 64)
+	case fmt.Stringer:
+		return strconv.ParseFloat(n.String(), 64)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", v)
+}
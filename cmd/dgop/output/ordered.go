@@ -0,0 +1,189 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// object is a JSON object that keeps its key order, so YAML and tables list
+// fields in the same order as the JSON output.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toOrdered converts v to objects, []any, json.Number, string, bool and nil
+// by way of its JSON encoding.
+func toOrdered(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{Key: keyTok.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
+
+// flatten lists the scalar leaves of v with dotted paths, skipping arrays,
+// which don't fit in a single cell. They can still be picked by --fields.
+func flatten(v any) object {
+	var out object
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch val := v.(type) {
+		case object:
+			for _, m := range val {
+				walk(joinPath(prefix, m.Key), m.Value)
+			}
+		case []any:
+		default:
+			out = append(out, member{Key: prefix, Value: val})
+		}
+	}
+	walk("", v)
+	return out
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// lookup resolves a dotted path, matching keys case-insensitively.
+func lookup(v any, path string) (any, bool) {
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := v.(object)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, m := range obj {
+			if strings.EqualFold(m.Key, part) {
+				v, found = m.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// cell renders a value for CSV, TSV and table output.
+func cell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return fmt.Sprint(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+// yamlNode builds block-style YAML from an ordered value.
+func yamlNode(v any) *yaml.Node {
+	switch val := v.(type) {
+	case object:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, m := range val {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: m.Key},
+				yamlNode(m.Value))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range val {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(val.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: val.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(val)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(val)}
+	}
+}
@@ -0,0 +1,266 @@
+// Package output renders command results as JSON, YAML, CSV, TSV, aligned
+// tables or Go templates.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	// FormatText is each command's own styled output and isn't handled here.
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatTable    Format = "table"
+	FormatTemplate Format = "template"
+)
+
+var formats = []Format{FormatText, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatTable, FormatTemplate}
+
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	if !slices.Contains(formats, f) {
+		return "", fmt.Errorf("unknown format %q (expected text, json, yaml, csv, tsv, table or template)", s)
+	}
+	return f, nil
+}
+
+type Options struct {
+	Format   Format
+	Template string
+	// Fields selects and orders columns by their JSON names. Nested values
+	// are addressed with dots, e.g. "cpu.usage".
+	Fields []string
+}
+
+// Writer renders results according to Options. Construct it with New so
+// templates are parsed once.
+type Writer struct {
+	opts Options
+	tmpl *template.Template
+}
+
+func New(opts Options) (*Writer, error) {
+	if opts.Format == "" {
+		opts.Format = FormatText
+	}
+	w := &Writer{opts: opts}
+
+	switch {
+	case opts.Template != "" && opts.Format != FormatTemplate:
+		return nil, fmt.Errorf("--template requires --format template")
+	case opts.Format == FormatTemplate && opts.Template == "":
+		return nil, fmt.Errorf("--format template requires --template")
+	case opts.Format == FormatTemplate && len(opts.Fields) > 0:
+		return nil, fmt.Errorf("--fields can't be combined with a template")
+	}
+
+	if opts.Format == FormatTemplate {
+		tmpl, err := template.New("output").Funcs(Funcs()).Parse(opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		w.tmpl = tmpl
+	}
+	return w, nil
+}
+
+// Text reports whether the command should print its own styled output.
+func (w *Writer) Text() bool {
+	return w.opts.Format == FormatText && len(w.opts.Fields) == 0
+}
+
+// Write renders data. rows is the list that tabular formats and --fields
+// apply to, such as the processes of a process listing; when nil, data is
+// treated as a single row. Templates always see data itself.
+func (w *Writer) Write(out io.Writer, data any, rows any) error {
+	if w.opts.Format == FormatTemplate {
+		return w.writeTemplate(out, data)
+	}
+
+	format := w.opts.Format
+	if format == FormatText {
+		// --fields alone implies a table.
+		format = FormatTable
+	}
+
+	if format == FormatJSON && len(w.opts.Fields) == 0 {
+		return json.NewEncoder(out).Encode(data)
+	}
+
+	list := rows != nil
+	source := data
+	if list {
+		source = rows
+	}
+	ordered, err := toOrdered(source)
+	if err != nil {
+		return err
+	}
+
+	var records []any
+	if list {
+		records, _ = ordered.([]any)
+	} else {
+		records = []any{ordered}
+	}
+
+	if len(w.opts.Fields) > 0 {
+		projected, err := project(records, w.opts.Fields)
+		if err != nil {
+			return err
+		}
+		records = projected
+		if list {
+			ordered = projected
+		} else {
+			ordered = projected[0]
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(out).Encode(ordered)
+	case FormatYAML:
+		if len(w.opts.Fields) == 0 {
+			// Keep the whole result, not just the rows.
+			if ordered, err = toOrdered(data); err != nil {
+				return err
+			}
+		}
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+		if err := enc.Encode(yamlNode(ordered)); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(out)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		header, table := tabulate(records, w.opts.Fields)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(table); err != nil {
+			return err
+		}
+		return cw.Error()
+	case FormatTable:
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		header, table := tabulate(records, w.opts.Fields)
+		upper := make([]string, len(header))
+		for i, h := range header {
+			upper[i] = strings.ToUpper(h)
+		}
+		fmt.Fprintln(tw, strings.Join(upper, "\t"))
+		for _, row := range table {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+func (w *Writer) writeTemplate(out io.Writer, data any) error {
+	var buf strings.Builder
+	if err := w.tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	s := buf.String()
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	_, err := io.WriteString(out, s)
+	return err
+}
+
+// project keeps only fields, in the order given. A field no row has is
+// almost certainly a typo; fields missing from only some rows are fine, since
+// omitempty drops them.
+func project(records []any, fields []string) ([]any, error) {
+	for _, f := range fields {
+		found := len(records) == 0
+		for _, rec := range records {
+			if _, ok := lookup(rec, f); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", f, strings.Join(keys(flatten(records[0])), ", "))
+		}
+	}
+
+	out := make([]any, len(records))
+	for i, rec := range records {
+		obj := make(object, len(fields))
+		for j, f := range fields {
+			v, _ := lookup(rec, f)
+			obj[j] = member{Key: f, Value: v}
+		}
+		out[i] = obj
+	}
+	return out, nil
+}
+
+// tabulate turns records into a header and string cells. With fields the
+// header is exactly those fields, otherwise records are flattened and columns
+// appear in first-seen order across all rows.
+func tabulate(records []any, fields []string) ([]string, [][]string) {
+	if len(fields) > 0 {
+		// Records have already been projected onto fields.
+		table := make([][]string, len(records))
+		for i, rec := range records {
+			row := make([]string, len(fields))
+			for j, m := range rec.(object) {
+				row[j] = cell(m.Value)
+			}
+			table[i] = row
+		}
+		return fields, table
+	}
+
+	flat := make([]object, len(records))
+	var header []string
+	for i, rec := range records {
+		flat[i] = flatten(rec)
+		for _, m := range flat[i] {
+			if !slices.Contains(header, m.Key) {
+				header = append(header, m.Key)
+			}
+		}
+	}
+
+	table := make([][]string, len(flat))
+	for i, obj := range flat {
+		row := make([]string, len(header))
+		for _, m := range obj {
+			row[slices.Index(header, m.Key)] = cell(m.Value)
+		}
+		table[i] = row
+	}
+	return header, table
+}
+
+func keys(o object) []string {
+	out := make([]string, len(o))
+	for i, m := range o {
+		out[i] = m.Key
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type proc struct {
+	PID     int32   `json:"pid"`
+	CPU     float64 `json:"cpu"`
+	Command string  `json:"command"`
+	Path    string  `json:"path,omitempty"`
+}
+
+type usage struct {
+	Usage float64 `json:"usage"`
+	Cores []int   `json:"cores"`
+}
+
+type result struct {
+	CPU       *usage  `json:"cpu"`
+	Processes []*proc `json:"processes"`
+	Cursor    string  `json:"cursor"`
+}
+
+func sample() *result {
+	return &result{
+		CPU: &usage{Usage: 12.5, Cores: []int{10, 15}},
+		Processes: []*proc{
+			{PID: 1, CPU: 0.5, Command: "init"},
+			{PID: 42, CPU: 30, Command: "go, build", Path: "/usr/bin/go"},
+		},
+		Cursor: "abc",
+	}
+}
+
+func render(t *testing.T, opts Options, data, rows any) string {
+	t.Helper()
+	w, err := New(opts)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, w.Write(&buf, data, rows))
+	return buf.String()
+}
+
+func TestCSVAndTSV(t *testing.T) {
+	data := sample()
+
+	assert.Equal(t, "pid,cpu,command,path\n1,0.5,init,\n42,30,\"go, build\",/usr/bin/go\n",
+		render(t, Options{Format: FormatCSV}, data, data.Processes))
+	assert.Equal(t, "command\tPID\ninit\t1\ngo, build\t42\n",
+		render(t, Options{Format: FormatTSV, Fields: []string{"command", "PID"}}, data, data.Processes))
+}
+
+func TestSingleObjectFlattens(t *testing.T) {
+	out := render(t, Options{Format: FormatCSV}, sample().CPU, nil)
+	assert.Equal(t, "usage\n12.5\n", out, "arrays are left out of the default columns")
+
+	out = render(t, Options{Format: FormatCSV, Fields: []string{"cpu.usage", "cpu.cores", "cursor"}}, sample(), nil)
+	assert.Equal(t, "cpu.usage,cpu.cores,cursor\n12.5,\"[10,15]\",abc\n", out)
+}
+
+func TestTable(t *testing.T) {
+	data := sample()
+	out := render(t, Options{Fields: []string{"pid", "command"}}, data, data.Processes)
+	assert.Equal(t, "PID  COMMAND\n1    init\n42   go, build\n", out)
+}
+
+func TestYAMLKeepsOrder(t *testing.T) {
+	out := render(t, Options{Format: FormatYAML}, sample().CPU, nil)
+	assert.Equal(t, "usage: 12.5\ncores:\n  - 10\n  - 15\n", out)
+}
+
+func TestJSONFields(t *testing.T) {
+	data := sample()
+	out := render(t, Options{Format: FormatJSON, Fields: []string{"pid", "cpu"}}, data, data.Processes)
+	assert.Equal(t, `[{"pid":1,"cpu":0.5},{"pid":42,"cpu":30}]`+"\n", out)
+}
+
+func TestUnknownField(t *testing.T) {
+	data := sample()
+	w, err := New(Options{Format: FormatCSV, Fields: []string{"pdi"}})
+	require.NoError(t, err)
+	err = w.Write(&bytes.Buffer{}, data, data.Processes)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: pid, cpu, command")
+}
+
+func TestTemplate(t *testing.T) {
+	out := render(t, Options{
+		Format:   FormatTemplate,
+		Template: `{{round .CPU.Usage}}% {{bar .CPU.Usage 4}} {{humanBytes 1536}} {{range .Processes}}{{.PID}} {{end}}`,
+	}, sample(), nil)
+	assert.Equal(t, "13% █░░░ 1.50 KB 1 42 \n", out)
+}
+
+func TestOptionValidation(t *testing.T) {
+	_, err := New(Options{Format: FormatTemplate})
+	assert.Error(t, err)
+	_, err = New(Options{Format: FormatJSON, Template: "{{.}}"})
+	assert.Error(t, err)
+	_, err = New(Options{Format: FormatTemplate, Template: "{{.Broken"})
+	assert.Error(t, err)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/AvengeMedia/dankgo/app"
 	"github.com/AvengeMedia/dankgo/errdefs/humaerr"
 	"github.com/AvengeMedia/dankgo/httpapi"
 	"github.com/AvengeMedia/dankgo/httpapi/middleware"
 	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/alerts"
+	"github.com/AvengeMedia/dgop/api/auth"
 	gops_handler "github.com/AvengeMedia/dgop/api/gops"
 	"github.com/AvengeMedia/dgop/api/server"
+	"github.com/AvengeMedia/dgop/api/session"
 	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/fleet"
 	"github.com/AvengeMedia/dgop/gops"
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
@@ -29,14 +36,72 @@ var serverCmd = &cobra.Command{
 }
 
 func runServerCommand(cmd *cobra.Command, args []string) error {
-	cfg := config.NewConfig()
-	return startAPI(cmd.Context(), cfg)
+	settings, err := newConfigManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer settings.Close()
+
+	// Shut down gracefully so the unix socket is removed on exit.
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return startAPI(ctx, settings)
 }
 
-func startAPI(ctx context.Context, cfg *config.Config) error {
+func startAPI(ctx context.Context, settings *config.Manager) error {
+	cfg := settings.Get()
+	gopsUtil := gops.NewGopsUtil()
+	useCursorKey(gopsUtil)
+	gopsUtil.SetDeviceFilter(cfg.Devices)
+	if err := gopsUtil.SetModuleTimeouts(cfg.Timeouts); err != nil {
+		return err
+	}
+	if err := gopsUtil.EnableCache(cfg.Cache); err != nil {
+		return err
+	}
+	gopsUtil.EnableProcessTable()
+	go watchServerConfig(ctx, settings, gopsUtil)
+
+	alertsCfg, err := config.LoadAlertsConfig(alertsFile)
+	if err != nil {
+		return err
+	}
+	engine, err := alerts.NewEngine(gopsUtil, alertsCfg)
+	if err != nil {
+		return fmt.Errorf("invalid alert rules: %w", err)
+	}
+
+	authCfg, err := config.LoadAuthConfig(cfg.AuthFile)
+	if err != nil {
+		return err
+	}
+	authenticator, err := auth.New(authCfg, cfg.TLSClientCA != "")
+	if err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+	tlsCfg, err := serverTLSConfig(cfg, len(authCfg.Tokens) > 0)
+	if err != nil {
+		return err
+	}
+
 	srvImpl := &server.Server{
-		Cfg:  cfg,
-		Gops: gops.NewGopsUtil(),
+		Cfg:      cfg,
+		Gops:     gopsUtil,
+		Alerts:   engine,
+		Sessions: session.NewStore(cfg.SessionTTL, cfg.SessionMaxBytes),
+	}
+
+	if !engine.Empty() {
+		go engine.Run(ctx, logAlertTransition)
+	}
+
+	peerFleet, err := newFleet(gopsUtil)
+	if err != nil {
+		return err
+	}
+	if peerFleet != nil {
+		srvImpl.Fleet = peerFleet
+		go peerFleet.Run(ctx)
 	}
 
 	r := chi.NewRouter()
@@ -48,6 +113,7 @@ func startAPI(ctx context.Context, cfg *config.Config) error {
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Logger)
+		r.Use(authenticator.Middleware)
 
 		huma.NewError = humaerr.HumaErrorFunc
 
@@ -68,11 +134,89 @@ func startAPI(ctx context.Context, cfg *config.Config) error {
 		gops_handler.RegisterHandlers(srvImpl, gopsGroup)
 	})
 
-	addr := cfg.ApiPort
-	log.Infof(" Starting DankGop API server on %s", addr)
-	log.Infof(" API Documentation: http://localhost%s/docs", addr)
-	log.Infof(" OpenAPI Spec: http://localhost%s/openapi.json", addr)
-	log.Infof(" Health Check: http://localhost%s/health", addr)
+	listeners, err := apiListeners(cfg, tlsCfg)
+	if err != nil {
+		return err
+	}
 
-	return app.Serve(ctx, httpapi.NewServer(addr, r))
+	for _, ln := range listeners {
+		base := listenerURL(ln, tlsCfg != nil)
+		log.Infof(" Starting DankGop API server on %s", base)
+		if !authenticator.Enabled() && !isLoopbackListener(ln) {
+			log.Warnf(" Authentication is disabled and %s is reachable from the network", base)
+		}
+	}
+	if !cfg.ApiNoTCP {
+		base := listenerURL(listeners[0], tlsCfg != nil)
+		log.Infof(" API Documentation: %s/docs", base)
+		log.Infof(" OpenAPI Spec: %s/openapi.json", base)
+		log.Infof(" Health Check: %s/health", base)
+	}
+
+	httpSrv := httpapi.NewServer(cfg.ListenAddr(), r)
+	httpSrv.ConnContext = auth.ConnContext
+	return serveListeners(ctx, httpSrv, listeners)
+}
+
+// watchServerConfig applies config.toml edits that are safe to change while
+// running. Listener, TLS and auth settings need a restart.
+func watchServerConfig(ctx context.Context, settings *config.Manager, gopsUtil *gops.GopsUtil) {
+	running := settings.Get().ServerConfig
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-settings.Changes():
+		}
+
+		if err := settings.Err(); err != nil {
+			log.Warnf(" Ignoring config change: %v", err)
+			continue
+		}
+		next := settings.Get()
+		gopsUtil.SetDeviceFilter(next.Devices)
+		if err := gopsUtil.EnableCache(next.Cache); err != nil {
+			log.Warnf(" Ignoring cache settings: %v", err)
+		}
+		if err := gopsUtil.SetModuleTimeouts(next.Timeouts); err != nil {
+			log.Warnf(" Ignoring timeout settings: %v", err)
+		}
+		if next.ServerConfig != running {
+			log.Warnf(" Server settings in %s changed; restart dgop server to apply them", settings.Path())
+		}
+		log.Infof(" Reloaded %s", settings.Path())
+	}
+}
+
+// newFleet builds the federation poller from --peers and the peers file, or
+// returns nil when no peers are configured.
+func newFleet(gopsUtil *gops.GopsUtil) (*fleet.Fleet, error) {
+	peersCfg, err := config.LoadPeersConfig(peersFile)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := append(peersCfg.Peers, peers...)
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	interval := fleet.DefaultInterval
+	if peersCfg.Interval != "" {
+		if interval, err = time.ParseDuration(peersCfg.Interval); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid peers interval %q", peersCfg.Interval)
+		}
+	}
+
+	clientOpts, err := clientAuthOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := fleet.New(specs, fleet.WithLocal(gopsUtil, ""), fleet.WithInterval(interval), fleet.WithClientOptions(clientOpts...))
+	if err != nil {
+		return nil, err
+	}
+	log.Infof(" Federating %d peers every %s", len(specs), interval)
+	return f, nil
 }
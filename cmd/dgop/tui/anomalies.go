@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderAnomalyStrip returns a line listing the anomalies above the
+// footer, or an empty string when there are none. Fork bombs and stuck
+// processes, which need someone now, turn it the error color.
+func (m *ResponsiveTUIModel) renderAnomalyStrip() string {
+	if len(m.anomalies) == 0 {
+		return ""
+	}
+
+	colors := m.getColors()
+	color := colors.Status.Warning
+	summaries := make([]string, 0, len(m.anomalies))
+	for _, a := range m.anomalies {
+		if a.Kind == models.AnomalyForkBomb || a.Kind == models.AnomalyStuck {
+			color = colors.Status.Error
+		}
+		summaries = append(summaries, a.Summary)
+	}
+
+	text := fmt.Sprintf("⚠ %d anomalies: %s", len(summaries), strings.Join(summaries, " | "))
+	style := m.footerStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(color))
+	return style.Render(m.truncate(text, m.width-4))
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os"
@@ -18,6 +19,10 @@ func NewResponsiveTUIModel(gopsUtil *gops.GopsUtil) *ResponsiveTUIModel {
 }
 
 func NewResponsiveTUIModelWithOptions(gopsUtil *gops.GopsUtil, hideCPUCores, summarizeCores bool) *ResponsiveTUIModel {
+	return NewResponsiveTUIModelWithSource(NewLocalSource(gopsUtil), hideCPUCores, summarizeCores)
+}
+
+func NewResponsiveTUIModelWithSource(source DataSource, hideCPUCores, summarizeCores bool) *ResponsiveTUIModel {
 	colorManager, err := config.NewColorManager()
 	if err != nil {
 		colorManager = nil
@@ -70,7 +75,7 @@ func NewResponsiveTUIModelWithOptions(gopsUtil *gops.GopsUtil, hideCPUCores, sum
 	t.SetStyles(s)
 
 	model := &ResponsiveTUIModel{
-		gops:           gopsUtil,
+		source:         source,
 		colorManager:   colorManager,
 		keybindManager: keybindManager,
 		processTable:   t,
@@ -78,6 +83,7 @@ func NewResponsiveTUIModelWithOptions(gopsUtil *gops.GopsUtil, hideCPUCores, sum
 		procLimit:      0,
 		maxNetHistory:  60,
 		maxDiskHistory: 60,
+		sampling:       config.Default().Sampling,
 		selectedPID:    -1,
 		logoTestMode:   false,
 		hideCPUCores:   hideCPUCores,
@@ -91,7 +97,9 @@ func NewResponsiveTUIModelWithOptions(gopsUtil *gops.GopsUtil, hideCPUCores, sum
 		model.keybinds = defaultResolvedKeybinds()
 	}
 
-	hardware, _ := gopsUtil.GetSystemHardware()
+	_, model.remote = source.(*RemoteSource)
+
+	hardware, _ := source.Hardware(context.Background())
 	model.hardware = hardware
 	model.distroLogo, model.distroColor = getDistroInfo(hardware)
 
@@ -186,6 +194,9 @@ func (m *ResponsiveTUIModel) renderSystemInfoPanel(width, height int) string {
 			username = "user"
 		}
 		userHostLine := fmt.Sprintf("%s@%s", username, m.hardware.Hostname)
+		if m.remote {
+			userHostLine = fmt.Sprintf("%s (remote)", m.hardware.Hostname)
+		}
 		leftLines = append(leftLines, userHostLine)
 		styledLeftLines = append(styledLeftLines, userHostLine)
 
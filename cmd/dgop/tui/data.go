@@ -3,7 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
-	"syscall"
+	"time"
 
 	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
@@ -33,19 +33,62 @@ type fetchTempMsg struct {
 	err   error
 }
 
+type alertsMsg struct {
+	firing []models.Alert
+	err    error
+}
+
+type alertTickMsg struct{}
+
+type fetchFleetMsg struct {
+	fleet *models.FleetInfo
+	err   error
+}
+
+type fetchUnitsMsg struct {
+	units *models.UnitsResponse
+	err   error
+}
+
+type fetchEventsMsg struct {
+	// cursor is the one the request was sent with, so a reply that
+	// another has overtaken isn't logged twice.
+	cursor string
+	events *models.ProcessEventsResponse
+	err    error
+}
+
+type fetchAnomaliesMsg struct {
+	cursor    string
+	anomalies *models.AnomaliesResponse
+	err       error
+}
+
+type fetchThreadsMsg struct {
+	pid     int32
+	threads *models.ThreadListResponse
+	err     error
+}
+
+type fetchDetailsMsg struct {
+	pid     int32
+	details *models.ProcessDetails
+	err     error
+}
+
 type processKillResultMsg struct {
 	message string
 }
 
-func killProcess(pid int32, force bool) tea.Cmd {
+func (m *ResponsiveTUIModel) killProcess(pid int32, force bool) tea.Cmd {
+	source := m.source
 	return func() tea.Msg {
-		sig := syscall.SIGTERM
-		sigName := "SIGTERM"
+		signal := "TERM"
 		if force {
-			sig = syscall.SIGKILL
-			sigName = "SIGKILL"
+			signal = "KILL"
 		}
-		err := syscall.Kill(int(pid), sig)
+		sigName := "SIG" + signal
+		err := source.Signal(context.Background(), pid, signal)
 		if err != nil {
 			return processKillResultMsg{message: fmt.Sprintf("Failed to kill PID %d: %v", pid, err)}
 		}
@@ -71,7 +114,7 @@ func (m *ResponsiveTUIModel) fetchData() tea.Cmd {
 		}
 
 		modules := []string{"cpu", "memory", "system", "processes"}
-		metrics, err := m.gops.GetMeta(context.Background(), modules, params)
+		metrics, err := m.source.Meta(context.Background(), modules, params)
 
 		if err != nil {
 			return fetchDataMsg{err: err, generation: generation}
@@ -104,21 +147,105 @@ func (m *ResponsiveTUIModel) fetchData() tea.Cmd {
 
 func (m *ResponsiveTUIModel) fetchNetworkData() tea.Cmd {
 	return func() tea.Msg {
-		rates, err := m.gops.GetNetworkRates(m.networkCursor)
+		rates, err := m.source.NetworkRates(context.Background(), m.networkCursor)
 		return fetchNetworkMsg{rates: rates, err: err}
 	}
 }
 
 func (m *ResponsiveTUIModel) fetchDiskData() tea.Cmd {
 	return func() tea.Msg {
-		rates, err := m.gops.GetDiskRates(m.diskCursor)
+		rates, err := m.source.DiskRates(context.Background(), m.diskCursor)
 		return fetchDiskMsg{rates: rates, err: err}
 	}
 }
 
 func (m *ResponsiveTUIModel) fetchTemperatureData() tea.Cmd {
 	return func() tea.Msg {
-		temps, err := m.gops.GetSystemTemperatures()
+		temps, err := m.source.Temperatures(context.Background())
 		return fetchTempMsg{temps: temps, err: err}
 	}
 }
+
+func (m *ResponsiveTUIModel) fetchFleetData() tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		info, err := source.Fleet(context.Background())
+		return fetchFleetMsg{fleet: info, err: err}
+	}
+}
+
+func (m *ResponsiveTUIModel) fetchUnitsData() tea.Cmd {
+	source := m.source
+	cursor := m.unitsCursor
+	return func() tea.Msg {
+		meta, err := source.Meta(context.Background(), []string{"units"}, gops.MetaParams{UnitsCursor: cursor})
+		if err != nil {
+			return fetchUnitsMsg{err: err}
+		}
+		if e, ok := meta.Errors["units"]; ok {
+			return fetchUnitsMsg{err: fmt.Errorf("%s: %s", e.Code, e.Message)}
+		}
+		return fetchUnitsMsg{units: meta.Units}
+	}
+}
+
+func (m *ResponsiveTUIModel) fetchEventsData() tea.Cmd {
+	source := m.source
+	cursor := m.eventsCursor
+	return func() tea.Msg {
+		meta, err := source.Meta(context.Background(), []string{"proc-events"}, gops.MetaParams{ProcEventsCursor: cursor})
+		if err != nil {
+			return fetchEventsMsg{cursor: cursor, err: err}
+		}
+		if e, ok := meta.Errors["proc-events"]; ok {
+			return fetchEventsMsg{cursor: cursor, err: fmt.Errorf("%s: %s", e.Code, e.Message)}
+		}
+		return fetchEventsMsg{cursor: cursor, events: meta.ProcEvents}
+	}
+}
+
+func (m *ResponsiveTUIModel) fetchAnomaliesData() tea.Cmd {
+	source := m.source
+	cursor := m.anomaliesCursor
+	return func() tea.Msg {
+		meta, err := source.Meta(context.Background(), []string{"anomalies"}, gops.MetaParams{AnomaliesCursor: cursor})
+		if err != nil {
+			return fetchAnomaliesMsg{cursor: cursor, err: err}
+		}
+		if e, ok := meta.Errors["anomalies"]; ok {
+			return fetchAnomaliesMsg{cursor: cursor, err: fmt.Errorf("%s: %s", e.Code, e.Message)}
+		}
+		return fetchAnomaliesMsg{cursor: cursor, anomalies: meta.Anomalies}
+	}
+}
+
+func (m *ResponsiveTUIModel) fetchThreadsData() tea.Cmd {
+	source := m.source
+	pid, cursor := m.threadsPID, m.threadsCursor
+	return func() tea.Msg {
+		threads, err := source.Threads(context.Background(), pid, cursor)
+		return fetchThreadsMsg{pid: pid, threads: threads, err: err}
+	}
+}
+
+func (m *ResponsiveTUIModel) fetchDetailsData(pid int32) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		details, err := source.ProcessDetails(context.Background(), pid)
+		return fetchDetailsMsg{pid: pid, details: details, err: err}
+	}
+}
+
+func (m *ResponsiveTUIModel) evaluateAlerts() tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		firing, err := source.FiringAlerts(context.Background())
+		return alertsMsg{firing: firing, err: err}
+	}
+}
+
+func (m *ResponsiveTUIModel) scheduleAlerts() tea.Cmd {
+	return tea.Tick(m.source.AlertInterval(), func(time.Time) tea.Msg {
+		return alertTickMsg{}
+	})
+}
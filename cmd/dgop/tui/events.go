@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxEventLog is how many process events the log keeps.
+const maxEventLog = 200
+
+// logEvents appends the events of a reply to the log and moves the cursor
+// on.
+func (m *ResponsiveTUIModel) logEvents(events *models.ProcessEventsResponse) {
+	m.eventsCursor = events.Cursor
+	m.eventsSource = events.Source
+	m.eventLog = append(m.eventLog, events.Events...)
+	if n := len(m.eventLog) - maxEventLog; n > 0 {
+		m.eventLog = append([]*models.ProcessEvent(nil), m.eventLog[n:]...)
+	}
+}
+
+func (m *ResponsiveTUIModel) renderEventsPanel(width, height int) string {
+	style := m.panelStyle(width, height)
+	colors := m.getColors()
+	titleStyle := m.titleStyle()
+	innerWidth := width - 4
+	maxLines := height - 2
+
+	var lines []string
+
+	if m.eventsSource == "" {
+		lines = append(lines, titleStyle.Render("PROCESS EVENTS"))
+		if m.eventsErr != nil {
+			lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.eventsErr), innerWidth))
+		} else {
+			lines = append(lines, "Loading...")
+		}
+		return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+	}
+
+	lines = append(lines, titleStyle.Render(m.truncate(fmt.Sprintf("PROCESS EVENTS (%d) from %s, newest first", len(m.eventLog), m.eventsSource), innerWidth)))
+	if m.eventsErr != nil {
+		lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.eventsErr), innerWidth))
+	}
+
+	nameWidth := innerWidth - 56
+	if nameWidth < 8 {
+		nameWidth = 8
+	}
+	rowFormat := fmt.Sprintf("%%-8s %%-7s %%-7s %%-7s %%-%ds %%7s %%6s %%8s %%9s", nameWidth)
+	header := fmt.Sprintf(rowFormat, "TIME", "EVENT", "PID", "PPID", "COMMAND", "RUNTIME", "CPU", "MEM", "EXIT")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(m.truncate(header, innerWidth)))
+
+	spawnedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Status.Success))
+	failedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Status.Error))
+
+	if len(m.eventLog) == 0 {
+		lines = append(lines, "No processes started or exited yet")
+	}
+	for i := len(m.eventLog) - 1; i >= 0; i-- {
+		e := m.eventLog[i]
+		var runtime, cpu, mem, exit string
+		if e.Type == models.ProcessExited {
+			runtime = fmt.Sprintf("%.1fs", e.Runtime)
+			cpu = fmt.Sprintf("%.1f%%", e.CPU)
+			switch {
+			case e.Signal != 0:
+				exit = fmt.Sprintf("sig %d", e.Signal)
+			case e.ExitCode != nil:
+				exit = fmt.Sprintf("code %d", *e.ExitCode)
+			}
+		}
+		if e.MemoryKB > 0 {
+			mem = m.formatBytes(e.MemoryKB * 1024)
+		}
+		row := fmt.Sprintf(rowFormat,
+			time.UnixMilli(e.Time).Format("15:04:05"),
+			e.Type,
+			fmt.Sprintf("%d", e.PID),
+			fmt.Sprintf("%d", e.PPID),
+			m.truncate(e.Command, nameWidth),
+			runtime,
+			cpu,
+			mem,
+			exit)
+		row = m.truncate(row, innerWidth)
+		switch {
+		case e.Type == models.ProcessSpawned:
+			row = spawnedStyle.Render(row)
+		case e.Signal != 0 || (e.ExitCode != nil && *e.ExitCode != 0):
+			row = failedStyle.Render(row)
+		}
+		lines = append(lines, row)
+	}
+
+	return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+}
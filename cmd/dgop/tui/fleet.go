@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *ResponsiveTUIModel) renderFleetPanel(width, height int) string {
+	style := m.panelStyle(width, height)
+	colors := m.getColors()
+	titleStyle := m.titleStyle()
+	innerWidth := width - 4
+	maxLines := height - 2
+
+	var lines []string
+
+	if m.fleet == nil {
+		lines = append(lines, titleStyle.Render("FLEET"))
+		switch {
+		case m.fleetErr != nil:
+			lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.fleetErr), innerWidth))
+		case m.fleetUnavailable:
+			lines = append(lines,
+				"Fleet mode is not enabled.",
+				"Run `dgop top --peers host1,host2`, or attach with",
+				"--remote to a server started with --peers.")
+		default:
+			lines = append(lines, "Loading...")
+		}
+		return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+	}
+
+	healthy := 0
+	for _, p := range m.fleet.Peers {
+		if p.Healthy {
+			healthy++
+		}
+	}
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("FLEET (%d/%d healthy)", healthy, len(m.fleet.Peers))))
+
+	hostWidth := innerWidth - 52
+	if hostWidth < 8 {
+		hostWidth = 8
+	}
+	rowFormat := fmt.Sprintf("%%-%ds %%-5s %%6s %%6s %%6s %%6s %%-18s", hostWidth)
+	header := fmt.Sprintf(rowFormat, "HOST", "STATE", "CPU", "TEMP", "MEM", "LOAD", "FULLEST DISK")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(m.truncate(header, innerWidth)))
+
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Status.Success))
+	downStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Status.Error))
+
+	for _, peer := range m.fleet.Peers {
+		meta := m.fleet.Hosts[peer.Host]
+		host := m.truncate(peer.Host, hostWidth)
+		if !peer.Healthy || meta == nil {
+			row := fmt.Sprintf(rowFormat, host, "down", "-", "-", "-", "-", m.truncate(peer.Error, 18))
+			lines = append(lines, downStyle.Render(m.truncate(row, innerWidth)))
+			continue
+		}
+
+		cpu, temp, mem, load, disk := "-", "-", "-", "-", "-"
+		if meta.CPU != nil {
+			cpu = fmt.Sprintf("%.0f%%", meta.CPU.Usage)
+			if meta.CPU.Temperature > 0 {
+				temp = fmt.Sprintf("%.0f°C", meta.CPU.Temperature)
+			}
+		}
+		if meta.Memory != nil {
+			mem = fmt.Sprintf("%.0f%%", meta.Memory.UsedPercent)
+		}
+		if meta.System != nil {
+			if fields := strings.Fields(meta.System.LoadAvg); len(fields) > 0 {
+				load = fields[0]
+			}
+		}
+		if fullest := fullestMount(meta.DiskMounts); fullest != nil {
+			disk = fmt.Sprintf("%s %s", fullest.Percent, fullest.Mount)
+		}
+
+		row := fmt.Sprintf(rowFormat, host, okStyle.Render("up   "), cpu, temp, mem, load, m.truncate(disk, 18))
+		lines = append(lines, row)
+	}
+
+	if procs := m.fleet.Summary.TopProcesses; len(procs) > 0 && len(lines)+3 <= maxLines {
+		lines = append(lines, "", titleStyle.Render("TOP PROCESSES"))
+		for _, p := range procs {
+			row := fmt.Sprintf("%-*s %7d %6.1f%%  %s", hostWidth, m.truncate(p.Host, hostWidth), p.PID, p.CPU, p.Command)
+			lines = append(lines, m.truncate(row, innerWidth))
+		}
+	}
+
+	return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+}
+
+func fullestMount(mounts []*models.DiskMountInfo) *models.DiskMountInfo {
+	var fullest *models.DiskMountInfo
+	var best float64 = -1
+	for _, d := range mounts {
+		var pct float64
+		if _, err := fmt.Sscanf(d.Percent, "%f", &pct); err != nil {
+			continue
+		}
+		if pct > best {
+			best = pct
+			fullest = d
+		}
+	}
+	return fullest
+}
+
+func limitLines(lines []string, max int) []string {
+	if max > 0 && len(lines) > max {
+		return lines[:max]
+	}
+	return lines
+}
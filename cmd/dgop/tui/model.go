@@ -30,8 +30,8 @@ type DiskSample struct {
 
 type tickMsg time.Time
 
-func tick() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+func tick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -50,9 +50,13 @@ func defaultResolvedKeybinds() map[string]models.KeyAction {
 }
 
 type ResponsiveTUIModel struct {
-	gops           *gops.GopsUtil
+	source         DataSource
+	remote         bool
 	colorManager   *config.ColorManager
 	keybindManager *config.KeybindManager
+	settings       *config.Manager
+	configErr      error
+	sampling       config.SamplingConfig
 	keybinds       map[string]models.KeyAction
 	metrics        *models.SystemMetrics
 	width          int
@@ -113,6 +117,51 @@ type ResponsiveTUIModel struct {
 	searchActive bool
 	searchInput  string
 	searchQuery  string
+
+	firingAlerts []models.Alert
+
+	showFleet        bool
+	fleet            *models.FleetInfo
+	fleetErr         error
+	fleetUnavailable bool
+	lastFleetUpdate  time.Time
+
+	showUnits       bool
+	units           *models.UnitsResponse
+	unitsErr        error
+	unitsCursor     string
+	lastUnitsUpdate time.Time
+
+	// showThreads replaces the process table with the threads of
+	// threadsPID, like htop's H.
+	showThreads       bool
+	threadsPID        int32
+	threadsName       string
+	threads           *models.ThreadListResponse
+	threadsErr        error
+	threadsCursor     string
+	lastThreadsUpdate time.Time
+
+	// eventLog holds the latest process events, oldest first, kept while
+	// the panel is hidden so reopening it continues where it left off.
+	showEvents       bool
+	eventLog         []*models.ProcessEvent
+	eventsSource     models.ProcessEventSource
+	eventsErr        error
+	eventsCursor     string
+	lastEventsUpdate time.Time
+
+	// anomalies feeds the alerts strip above the footer, refreshed
+	// whichever panel is showing.
+	anomalies           []*models.Anomaly
+	anomaliesCursor     string
+	lastAnomaliesUpdate time.Time
+
+	// details is the extended view of the process the details panel shows,
+	// fetched for detailsPID.
+	details           *models.ProcessDetails
+	detailsPID        int32
+	lastDetailsUpdate time.Time
 }
 
 func (m *ResponsiveTUIModel) Cleanup() {
@@ -129,6 +178,12 @@ func (m *ResponsiveTUIModel) Cleanup() {
 	}
 }
 
+// tickInterval is the finest sampling interval; each kind of data is fetched
+// on the first tick after its own interval has passed.
+func (m *ResponsiveTUIModel) tickInterval() time.Duration {
+	return min(time.Second, m.sampling.Refresh, m.sampling.Network, m.sampling.Disk)
+}
+
 func (m *ResponsiveTUIModel) action(key string) models.KeyAction {
 	if m.keybinds == nil {
 		return ""
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AvengeMedia/dgop/gops"
 	"github.com/AvengeMedia/dgop/models"
@@ -159,7 +161,69 @@ func (m *ResponsiveTUIModel) sortProcessesLocally() {
 		sort.Slice(processes, func(i, j int) bool {
 			return processes[i].PID < processes[j].PID
 		})
+	case gops.SortByPSS:
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].PSSKB > processes[j].PSSKB
+		})
 	}
 
 	m.metrics.Processes = processes
 }
+
+// detailsTarget returns the PID the details panel should show extended
+// details for, or 0 while the panel is hidden.
+func (m *ResponsiveTUIModel) detailsTarget() int32 {
+	if !m.showDetails || m.showFleet || m.showUnits || m.showThreads {
+		return 0
+	}
+	visible := m.visibleProcesses()
+	if cursor := m.processTable.Cursor(); cursor < len(visible) {
+		return visible[cursor].PID
+	}
+	return 0
+}
+
+// writeExtendedDetails adds the fields only GetProcessDetails reads to the
+// details panel, most useful first since the panel cuts off what doesn't fit.
+func (m *ResponsiveTUIModel) writeExtendedDetails(content *strings.Builder, d *models.ProcessDetails, maxWidth int) {
+	fmt.Fprintf(content, "State: %s  Nice: %d  Prio: %d\n", d.State, d.Nice, d.Priority)
+	if d.StartTime != "" {
+		fmt.Fprintf(content, "Started: %s (%s)\n", d.StartTime, formatAge(d.AgeSeconds))
+	}
+	if d.Memory != nil {
+		uss := d.Memory.PrivateClean + d.Memory.PrivateDirty
+		fmt.Fprintf(content, "PSS: %s  USS: %s  Swap: %s\n",
+			m.formatBytes(d.Memory.PSS*1024), m.formatBytes(uss*1024), m.formatBytes(d.Memory.Swap*1024))
+	}
+	if d.IO != nil {
+		fmt.Fprintf(content, "I/O: read %s  write %s\n", m.formatBytes(d.IO.ReadBytes), m.formatBytes(d.IO.WriteBytes))
+	}
+	fmt.Fprintf(content, "FDs: %d  Ctx: %d vol / %d invol\n", d.NumFDs, d.VoluntaryCtxSwitches, d.InvoluntaryCtxSwitches)
+	fmt.Fprintf(content, "OOM: %d (adj %d)\n", d.OOMScore, d.OOMScoreAdj)
+	if d.Cwd != "" {
+		fmt.Fprintf(content, "Cwd: %s\n", truncateString(d.Cwd, maxWidth-5))
+	}
+	if d.CGroup != "" {
+		fmt.Fprintf(content, "Cgroup: %s\n", truncateString(d.CGroup, maxWidth-8))
+	}
+
+	threads := slices.Clone(d.Threads)
+	sort.SliceStable(threads, func(i, j int) bool { return threads[i].CPU > threads[j].CPU })
+	fmt.Fprintf(content, "Threads: %d\n", len(threads))
+	for _, t := range threads[:min(len(threads), 5)] {
+		fmt.Fprintf(content, "  %-7d %5.1f%% %s %s\n", t.TID, t.CPU, t.State, truncateString(t.Name, maxWidth-18))
+	}
+}
+
+// formatAge renders a duration in seconds as its two largest units.
+func formatAge(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/gops"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type configUpdateMsg struct{}
+
+// UseConfig applies settings and keeps following its reloads. Process
+// defaults only apply here, so a reload doesn't undo sorting and grouping
+// changed from the keyboard.
+func (m *ResponsiveTUIModel) UseConfig(settings *config.Manager) {
+	m.settings = settings
+	cfg := settings.Get()
+
+	m.sortBy = gops.ProcSortBy(cfg.Defaults.Sort)
+	m.procLimit = cfg.Defaults.Limit
+	m.mergeChildren = cfg.Defaults.MergeChildren
+	m.showDetails = cfg.TUI.ShowDetails
+	m.applyConfig(cfg)
+}
+
+func (m *ResponsiveTUIModel) applyConfig(cfg *config.Config) {
+	m.sampling = cfg.Sampling
+	m.hideCPUCores = cfg.TUI.HideCPUCores
+	m.summarizeCores = cfg.TUI.SummarizeCores
+	if local, ok := m.source.(*LocalSource); ok {
+		local.gops.SetDeviceFilter(cfg.Devices)
+		// The config was validated when it was loaded.
+		_ = local.gops.SetModuleTimeouts(cfg.Timeouts)
+	}
+
+	m.maxNetHistory = cfg.History.NetworkSamples
+	if over := len(m.networkHistory) - m.maxNetHistory; over > 0 {
+		m.networkHistory = m.networkHistory[over:]
+	}
+	m.maxDiskHistory = cfg.History.DiskSamples
+	if over := len(m.diskHistory) - m.maxDiskHistory; over > 0 {
+		m.diskHistory = m.diskHistory[over:]
+	}
+}
+
+func (m *ResponsiveTUIModel) listenForConfigChanges() tea.Cmd {
+	return func() tea.Msg {
+		<-m.settings.Changes()
+		return configUpdateMsg{}
+	}
+}
+
+func (m *ResponsiveTUIModel) reloadConfig() {
+	m.configErr = m.settings.Err()
+	if m.configErr == nil {
+		m.applyConfig(m.settings.Get())
+	}
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AvengeMedia/dgop/alerts"
+	"github.com/AvengeMedia/dgop/api/client"
+	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/fleet"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const (
+	remoteAlertInterval = 10 * time.Second
+	fleetSummaryLimit   = 10
+)
+
+// DataSource supplies the TUI with metrics, either from this machine or from
+// a remote dgop API server.
+type DataSource interface {
+	Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error)
+	NetworkRates(ctx context.Context, cursor string) (*models.NetworkRateResponse, error)
+	DiskRates(ctx context.Context, cursor string) (*models.DiskRateResponse, error)
+	DiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error)
+	Temperatures(ctx context.Context) ([]models.TemperatureSensor, error)
+	Hardware(ctx context.Context) (*models.SystemHardware, error)
+	ProcessDetails(ctx context.Context, pid int32) (*models.ProcessDetails, error)
+	Threads(ctx context.Context, pid int32, cursor string) (*models.ThreadListResponse, error)
+	Signal(ctx context.Context, pid int32, signal string) error
+
+	// FiringAlerts returns the alerts that are currently firing.
+	FiringAlerts(ctx context.Context) ([]models.Alert, error)
+	// AlertInterval is how often FiringAlerts should be polled, or zero
+	// when there are no alerts to show.
+	AlertInterval() time.Duration
+
+	// Fleet returns the federated hosts, or nil when fleet mode isn't
+	// available from this source.
+	Fleet(ctx context.Context) (*models.FleetInfo, error)
+}
+
+// LocalSource reads metrics from this machine.
+type LocalSource struct {
+	gops   *gops.GopsUtil
+	alerts *alerts.Engine
+	fleet  *fleet.Fleet
+}
+
+func NewLocalSource(gopsUtil *gops.GopsUtil) *LocalSource {
+	src := &LocalSource{gops: gopsUtil}
+
+	// The TUI only displays alert state; actions are left to `dgop server`
+	// and `dgop watch` so they don't fire twice.
+	if alertsCfg, err := config.LoadAlertsConfig(""); err == nil {
+		if engine, err := alerts.NewEngine(gopsUtil, alertsCfg, alerts.WithoutActions()); err == nil && !engine.Empty() {
+			src.alerts = engine
+		}
+	}
+
+	return src
+}
+
+// SetFleet enables the fleet view. The caller is responsible for running f.
+func (s *LocalSource) SetFleet(f *fleet.Fleet) {
+	s.fleet = f
+}
+
+func (s *LocalSource) Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error) {
+	return s.gops.GetMeta(ctx, modules, params)
+}
+
+func (s *LocalSource) NetworkRates(ctx context.Context, cursor string) (*models.NetworkRateResponse, error) {
+	return s.gops.GetNetworkRates(ctx, cursor)
+}
+
+func (s *LocalSource) DiskRates(ctx context.Context, cursor string) (*models.DiskRateResponse, error) {
+	return s.gops.GetDiskRates(ctx, cursor)
+}
+
+func (s *LocalSource) DiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error) {
+	return s.gops.GetDiskMounts(ctx)
+}
+
+func (s *LocalSource) Temperatures(ctx context.Context) ([]models.TemperatureSensor, error) {
+	return s.gops.GetSystemTemperatures(ctx)
+}
+
+func (s *LocalSource) Hardware(ctx context.Context) (*models.SystemHardware, error) {
+	return s.gops.GetSystemHardware(ctx)
+}
+
+func (s *LocalSource) ProcessDetails(ctx context.Context, pid int32) (*models.ProcessDetails, error) {
+	return s.gops.GetProcessDetails(ctx, pid, false)
+}
+
+func (s *LocalSource) Threads(ctx context.Context, pid int32, cursor string) (*models.ThreadListResponse, error) {
+	return s.gops.GetThreads(ctx, pid, 0, cursor)
+}
+
+func (s *LocalSource) Signal(_ context.Context, pid int32, signal string) error {
+	sig, err := gops.ParseSignal(signal)
+	if err != nil {
+		return err
+	}
+	return s.gops.SignalProcess(pid, sig)
+}
+
+func (s *LocalSource) FiringAlerts(ctx context.Context) ([]models.Alert, error) {
+	if s.alerts == nil {
+		return nil, nil
+	}
+	if _, err := s.alerts.Tick(ctx); err != nil {
+		return nil, err
+	}
+	return s.alerts.Firing(), nil
+}
+
+func (s *LocalSource) AlertInterval() time.Duration {
+	if s.alerts == nil {
+		return 0
+	}
+	return s.alerts.Interval()
+}
+
+func (s *LocalSource) Fleet(_ context.Context) (*models.FleetInfo, error) {
+	if s.fleet == nil {
+		return nil, nil
+	}
+	return s.fleet.Info(fleetSummaryLimit), nil
+}
+
+// RemoteSource reads metrics from a dgop API server. Cursors round-trip
+// through the server the same way they do for local sampling.
+type RemoteSource struct {
+	client *client.Client
+}
+
+func NewRemoteSource(c *client.Client) *RemoteSource {
+	return &RemoteSource{client: c}
+}
+
+func (s *RemoteSource) Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error) {
+	return s.client.Meta(ctx, modules, params)
+}
+
+func (s *RemoteSource) NetworkRates(ctx context.Context, cursor string) (*models.NetworkRateResponse, error) {
+	return s.client.NetworkRates(ctx, cursor)
+}
+
+func (s *RemoteSource) DiskRates(ctx context.Context, cursor string) (*models.DiskRateResponse, error) {
+	return s.client.DiskRates(ctx, cursor)
+}
+
+func (s *RemoteSource) DiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error) {
+	return s.client.DiskMounts(ctx)
+}
+
+func (s *RemoteSource) Temperatures(ctx context.Context) ([]models.TemperatureSensor, error) {
+	return s.client.Temperatures(ctx)
+}
+
+func (s *RemoteSource) Hardware(ctx context.Context) (*models.SystemHardware, error) {
+	return s.client.Hardware(ctx)
+}
+
+func (s *RemoteSource) ProcessDetails(ctx context.Context, pid int32) (*models.ProcessDetails, error) {
+	return s.client.ProcessDetails(ctx, pid, false)
+}
+
+func (s *RemoteSource) Threads(ctx context.Context, pid int32, cursor string) (*models.ThreadListResponse, error) {
+	return s.client.Threads(ctx, pid, cursor)
+}
+
+func (s *RemoteSource) Signal(ctx context.Context, pid int32, signal string) error {
+	return s.client.Signal(ctx, pid, signal)
+}
+
+func (s *RemoteSource) FiringAlerts(ctx context.Context) ([]models.Alert, error) {
+	info, err := s.client.Alerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var firing []models.Alert
+	for _, a := range info.Alerts {
+		if a.State == models.AlertFiring {
+			firing = append(firing, a)
+		}
+	}
+	return firing, nil
+}
+
+func (s *RemoteSource) AlertInterval() time.Duration {
+	return remoteAlertInterval
+}
+
+func (s *RemoteSource) Fleet(ctx context.Context) (*models.FleetInfo, error) {
+	info, err := s.client.Fleet(ctx, fleetSummaryLimit)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	return info, err
+}
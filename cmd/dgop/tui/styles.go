@@ -72,46 +72,9 @@ func (m *ResponsiveTUIModel) footerStyle() lipgloss.Style {
 }
 
 func (m *ResponsiveTUIModel) getProgressBarColor(usage float64, colorType string) string {
-	colors := m.getColors()
-
-	switch colorType {
-	case "memory":
-		if usage > 80 {
-			return colors.ProgressBars.MemoryHigh
-		} else if usage > 60 {
-			return colors.ProgressBars.MemoryMedium
-		}
-		return colors.ProgressBars.MemoryLow
-	case "disk":
-		if usage > 90 {
-			return colors.ProgressBars.DiskHigh
-		} else if usage > 70 {
-			return colors.ProgressBars.DiskMedium
-		}
-		return colors.ProgressBars.DiskLow
-	case "cpu":
-		if usage > 80 {
-			return colors.ProgressBars.CPUHigh
-		} else if usage > 60 {
-			return colors.ProgressBars.CPUMedium
-		}
-		return colors.ProgressBars.CPULow
-	default:
-		return colors.ProgressBars.MemoryLow
-	}
+	return m.getColors().UsageColor(colorType, models.UsageLevel(colorType, usage))
 }
 
 func (m *ResponsiveTUIModel) getTemperatureColor(temp float64) string {
-	colors := m.getColors()
-
-	switch {
-	case temp > 85:
-		return colors.Temperature.Danger
-	case temp > 70:
-		return colors.Temperature.Hot
-	case temp > 50:
-		return colors.Temperature.Warm
-	default:
-		return colors.Temperature.Cold
-	}
+	return m.getColors().TemperatureColor(temp)
 }
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *ResponsiveTUIModel) renderThreadsPanel(width, height int) string {
+	style := m.panelStyle(width, height)
+	titleStyle := m.titleStyle()
+	innerWidth := width - 4
+	maxLines := height - 2
+
+	title := fmt.Sprintf("THREADS of %s (%d)", m.threadsName, m.threadsPID)
+	var lines []string
+
+	if m.threads == nil {
+		lines = append(lines, titleStyle.Render(m.truncate(title, innerWidth)))
+		if m.threadsErr != nil {
+			lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.threadsErr), innerWidth))
+		} else {
+			lines = append(lines, "Loading...")
+		}
+		return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+	}
+
+	title = fmt.Sprintf("THREADS of %s (%d): %d by CPU", m.threadsName, m.threadsPID, len(m.threads.Threads))
+	lines = append(lines, titleStyle.Render(m.truncate(title, innerWidth)))
+	if m.threadsErr != nil {
+		lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.threadsErr), innerWidth))
+	}
+
+	nameWidth := innerWidth - 29
+	if nameWidth < 8 {
+		nameWidth = 8
+	}
+	rowFormat := fmt.Sprintf("%%-8s %%-5s %%7s %%5s  %%-%ds", nameWidth)
+	header := fmt.Sprintf(rowFormat, "TID", "STATE", "CPU", "CPU#", "NAME")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(m.truncate(header, innerWidth)))
+
+	for _, t := range m.threads.Threads {
+		row := fmt.Sprintf(rowFormat,
+			fmt.Sprintf("%d", t.TID),
+			t.State,
+			fmt.Sprintf("%.1f%%", t.CPU),
+			fmt.Sprintf("%d", t.LastCPU),
+			m.truncate(t.Name, nameWidth))
+		lines = append(lines, m.truncate(row, innerWidth))
+	}
+
+	return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -15,10 +16,10 @@ import (
 var Version = "dev"
 
 func (m *ResponsiveTUIModel) Init() tea.Cmd {
-	diskMounts, _ := m.gops.GetDiskMounts()
+	diskMounts, _ := m.source.DiskMounts(context.Background())
 	m.diskMounts = diskMounts
 
-	cmds := []tea.Cmd{tick(), m.fetchData(), m.fetchTemperatureData()}
+	cmds := []tea.Cmd{tick(m.tickInterval()), m.fetchData(), m.fetchTemperatureData()}
 
 	if m.colorManager != nil {
 		cmds = append(cmds, m.listenForColorChanges())
@@ -28,6 +29,14 @@ func (m *ResponsiveTUIModel) Init() tea.Cmd {
 		cmds = append(cmds, m.listenForKeybindChanges())
 	}
 
+	if m.settings != nil {
+		cmds = append(cmds, m.listenForConfigChanges())
+	}
+
+	if m.source.AlertInterval() > 0 {
+		cmds = append(cmds, m.evaluateAlerts())
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -80,7 +89,7 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				force := m.killConfirmSelection == 1
 				m.killConfirmPID = 0
 				m.killConfirmSelection = 0
-				return m, killProcess(pid, force)
+				return m, m.killProcess(pid, force)
 			}
 			return m, nil
 		}
@@ -123,6 +132,57 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.fetchData()
 		case models.ActionDetails:
 			m.showDetails = !m.showDetails
+		case models.ActionFleet:
+			m.showFleet = !m.showFleet
+			if m.showFleet {
+				m.showUnits = false
+				m.showThreads = false
+				m.showEvents = false
+				m.lastFleetUpdate = time.Now()
+				return m, m.fetchFleetData()
+			}
+			return m, nil
+		case models.ActionThreads:
+			if m.showThreads {
+				m.showThreads = false
+				return m, nil
+			}
+			visible := m.visibleProcesses()
+			cursor := m.processTable.Cursor()
+			if cursor >= len(visible) {
+				return m, nil
+			}
+			m.showThreads = true
+			m.showFleet = false
+			m.showUnits = false
+			m.showEvents = false
+			m.threadsPID = visible[cursor].PID
+			m.threadsName = visible[cursor].Command
+			m.threads = nil
+			m.threadsErr = nil
+			m.threadsCursor = ""
+			m.lastThreadsUpdate = time.Now()
+			return m, m.fetchThreadsData()
+		case models.ActionUnits:
+			m.showUnits = !m.showUnits
+			if m.showUnits {
+				m.showFleet = false
+				m.showThreads = false
+				m.showEvents = false
+				m.lastUnitsUpdate = time.Now()
+				return m, m.fetchUnitsData()
+			}
+			return m, nil
+		case models.ActionEvents:
+			m.showEvents = !m.showEvents
+			if m.showEvents {
+				m.showFleet = false
+				m.showUnits = false
+				m.showThreads = false
+				m.lastEventsUpdate = time.Now()
+				return m, m.fetchEventsData()
+			}
+			return m, nil
 		case models.ActionSearch:
 			m.searchActive = true
 			m.searchInput = ""
@@ -194,24 +254,55 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tickMsg:
-		cmds = append(cmds, tick())
+		cmds = append(cmds, tick(m.tickInterval()))
 		now := time.Now()
 
-		if now.Sub(m.lastUpdate) >= time.Second {
+		if now.Sub(m.lastUpdate) >= m.sampling.Refresh {
 			cmds = append(cmds, m.fetchData())
 		}
 
-		if now.Sub(m.lastNetworkUpdate) >= 2*time.Second {
+		if now.Sub(m.lastNetworkUpdate) >= m.sampling.Network {
 			cmds = append(cmds, m.fetchNetworkData())
 			m.lastNetworkUpdate = now
 		}
 
-		if now.Sub(m.lastDiskUpdate) >= 2*time.Second {
+		if now.Sub(m.lastDiskUpdate) >= m.sampling.Disk {
 			cmds = append(cmds, m.fetchDiskData())
 			m.lastDiskUpdate = now
 		}
 
-		if now.Sub(m.lastTempUpdate) >= 10*time.Second {
+		if m.showFleet && now.Sub(m.lastFleetUpdate) >= 2*time.Second {
+			cmds = append(cmds, m.fetchFleetData())
+			m.lastFleetUpdate = now
+		}
+
+		if m.showUnits && now.Sub(m.lastUnitsUpdate) >= 2*time.Second {
+			cmds = append(cmds, m.fetchUnitsData())
+			m.lastUnitsUpdate = now
+		}
+
+		if now.Sub(m.lastAnomaliesUpdate) >= 5*time.Second {
+			cmds = append(cmds, m.fetchAnomaliesData())
+			m.lastAnomaliesUpdate = now
+		}
+
+		if m.showEvents && now.Sub(m.lastEventsUpdate) >= 2*time.Second {
+			cmds = append(cmds, m.fetchEventsData())
+			m.lastEventsUpdate = now
+		}
+
+		if m.showThreads && now.Sub(m.lastThreadsUpdate) >= 2*time.Second {
+			cmds = append(cmds, m.fetchThreadsData())
+			m.lastThreadsUpdate = now
+		}
+
+		if pid := m.detailsTarget(); pid > 0 && (pid != m.detailsPID || now.Sub(m.lastDetailsUpdate) >= 2*time.Second) {
+			cmds = append(cmds, m.fetchDetailsData(pid))
+			m.detailsPID = pid
+			m.lastDetailsUpdate = now
+		}
+
+		if now.Sub(m.lastTempUpdate) >= m.sampling.Temperature {
 			cmds = append(cmds, m.fetchTemperatureData())
 			m.lastTempUpdate = now
 		}
@@ -243,6 +334,10 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateProcessTable()
 
 	case fetchNetworkMsg:
+		if msg.err != nil {
+			// Start a new baseline rather than resend a rejected cursor.
+			m.networkCursor = ""
+		}
 		if msg.rates != nil && len(msg.rates.Interfaces) > 0 {
 			m.networkCursor = msg.rates.Cursor
 
@@ -266,6 +361,9 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case fetchDiskMsg:
+		if msg.err != nil {
+			m.diskCursor = ""
+		}
 		// Force some data into history to test rendering
 		if len(m.diskHistory) == 0 {
 			// Add some initial samples to get started
@@ -326,11 +424,85 @@ func (m *ResponsiveTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateTableStyles()
 		cmds = append(cmds, m.listenForColorChanges())
 
+	case alertsMsg:
+		if msg.err == nil {
+			m.firingAlerts = msg.firing
+		}
+		cmds = append(cmds, m.scheduleAlerts())
+
+	case fetchFleetMsg:
+		m.fleetErr = msg.err
+		m.fleetUnavailable = msg.err == nil && msg.fleet == nil
+		if msg.err == nil {
+			m.fleet = msg.fleet
+		}
+
+	case fetchUnitsMsg:
+		m.unitsErr = msg.err
+		if gops.IsCursorError(msg.err) {
+			// Start a new baseline rather than resend a rejected cursor.
+			m.unitsCursor = ""
+		}
+		if msg.err == nil && msg.units != nil {
+			m.units = msg.units
+			m.unitsCursor = msg.units.Cursor
+		}
+
+	case fetchEventsMsg:
+		if msg.cursor == m.eventsCursor {
+			m.eventsErr = msg.err
+			if gops.IsCursorError(msg.err) {
+				// The next fetch takes a new baseline; events in between
+				// are lost.
+				m.eventsCursor = ""
+			}
+			if msg.err == nil && msg.events != nil {
+				m.logEvents(msg.events)
+			}
+		}
+
+	case fetchAnomaliesMsg:
+		// A server without the module, or a failed collection, leaves the
+		// strip as it was rather than taking room to say so. A rejected
+		// cursor clears it, as what it showed is no longer known.
+		if msg.cursor == m.anomaliesCursor && gops.IsCursorError(msg.err) {
+			m.anomaliesCursor = ""
+			m.anomalies = nil
+		}
+		if msg.cursor == m.anomaliesCursor && msg.err == nil && msg.anomalies != nil {
+			m.anomalies = msg.anomalies.Anomalies
+			m.anomaliesCursor = msg.anomalies.Cursor
+		}
+
+	case fetchThreadsMsg:
+		if msg.pid == m.threadsPID {
+			m.threadsErr = msg.err
+			if gops.IsCursorError(msg.err) {
+				m.threadsCursor = ""
+			}
+			if msg.err == nil {
+				m.threads = msg.threads
+				m.threadsCursor = msg.threads.Cursor
+			}
+		}
+
+	case fetchDetailsMsg:
+		if msg.pid == m.detailsPID {
+			m.details = msg.details
+		}
+
+	case alertTickMsg:
+		cmds = append(cmds, m.evaluateAlerts())
+
 	case keybindUpdateMsg:
 		if m.keybindManager != nil {
 			m.keybinds = m.keybindManager.Resolve()
 		}
 		cmds = append(cmds, m.listenForKeybindChanges())
+
+	case configUpdateMsg:
+		m.reloadConfig()
+		cmds = append(cmds, m.listenForConfigChanges())
 	}
 
 	return m, tea.Batch(cmds...)
@@ -368,8 +540,12 @@ func (m *ResponsiveTUIModel) renderTooSmall() string {
 func (m *ResponsiveTUIModel) renderLayout() string {
 	header := m.renderHeader()
 	footer := m.renderFooter()
+	strip := m.renderAnomalyStrip()
 	headerHeight := lipgloss.Height(header)
 	footerHeight := lipgloss.Height(footer)
+	if strip != "" {
+		footerHeight += lipgloss.Height(strip)
+	}
 
 	availableHeight := m.height - headerHeight - footerHeight
 	if availableHeight < 8 {
@@ -381,6 +557,9 @@ func (m *ResponsiveTUIModel) renderLayout() string {
 	var sections []string
 	sections = append(sections, header)
 	sections = append(sections, mainContent)
+	if strip != "" {
+		sections = append(sections, strip)
+	}
 	sections = append(sections, footer)
 
 	return strings.Join(sections, "\n")
@@ -515,8 +694,9 @@ func (m *ResponsiveTUIModel) renderMainContentWithHeight(availableHeight int) st
 
 	// Chrome calculation (full borders only - gaps are rendered but not budgeted)
 	leftPanels := 3
+	showDetails := m.showDetails && !m.showFleet && !m.showUnits && !m.showThreads && !m.showEvents
 	rightPanels := 2
-	if m.showDetails {
+	if showDetails {
 		rightPanels = 3
 	}
 
@@ -561,7 +741,7 @@ func (m *ResponsiveTUIModel) renderMainContentWithHeight(availableHeight int) st
 	detMax := 24
 
 	var rightHeights []int
-	if m.showDetails {
+	if showDetails {
 		rightSpecs := []panelSpec{
 			{cpuMin, cpuMax, 0},   // CPU: no flex
 			{procMin, procMax, 3}, // Processes: main flex
@@ -587,13 +767,22 @@ func (m *ResponsiveTUIModel) renderMainContentWithHeight(availableHeight int) st
 
 	cpuPanel := m.renderCPUPanel(rightWidth, rightHeights[0])
 	var processColumn string
-	if m.showDetails {
+	switch {
+	case m.showFleet:
+		processColumn = m.renderFleetPanel(rightWidth, rightHeights[1])
+	case m.showUnits:
+		processColumn = m.renderUnitsPanel(rightWidth, rightHeights[1])
+	case m.showThreads:
+		processColumn = m.renderThreadsPanel(rightWidth, rightHeights[1])
+	case m.showEvents:
+		processColumn = m.renderEventsPanel(rightWidth, rightHeights[1])
+	case showDetails:
 		processPanel := m.renderProcessPanel(rightWidth, rightHeights[1])
 		detailsPanel := m.renderProcessDetailsPanel(rightWidth, rightHeights[2])
 
 		// Stack with borders only
 		processColumn = lipgloss.JoinVertical(lipgloss.Left, processPanel, detailsPanel)
-	} else {
+	default:
 		// Processes get ALL the available space
 		processPanel := m.renderProcessPanel(rightWidth, rightHeights[1])
 		processColumn = processPanel
@@ -677,13 +866,50 @@ func (m *ResponsiveTUIModel) renderFooter() string {
 		groupStatus = "*"
 	}
 	k := m.hint
-	controls := fmt.Sprintf("Controls: [%s]uit [%s]efresh [%s]etails [%s]group%s [%s] kill [%s] search | Sort: [%s]cpu [%s]mem [%s]name [%s]pid | %s%s Navigate",
-		k(models.ActionQuit), k(models.ActionRefresh), k(models.ActionDetails), k(models.ActionGroup), groupStatus, k(models.ActionKill), k(models.ActionSearch),
+	controls := m.renderConfigError() + m.renderFiringAlerts() + fmt.Sprintf("Controls: [%s]uit [%s]efresh [%s]etails [%s]group%s [%s] kill [%s] search [%s]leet [%s]nits [%s] threads [%s]vents | Sort: [%s]cpu [%s]mem [%s]name [%s]pid | %s%s Navigate",
+		k(models.ActionQuit), k(models.ActionRefresh), k(models.ActionDetails), k(models.ActionGroup), groupStatus, k(models.ActionKill), k(models.ActionSearch), k(models.ActionFleet), k(models.ActionUnits), k(models.ActionThreads), k(models.ActionEvents),
 		k(models.ActionSortCPU), k(models.ActionSortMemory), k(models.ActionSortName), k(models.ActionSortPID),
 		k(models.ActionNavUp), k(models.ActionNavDown))
 	return style.Render(controls)
 }
 
+// renderConfigError returns a footer prefix while config.toml has an error
+// that kept it from being reloaded.
+func (m *ResponsiveTUIModel) renderConfigError() string {
+	if m.configErr == nil {
+		return ""
+	}
+
+	colors := m.getColors()
+	errStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(colors.Status.Warning)).
+		Background(lipgloss.Color(colors.UI.FooterBackground))
+	// Validation errors span several lines; the footer has one.
+	msg := strings.Join(strings.Fields(m.configErr.Error()), " ")
+	return errStyle.Render("⚠ config not reloaded: "+msg) + " | "
+}
+
+// renderFiringAlerts returns a footer prefix naming the firing alerts, or an
+// empty string when nothing is firing.
+func (m *ResponsiveTUIModel) renderFiringAlerts() string {
+	if len(m.firingAlerts) == 0 {
+		return ""
+	}
+
+	colors := m.getColors()
+	names := make([]string, 0, len(m.firingAlerts))
+	for _, a := range m.firingAlerts {
+		names = append(names, a.Name)
+	}
+
+	alertStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(colors.Status.Error)).
+		Background(lipgloss.Color(colors.UI.FooterBackground))
+	return alertStyle.Render(fmt.Sprintf("⚠ %d firing: %s", len(names), strings.Join(names, ", "))) + " | "
+}
+
 func (m *ResponsiveTUIModel) renderProcessPanel(width, height int) string {
 	style := m.panelStyle(width, height)
 
@@ -700,6 +926,8 @@ func (m *ResponsiveTUIModel) renderProcessPanel(width, height int) string {
 		sortIndicator = " ↓NAME"
 	case gops.SortByPID:
 		sortIndicator = " ↓PID"
+	case gops.SortByPSS:
+		sortIndicator = " ↓PSS"
 	}
 
 	processCount := len(m.visibleProcesses())
@@ -789,6 +1017,10 @@ func (m *ResponsiveTUIModel) renderProcessDetailsPanel(width, height int) string
 			} else {
 				fmt.Fprintf(&content, "Full Command: %s", proc.FullCommand)
 			}
+			if m.details != nil && m.details.PID == proc.PID {
+				content.WriteString("\n")
+				m.writeExtendedDetails(&content, m.details, width-6)
+			}
 		} else {
 			content.WriteString("No process selected")
 		}
@@ -796,7 +1028,12 @@ func (m *ResponsiveTUIModel) renderProcessDetailsPanel(width, height int) string
 		content.WriteString("Loading process data...")
 	}
 
-	return style.Render(content.String())
+	// The extended details can run past the panel; keep its size fixed.
+	contentStr := content.String()
+	if lines := strings.Split(contentStr, "\n"); height > 2 && len(lines) > height-2 {
+		contentStr = strings.Join(lines[:height-2], "\n")
+	}
+	return style.Render(contentStr)
 }
 
 func (m *ResponsiveTUIModel) renderNetworkPanel(width, height int) string {
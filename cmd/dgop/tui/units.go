@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *ResponsiveTUIModel) renderUnitsPanel(width, height int) string {
+	style := m.panelStyle(width, height)
+	colors := m.getColors()
+	titleStyle := m.titleStyle()
+	innerWidth := width - 4
+	maxLines := height - 2
+
+	var lines []string
+
+	if m.units == nil {
+		lines = append(lines, titleStyle.Render("UNITS"))
+		if m.unitsErr != nil {
+			lines = append(lines, m.truncate(fmt.Sprintf("Error: %v", m.unitsErr), innerWidth))
+		} else {
+			lines = append(lines, "Loading...")
+		}
+		return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+	}
+
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("UNITS (%d) by memory", len(m.units.Units))))
+
+	nameWidth := innerWidth - 46
+	if nameWidth < 12 {
+		nameWidth = 12
+	}
+	rowFormat := fmt.Sprintf("%%-%ds %%-8s %%6s %%9s %%9s %%5s %%4s", nameWidth)
+	header := fmt.Sprintf(rowFormat, "UNIT", "STATE", "CPU", "MEM", "I/O", "TASKS", "RST")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(m.truncate(header, innerWidth)))
+
+	failedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Status.Error))
+
+	for _, u := range m.units.Units {
+		io := m.formatBytes(uint64(u.IOReadRate+u.IOWriteRate)) + "/s"
+		row := fmt.Sprintf(rowFormat,
+			m.truncate(u.Name, nameWidth),
+			m.truncate(u.ActiveState, 8),
+			fmt.Sprintf("%.1f%%", u.CPU),
+			m.formatBytes(u.MemoryBytes),
+			io,
+			fmt.Sprintf("%d", u.Tasks),
+			fmt.Sprintf("%d", u.Restarts))
+		row = m.truncate(row, innerWidth)
+		if u.ActiveState == "failed" {
+			row = failedStyle.Render(row)
+		}
+		lines = append(lines, row)
+	}
+
+	return style.Render(strings.Join(limitLines(lines, maxLines), "\n"))
+}
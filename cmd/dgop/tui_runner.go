@@ -2,13 +2,15 @@ package main
 
 import (
 	"github.com/AvengeMedia/dgop/cmd/dgop/tui"
-	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/config"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func runTUIWithOptions(gopsUtil *gops.GopsUtil, hideCPUCores, summarizeCores bool) error {
+func runTUIWithSource(source tui.DataSource, settings *config.Manager) error {
 	tui.Version = Version
-	model := tui.NewResponsiveTUIModelWithOptions(gopsUtil, hideCPUCores, summarizeCores)
+	cfg := settings.Get()
+	model := tui.NewResponsiveTUIModelWithSource(source, cfg.TUI.HideCPUCores, cfg.TUI.SummarizeCores)
+	model.UseConfig(settings)
 	defer model.Cleanup()
 
 	p := tea.NewProgram(
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/alerts"
+	"github.com/AvengeMedia/dgop/config"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Evaluate alert rules in the foreground",
+	Long:  "Evaluate the alert rules from alerts.toml, run their actions and print every state change.",
+}
+
+func runWatchCommand(cmd *cobra.Command, gopsUtil *gops.GopsUtil) error {
+	alertsCfg, err := config.LoadAlertsConfig(alertsFile)
+	if err != nil {
+		return err
+	}
+
+	gopsUtil.EnableProcessTable()
+	engine, err := alerts.NewEngine(gopsUtil, alertsCfg)
+	if err != nil {
+		return fmt.Errorf("invalid alert rules: %w", err)
+	}
+	if engine.Empty() {
+		path := alertsFile
+		if path == "" {
+			path, _ = config.AlertsFilePath()
+		}
+		return fmt.Errorf("no alert rules configured in %s", path)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if !jsonOutput {
+		fmt.Println(titleStyle.Render("WATCHING ALERTS"))
+		fmt.Printf("  Evaluating every %s, Ctrl+C to stop\n\n", engine.Interval())
+	}
+
+	return engine.Run(ctx, printAlertTransition)
+}
+
+func printAlertTransition(t alerts.Transition) {
+	if jsonOutput {
+		_ = outputJSON(t.Alert)
+		return
+	}
+
+	color := "7"
+	switch t.Alert.State {
+	case models.AlertFiring:
+		color = "1"
+	case models.AlertPending:
+		color = "3"
+	case models.AlertResolved:
+		color = "2"
+	}
+	state := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color)).Render(fmt.Sprintf("%-8s", t.Alert.State))
+
+	fmt.Printf("%s %s %s %s (value %.2f)\n",
+		valueStyle.Render(time.Now().Format("15:04:05")),
+		state,
+		keyStyle.Render(t.Alert.Name),
+		valueStyle.Render(t.Alert.Expr),
+		t.Alert.Value)
+}
+
+func logAlertTransition(t alerts.Transition) {
+	log.Infof("alert %s: %s -> %s (value %.2f)", t.Alert.Name, t.From, t.Alert.State, t.Alert.Value)
+}
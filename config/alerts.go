@@ -0,0 +1,20 @@
+package config
+
+import (
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// AlertsFilePath returns the default location of the alert rules file.
+func AlertsFilePath() (string, error) {
+	return configFilePath("alerts.toml")
+}
+
+// LoadAlertsConfig reads alert rules from path, or from the default location
+// when path is empty. A missing file is not an error and yields no rules.
+func LoadAlertsConfig(path string) (*models.AlertsConfig, error) {
+	cfg := &models.AlertsConfig{}
+	if err := loadTOMLFile(path, "alerts.toml", cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// AuthFilePath returns the default location of the API credentials file.
+func AuthFilePath() (string, error) {
+	return configFilePath("auth.toml")
+}
+
+// LoadAuthConfig reads API tokens and client certificate scopes from path,
+// or from the default location when path is empty. A missing file yields no
+// credentials.
+func LoadAuthConfig(path string) (*models.AuthConfig, error) {
+	cfg := &models.AuthConfig{}
+	if err := loadTOMLFile(path, "auth.toml", cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -1,24 +1,285 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"log"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/AvengeMedia/dankgo/paths"
+	"github.com/AvengeMedia/dgop/api/session"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/BurntSushi/toml"
 	"github.com/caarlos0/env/v11"
 )
 
 var appPaths = paths.New("dgop")
 
+// Config is the effective configuration: built-in defaults, overlaid by
+// config.toml, overlaid by environment variables. Command-line flags are
+// applied on top by the commands themselves.
 type Config struct {
-	ApiPort string `env:"API_PORT" envDefault:":63484"` // Default port for the API server
+	ServerConfig `toml:"server"`
+	Defaults     DefaultsConfig      `toml:"defaults"`
+	Devices      models.DeviceFilter `toml:"devices"`
+	Sampling     SamplingConfig      `toml:"sampling"`
+	History      HistoryConfig       `toml:"history"`
+	TUI          TUIConfig           `toml:"tui"`
+	// Cache is how long the server reuses each module's result, applied
+	// without a restart.
+	Cache map[string]time.Duration `toml:"cache" env:"DGOP_CACHE"`
+	// Timeouts is how long a meta request waits for each module before
+	// reporting it as timed out.
+	Timeouts map[string]time.Duration `toml:"timeouts" env:"DGOP_TIMEOUTS"`
+}
+
+type ServerConfig struct {
+	ApiPort       string `toml:"port" env:"DGOP_PORT"`                   // Default port for the API server, also read from API_PORT
+	ApiBind       string `toml:"bind" env:"DGOP_BIND"`                   // Address to bind the port on, all interfaces when empty
+	ApiNoTCP      bool   `toml:"no_tcp" env:"DGOP_NO_TCP"`               // Only serve the unix socket
+	ApiSocket     string `toml:"socket" env:"DGOP_SOCKET"`               // Unix socket path
+	ApiSocketMode string `toml:"socket_mode" env:"DGOP_SOCKET_MODE"`     // Unix socket file mode
+	AllowActions  bool   `toml:"allow_actions" env:"DGOP_ALLOW_ACTIONS"` // Allow clients to signal processes
+	TLSCert       string `toml:"tls_cert" env:"DGOP_TLS_CERT"`           // Serve HTTPS with this certificate
+	TLSKey        string `toml:"tls_key" env:"DGOP_TLS_KEY"`             // Key for TLSCert
+	TLSClientCA   string `toml:"tls_client_ca" env:"DGOP_TLS_CLIENT_CA"` // Verify client certificates against this CA (mTLS)
+	AuthFile      string `toml:"auth_file" env:"DGOP_AUTH_FILE"`         // Bearer tokens and client scopes, default ~/.config/dgop/auth.toml
+
+	SessionTTL      time.Duration `toml:"session_ttl" env:"DGOP_SESSION_TTL"`             // Drop cursor sessions unused for this long
+	SessionMaxBytes int           `toml:"session_max_bytes" env:"DGOP_SESSION_MAX_BYTES"` // Memory cap for cursors held by sessions
+}
+
+// DefaultsConfig holds the defaults for the CLI's process and module flags.
+type DefaultsConfig struct {
+	Modules       []string `toml:"modules" env:"DGOP_MODULES"`
+	Sort          string   `toml:"sort" env:"DGOP_SORT"`
+	Limit         int      `toml:"limit" env:"DGOP_LIMIT"`
+	MergeChildren bool     `toml:"merge_children" env:"DGOP_MERGE_CHILDREN"`
+	NoCPU         bool     `toml:"no_cpu" env:"DGOP_NO_CPU"`
+	GPUPciIds     []string `toml:"gpu_pci_ids" env:"DGOP_GPU_PCI_IDS"`
+}
+
+// SamplingConfig sets how often the TUI refreshes each kind of data.
+type SamplingConfig struct {
+	Refresh     time.Duration `toml:"refresh" env:"DGOP_REFRESH_INTERVAL"`
+	Network     time.Duration `toml:"network" env:"DGOP_NETWORK_INTERVAL"`
+	Disk        time.Duration `toml:"disk" env:"DGOP_DISK_INTERVAL"`
+	Temperature time.Duration `toml:"temperature" env:"DGOP_TEMPERATURE_INTERVAL"`
+}
+
+// HistoryConfig sets how many samples the TUI graphs keep.
+type HistoryConfig struct {
+	NetworkSamples int `toml:"network_samples" env:"DGOP_NETWORK_HISTORY"`
+	DiskSamples    int `toml:"disk_samples" env:"DGOP_DISK_HISTORY"`
+}
+
+type TUIConfig struct {
+	HideCPUCores   bool `toml:"hide_cpu_cores" env:"DGOP_HIDE_CPU_CORES"`
+	SummarizeCores bool `toml:"summarize_cores" env:"DGOP_SUMMARIZE_CORES"`
+	ShowDetails    bool `toml:"show_details" env:"DGOP_SHOW_DETAILS"`
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		ServerConfig: ServerConfig{
+			ApiPort:         ":63484",
+			ApiSocketMode:   "0660",
+			SessionTTL:      session.DefaultTTL,
+			SessionMaxBytes: session.DefaultMaxMemory,
+		},
+		Defaults: DefaultsConfig{
+			Modules:       []string{"all"},
+			Sort:          string(gops.SortByCPU),
+			MergeChildren: true,
+		},
+		Sampling: SamplingConfig{
+			Refresh:     time.Second,
+			Network:     2 * time.Second,
+			Disk:        2 * time.Second,
+			Temperature: 10 * time.Second,
+		},
+		History: HistoryConfig{
+			NetworkSamples: 60,
+			DiskSamples:    60,
+		},
+		Cache:    maps.Clone(gops.DefaultCacheTTLs),
+		Timeouts: maps.Clone(gops.DefaultModuleTimeouts),
+	}
+}
+
+func ConfigFilePath() (string, error) {
+	return configFilePath("config.toml")
+}
+
+// Load builds the effective configuration from path, or config.toml in the
+// config directory when path is empty. A missing file is not an error, but
+// unknown keys are, so typos don't go unnoticed.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path == "" {
+		defaultPath, err := ConfigFilePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	default:
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			return nil, fmt.Errorf("%s: unknown keys: %s", path, strings.Join(keys, ", "))
+		}
+	}
+
+	if err := parseEnv(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse environment: %w", err)
+	}
+	return cfg, nil
+}
+
+// deprecatedEnv maps environment variables still read for compatibility
+// to the names that replaced them.
+var deprecatedEnv = map[string]string{
+	"API_PORT": "DGOP_PORT",
+}
+
+// parseEnv overlays the DGOP_* environment variables on cfg. A deprecated
+// name is read when its replacement isn't set.
+func parseEnv(cfg *Config) error {
+	environ := env.ToMap(os.Environ())
+	for old, name := range deprecatedEnv {
+		if value, ok := environ[old]; ok {
+			if _, set := environ[name]; !set {
+				environ[name] = value
+			}
+		}
+	}
+	return env.ParseWithOptions(cfg, env.Options{Environment: environ})
+}
+
+// Validate checks values that decode fine but can't work.
+func (c *Config) Validate() error {
+	var errs []string
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if _, err := strconv.ParseUint(c.ApiSocketMode, 8, 32); err != nil {
+		add("server.socket_mode: %q is not an octal file mode", c.ApiSocketMode)
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		add("server: tls_cert and tls_key must be set together")
+	}
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		add("server.tls_client_ca requires tls_cert and tls_key")
+	}
+	if c.ApiNoTCP && c.ApiSocket == "" {
+		add("server.no_tcp requires a socket")
+	}
+	// A session kept longer would hold cursors the server rejects as
+	// expired.
+	if c.SessionTTL < time.Second || c.SessionTTL > gops.CursorMaxAge {
+		add("server.session_ttl: %s is outside 1s to %s", c.SessionTTL, gops.CursorMaxAge)
+	}
+	if c.SessionMaxBytes < 64<<10 {
+		add("server.session_max_bytes: need at least 65536")
+	}
+
+	for _, m := range c.Defaults.Modules {
+		if !gops.IsModule(m) {
+			add("defaults.modules: unknown module %q", m)
+		}
+	}
+	sorts := []gops.ProcSortBy{gops.SortByCPU, gops.SortByMemory, gops.SortByName, gops.SortByPID, gops.SortByPSS}
+	if !slices.Contains(sorts, gops.ProcSortBy(c.Defaults.Sort)) {
+		add("defaults.sort: %q is not one of cpu, memory, name, pid, pss", c.Defaults.Sort)
+	}
+	if c.Defaults.Limit < 0 {
+		add("defaults.limit: must not be negative")
+	}
+
+	if err := gops.ValidateDeviceFilter(c.Devices); err != nil {
+		add("devices: %v", err)
+	}
+	if err := gops.ValidateCacheTTLs(c.Cache); err != nil {
+		add("cache: %v", err)
+	}
+	if err := gops.ValidateModuleTimeouts(c.Timeouts); err != nil {
+		add("timeouts: %v", err)
+	}
+
+	for name, d := range map[string]time.Duration{
+		"refresh":     c.Sampling.Refresh,
+		"network":     c.Sampling.Network,
+		"disk":        c.Sampling.Disk,
+		"temperature": c.Sampling.Temperature,
+	} {
+		if d < 100*time.Millisecond {
+			add("sampling.%s: %s is below the 100ms minimum", name, d)
+		}
+	}
+
+	if c.History.NetworkSamples < 2 {
+		add("history.network_samples: need at least 2")
+	}
+	if c.History.DiskSamples < 2 {
+		add("history.disk_samples: need at least 2")
+	}
+
+	if len(errs) > 0 {
+		slices.Sort(errs)
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// Encode renders c as TOML.
+func (c *Config) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ListenAddr combines ApiBind with the port from ApiPort.
+func (c *ServerConfig) ListenAddr() string {
+	if c.ApiBind == "" {
+		return c.ApiPort
+	}
+	port := strings.TrimPrefix(c.ApiPort, ":")
+	if _, p, err := net.SplitHostPort(c.ApiPort); err == nil {
+		port = p
+	}
+	return net.JoinHostPort(c.ApiBind, port)
 }
 
 // Parse environment variables into a Config struct
 func NewConfig() *Config {
-	cfg := Config{}
-	if err := env.Parse(&cfg); err != nil {
+	cfg := Default()
+	if err := parseEnv(cfg); err != nil {
 		log.Fatal("Error parsing environment", "err", err)
 	}
 
-	return &cfg
+	return cfg
 }
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `
+[server]
+port = ":9000"
+bind = "127.0.0.1"
+
+[defaults]
+sort = "memory"
+limit = 20
+
+[devices]
+exclude_mounts = ["/mnt/*"]
+
+[sampling]
+network = "5s"
+
+[cache]
+hardware = "10m"
+
+[timeouts]
+diskmounts = "10s"
+`)
+	t.Setenv("DGOP_LIMIT", "5")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9000", cfg.ListenAddr())
+	assert.Equal(t, "memory", cfg.Defaults.Sort)
+	assert.Equal(t, 5, cfg.Defaults.Limit, "environment overrides the file")
+	assert.Equal(t, []string{"/mnt/*"}, cfg.Devices.ExcludeMounts)
+	assert.Equal(t, 5*time.Second, cfg.Sampling.Network)
+	assert.Equal(t, time.Second, cfg.Sampling.Refresh, "unset keys keep their defaults")
+	assert.True(t, cfg.Defaults.MergeChildren)
+	assert.Equal(t, 10*time.Minute, cfg.Cache["hardware"])
+	assert.Equal(t, 5*time.Second, cfg.Cache["diskmounts"], "other modules keep their TTL")
+	assert.Equal(t, 10*time.Second, cfg.Timeouts["diskmounts"])
+	assert.Equal(t, 2*time.Second, cfg.Timeouts["memory"])
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "[defaults]\nsrot = \"cpu\"\n")
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defaults.srot")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"bad sort", func(c *Config) { c.Defaults.Sort = "size" }, "defaults.sort"},
+		{"bad module", func(c *Config) { c.Defaults.Modules = []string{"cpu", "gpus"} }, "unknown module"},
+		{"bad socket mode", func(c *Config) { c.ApiSocketMode = "rw" }, "socket_mode"},
+		{"cert without key", func(c *Config) { c.TLSCert = "a.crt" }, "tls_key"},
+		{"no listener", func(c *Config) { c.ApiNoTCP = true }, "no_tcp"},
+		{"fast sampling", func(c *Config) { c.Sampling.Disk = time.Millisecond }, "sampling.disk"},
+		{"short history", func(c *Config) { c.History.DiskSamples = 1 }, "history.disk_samples"},
+		{"bad glob", func(c *Config) { c.Devices.Disks = []string{"sd["} }, "devices"},
+		{"uncacheable module", func(c *Config) { c.Cache["cpu"] = time.Second }, "cache"},
+		{"zero timeout", func(c *Config) { c.Timeouts["disk"] = 0 }, "timeouts"},
+		{"session outlives cursors", func(c *Config) { c.SessionTTL = 2 * time.Hour }, "session_ttl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.Devices.Interfaces = []string{"enp*"}
+	cfg.Sampling.Temperature = 30 * time.Second
+
+	data, err := cfg.Encode()
+	require.NoError(t, err)
+
+	loaded, err := Load(writeConfig(t, t.TempDir(), string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, cfg, loaded)
+}
+
+func TestManagerReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "[history]\nnetwork_samples = 30\n")
+
+	m, err := NewManager(path, func(c *Config) { c.Defaults.Limit = 7 })
+	require.NoError(t, err)
+	defer m.Close()
+	assert.Equal(t, 30, m.Get().History.NetworkSamples)
+	assert.Equal(t, 7, m.Get().Defaults.Limit)
+
+	// A single save can produce several events, so wait for the outcome
+	// rather than for one notification.
+	eventually := func(cond func() bool) {
+		t.Helper()
+		require.Eventually(t, cond, 5*time.Second, 10*time.Millisecond)
+	}
+
+	writeConfig(t, dir, "[history]\nnetwork_samples = 90\n")
+	eventually(func() bool { return m.Get().History.NetworkSamples == 90 })
+	assert.NoError(t, m.Err())
+	assert.Equal(t, 7, m.Get().Defaults.Limit, "overrides survive a reload")
+
+	writeConfig(t, dir, "[history]\nnetwork_samples = 1\n")
+	eventually(func() bool { return m.Err() != nil })
+	assert.Equal(t, 90, m.Get().History.NetworkSamples, "an invalid edit keeps the previous config")
+}
+
+func TestLoadCursorKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor.key")
+
+	key, err := loadCursorKey(path)
+	require.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	again, err := loadCursorKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, again, "the key is kept between runs")
+
+	require.NoError(t, os.WriteFile(path, []byte("short"), 0600))
+	_, err = loadCursorKey(path)
+	assert.Error(t, err)
+}
+
+func TestLoadReadsDeprecatedPort(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.toml")
+
+	t.Setenv("API_PORT", ":7000")
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, ":7000", cfg.ApiPort)
+
+	t.Setenv("DGOP_PORT", ":8000")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, ":8000", cfg.ApiPort, "the new name wins")
+}
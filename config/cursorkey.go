@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/AvengeMedia/dgop/gops"
+)
+
+// CursorKey returns this install's cursor signing key from cursor.key in
+// the config directory, creating it on first use. Sharing one key between
+// runs lets a cursor from one dgop invocation be passed to the next.
+func CursorKey() ([]byte, error) {
+	path, err := configFilePath("cursor.key")
+	if err != nil {
+		return nil, err
+	}
+	return loadCursorKey(path)
+}
+
+func loadCursorKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	switch {
+	case err == nil && len(key) >= gops.CursorKeySize:
+		return key, nil
+	case err == nil:
+		return nil, fmt.Errorf("%s: key is shorter than %d bytes", path, gops.CursorKeySize)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Write the key aside and link it into place, so a concurrent dgop
+	// never reads a partial key and whichever links first wins.
+	key = gops.NewCursorKey()
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cursor.key-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(key)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Link(tmp.Name(), path); errors.Is(err, fs.ErrExist) {
+		return loadCursorKey(path)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const reloadDelay = 100 * time.Millisecond
+
+// Manager holds the effective configuration and reloads it when config.toml
+// changes. Invalid edits are reported through Err and leave the previous
+// configuration in place.
+type Manager struct {
+	mu       sync.RWMutex
+	cfg      *Config
+	err      error
+	override func(*Config)
+	watcher  *fsnotify.Watcher
+	filePath string
+	notify   chan struct{}
+}
+
+// NewManager loads path (config.toml in the config directory when empty).
+// override, if set, is applied after every load so command-line flags keep
+// precedence over the file.
+func NewManager(path string, override func(*Config)) (*Manager, error) {
+	if path == "" {
+		defaultPath, err := ConfigFilePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		override: override,
+		filePath: absPath,
+		notify:   make(chan struct{}, 1),
+	}
+
+	cfg, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	m.cfg = cfg
+
+	if err := m.startWatching(); err != nil {
+		return nil, fmt.Errorf("failed to start file watching: %w", err)
+	}
+
+	return m, nil
+}
+
+// Get returns the current configuration. Callers must not modify it.
+func (m *Manager) Get() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// Err returns the error from the last reload, or nil if it succeeded.
+func (m *Manager) Err() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.err
+}
+
+func (m *Manager) Path() string {
+	return m.filePath
+}
+
+func (m *Manager) Changes() <-chan struct{} {
+	return m.notify
+}
+
+func (m *Manager) Close() error {
+	if m.watcher != nil {
+		return m.watcher.Close()
+	}
+	return nil
+}
+
+func (m *Manager) load() (*Config, error) {
+	cfg, err := Load(m.filePath)
+	if err != nil {
+		return nil, err
+	}
+	if m.override != nil {
+		m.override(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (m *Manager) reload() {
+	cfg, err := m.load()
+
+	m.mu.Lock()
+	m.err = err
+	if err == nil {
+		m.cfg = cfg
+	}
+	m.mu.Unlock()
+
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// startWatching watches the directory rather than the file so that creating
+// the file, or saving it with an editor that replaces it, is picked up too.
+// Reloads wait for the events of one save to settle, since a truncating
+// write briefly leaves the file empty.
+func (m *Manager) startWatching() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	m.watcher = watcher
+
+	go func() {
+		debounce := time.NewTimer(0)
+		<-debounce.C
+		defer debounce.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Name == m.filePath && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+					debounce.Reset(reloadDelay)
+				}
+			case <-debounce.C:
+				m.reload()
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return watcher.Add(filepath.Dir(m.filePath))
+}
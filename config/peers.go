@@ -0,0 +1,20 @@
+package config
+
+import (
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// PeersFilePath returns the default location of the federation peers file.
+func PeersFilePath() (string, error) {
+	return configFilePath("peers.toml")
+}
+
+// LoadPeersConfig reads the peer list from path, or from the default
+// location when path is empty. A missing file yields no peers.
+func LoadPeersConfig(path string) (*models.PeersConfig, error) {
+	cfg := &models.PeersConfig{}
+	if err := loadTOMLFile(path, "peers.toml", cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
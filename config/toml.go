@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+func configFilePath(name string) (string, error) {
+	configDir, err := appPaths.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return filepath.Join(configDir, name), nil
+}
+
+// loadTOMLFile decodes path into v, or the named file in the config
+// directory when path is empty. A missing file leaves v untouched.
+func loadTOMLFile(path, name string, v any) error {
+	if path == "" {
+		defaultPath, err := configFilePath(name)
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := toml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,384 @@
+// Package fixture captures the /proc and /sys files dgop's collectors read
+// into a tarball that can be replayed with --sysroot.
+package fixture
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestName is the file at the top of every fixture describing it. The
+// collectors never read it.
+const ManifestName = "dgop-fixture.json"
+
+// maxFileSize skips anything larger than the small text files dgop reads.
+const maxFileSize = 1 << 20
+
+// maxLinkDepth bounds symlink chains, which sysfs is full of.
+const maxLinkDepth = 16
+
+// capturePaths are the globs, relative to the root, of everything the Linux
+// collectors and gopsutil read. A "**" element matches any number of
+// directories. Symlinks along the way are kept as links and
+// their targets captured, so the sysfs layout survives.
+var capturePaths = []string{
+	// cpu, memory, load, disks and network
+	"/proc/cpuinfo",
+	"/proc/stat",
+	"/proc/meminfo",
+	"/proc/vmstat",
+	"/proc/swaps",
+	"/proc/loadavg",
+	"/proc/uptime",
+	"/proc/diskstats",
+	"/proc/mounts",
+	"/proc/self",
+	"/proc/self/mountinfo",
+	"/proc/1/mountinfo",
+	"/proc/net/dev",
+	"/proc/spl/kstat/zfs/arcstats",
+	"/proc/sys/kernel/hostname",
+	"/proc/sys/kernel/osrelease",
+	"/sys/devices/system/cpu/online",
+	"/sys/devices/system/cpu/cpu*/cpufreq/scaling_cur_freq",
+
+	// processes
+	"/proc/[0-9]*/stat",
+	"/proc/[0-9]*/status",
+	"/proc/[0-9]*/statm",
+	"/proc/[0-9]*/comm",
+	"/proc/[0-9]*/cmdline",
+	"/proc/[0-9]*/smaps_rollup",
+	"/proc/[0-9]*/exe",
+	"/proc/[0-9]*/cgroup",
+	"/proc/[0-9]*/task/[0-9]*/stat",
+	"/proc/[0-9]*/wchan",
+
+	// units, from the unified cgroup hierarchy
+	"/sys/fs/cgroup/cgroup.controllers",
+	"/sys/fs/cgroup/**/cpu.stat",
+	"/sys/fs/cgroup/**/io.stat",
+	"/sys/fs/cgroup/**/memory.current",
+	"/sys/fs/cgroup/**/pids.current",
+
+	// desktop apps
+	"/usr/share/applications/*.desktop",
+	"/usr/local/share/applications/*.desktop",
+	"/var/lib/flatpak/exports/share/applications/*.desktop",
+	"/var/lib/snapd/desktop/applications/*.desktop",
+
+	// sensors
+	"/sys/class/hwmon/*/name",
+	"/sys/class/hwmon/*/temp*_input",
+	"/sys/class/hwmon/*/temp*_label",
+	"/sys/class/hwmon/*/temp*_crit",
+	"/sys/class/hwmon/*/temp*_max",
+	"/sys/class/thermal/thermal_zone*/type",
+	"/sys/class/thermal/thermal_zone*/temp",
+
+	// gpus
+	"/sys/bus/pci/devices/*/class",
+	"/sys/bus/pci/devices/*/vendor",
+	"/sys/bus/pci/devices/*/device",
+	"/sys/bus/pci/devices/*/driver",
+	"/sys/class/drm/card*/device/vendor",
+	"/sys/class/drm/card*/device/device",
+	"/sys/class/drm/card*/device/driver",
+	"/sys/class/drm/card*/device/hwmon/hwmon*/temp*_input",
+
+	// hardware
+	"/sys/class/dmi/id/*",
+	"/sys/devices/virtual/dmi/id/*",
+	"/sys/class/power_supply/*/uevent",
+	"/sys/class/power_supply/*/type",
+	"/sys/class/power_supply/*/status",
+	"/sys/class/power_supply/*/capacity",
+	"/etc/os-release",
+}
+
+// followRoots are the trees symlinks are followed into. Anything else, like
+// /proc/<pid>/exe pointing at a binary, is kept as a bare link.
+var followRoots = []string{"/proc/", "/sys/"}
+
+type Options struct {
+	// Root is the tree to capture, "/" for this machine.
+	Root    string
+	Redact  Redactions
+	Version string
+}
+
+// Report counts what a capture wrote.
+type Report struct {
+	Files    int `json:"files"`
+	Links    int `json:"links"`
+	Skipped  int `json:"skipped"`
+	Redacted int `json:"redacted"`
+}
+
+type manifest struct {
+	Version  string    `json:"version"`
+	Captured time.Time `json:"captured"`
+	Redacted []string  `json:"redacted,omitempty"`
+	Report   Report    `json:"report"`
+}
+
+type capturer struct {
+	root     string
+	tw       *tar.Writer
+	written  map[string]bool
+	redactor *redactor
+	report   Report
+	now      time.Time
+}
+
+// Capture writes a gzipped tarball of the collector inputs under opts.Root
+// to w. Entries are relative to the root, so extracting it into a directory
+// gives a tree for --sysroot. Files that can't be read, which includes other
+// users' processes when not root, are counted and skipped.
+func Capture(w io.Writer, opts Options) (*Report, error) {
+	root := opts.Root
+	if root == "" {
+		root = "/"
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	c := &capturer{
+		root:     root,
+		tw:       tar.NewWriter(gz),
+		written:  map[string]bool{},
+		redactor: newRedactor(root, opts.Redact),
+		now:      time.Now(),
+	}
+
+	for _, pattern := range capturePaths {
+		matches, err := glob(c.real(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("bad capture pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				return nil, err
+			}
+			if err := c.add("/"+filepath.ToSlash(rel), 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest{
+		Version:  opts.Version,
+		Captured: c.now.UTC(),
+		Redacted: opts.Redact.Names(),
+		Report:   c.report,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writeData(ManifestName, append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	if err := c.tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return &c.report, nil
+}
+
+// glob is filepath.Glob with a "**" element matching any number of
+// directories, for trees like the cgroup hierarchy whose depth varies.
+// Symlinked directories aren't descended into.
+func glob(pattern string) ([]string, error) {
+	sep := string(filepath.Separator)
+	base, rest, ok := strings.Cut(pattern, sep+"**"+sep)
+	if !ok {
+		return filepath.Glob(pattern)
+	}
+
+	var matches []string
+	err := filepath.WalkDir(base, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			// Unreadable or missing directories match nothing, as in Glob.
+			return nil
+		}
+		found, err := glob(filepath.Join(dir, rest))
+		matches = append(matches, found...)
+		return err
+	})
+	return matches, err
+}
+
+func (c *capturer) real(p string) string {
+	return filepath.Join(c.root, filepath.FromSlash(p))
+}
+
+// add captures p, a cleaned absolute path inside the tree, walking it one
+// element at a time so every symlink on the way is recorded.
+func (c *capturer) add(p string, depth int) error {
+	if depth > maxLinkDepth {
+		c.report.Skipped++
+		return nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	dir := "/"
+	for i, part := range parts {
+		cur := path.Join(dir, part)
+		last := i == len(parts)-1
+
+		info, err := os.Lstat(c.real(cur))
+		if err != nil {
+			c.report.Skipped++
+			return nil
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(c.real(cur))
+			if err != nil {
+				c.report.Skipped++
+				return nil
+			}
+			if err := c.writeLink(cur, target); err != nil {
+				return err
+			}
+
+			resolved := path.Clean(target)
+			if !path.IsAbs(target) {
+				resolved = path.Join(dir, target)
+			}
+			// i2c sensors can block while a bus is held, which is why the
+			// collectors skip them too.
+			if !followable(resolved) || strings.Contains(resolved, "/i2c-") {
+				return nil
+			}
+			if !last {
+				resolved = path.Join(append([]string{resolved}, parts[i+1:]...)...)
+			}
+			return c.add(resolved, depth+1)
+		}
+
+		if last {
+			switch {
+			case info.IsDir():
+				return c.writeDir(cur)
+			case info.Mode().IsRegular():
+				return c.writeFile(cur)
+			}
+			return nil
+		}
+		dir = cur
+	}
+	return nil
+}
+
+func followable(p string) bool {
+	for _, root := range followRoots {
+		if strings.HasPrefix(p, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *capturer) writeFile(p string) error {
+	if c.written[p] {
+		return nil
+	}
+	// /proc and /sys report a size of 0 or 4096, so read to find out.
+	f, err := os.Open(c.real(p))
+	if err != nil {
+		c.report.Skipped++
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxFileSize+1))
+	f.Close()
+	if err != nil || len(data) > maxFileSize {
+		c.report.Skipped++
+		return nil
+	}
+
+	redacted := c.redactor.file(p, data)
+	if string(redacted) != string(data) {
+		c.report.Redacted++
+	}
+	if err := c.writeParents(p); err != nil {
+		return err
+	}
+	c.report.Files++
+	return c.writeData(strings.TrimPrefix(p, "/"), redacted)
+}
+
+func (c *capturer) writeData(name string, data []byte) error {
+	c.written["/"+name] = true
+	if err := c.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  c.now,
+	}); err != nil {
+		return err
+	}
+	_, err := c.tw.Write(data)
+	return err
+}
+
+func (c *capturer) writeLink(p, target string) error {
+	if c.written[p] {
+		return nil
+	}
+	if err := c.writeParents(p); err != nil {
+		return err
+	}
+	c.written[p] = true
+	c.report.Links++
+	return c.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     strings.TrimPrefix(p, "/"),
+		Linkname: c.redactor.link(target),
+		Mode:     0o777,
+		ModTime:  c.now,
+	})
+}
+
+// writeParents adds the directories above p, so the tree extracts the same
+// way with any tar and links into it resolve.
+func (c *capturer) writeParents(p string) error {
+	dir := path.Dir(p)
+	if dir == "/" || c.written[dir] {
+		return nil
+	}
+	return c.writeDir(dir)
+}
+
+func (c *capturer) writeDir(p string) error {
+	if c.written[p] {
+		return nil
+	}
+	if err := c.writeParents(p); err != nil {
+		return err
+	}
+	c.written[p] = true
+	return c.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     strings.TrimPrefix(p, "/") + "/",
+		Mode:     0o755,
+		ModTime:  c.now,
+	})
+}
@@ -0,0 +1,160 @@
+package fixture
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRoot builds a small machine: one process, a coretemp sensor reached
+// through the usual sysfs class link, DMI data and a login user.
+func fakeRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"proc/meminfo":                                               "MemTotal:       8192000 kB\nMemFree:        4096000 kB\nMemAvailable:   6000000 kB\n",
+		"proc/sys/kernel/hostname":                                   "build-box.example.com\n",
+		"proc/42/cmdline":                                            "/usr/bin/backup\x00--password\x00hunter2\x00",
+		"proc/42/mountinfo":                                          "25 1 0:22 / /home/alice rw - ext4 /dev/sda2 rw\n",
+		"proc/42/stat":                                               "42 (backup) S 1 42 42 0 -1 4194560 100 0 0 0 10 5 0 0 20 0 1 0 100 1000000 200 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		"proc/42/task/42/stat":                                       "42 (backup) S 1 42 42 0 -1 4194560 100 0 0 0 10 5 0 0 20 0 1 0 100 1000000 200 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		"proc/42/wchan":                                              "nfs_wait_bit_killable\n",
+		"sys/class/dmi/id/board_name":                                "X570 on build-box\n",
+		"sys/class/dmi/id/product_serial":                            "ABC123\n",
+		"sys/class/power_supply/BAT0/uevent":                         "POWER_SUPPLY_NAME=BAT0\nPOWER_SUPPLY_SERIAL_NUMBER=  4242\n",
+		"sys/devices/platform/coretemp.0/hwmon/hwmon1/name":          "coretemp\n",
+		"sys/devices/platform/coretemp.0/hwmon/hwmon1/temp1_input":   "54000\n",
+		"sys/devices/platform/coretemp.0/hwmon/hwmon1/power/control": "auto\n",
+		"etc/passwd":                                                 "root:x:0:0::/root:/bin/sh\nalice:x:1000:1000::/home/alice:/bin/sh\n",
+		"sys/fs/cgroup/cgroup.controllers":                           "cpu io memory pids\n",
+		"sys/fs/cgroup/system.slice/backup.service/cpu.stat":         "usage_usec 1500000\n",
+		"sys/fs/cgroup/system.slice/backup.service/memory.current":   "4096\n",
+		"sys/fs/cgroup/system.slice/backup.service/memory.stat":      "anon 4096\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sys/class/hwmon"), 0o755))
+	require.NoError(t, os.Symlink("../../devices/platform/coretemp.0/hwmon/hwmon1", filepath.Join(root, "sys/class/hwmon/hwmon1")))
+	require.NoError(t, os.Symlink("42", filepath.Join(root, "proc/self")))
+	require.NoError(t, os.Symlink("/home/alice/bin/backup", filepath.Join(root, "proc/42/exe")))
+	return root
+}
+
+// extract unpacks a capture the way tar xzf would.
+func extract(t *testing.T, data []byte) (string, map[string]bool) {
+	t.Helper()
+	dir := t.TempDir()
+	names := map[string]bool{}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names[hdr.Name] = true
+		p := filepath.Join(dir, hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			require.NoError(t, os.MkdirAll(p, 0o755))
+		case tar.TypeSymlink:
+			require.NoError(t, os.Symlink(hdr.Linkname, p))
+		case tar.TypeReg:
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(p, content, 0o644))
+		}
+	}
+	return dir, names
+}
+
+func read(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCaptureKeepsLayout(t *testing.T) {
+	var buf bytes.Buffer
+	report, err := Capture(&buf, Options{Root: fakeRoot(t), Version: "test"})
+	require.NoError(t, err)
+	assert.Zero(t, report.Redacted)
+
+	dir, names := extract(t, buf.Bytes())
+	assert.True(t, names[ManifestName])
+	assert.False(t, names["sys/devices/platform/coretemp.0/hwmon/hwmon1/power/control"], "only files the collectors read are captured")
+
+	link, err := os.Readlink(filepath.Join(dir, "sys/class/hwmon/hwmon1"))
+	require.NoError(t, err)
+	assert.Equal(t, "../../devices/platform/coretemp.0/hwmon/hwmon1", link)
+	assert.Equal(t, "54000\n", read(t, dir, "sys/class/hwmon/hwmon1/temp1_input"))
+	assert.Equal(t, "/usr/bin/backup\x00--password\x00hunter2\x00", read(t, dir, "proc/self/cmdline"))
+	assert.Equal(t, "ABC123\n", read(t, dir, "sys/class/dmi/id/product_serial"))
+	assert.Equal(t, "usage_usec 1500000\n", read(t, dir, "sys/fs/cgroup/system.slice/backup.service/cpu.stat"))
+	assert.Equal(t, "4096\n", read(t, dir, "sys/fs/cgroup/system.slice/backup.service/memory.current"))
+	assert.False(t, names["sys/fs/cgroup/system.slice/backup.service/memory.stat"])
+	assert.True(t, names["proc/42/task/42/stat"])
+	assert.Equal(t, "nfs_wait_bit_killable\n", read(t, dir, "proc/42/wchan"))
+}
+
+func TestCaptureRedacts(t *testing.T) {
+	redact, err := ParseRedactions([]string{"all"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	report, err := Capture(&buf, Options{Root: fakeRoot(t), Redact: redact})
+	require.NoError(t, err)
+	assert.Positive(t, report.Redacted)
+
+	dir, _ := extract(t, buf.Bytes())
+	assert.Equal(t, "redacted-host\n", read(t, dir, "proc/sys/kernel/hostname"))
+	assert.Equal(t, "X570 on redacted-host\n", read(t, dir, "sys/class/dmi/id/board_name"))
+	assert.Equal(t, "/usr/bin/backup\x00", read(t, dir, "proc/42/cmdline"))
+	assert.Equal(t, "25 1 0:22 / /home/user1 rw - ext4 /dev/sda2 rw\n", read(t, dir, "proc/42/mountinfo"))
+	assert.Equal(t, "REDACTED\n", read(t, dir, "sys/class/dmi/id/product_serial"))
+	assert.Contains(t, read(t, dir, "sys/class/power_supply/BAT0/uevent"), "POWER_SUPPLY_SERIAL_NUMBER=REDACTED\n")
+
+	exe, err := os.Readlink(filepath.Join(dir, "proc/42/exe"))
+	require.NoError(t, err)
+	assert.Equal(t, "/home/user1/bin/backup", exe)
+}
+
+func TestCaptureReplays(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("replaying a capture needs the Linux collectors")
+	}
+	var buf bytes.Buffer
+	_, err := Capture(&buf, Options{Root: fakeRoot(t)})
+	require.NoError(t, err)
+	dir, _ := extract(t, buf.Bytes())
+
+	g := gops.NewGopsUtil()
+	require.NoError(t, g.UseSysroot(dir))
+	mem, err := g.GetMemoryInfo(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8192000), mem.Total)
+}
+
+func TestParseRedactions(t *testing.T) {
+	r, err := ParseRedactions([]string{"serials", "cmdline"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"serials", "cmdlines"}, r.Names())
+
+	_, err = ParseRedactions([]string{"passwords"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package fixture
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// Redactions selects what to scrub from a capture before it leaves the
+// machine.
+type Redactions struct {
+	// Hostnames replaces the machine's hostname everywhere.
+	Hostnames bool
+	// Usernames replaces the names of login users (uid 1000 and up), e.g.
+	// in /home paths.
+	Usernames bool
+	// Serials blanks DMI serial numbers and UUIDs and power supply serials.
+	Serials bool
+	// CommandLines cuts process command lines down to the program.
+	CommandLines bool
+}
+
+var redactionNames = []string{"hostnames", "usernames", "serials", "cmdlines"}
+
+// ParseRedactions accepts any of hostnames, usernames, serials and cmdlines,
+// or all.
+func ParseRedactions(names []string) (Redactions, error) {
+	var r Redactions
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "all":
+			r = Redactions{Hostnames: true, Usernames: true, Serials: true, CommandLines: true}
+		case "hostnames", "hostname":
+			r.Hostnames = true
+		case "usernames", "username", "users":
+			r.Usernames = true
+		case "serials", "serial":
+			r.Serials = true
+		case "cmdlines", "cmdline":
+			r.CommandLines = true
+		case "", "none":
+		default:
+			return r, fmt.Errorf("unknown redaction %q (expected %s or all)", name, strings.Join(redactionNames, ", "))
+		}
+	}
+	return r, nil
+}
+
+// Names lists the enabled redactions.
+func (r Redactions) Names() []string {
+	var out []string
+	for i, on := range []bool{r.Hostnames, r.Usernames, r.Serials, r.CommandLines} {
+		if on {
+			out = append(out, redactionNames[i])
+		}
+	}
+	return out
+}
+
+// serialFiles are the DMI and power supply attributes that identify a unit.
+var serialFiles = []string{"board_serial", "product_serial", "chassis_serial", "product_uuid", "serial_number"}
+
+var cmdlinePath = regexp.MustCompile(`^/proc/[0-9]+/cmdline$`)
+
+type redactor struct {
+	opts Redactions
+	// names matches hostnames and usernames as whole words.
+	names    *regexp.Regexp
+	replaces map[string]string
+}
+
+func newRedactor(root string, opts Redactions) *redactor {
+	r := &redactor{opts: opts, replaces: map[string]string{}}
+
+	if opts.Hostnames {
+		for _, host := range hostnames(root) {
+			r.replaces[host] = "redacted-host"
+		}
+	}
+	if opts.Usernames {
+		for i, name := range loginUsers(root) {
+			r.replaces[name] = "user" + strconv.Itoa(i+1)
+		}
+	}
+
+	if len(r.replaces) > 0 {
+		words := make([]string, 0, len(r.replaces))
+		for word := range r.replaces {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+		// Longest first, so an FQDN wins over its short name.
+		slices.SortFunc(words, func(a, b string) int { return len(b) - len(a) })
+		r.names = regexp.MustCompile(`\b(` + strings.Join(words, "|") + `)\b`)
+	}
+	return r
+}
+
+func (r *redactor) file(p string, data []byte) []byte {
+	if r.opts.CommandLines && cmdlinePath.MatchString(p) {
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+
+	if r.opts.Serials {
+		base := path.Base(p)
+		switch {
+		case slices.Contains(serialFiles, base):
+			return []byte(redacted + "\n")
+		case base == "uevent":
+			data = redactUevent(data)
+		}
+	}
+
+	if r.names != nil {
+		data = r.names.ReplaceAllFunc(data, func(word []byte) []byte {
+			return []byte(r.replaces[string(word)])
+		})
+	}
+	return data
+}
+
+func (r *redactor) link(target string) string {
+	if r.names == nil {
+		return target
+	}
+	return r.names.ReplaceAllStringFunc(target, func(word string) string {
+		return r.replaces[word]
+	})
+}
+
+// redactUevent blanks KEY=value lines whose key names a serial number.
+func redactUevent(data []byte) []byte {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, _, ok := strings.Cut(line, "="); ok && strings.Contains(key, "SERIAL") {
+			line = key + "=" + redacted
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// hostnames returns the captured machine's hostname and, for an FQDN, its
+// short form.
+func hostnames(root string) []string {
+	var host string
+	if data, err := os.ReadFile(filepath.Join(root, "proc/sys/kernel/hostname")); err == nil {
+		host = strings.TrimSpace(string(data))
+	} else if root == "/" {
+		host, _ = os.Hostname()
+	}
+	if host == "" || host == "localhost" {
+		return nil
+	}
+	out := []string{host}
+	if short, _, ok := strings.Cut(host, "."); ok && short != "" {
+		out = append(out, short)
+	}
+	return out
+}
+
+// loginUsers returns the names of regular users from the tree's passwd,
+// plus the current user when capturing this machine.
+func loginUsers(root string) []string {
+	var names []string
+	if data, err := os.ReadFile(filepath.Join(root, "etc/passwd")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Split(line, ":")
+			if len(fields) < 3 {
+				continue
+			}
+			uid, err := strconv.Atoi(fields[2])
+			if err != nil || uid < 1000 || uid == 65534 {
+				continue
+			}
+			names = append(names, fields[0])
+		}
+	}
+	if root == "/" {
+		if u, err := user.Current(); err == nil && u.Uid != "0" && !slices.Contains(names, u.Username) {
+			names = append(names, u.Username)
+		}
+	}
+	return names
+}
@@ -0,0 +1,357 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/dgop/api/client"
+	"github.com/AvengeMedia/dgop/gops"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const (
+	DefaultInterval = 5 * time.Second
+	defaultPort     = "63484"
+	// hostProcLimit is how many processes are kept per host. The fleet-wide
+	// top list is built from these, so it only has to cover the summary.
+	hostProcLimit = 25
+)
+
+var pollModules = []string{"cpu", "memory", "system", "diskmounts", "processes"}
+
+// sampler is what the fleet polls: a remote dgop server or this machine.
+type sampler interface {
+	Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error)
+}
+
+type localSampler struct {
+	gops *gops.GopsUtil
+}
+
+func (s localSampler) Meta(ctx context.Context, modules []string, params gops.MetaParams) (*models.MetaInfo, error) {
+	return s.gops.GetMeta(ctx, modules, params)
+}
+
+type member struct {
+	sampler sampler
+
+	// Only touched by the poll goroutine for this member.
+	cpuCursor  string
+	procCursor string
+
+	health models.PeerHealth
+	meta   *models.MetaInfo
+}
+
+// Fleet polls a set of dgop servers (and optionally this machine) and keeps
+// the latest sample of each.
+type Fleet struct {
+	interval   time.Duration
+	clientOpts []client.Option
+	local      *gops.GopsUtil
+	localName  string
+	members    []*member
+	now        func() time.Time
+
+	mu        sync.RWMutex
+	updatedAt time.Time
+}
+
+type Option func(*Fleet)
+
+func WithInterval(d time.Duration) Option {
+	return func(f *Fleet) {
+		if d > 0 {
+			f.interval = d
+		}
+	}
+}
+
+// WithLocal includes this machine in the fleet under name (the hostname when
+// empty).
+func WithLocal(gopsUtil *gops.GopsUtil, name string) Option {
+	return func(f *Fleet) {
+		f.local = gopsUtil
+		f.localName = name
+	}
+}
+
+// WithClientOptions configures the HTTP clients used to reach the peers.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(f *Fleet) {
+		f.clientOpts = append(f.clientOpts, opts...)
+	}
+}
+
+func New(peers []string, opts ...Option) (*Fleet, error) {
+	f := &Fleet{
+		interval: DefaultInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	seen := make(map[string]struct{})
+	if f.local != nil {
+		if f.localName == "" {
+			f.localName, _ = os.Hostname()
+		}
+		if f.localName == "" {
+			f.localName = "local"
+		}
+		seen[f.localName] = struct{}{}
+		f.members = append(f.members, &member{
+			sampler: localSampler{gops: f.local},
+			health:  models.PeerHealth{Host: f.localName, URL: "local"},
+		})
+	}
+
+	for _, spec := range peers {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, peerURL, err := ParsePeer(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := seen[name]; dup {
+			return nil, fmt.Errorf("peer %q: duplicate host", spec)
+		}
+		seen[name] = struct{}{}
+
+		c, err := client.New(peerURL, f.clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("peer %q: %w", spec, err)
+		}
+		f.members = append(f.members, &member{
+			sampler: c,
+			health:  models.PeerHealth{Host: name, URL: c.BaseURL()},
+		})
+	}
+
+	return f, nil
+}
+
+// ParsePeer accepts "host", "host:port" or a full URL and returns the name
+// the peer is reported under along with its base URL. The port defaults to
+// the API server's default port and is only part of the name when it differs.
+func ParsePeer(spec string) (name, baseURL string, err error) {
+	raw := spec
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", "", fmt.Errorf("invalid peer %q", spec)
+	}
+	name = u.Hostname()
+	switch u.Port() {
+	case "":
+		u.Host = net.JoinHostPort(u.Hostname(), defaultPort)
+	case defaultPort:
+	default:
+		name = u.Host
+	}
+
+	return name, strings.TrimRight(u.String(), "/"), nil
+}
+
+func (f *Fleet) Empty() bool {
+	return len(f.members) == 0
+}
+
+func (f *Fleet) Interval() time.Duration {
+	return f.interval
+}
+
+// Run polls every member each interval until ctx is cancelled.
+func (f *Fleet) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll samples every member once, concurrently. A member that doesn't answer
+// within the interval is marked unhealthy until it does.
+func (f *Fleet) Poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, f.interval)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, m := range f.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.pollMember(ctx, m)
+		}()
+	}
+	wg.Wait()
+
+	f.mu.Lock()
+	f.updatedAt = f.now()
+	f.mu.Unlock()
+}
+
+func (f *Fleet) pollMember(ctx context.Context, m *member) {
+	params := gops.MetaParams{
+		SortBy:        gops.SortByCPU,
+		ProcLimit:     hostProcLimit,
+		EnableCPU:     true,
+		MergeChildren: true,
+		CPUCursor:     m.cpuCursor,
+		ProcCursor:    m.procCursor,
+	}
+
+	start := f.now()
+	meta, err := m.sampler.Meta(ctx, pollModules, params)
+	latency := f.now().Sub(start)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	m.health.LatencyMs = latency.Milliseconds()
+	if err != nil {
+		m.health.Healthy = false
+		m.health.Failures++
+		m.health.Error = err.Error()
+		m.meta = nil
+		return
+	}
+
+	m.health.Healthy = true
+	m.health.Failures = 0
+	m.health.Error = ""
+	m.health.LastSeen = f.now().UnixMilli()
+	m.meta = meta
+
+	if meta.CPU != nil {
+		m.cpuCursor = meta.CPU.Cursor
+	}
+	m.procCursor = meta.Cursor
+}
+
+// Info returns the latest sample of every healthy host, the health of every
+// member and fleet-wide summaries with at most limit entries each.
+func (f *Fleet) Info(limit int) *models.FleetInfo {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	info := &models.FleetInfo{
+		Hosts: make(map[string]*models.MetaInfo),
+		Peers: make([]models.PeerHealth, 0, len(f.members)),
+	}
+	if !f.updatedAt.IsZero() {
+		info.UpdatedAt = f.updatedAt.UnixMilli()
+	}
+
+	for _, m := range f.members {
+		info.Peers = append(info.Peers, m.health)
+		if m.health.Healthy && m.meta != nil {
+			info.Hosts[m.health.Host] = m.meta
+		}
+	}
+
+	info.Summary = summarize(info.Hosts, limit)
+	return info
+}
+
+func summarize(hosts map[string]*models.MetaInfo, limit int) models.FleetSummary {
+	summary := models.FleetSummary{
+		TopProcesses: []models.FleetProcess{},
+		HottestCPUs:  []models.FleetCPU{},
+		FullestDisks: []models.FleetDisk{},
+	}
+
+	for host, meta := range hosts {
+		for _, p := range meta.Processes {
+			summary.TopProcesses = append(summary.TopProcesses, models.FleetProcess{Host: host, ProcessInfo: p})
+		}
+		if meta.CPU != nil {
+			summary.HottestCPUs = append(summary.HottestCPUs, models.FleetCPU{
+				Host:        host,
+				Usage:       meta.CPU.Usage,
+				Temperature: meta.CPU.Temperature,
+			})
+		}
+		for _, d := range meta.DiskMounts {
+			pct, err := strconv.ParseFloat(strings.TrimSuffix(d.Percent, "%"), 64)
+			if err != nil {
+				continue
+			}
+			summary.FullestDisks = append(summary.FullestDisks, models.FleetDisk{
+				Host:    host,
+				Mount:   d.Mount,
+				Device:  d.Device,
+				Size:    d.Size,
+				Used:    d.Used,
+				Percent: pct,
+			})
+		}
+	}
+
+	// Ties are broken by host so the order is stable across requests.
+	sort.Slice(summary.TopProcesses, func(i, j int) bool {
+		a, b := summary.TopProcesses[i], summary.TopProcesses[j]
+		if a.CPU != b.CPU {
+			return a.CPU > b.CPU
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.PID < b.PID
+	})
+	sort.Slice(summary.HottestCPUs, func(i, j int) bool {
+		a, b := summary.HottestCPUs[i], summary.HottestCPUs[j]
+		if a.Temperature != b.Temperature {
+			return a.Temperature > b.Temperature
+		}
+		if a.Usage != b.Usage {
+			return a.Usage > b.Usage
+		}
+		return a.Host < b.Host
+	})
+	sort.Slice(summary.FullestDisks, func(i, j int) bool {
+		a, b := summary.FullestDisks[i], summary.FullestDisks[j]
+		if a.Percent != b.Percent {
+			return a.Percent > b.Percent
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.Mount < b.Mount
+	})
+
+	if limit > 0 {
+		summary.TopProcesses = truncate(summary.TopProcesses, limit)
+		summary.HottestCPUs = truncate(summary.HottestCPUs, limit)
+		summary.FullestDisks = truncate(summary.FullestDisks, limit)
+	}
+	return summary
+}
+
+func truncate[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
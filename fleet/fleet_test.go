@@ -0,0 +1,173 @@
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPeer(t *testing.T, meta *models.MetaInfo) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	var lastQuery atomic.Value
+	lastQuery.Store("")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gops/meta" {
+			http.NotFound(w, r)
+			return
+		}
+		lastQuery.Store(r.URL.RawQuery)
+		json.NewEncoder(w).Encode(meta)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &lastQuery
+}
+
+func hostName(t *testing.T, url string) string {
+	t.Helper()
+	name, _, err := ParsePeer(url)
+	require.NoError(t, err)
+	return name
+}
+
+func TestParsePeer(t *testing.T) {
+	tests := []struct {
+		spec, name, url string
+	}{
+		{"lab-01", "lab-01", "http://lab-01:63484"},
+		{"lab-01:9000", "lab-01:9000", "http://lab-01:9000"},
+		{"lab-01:63484", "lab-01", "http://lab-01:63484"},
+		{"https://lab-02.example.com", "lab-02.example.com", "https://lab-02.example.com:63484"},
+		{"http://10.0.0.5:63484/", "10.0.0.5", "http://10.0.0.5:63484"},
+	}
+	for _, tt := range tests {
+		name, url, err := ParsePeer(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.name, name, tt.spec)
+		assert.Equal(t, tt.url, url, tt.spec)
+	}
+
+	_, _, err := ParsePeer("http://")
+	assert.Error(t, err)
+}
+
+func TestNewRejectsDuplicatePeers(t *testing.T) {
+	_, err := New([]string{"lab-01", "http://lab-01:63484"})
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestFleetPollAndSummaries(t *testing.T) {
+	alpha, alphaQuery := newPeer(t, &models.MetaInfo{
+		CPU:    &models.CPUInfo{Usage: 80, Temperature: 71, Cursor: "alpha-cpu"},
+		Memory: &models.MemoryInfo{UsedPercent: 40},
+		DiskMounts: []*models.DiskMountInfo{
+			{Mount: "/", Device: "/dev/sda1", Percent: "91%"},
+			{Mount: "/boot", Device: "/dev/sda2", Percent: "20%"},
+		},
+		Processes: []*models.ProcessInfo{
+			{PID: 10, Command: "ffmpeg", CPU: 60},
+			{PID: 11, Command: "bash", CPU: 1},
+		},
+		Cursor: "alpha-proc",
+	})
+	beta, _ := newPeer(t, &models.MetaInfo{
+		CPU:        &models.CPUInfo{Usage: 20, Temperature: 85},
+		DiskMounts: []*models.DiskMountInfo{{Mount: "/data", Device: "/dev/nvme0n1", Percent: "55%"}},
+		Processes:  []*models.ProcessInfo{{PID: 20, Command: "postgres", CPU: 30}},
+	})
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"boom"}`, http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+
+	f, err := New([]string{alpha.URL, beta.URL, failing.URL}, WithInterval(2*time.Second))
+	require.NoError(t, err)
+	alphaName, betaName := hostName(t, alpha.URL), hostName(t, beta.URL)
+
+	f.Poll(t.Context())
+	info := f.Info(2)
+
+	require.Len(t, info.Hosts, 2)
+	assert.Contains(t, info.Hosts, alphaName)
+	assert.Contains(t, info.Hosts, betaName)
+	assert.NotZero(t, info.UpdatedAt)
+
+	require.Len(t, info.Peers, 3)
+	assert.True(t, info.Peers[0].Healthy)
+	assert.NotZero(t, info.Peers[0].LastSeen)
+	assert.False(t, info.Peers[2].Healthy)
+	assert.Equal(t, 1, info.Peers[2].Failures)
+	assert.Contains(t, info.Peers[2].Error, "500")
+
+	require.Len(t, info.Summary.TopProcesses, 2)
+	assert.Equal(t, alphaName, info.Summary.TopProcesses[0].Host)
+	assert.Equal(t, "ffmpeg", info.Summary.TopProcesses[0].Command)
+	assert.Equal(t, betaName, info.Summary.TopProcesses[1].Host)
+
+	require.Len(t, info.Summary.HottestCPUs, 2)
+	assert.Equal(t, betaName, info.Summary.HottestCPUs[0].Host)
+	assert.Equal(t, 85.0, info.Summary.HottestCPUs[0].Temperature)
+
+	require.Len(t, info.Summary.FullestDisks, 2)
+	assert.Equal(t, "/", info.Summary.FullestDisks[0].Mount)
+	assert.Equal(t, 91.0, info.Summary.FullestDisks[0].Percent)
+	assert.Equal(t, "/data", info.Summary.FullestDisks[1].Mount)
+
+	// The second poll carries the cursors the peer handed out.
+	f.Poll(t.Context())
+	query := alphaQuery.Load().(string)
+	assert.Contains(t, query, "cpu_cursor=alpha-cpu")
+	assert.Contains(t, query, "proc_cursor=alpha-proc")
+}
+
+func TestFleetPeerRecovers(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(&models.MetaInfo{CPU: &models.CPUInfo{Usage: 5}})
+	}))
+	t.Cleanup(srv.Close)
+
+	f, err := New([]string{srv.URL})
+	require.NoError(t, err)
+
+	f.Poll(t.Context())
+	f.Poll(t.Context())
+	info := f.Info(10)
+	assert.Empty(t, info.Hosts)
+	assert.Equal(t, 2, info.Peers[0].Failures)
+
+	down.Store(false)
+	f.Poll(t.Context())
+	info = f.Info(10)
+	assert.Len(t, info.Hosts, 1)
+	assert.True(t, info.Peers[0].Healthy)
+	assert.Zero(t, info.Peers[0].Failures)
+	assert.Empty(t, info.Peers[0].Error)
+}
+
+func TestFleetUnreachablePeer(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	f, err := New([]string{url}, WithInterval(time.Second))
+	require.NoError(t, err)
+
+	f.Poll(t.Context())
+	info := f.Info(10)
+	require.Len(t, info.Peers, 1)
+	assert.False(t, info.Peers[0].Healthy)
+	assert.NotEmpty(t, info.Peers[0].Error)
+	assert.Empty(t, info.Summary.TopProcesses)
+}
@@ -0,0 +1,412 @@
+package gops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+const (
+	// zombieSamples is how many samples in a row a zombie must be seen in
+	// before its parent is flagged; every zombie is one for a moment.
+	zombieSamples = 2
+	// forkMinNew keeps a burst of a few processes from counting as a fork
+	// bomb however short the interval.
+	forkMinNew = 30
+	// maxAnomalyPIDs bounds the PIDs listed with one anomaly.
+	maxAnomalyPIDs = 20
+	// memSamples is how many memory samples the cursor keeps over the
+	// growth window.
+	memSamples = 8
+	// memTrackKB is the resident size from which a process's memory is
+	// sampled, and growthMinKB the least growth that's flagged.
+	memTrackKB  = 16 * 1024
+	growthMinKB = 32 * 1024
+)
+
+// AnomalyOptions are the thresholds GetAnomalies flags at. Zero fields
+// take the defaults.
+type AnomalyOptions struct {
+	// StuckSamples is how many samples in a row a process must be in
+	// uninterruptible sleep. Default 3.
+	StuckSamples int
+	// ForkRate is how many new processes a second under one ancestor make
+	// a fork bomb. Default 20.
+	ForkRate float64
+	// GrowthWindow is the span memory growth is judged over. Default 10m.
+	GrowthWindow time.Duration
+}
+
+func (o AnomalyOptions) withDefaults() AnomalyOptions {
+	if o.StuckSamples <= 0 {
+		o.StuckSamples = 3
+	}
+	if o.ForkRate <= 0 {
+		o.ForkRate = 20
+	}
+	if o.GrowthWindow <= 0 {
+		o.GrowthWindow = 10 * time.Minute
+	}
+	return o
+}
+
+// anomaliesCursor is what GetAnomalies remembers between calls: the
+// processes running, how long each zombie or uninterruptible process has
+// been so, and memory samples of the larger processes. Mem rows line up
+// with MemAt, with zero where the process wasn't sampled.
+type anomaliesCursor struct {
+	At     int64         `json:"t"`
+	PIDs   []int32       `json:"pid"`
+	Starts []int64       `json:"start"`
+	States []stateStreak `json:"st,omitempty"`
+	MemAt  []int64       `json:"mt,omitempty"`
+	Mem    []memSeries   `json:"m,omitempty"`
+}
+
+type stateStreak struct {
+	PID     int32  `json:"p"`
+	Start   int64  `json:"s"`
+	State   string `json:"x"`
+	Samples int    `json:"n"`
+	Since   int64  `json:"t"`
+}
+
+type memSeries struct {
+	PID   int32    `json:"p"`
+	Start int64    `json:"s"`
+	KB    []uint64 `json:"kb"`
+}
+
+// procKey tells a process apart from an earlier one with the same PID.
+type procKey struct {
+	pid   int32
+	start int64
+}
+
+// GetAnomalies flags processes in trouble: parents leaving zombies
+// unreaped, processes stuck in uninterruptible sleep for opts.StuckSamples
+// samples in a row, ancestors of processes multiplying faster than
+// opts.ForkRate, and processes whose memory kept growing over
+// opts.GrowthWindow. All but zombies need the cursor of earlier calls;
+// zombies and stuck processes are only seen on Linux.
+func (self *GopsUtil) GetAnomalies(ctx context.Context, cursorStr string, opts AnomalyOptions) (*models.AnomaliesResponse, error) {
+	opts = opts.withDefaults()
+	var prev anomaliesCursor
+	if cursorStr != "" {
+		payload, err := self.openCursor("anomalies", cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		if err := unpackCursor(payload, &prev); err != nil || !prev.valid() {
+			return nil, &CursorError{Module: "anomalies", Err: ErrCursorMalformed}
+		}
+	}
+
+	var notBefore time.Time
+	if prev.At != 0 {
+		notBefore = time.UnixMilli(prev.At + 1)
+	}
+	scan, err := cachedModule(ctx, self, "processes", "", notBefore, func(ctx context.Context) (*processScan, error) {
+		return self.scanProcesses(ctx, false, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	next := anomaliesCursor{
+		At:     scan.at,
+		PIDs:   make([]int32, len(scan.infos)),
+		Starts: scan.starts,
+	}
+	byPID := make(map[int32]int, len(scan.infos))
+	for i, info := range scan.infos {
+		next.PIDs[i] = info.PID
+		byPID[info.PID] = i
+	}
+
+	var anomalies []*models.Anomaly
+	anomalies = append(anomalies, self.stateAnomalies(scan, byPID, &prev, &next, opts)...)
+	if prev.At != 0 {
+		anomalies = append(anomalies, forkAnomalies(scan, byPID, &prev, opts)...)
+	}
+	anomalies = append(anomalies, memoryAnomalies(scan, &prev, &next, opts)...)
+
+	kindOrder := map[models.AnomalyKind]int{
+		models.AnomalyForkBomb:     0,
+		models.AnomalyStuck:        1,
+		models.AnomalyZombies:      2,
+		models.AnomalyMemoryGrowth: 3,
+	}
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		a, b := anomalies[i], anomalies[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.PID < b.PID
+	})
+	if anomalies == nil {
+		anomalies = []*models.Anomaly{}
+	}
+
+	return &models.AnomaliesResponse{
+		Anomalies: anomalies,
+		Samples:   len(next.MemAt),
+		Cursor:    self.sealCursor("anomalies", packCursor(next)),
+	}, nil
+}
+
+func (c *anomaliesCursor) valid() bool {
+	if len(c.Starts) != len(c.PIDs) {
+		return false
+	}
+	for _, m := range c.Mem {
+		if len(m.KB) != len(c.MemAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// stateAnomalies carries the zombie and uninterruptible streaks on into
+// next and flags those that have lasted.
+func (self *GopsUtil) stateAnomalies(scan *processScan, byPID map[int32]int, prev, next *anomaliesCursor, opts AnomalyOptions) []*models.Anomaly {
+	streaks := make(map[procKey]stateStreak, len(prev.States))
+	for _, s := range prev.States {
+		streaks[procKey{s.PID, s.Start}] = s
+	}
+
+	var anomalies []*models.Anomaly
+	zombies := make(map[int32][]stateStreak)
+	for i, info := range scan.infos {
+		// Kernel threads, children of kthreadd, can wait in D by design.
+		if info.State != "Z" && (info.State != "D" || info.PPID == 2 || info.PID == 2) {
+			continue
+		}
+		s, ok := streaks[procKey{info.PID, scan.starts[i]}]
+		if ok && s.State == info.State {
+			s.Samples++
+		} else {
+			s = stateStreak{PID: info.PID, Start: scan.starts[i], State: info.State, Samples: 1, Since: scan.at}
+		}
+		next.States = append(next.States, s)
+
+		switch {
+		case info.State == "Z" && s.Samples >= zombieSamples:
+			zombies[info.PPID] = append(zombies[info.PPID], s)
+		case info.State == "D" && s.Samples >= opts.StuckSamples:
+			a := &models.Anomaly{
+				Kind:        models.AnomalyStuck,
+				PID:         info.PID,
+				Command:     info.Command,
+				Username:    info.Username,
+				Samples:     s.Samples,
+				Since:       s.Since,
+				WaitChannel: self.readWaitChannel(info.PID),
+				MemoryKB:    info.RSSKB,
+			}
+			a.Summary = fmt.Sprintf("%s (%d) in uninterruptible sleep for %d samples (%s)",
+				a.Command, a.PID, a.Samples, (time.Duration(scan.at-s.Since) * time.Millisecond).Round(time.Second))
+			if a.WaitChannel != "" {
+				a.Summary += ", waiting in " + a.WaitChannel
+			}
+			anomalies = append(anomalies, a)
+		}
+	}
+
+	for ppid, group := range zombies {
+		a := &models.Anomaly{
+			Kind:    models.AnomalyZombies,
+			PID:     ppid,
+			Count:   len(group),
+			Samples: group[0].Samples,
+			Since:   group[0].Since,
+		}
+		if i, ok := byPID[ppid]; ok {
+			a.Command, a.Username = scan.infos[i].Command, scan.infos[i].Username
+		}
+		for _, z := range group {
+			a.PIDs = append(a.PIDs, z.PID)
+			a.Samples = max(a.Samples, z.Samples)
+			a.Since = min(a.Since, z.Since)
+		}
+		slices.Sort(a.PIDs)
+		a.PIDs = a.PIDs[:min(len(a.PIDs), maxAnomalyPIDs)]
+		noun := "zombies"
+		if a.Count == 1 {
+			noun = "zombie"
+		}
+		a.Summary = fmt.Sprintf("%d %s left unreaped by %s (%d)", a.Count, noun, nameOr(a.Command, "?"), a.PID)
+		anomalies = append(anomalies, a)
+	}
+	return anomalies
+}
+
+// forkAnomalies counts the processes started since prev under each
+// ancestor that was already running, by command, and flags those growing
+// faster than opts.ForkRate. A bomb's processes whose parents exited are
+// counted under the process that adopted them.
+func forkAnomalies(scan *processScan, byPID map[int32]int, prev *anomaliesCursor, opts AnomalyOptions) []*models.Anomaly {
+	elapsed := float64(scan.at-prev.At) / 1000
+	if elapsed <= 0 {
+		return nil
+	}
+	existed := make(map[procKey]bool, len(prev.PIDs))
+	for i, pid := range prev.PIDs {
+		existed[procKey{pid, prev.Starts[i]}] = true
+	}
+	isNew := func(i int) bool { return !existed[procKey{scan.infos[i].PID, scan.starts[i]}] }
+
+	type burstKey struct {
+		origin  int32
+		command string
+	}
+	parentOf := func(pid int32) (int32, bool) {
+		if i, ok := byPID[pid]; ok {
+			return scan.infos[i].PPID, true
+		}
+		return 0, false
+	}
+	running := func(pid int32) bool {
+		i, ok := byPID[pid]
+		return !ok || !isNew(i)
+	}
+	bursts := make(map[burstKey][]int32)
+	for i, info := range scan.infos {
+		if !isNew(i) {
+			continue
+		}
+		origin, _ := findAncestor(info.PPID, parentOf, running)
+		key := burstKey{origin, info.Command}
+		bursts[key] = append(bursts[key], info.PID)
+	}
+
+	var anomalies []*models.Anomaly
+	for key, pids := range bursts {
+		rate := float64(len(pids)) / elapsed
+		if len(pids) < forkMinNew || rate < opts.ForkRate {
+			continue
+		}
+		slices.Sort(pids)
+		a := &models.Anomaly{
+			Kind:         models.AnomalyForkBomb,
+			PID:          key.origin,
+			ChildCommand: key.command,
+			Count:        len(pids),
+			PIDs:         pids[:min(len(pids), maxAnomalyPIDs)],
+			Rate:         rate,
+		}
+		if i, ok := byPID[key.origin]; ok {
+			a.Command, a.Username = scan.infos[i].Command, scan.infos[i].Username
+		}
+		a.Summary = fmt.Sprintf("%d new %s processes under %s (%d) in %.1fs, %.0f/s",
+			a.Count, nameOr(a.ChildCommand, "?"), nameOr(a.Command, "?"), a.PID, elapsed, rate)
+		anomalies = append(anomalies, a)
+	}
+	return anomalies
+}
+
+// memoryAnomalies samples the resident memory of the larger processes
+// into next, at most memSamples times per window, and flags those that
+// grew by growthMinKB and a tenth over at least half the window with
+// three steps in four going up, so a single jump isn't a trend. RSS is
+// used as the other measures switch methods as a process grows.
+func memoryAnomalies(scan *processScan, prev, next *anomaliesCursor, opts AnomalyOptions) []*models.Anomaly {
+	window := opts.GrowthWindow.Milliseconds()
+	rows := make(map[procKey][]uint64, len(prev.Mem))
+	for _, m := range prev.Mem {
+		rows[procKey{m.PID, m.Start}] = m.KB
+	}
+
+	times := slices.Clone(prev.MemAt)
+	sample := len(times) == 0 || scan.at-times[len(times)-1] >= window/memSamples
+	if sample {
+		times = append(times, scan.at)
+	}
+	drop := 0
+	for drop < len(times) && (scan.at-times[drop] > window || len(times)-drop > memSamples) {
+		drop++
+	}
+	next.MemAt = times[drop:]
+
+	var anomalies []*models.Anomaly
+	for i, info := range scan.infos {
+		key := procKey{info.PID, scan.starts[i]}
+		row, tracked := rows[key]
+		if !tracked && info.RSSKB < memTrackKB {
+			continue
+		}
+		if !tracked {
+			row = make([]uint64, len(prev.MemAt))
+		}
+		if sample {
+			row = append(slices.Clone(row), info.RSSKB)
+		}
+		row = row[drop:]
+		if slices.ContainsFunc(row, func(kb uint64) bool { return kb > 0 }) {
+			next.Mem = append(next.Mem, memSeries{PID: info.PID, Start: key.start, KB: row})
+		}
+
+		// The latest point is the scan itself.
+		var at []int64
+		var kb []uint64
+		for j, v := range row {
+			if v > 0 {
+				at, kb = append(at, next.MemAt[j]), append(kb, v)
+			}
+		}
+		if !sample {
+			at, kb = append(at, scan.at), append(kb, info.RSSKB)
+		}
+		if a := growthAnomaly(info, at, kb, window); a != nil {
+			anomalies = append(anomalies, a)
+		}
+	}
+	return anomalies
+}
+
+func growthAnomaly(info *models.ProcessInfo, at []int64, kb []uint64, window int64) *models.Anomaly {
+	n := len(kb)
+	if n < 4 || at[n-1]-at[0] < window/2 {
+		return nil
+	}
+	first, last := kb[0], kb[n-1]
+	if last < first+growthMinKB || last-first < first/10 {
+		return nil
+	}
+	up := 0
+	for j := 1; j < n; j++ {
+		if kb[j] > kb[j-1] {
+			up++
+		}
+	}
+	if up*4 < (n-1)*3 {
+		return nil
+	}
+
+	span := time.Duration(at[n-1]-at[0]) * time.Millisecond
+	a := &models.Anomaly{
+		Kind:          models.AnomalyMemoryGrowth,
+		PID:           info.PID,
+		Command:       info.Command,
+		Username:      info.Username,
+		MemoryKB:      last,
+		GrowthKB:      last - first,
+		WindowSeconds: span.Seconds(),
+		Rate:          float64(last-first) / span.Minutes(),
+	}
+	a.Summary = fmt.Sprintf("%s (%d) grew %s to %s in %s, %s/min",
+		a.Command, a.PID, formatBytes(a.GrowthKB*1024), formatBytes(last*1024),
+		span.Round(time.Second), formatBytes(uint64(a.Rate*1024)))
+	return a
+}
+
+func nameOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package gops
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnomalies(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"proc/meminfo":     fixtureMeminfo,
+		"proc/1/stat":      fixtureStat(1, 0, "systemd", 'S', 4194560),
+		"proc/1/status":    "Name:\tsystemd\nUid:\t0\t0\t0\t0\n",
+		"proc/2/stat":      fixtureStat(2, 0, "kthreadd", 'S', 2129984),
+		"proc/3/stat":      fixtureStat(3, 2, "kworker/0:1", 'D', 2129984),
+		"proc/300/stat":    fixtureStat(300, 1, "cp", 'D', 4194560),
+		"proc/300/status":  "Name:\tcp\nUid:\t0\t0\t0\t0\n",
+		"proc/300/cmdline": "cp\x00/mnt/nfs/big\x00.\x00",
+		"proc/300/wchan":   "rpc_wait_bit_killable",
+		"proc/400/stat":    fixtureStat(400, 1, "badd", 'S', 4194560),
+		"proc/400/status":  "Name:\tbadd\nUid:\t0\t0\t0\t0\n",
+		"proc/401/stat":    fixtureStat(401, 400, "worker", 'Z', 4194560),
+		"proc/402/stat":    fixtureStat(402, 400, "worker", 'Z', 4194560),
+		"proc/500/stat":    fixtureStat(500, 1, "bash", 'S', 4194560),
+		"proc/500/status":  "Name:\tbash\nUid:\t0\t0\t0\t0\n",
+	}
+	writeFixture(t, root, files)
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+	opts := AnomalyOptions{StuckSamples: 2}
+
+	first, err := g.GetAnomalies(t.Context(), "", opts)
+	require.NoError(t, err)
+	assert.Empty(t, first.Anomalies, "zombies and stuck processes need a second sample")
+
+	// Start a fork bomb under bash.
+	bomb := make(map[string]string)
+	for pid := 1000; pid < 1040; pid++ {
+		bomb[fmt.Sprintf("proc/%d/stat", pid)] = fixtureStat(int32(pid), 500, "bomb", 'R', 4194560)
+		bomb[fmt.Sprintf("proc/%d/status", pid)] = "Name:\tbomb\nUid:\t0\t0\t0\t0\n"
+	}
+	writeFixture(t, root, bomb)
+	time.Sleep(5 * time.Millisecond)
+
+	second, err := g.GetAnomalies(t.Context(), first.Cursor, opts)
+	require.NoError(t, err)
+	kinds := func(res *models.AnomaliesResponse) []models.AnomalyKind {
+		var out []models.AnomalyKind
+		for _, a := range res.Anomalies {
+			out = append(out, a.Kind)
+		}
+		return out
+	}
+	require.Equal(t, []models.AnomalyKind{models.AnomalyForkBomb, models.AnomalyStuck, models.AnomalyZombies}, kinds(second))
+
+	fork := second.Anomalies[0]
+	assert.Equal(t, int32(500), fork.PID)
+	assert.Equal(t, "bash", fork.Command)
+	assert.Equal(t, "bomb", fork.ChildCommand)
+	assert.Equal(t, 40, fork.Count)
+
+	stuck := second.Anomalies[1]
+	assert.Equal(t, int32(300), stuck.PID, "kernel threads may sleep in D")
+	assert.Equal(t, 2, stuck.Samples)
+	assert.Equal(t, "rpc_wait_bit_killable", stuck.WaitChannel)
+	assert.Contains(t, stuck.Summary, "waiting in rpc_wait_bit_killable")
+
+	zombies := second.Anomalies[2]
+	assert.Equal(t, int32(400), zombies.PID)
+	assert.Equal(t, "badd", zombies.Command)
+	assert.Equal(t, 2, zombies.Count)
+	assert.Equal(t, []int32{401, 402}, zombies.PIDs)
+
+	time.Sleep(5 * time.Millisecond)
+	third, err := g.GetAnomalies(t.Context(), second.Cursor, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []models.AnomalyKind{models.AnomalyStuck, models.AnomalyZombies}, kinds(third), "the bomb stopped growing")
+	assert.Equal(t, 3, third.Anomalies[0].Samples)
+	assert.Equal(t, stuck.Since, third.Anomalies[0].Since)
+
+	_, err = g.GetAnomalies(t.Context(), "garbage", opts)
+	var cursorErr *CursorError
+	assert.ErrorAs(t, err, &cursorErr)
+}
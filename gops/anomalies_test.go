@@ -0,0 +1,105 @@
+package gops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memScan is a scan at the given Unix milliseconds of processes with PIDs
+// 1, 2, ... and the given RSS.
+func memScan(at int64, rssKB ...uint64) *processScan {
+	scan := &processScan{at: at}
+	for i, kb := range rssKB {
+		scan.infos = append(scan.infos, &models.ProcessInfo{PID: int32(i + 1), Command: "proc", RSSKB: kb})
+		scan.starts = append(scan.starts, 1)
+	}
+	return scan
+}
+
+func TestMemoryAnomalies(t *testing.T) {
+	opts := AnomalyOptions{GrowthWindow: 8 * time.Minute}.withDefaults()
+	base := time.Now().UnixMilli()
+	var prev anomaliesCursor
+	for i := range 12 {
+		at := base + int64(i)*time.Minute.Milliseconds()
+		leaking := uint64(100*1024 + i*20*1024)
+		// Grows as much once, then flat.
+		jump := uint64(200 * 1024)
+		if i >= 2 {
+			jump = 400 * 1024
+		}
+		var next anomaliesCursor
+		got := memoryAnomalies(memScan(at, leaking, 300*1024, jump, 1024), &prev, &next, opts)
+		prev = next
+
+		require.True(t, next.valid())
+		assert.LessOrEqual(t, len(next.MemAt), memSamples)
+		assert.Len(t, next.Mem, 3, "small processes aren't sampled")
+		if i < 4 {
+			assert.Empty(t, got, "sample %d: the samples span under half the window", i)
+			continue
+		}
+		require.Len(t, got, 1, "sample %d", i)
+		a := got[0]
+		assert.Equal(t, int32(1), a.PID)
+		assert.Equal(t, leaking, a.MemoryKB)
+		assert.InDelta(t, 20*1024, a.Rate, 1)
+		assert.LessOrEqual(t, a.WindowSeconds, opts.GrowthWindow.Seconds())
+	}
+
+	// A call between samples adds the scan as the latest point.
+	var next anomaliesCursor
+	at := base + 11*time.Minute.Milliseconds() + 1000
+	got := memoryAnomalies(memScan(at, 400*1024, 300*1024, 400*1024, 1024), &prev, &next, opts)
+	assert.Equal(t, prev.MemAt, next.MemAt)
+	require.Len(t, got, 1)
+	assert.Equal(t, uint64(400*1024), got[0].MemoryKB)
+}
+
+func TestForkAnomalies(t *testing.T) {
+	prev := anomaliesCursor{At: 1000, PIDs: []int32{1, 500}, Starts: []int64{1, 1}}
+	scan := &processScan{at: 3000}
+	add := func(pid, ppid int32, command string) {
+		scan.infos = append(scan.infos, &models.ProcessInfo{PID: pid, PPID: ppid, Command: command})
+		scan.starts = append(scan.starts, 1)
+	}
+	add(1, 0, "systemd")
+	add(500, 1, "bash")
+	// A bomb whose first generation exited, so the rest were adopted.
+	add(1000, 500, "bomb")
+	for pid := int32(1001); pid < 1060; pid++ {
+		add(pid, 1000, "bomb")
+	}
+	for pid := int32(2000); pid < 2040; pid++ {
+		add(pid, 1, "bomb")
+	}
+	// A few builds' worth of compilers stay under the rate.
+	for pid := int32(3000); pid < 3010; pid++ {
+		add(pid, 500, "cc1")
+	}
+	byPID := make(map[int32]int)
+	for i, info := range scan.infos {
+		byPID[info.PID] = i
+	}
+
+	got := forkAnomalies(scan, byPID, &prev, AnomalyOptions{}.withDefaults())
+	require.Len(t, got, 2)
+	byOrigin := map[int32]*models.Anomaly{got[0].PID: got[0], got[1].PID: got[1]}
+	under := byOrigin[500]
+	require.NotNil(t, under)
+	assert.Equal(t, models.AnomalyForkBomb, under.Kind)
+	assert.Equal(t, "bash", under.Command)
+	assert.Equal(t, "bomb", under.ChildCommand)
+	assert.Equal(t, 60, under.Count)
+	assert.Equal(t, 30.0, under.Rate)
+	assert.Len(t, under.PIDs, maxAnomalyPIDs)
+	adopted := byOrigin[1]
+	require.NotNil(t, adopted)
+	assert.Equal(t, 40, adopted.Count)
+
+	assert.Empty(t, forkAnomalies(scan, byPID, &prev, AnomalyOptions{ForkRate: 50}.withDefaults()))
+}
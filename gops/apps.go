@@ -0,0 +1,400 @@
+package gops
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// appIndexTTL is how long the desktop entries are reused before the
+// application directories are read again, so newly installed apps show up.
+const appIndexTTL = time.Minute
+
+// desktopApp is the part of a .desktop file dgop uses.
+type desktopApp struct {
+	id   string
+	name string
+	icon string
+}
+
+// appIndex maps desktop file IDs, Exec names and StartupWMClass values to
+// the entries that declare them. Keys are lowercase.
+type appIndex struct {
+	loadedAt time.Time
+	byID     map[string]*desktopApp
+	byExec   map[string]*desktopApp
+	byClass  map[string]*desktopApp
+}
+
+// wrapperExecs start something else, so an Exec line naming them says
+// nothing about which process is the app.
+var wrapperExecs = map[string]bool{
+	"env": true, "sh": true, "bash": true, "flatpak": true, "snap": true,
+	"python": true, "python3": true, "perl": true, "java": true, "gjs": true, "node": true,
+}
+
+// applicationDirs returns the XDG application directories, most important
+// first, including the Flatpak and Snap exports.
+func applicationDirs() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = path.Join(home, ".local/share")
+		}
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+
+	var dirs []string
+	if dataHome != "" {
+		dirs = append(dirs, dataHome, path.Join(dataHome, "flatpak/exports/share"))
+	}
+	dirs = append(dirs, strings.Split(dataDirs, ":")...)
+	dirs = append(dirs, "/var/lib/flatpak/exports/share", "/var/lib/snapd/desktop")
+	for i, dir := range dirs {
+		dirs[i] = path.Join(dir, "applications")
+	}
+	return dirs
+}
+
+// appIndex returns the desktop entries, rereading them once they're older
+// than appIndexTTL.
+func (self *GopsUtil) appIndex() *appIndex {
+	if idx := self.apps.Load(); idx != nil && time.Since(idx.loadedAt) < appIndexTTL {
+		return idx
+	}
+	idx := &appIndex{
+		loadedAt: time.Now(),
+		byID:     make(map[string]*desktopApp),
+		byExec:   make(map[string]*desktopApp),
+		byClass:  make(map[string]*desktopApp),
+	}
+	for _, dir := range applicationDirs() {
+		self.loadDesktopDir(idx, dir, "")
+	}
+	self.apps.Store(idx)
+	return idx
+}
+
+// loadDesktopDir adds the entries under dir. Files in subdirectories get
+// IDs with the directory names joined by dashes, as the desktop entry spec
+// says, and an ID seen in an earlier directory wins.
+func (self *GopsUtil) loadDesktopDir(idx *appIndex, dir, prefix string) {
+	entries, err := self.fs.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			self.loadDesktopDir(idx, path.Join(dir, name), prefix+name+"-")
+			continue
+		}
+		id, ok := strings.CutSuffix(name, ".desktop")
+		if !ok {
+			continue
+		}
+		id = prefix + id
+		key := strings.ToLower(id)
+		if _, seen := idx.byID[key]; seen {
+			continue
+		}
+		data, err := self.fs.ReadFile(path.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		entry, exec, class, ok := parseDesktopEntry(data)
+		if !ok {
+			continue
+		}
+		app := &desktopApp{id: id, name: entry.name, icon: entry.icon}
+		idx.byID[key] = app
+		if exec != "" && !wrapperExecs[exec] {
+			if _, dup := idx.byExec[exec]; !dup {
+				idx.byExec[exec] = app
+			}
+		}
+		if class != "" {
+			if _, dup := idx.byClass[class]; !dup {
+				idx.byClass[class] = app
+			}
+		}
+	}
+}
+
+// parseDesktopEntry reads the [Desktop Entry] group of a .desktop file. exec
+// is the lowercase base name of the program Exec runs, left empty for
+// entries hidden from menus, and class is StartupWMClass in lowercase. ok is
+// false for entries that aren't applications or are marked Hidden.
+func parseDesktopEntry(data []byte) (app desktopApp, exec, class string, ok bool) {
+	var typ string
+	var noDisplay, hidden, inEntry bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			if inEntry {
+				break
+			}
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Type":
+			typ = value
+		case "Name":
+			app.name = value
+		case "Icon":
+			app.icon = value
+		case "Exec":
+			exec = execName(value)
+		case "StartupWMClass":
+			class = strings.ToLower(value)
+		case "NoDisplay":
+			noDisplay = value == "true"
+		case "Hidden":
+			hidden = value == "true"
+		}
+	}
+	if typ != "Application" || hidden {
+		return app, "", "", false
+	}
+	if noDisplay {
+		exec = ""
+	}
+	return app, exec, class, true
+}
+
+// execName returns the lowercase base name of the program an Exec line
+// runs, skipping env and its assignments.
+func execName(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) > 0 && path.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		for len(fields) > 0 && (strings.Contains(fields[0], "=") || strings.HasPrefix(fields[0], "-")) {
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(path.Base(strings.Trim(fields[0], `"'`)))
+}
+
+// lookup finds the entry for an app ID taken from a cgroup, Flatpak or Snap.
+func (idx *appIndex) lookup(appID string) *desktopApp {
+	return idx.byID[strings.ToLower(appID)]
+}
+
+// match finds the entry whose Exec or StartupWMClass names the program of
+// a process that isn't in an app's cgroup.
+func (idx *appIndex) match(p *models.ProcessInfo) *desktopApp {
+	candidates := []string{strings.ToLower(p.Command)}
+	if p.ExecutablePath != "" {
+		candidates = append([]string{strings.ToLower(path.Base(p.ExecutablePath))}, candidates...)
+	}
+	for _, name := range candidates {
+		if app := idx.byExec[name]; app != nil {
+			return app
+		}
+		if app := idx.byClass[name]; app != nil {
+			return app
+		}
+	}
+	return nil
+}
+
+// appIDFromUnit returns the desktop app ID in a systemd unit name, as set
+// by launchers that follow the XDG convention (app[-<launcher>]-<id>-<random>.scope
+// or app[-<launcher>]-<id>[@<random>].service), or by snapd
+// (snap.<snap>.<app>-<uuid>.scope), or "" for other units.
+func appIDFromUnit(unit string) string {
+	if rest, ok := strings.CutPrefix(unit, "snap."); ok {
+		rest, isScope := strings.CutSuffix(rest, ".scope")
+		if !isScope {
+			if rest, ok = strings.CutSuffix(rest, ".service"); !ok {
+				return ""
+			}
+		}
+		snap, app, ok := strings.Cut(rest, ".")
+		// Older snapd separated the scope's UUID with a dot, newer with a dash.
+		app, _, _ = strings.Cut(app, ".")
+		if n := len(app) - len("-00000000-0000-0000-0000-000000000000"); isScope && n > 0 && app[n] == '-' {
+			app = app[:n]
+		}
+		if !ok || snap == "" || app == "" {
+			return ""
+		}
+		// snapd names the desktop file <snap>_<app>.desktop.
+		return snap + "_" + app
+	}
+
+	rest, ok := strings.CutPrefix(unit, "app-")
+	if !ok {
+		return ""
+	}
+	if scope, ok := strings.CutSuffix(rest, ".scope"); ok {
+		i := strings.LastIndexByte(scope, '-')
+		if i < 0 {
+			return ""
+		}
+		rest = scope[:i]
+	} else if service, ok := strings.CutSuffix(rest, ".service"); ok {
+		rest, _, _ = strings.Cut(service, "@")
+	} else {
+		return ""
+	}
+	// Dashes inside the ID are escaped, so a literal one ends the launcher.
+	if _, id, ok := strings.Cut(rest, "-"); ok {
+		rest = id
+	}
+	id := strings.TrimSuffix(unescapeUnit(rest), ".desktop")
+	// D-Bus activated apps carry the bus name: dbus-:1.2-org.gnome.Foo.
+	if after, ok := strings.CutPrefix(id, "dbus-:"); ok {
+		if _, name, ok := strings.Cut(after, "-"); ok {
+			id = name
+		}
+	}
+	return id
+}
+
+// unescapeUnit undoes systemd's \xNN escaping of unit names.
+func unescapeUnit(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if c, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// resolveApps fills in the app fields of procs. A process whose cgroup or
+// sandbox named no app is matched against the desktop entries, and failing
+// that takes the app of its nearest ancestor that has one, so helpers and
+// the shells inside a terminal count towards the app that started them.
+// outside returns processes left out of procs, such as by a filter, so
+// their descendants still find them; it may be nil.
+func (self *GopsUtil) resolveApps(procs []*models.ProcessInfo, outside func(pid int32) *models.ProcessInfo) {
+	idx := self.appIndex()
+	byPID := make(map[int32]*models.ProcessInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+		if p.AppID == "" {
+			if app := idx.match(p); app != nil {
+				p.AppID = app.id
+			}
+		}
+	}
+
+	lookup := func(pid int32) *models.ProcessInfo {
+		p, ok := byPID[pid]
+		if ok || outside == nil {
+			return p
+		}
+		if p = outside(pid); p != nil && p.AppID == "" {
+			if app := idx.match(p); app != nil {
+				p.AppID = app.id
+			}
+		}
+		byPID[pid] = p
+		return p
+	}
+	parentOf := func(pid int32) (int32, bool) {
+		if p := lookup(pid); p != nil {
+			return p.PPID, true
+		}
+		return 0, false
+	}
+	hasApp := func(pid int32) bool {
+		p := lookup(pid)
+		return p != nil && p.AppID != ""
+	}
+
+	for _, p := range procs {
+		if p.AppID != "" {
+			continue
+		}
+		if pid, ok := findAncestor(p.PPID, parentOf, hasApp); ok {
+			p.AppID = byPID[pid].AppID
+		}
+	}
+
+	for _, p := range procs {
+		if p.AppID == "" {
+			continue
+		}
+		p.AppName = p.AppID
+		if app := idx.lookup(p.AppID); app != nil {
+			p.AppName = app.name
+			p.IconName = app.icon
+		}
+	}
+}
+
+// groupProcessesByApp folds the processes of each app into one entry,
+// taken from its lowest PID, with the usage of the others added in.
+// Processes outside any app are merged by executable when mergeChildren is
+// set, as without grouping.
+func groupProcessesByApp(procList []*models.ProcessInfo, mergeChildren bool) []*models.ProcessInfo {
+	var others []*models.ProcessInfo
+	groups := make(map[string][]*models.ProcessInfo)
+	var order []string
+	for _, p := range procList {
+		if p.AppID == "" {
+			others = append(others, p)
+			continue
+		}
+		if _, ok := groups[p.AppID]; !ok {
+			order = append(order, p.AppID)
+		}
+		groups[p.AppID] = append(groups[p.AppID], p)
+	}
+
+	if mergeChildren {
+		others = mergeProcessesByExecutable(others)
+	}
+	result := others
+	for _, appID := range order {
+		members := groups[appID]
+		root := members[0]
+		for _, p := range members[1:] {
+			if p.PID < root.PID {
+				root = p
+			}
+		}
+		group := *root
+		group.ChildCount = 0
+		for _, p := range members {
+			if p != root {
+				addProcessUsage(&group, p)
+			}
+		}
+		result = append(result, &group)
+	}
+	return result
+}
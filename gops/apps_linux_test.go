@@ -0,0 +1,23 @@
+//go:build linux
+
+package gops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppIDFromCgroup(t *testing.T) {
+	assert.Equal(t, "org.gnome.Console",
+		appIDFromCgroup([]byte("0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-gnome-org.gnome.Console-3310.scope\n")))
+	assert.Equal(t, "firefox_firefox",
+		appIDFromCgroup([]byte("12:pids:/user.slice\n1:name=systemd:/user.slice/user-1000.slice/user@1000.service/app.slice/snap.firefox.firefox-0d7e6bd4-5c5b-4a5e-9a21-3c6f1d1e2b9a.scope\n0::/\n")))
+	assert.Empty(t, appIDFromCgroup([]byte("0::/system.slice/sshd.service\n")))
+}
+
+func TestFlatpakAppID(t *testing.T) {
+	info := "[Application]\nname=org.mozilla.firefox\nruntime=runtime/org.freedesktop.Platform/x86_64/23.08\n\n[Instance]\nname=ignored\n"
+	assert.Equal(t, "org.mozilla.firefox", flatpakAppID([]byte(info)))
+	assert.Empty(t, flatpakAppID([]byte("[Instance]\nname=ignored\n")))
+}
@@ -0,0 +1,116 @@
+package gops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppIDFromUnit(t *testing.T) {
+	tests := map[string]string{
+		"app-flatpak-org.mozilla.firefox-12345.scope":                       "org.mozilla.firefox",
+		"app-gnome-org.gnome.Nautilus-4567.scope":                           "org.gnome.Nautilus",
+		`app-niri-xdg\x2duser\x2ddirs-88.scope`:                             "xdg-user-dirs",
+		"app-org.kde.konsole@b1c2.service":                                  "org.kde.konsole",
+		"app-gnome-firefox.desktop-77.scope":                                "firefox",
+		`app-dbus\x2d:1.2\x2dorg.gnome.Calculator.SearchProvider@0.service`: "org.gnome.Calculator.SearchProvider",
+		"snap.firefox.firefox-8a7e4c7e-55e2-4a9c-a1b3-4c5ae0e8b6d1.scope":   "firefox_firefox",
+		"snap.spotify.spotify.service":                                      "spotify_spotify",
+		"session-2.scope":                                                   "",
+		"user@1000.service":                                                 "",
+		"app.slice":                                                         "",
+		"app-nosuffix":                                                      "",
+	}
+	for unit, want := range tests {
+		assert.Equal(t, want, appIDFromUnit(unit), unit)
+	}
+}
+
+func TestParseDesktopEntry(t *testing.T) {
+	app, exec, class, ok := parseDesktopEntry([]byte(`[Desktop Entry]
+Type=Application
+Name=Firefox
+Icon=firefox
+Exec=env MOZ_ENABLE_WAYLAND=1 /usr/lib/firefox/firefox %u
+StartupWMClass=Firefox
+
+[Desktop Action new-window]
+Name=New Window
+Exec=/usr/lib/firefox/firefox --new-window
+`))
+	require.True(t, ok)
+	assert.Equal(t, "Firefox", app.name)
+	assert.Equal(t, "firefox", app.icon)
+	assert.Equal(t, "firefox", exec)
+	assert.Equal(t, "firefox", class)
+
+	_, exec, _, ok = parseDesktopEntry([]byte("[Desktop Entry]\nType=Application\nName=Handler\nExec=handler %u\nNoDisplay=true\n"))
+	assert.True(t, ok)
+	assert.Empty(t, exec, "hidden from menus, so not matched by name")
+
+	_, _, _, ok = parseDesktopEntry([]byte("[Desktop Entry]\nType=Link\nName=Docs\n"))
+	assert.False(t, ok)
+	_, _, _, ok = parseDesktopEntry([]byte("[Desktop Entry]\nType=Application\nName=Gone\nHidden=true\n"))
+	assert.False(t, ok)
+}
+
+func writeDesktopFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestResolveAndGroupApps(t *testing.T) {
+	root := t.TempDir()
+	writeDesktopFiles(t, root, map[string]string{
+		"share/applications/org.mozilla.firefox.desktop": "[Desktop Entry]\nType=Application\nName=Firefox\nIcon=org.mozilla.firefox\nExec=firefox %u\n",
+		"share/applications/kitty.desktop":               "[Desktop Entry]\nType=Application\nName=kitty\nIcon=kitty\nExec=kitty\n",
+		"share/applications/python-tool.desktop":         "[Desktop Entry]\nType=Application\nName=Tool\nExec=python3 -m tool\n",
+	})
+	t.Setenv("XDG_DATA_HOME", "/nonexistent")
+	t.Setenv("XDG_DATA_DIRS", "/share")
+
+	g := NewGopsUtil()
+	g.fs = &RootFileSystem{Root: root}
+
+	procs := []*models.ProcessInfo{
+		{PID: 1, Command: "systemd"},
+		{PID: 100, PPID: 1, Command: "firefox", AppID: "org.mozilla.firefox", MemoryKB: 1000, CPU: 2},
+		{PID: 101, PPID: 100, Command: "Isolated Web Co", MemoryKB: 500, CPU: 1},
+		{PID: 102, PPID: 100, Command: "Isolated Web Co", MemoryKB: 300},
+		{PID: 200, PPID: 1, Command: "kitty", ExecutablePath: "/usr/bin/kitty", MemoryKB: 100},
+		{PID: 201, PPID: 200, Command: "bash", MemoryKB: 10},
+		{PID: 300, PPID: 1, Command: "python3", MemoryKB: 50},
+	}
+	g.resolveApps(procs, nil)
+
+	assert.Empty(t, procs[0].AppID)
+	assert.Equal(t, "Firefox", procs[1].AppName)
+	assert.Equal(t, "org.mozilla.firefox", procs[1].IconName)
+	assert.Equal(t, "org.mozilla.firefox", procs[2].AppID, "children inherit the app")
+	assert.Equal(t, "kitty", procs[4].AppID, "matched by Exec")
+	assert.Equal(t, "kitty", procs[5].AppID, "the shell belongs to the terminal")
+	assert.Empty(t, procs[6].AppID, "an interpreter named by Exec isn't matched")
+
+	grouped := groupProcessesByApp(procs, false)
+	require.Len(t, grouped, 4)
+	byName := make(map[string]*models.ProcessInfo)
+	for _, p := range grouped {
+		byName[p.Command] = p
+	}
+	firefox := byName["firefox"]
+	require.NotNil(t, firefox)
+	assert.Equal(t, int32(100), firefox.PID)
+	assert.Equal(t, uint64(1800), firefox.MemoryKB)
+	assert.Equal(t, 3.0, firefox.CPU)
+	assert.Equal(t, 2, firefox.ChildCount)
+	assert.Equal(t, uint64(110), byName["kitty"].MemoryKB)
+	assert.Equal(t, uint64(1000), procs[1].MemoryKB, "grouping leaves the scan alone")
+}
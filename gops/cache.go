@@ -0,0 +1,199 @@
+package gops
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTLs are how long the server reuses each module's result.
+// Rates and CPU usage are worked out per caller from their own cursor, so
+// cpu, net-rate and disk-rate aren't cached; processes and units cache
+// their scan, and each caller's CPU figures still come from its cursor.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"processes":  500 * time.Millisecond,
+	"units":      time.Second,
+	"memory":     500 * time.Millisecond,
+	"network":    500 * time.Millisecond,
+	"disk":       500 * time.Millisecond,
+	"system":     time.Second,
+	"gpu":        2 * time.Second,
+	"diskmounts": 5 * time.Second,
+	"hardware":   time.Hour,
+}
+
+// ValidateCacheTTLs checks that ttls only names cacheable modules.
+func ValidateCacheTTLs(ttls map[string]time.Duration) error {
+	for module, ttl := range ttls {
+		if _, ok := DefaultCacheTTLs[module]; !ok {
+			return fmt.Errorf("%q can't be cached (expected one of %s)", module, strings.Join(slices.Sorted(maps.Keys(DefaultCacheTTLs)), ", "))
+		}
+		if ttl < 0 {
+			return fmt.Errorf("%s: negative ttl", module)
+		}
+	}
+	return nil
+}
+
+type cacheEntry struct {
+	value any
+	at    time.Time
+}
+
+// moduleCache coalesces concurrent collections of a module into one and
+// reuses the result for the module's TTL.
+type moduleCache struct {
+	group singleflight.Group
+
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]cacheEntry
+	stats   map[string]*models.ModuleCacheStats
+}
+
+// EnableCache turns on coalescing and caching of module results, with ttls
+// overriding DefaultCacheTTLs. A zero TTL still coalesces concurrent
+// callers but keeps nothing. Calling it again updates the TTLs and keeps
+// the statistics.
+func (self *GopsUtil) EnableCache(ttls map[string]time.Duration) error {
+	if err := ValidateCacheTTLs(ttls); err != nil {
+		return err
+	}
+	merged := maps.Clone(DefaultCacheTTLs)
+	maps.Copy(merged, ttls)
+
+	if c := self.cache.Load(); c != nil {
+		c.mu.Lock()
+		c.ttls = merged
+		clear(c.entries)
+		c.mu.Unlock()
+		return nil
+	}
+	self.cache.Store(&moduleCache{
+		ttls:    merged,
+		entries: make(map[string]cacheEntry),
+		stats:   make(map[string]*models.ModuleCacheStats),
+	})
+	return nil
+}
+
+// CacheStats returns hit and miss counts per module, or nil when caching is
+// off.
+func (self *GopsUtil) CacheStats() *models.CacheStats {
+	c := self.cache.Load()
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := &models.CacheStats{Modules: make(map[string]models.ModuleCacheStats, len(c.ttls))}
+	for module, ttl := range c.ttls {
+		stats := models.ModuleCacheStats{TTL: ttl.String()}
+		if s := c.stats[module]; s != nil {
+			stats.Hits, stats.Misses, stats.Coalesced = s.Hits, s.Misses, s.Coalesced
+		}
+		out.Modules[module] = stats
+	}
+	return out
+}
+
+// invalidateCache drops cached results, e.g. when the device filter they
+// were collected with changes.
+func (self *GopsUtil) invalidateCache() {
+	if c := self.cache.Load(); c != nil {
+		c.mu.Lock()
+		clear(c.entries)
+		c.mu.Unlock()
+	}
+}
+
+// cachedModule returns fn's result through the cache when it is enabled.
+// variant separates results that depend on arguments, and results collected
+// before notBefore are never reused. Cached values are shared between
+// callers, who must not modify them.
+//
+// A shared collection outlives any one caller: it runs under the module's
+// own timeout rather than ctx, and a caller whose ctx ends stops waiting
+// for it.
+func cachedModule[T any](ctx context.Context, self *GopsUtil, module, variant string, notBefore time.Time, fn func(context.Context) (T, error)) (T, error) {
+	c := self.cache.Load()
+	if c == nil {
+		return fn(ctx)
+	}
+	key := module + "\x00" + variant
+
+	c.mu.Lock()
+	ttl := c.ttls[module]
+	stats := c.stats[module]
+	if stats == nil {
+		stats = &models.ModuleCacheStats{}
+		c.stats[module] = stats
+	}
+	if e, ok := c.entries[key]; ok && time.Since(e.at) < ttl && !e.at.Before(notBefore) {
+		stats.Hits++
+		c.mu.Unlock()
+		return e.value.(T), nil
+	}
+	c.mu.Unlock()
+
+	leader := false
+	ch := c.group.DoChan(key, func() (any, error) {
+		leader = true
+		c.mu.Lock()
+		stats.Misses++
+		c.mu.Unlock()
+
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), self.moduleTimeout(module))
+		defer cancel()
+		at := time.Now()
+		value, err := fn(flightCtx)
+		if err != nil {
+			return nil, err
+		}
+		e := cacheEntry{value: value, at: at}
+		if ttl > 0 {
+			c.mu.Lock()
+			// Variants such as process filters come from callers, so the
+			// module's expired ones are dropped rather than kept forever.
+			for k, old := range c.entries {
+				if strings.HasPrefix(k, module+"\x00") && at.Sub(old.at) >= ttl {
+					delete(c.entries, k)
+				}
+			}
+			c.entries[key] = e
+			c.mu.Unlock()
+		}
+		return e, nil
+	})
+
+	var zero T
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	if !leader {
+		c.mu.Lock()
+		stats.Coalesced++
+		c.mu.Unlock()
+	}
+
+	if res.Err != nil {
+		return zero, res.Err
+	}
+	e := res.Val.(cacheEntry)
+	if e.at.Before(notBefore) {
+		// Joined a collection that started too early for this caller.
+		return fn(ctx)
+	}
+	return e.value.(T), nil
+}
@@ -0,0 +1,145 @@
+package gops
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cachingUtil(t *testing.T, ttls map[string]time.Duration) *GopsUtil {
+	t.Helper()
+	g := NewGopsUtil()
+	require.NoError(t, g.EnableCache(ttls))
+	return g
+}
+
+func TestCachedModuleReusesResults(t *testing.T) {
+	g := cachingUtil(t, nil)
+	var calls atomic.Int32
+	collect := func(context.Context) (int32, error) { return calls.Add(1), nil }
+
+	first, err := cachedModule(t.Context(), g, "hardware", "", time.Time{}, collect)
+	require.NoError(t, err)
+	second, err := cachedModule(t.Context(), g, "hardware", "", time.Time{}, collect)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.EqualValues(t, 1, calls.Load())
+
+	other, err := cachedModule(t.Context(), g, "hardware", "other", time.Time{}, collect)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other, "variants are cached apart")
+
+	fresh, err := cachedModule(t.Context(), g, "hardware", "", time.Now(), collect)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, fresh, "results older than notBefore aren't reused")
+
+	stats := g.CacheStats().Modules["hardware"]
+	assert.Equal(t, models.ModuleCacheStats{TTL: "1h0m0s", Hits: 1, Misses: 3}, stats)
+}
+
+func TestCachedModuleZeroTTLKeepsNothing(t *testing.T) {
+	g := cachingUtil(t, map[string]time.Duration{"memory": 0})
+	var calls atomic.Int32
+	collect := func(context.Context) (int32, error) { return calls.Add(1), nil }
+
+	for range 3 {
+		_, err := cachedModule(t.Context(), g, "memory", "", time.Time{}, collect)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestCachedModuleDropsExpiredVariants(t *testing.T) {
+	g := cachingUtil(t, map[string]time.Duration{"processes": 20 * time.Millisecond})
+	collect := func(context.Context) (int, error) { return 1, nil }
+
+	for _, variant := range []string{"a", "b", "c"} {
+		_, err := cachedModule(t.Context(), g, "processes", variant, time.Time{}, collect)
+		require.NoError(t, err)
+	}
+	_, err := cachedModule(t.Context(), g, "memory", "", time.Time{}, collect)
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	_, err = cachedModule(t.Context(), g, "processes", "d", time.Time{}, collect)
+	require.NoError(t, err)
+
+	c := g.cache.Load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Len(t, c.entries, 2, "only the new variant and the other module are kept")
+}
+
+func TestCachedModuleCoalescesConcurrentCalls(t *testing.T) {
+	g := cachingUtil(t, map[string]time.Duration{"system": 0})
+	var calls atomic.Int32
+	release := make(chan struct{})
+	collect := func(context.Context) (int32, error) {
+		<-release
+		return calls.Add(1), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int32, 5)
+	for i := range results {
+		wg.Go(func() {
+			results[i], _ = cachedModule(t.Context(), g, "system", "", time.Time{}, collect)
+		})
+	}
+	// Give every caller time to join the first one's collection.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, calls.Load())
+	for _, r := range results {
+		assert.EqualValues(t, 1, r)
+	}
+	stats := g.CacheStats().Modules["system"]
+	assert.EqualValues(t, 1, stats.Misses)
+	assert.EqualValues(t, 4, stats.Coalesced)
+}
+
+func TestSetDeviceFilterInvalidatesCache(t *testing.T) {
+	g := cachingUtil(t, nil)
+	var calls atomic.Int32
+	collect := func(context.Context) (int32, error) { return calls.Add(1), nil }
+
+	_, _ = cachedModule(t.Context(), g, "diskmounts", "", time.Time{}, collect)
+	g.SetDeviceFilter(models.DeviceFilter{ExcludeMounts: []string{"/mnt/*"}})
+	_, _ = cachedModule(t.Context(), g, "diskmounts", "", time.Time{}, collect)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestEnableCacheRejectsUncachedModules(t *testing.T) {
+	assert.Error(t, NewGopsUtil().EnableCache(map[string]time.Duration{"cpu": time.Second}))
+	assert.Error(t, NewGopsUtil().EnableCache(map[string]time.Duration{"hardware": -time.Second}))
+}
+
+func TestCachedProcessScanIsSharedNotMutated(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	// The providers expect a single scan.
+	util := newProcessTestUtil(t, self)
+	require.NoError(t, util.EnableCache(nil))
+
+	first, err := util.GetProcesses(t.Context(), SortByPID, 0, false, false)
+	require.NoError(t, err)
+	require.Len(t, first.Processes, 1)
+	first.Processes[0].CPU = 42
+
+	second, err := util.GetProcesses(t.Context(), SortByPID, 0, false, false)
+	require.NoError(t, err)
+	require.Len(t, second.Processes, 1)
+	assert.Zero(t, second.Processes[0].CPU)
+	assert.Equal(t, openProcessCursor(t, util, first.Cursor), openProcessCursor(t, util, second.Cursor))
+	assert.EqualValues(t, 1, util.CacheStats().Modules["processes"].Hits)
+}
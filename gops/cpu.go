@@ -1,6 +1,7 @@
 package gops
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"sync"
@@ -27,19 +28,31 @@ type CPUTracker struct {
 
 var cpuTracker = &CPUTracker{}
 
-func (self *GopsUtil) GetCPUInfo() (*models.CPUInfo, error) {
-	return self.GetCPUInfoWithCursor("")
+func (self *GopsUtil) GetCPUInfo(ctx context.Context) (*models.CPUInfo, error) {
+	return self.GetCPUInfoWithCursor(ctx, "")
 }
 
-func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, error) {
+func (self *GopsUtil) GetCPUInfoWithCursor(ctx context.Context, cursor string) (*models.CPUInfo, error) {
 	cpuInfo := models.CPUInfo{}
 
+	var cursorData models.CPUCursorData
+	if cursor != "" {
+		payload, err := self.openCursor("cpu", cursor)
+		if err != nil {
+			return nil, err
+		}
+		jsonBytes, err := base64.RawURLEncoding.DecodeString(payload)
+		if err != nil || json.Unmarshal(jsonBytes, &cursorData) != nil {
+			return nil, &CursorError{Module: "cpu", Err: ErrCursorMalformed}
+		}
+	}
+
 	cpuTracker.mu.Lock()
 	defer cpuTracker.mu.Unlock()
 
 	if !cpuTracker.modelCached {
-		cpuTracker.cpuCount, _ = self.cpuProvider.Counts(true)
-		info, err := self.cpuProvider.Info()
+		cpuTracker.cpuCount, _ = self.cpuProvider.Counts(ctx, true)
+		info, err := self.cpuProvider.Info(ctx)
 		if err == nil && len(info) > 0 {
 			cpuTracker.cpuModel = info[0].ModelName
 			cpuTracker.cpuFreq = info[0].Mhz
@@ -49,7 +62,7 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 
 	now := time.Now()
 	if now.Sub(cpuTracker.freqLastRead) > 2*time.Second {
-		cpuTracker.freqValue = getCurrentCPUFreq()
+		cpuTracker.freqValue = self.getCurrentCPUFreq()
 		cpuTracker.freqLastRead = now
 	}
 	if cpuTracker.freqValue > 0 {
@@ -62,12 +75,12 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 	cpuInfo.Model = cpuTracker.cpuModel
 
 	if now.Sub(cpuTracker.tempLastRead) > 5*time.Second {
-		cpuTracker.tempValue = getCPUTemperatureCached()
+		cpuTracker.tempValue = self.getCPUTemperatureCached()
 		cpuTracker.tempLastRead = now
 	}
 	cpuInfo.Temperature = cpuTracker.tempValue
 
-	times, err := self.cpuProvider.Times(false)
+	times, err := self.cpuProvider.Times(ctx, false)
 	if err == nil && len(times) > 0 {
 		t := times[0]
 		cpuInfo.Total = []float64{
@@ -77,7 +90,7 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 		}
 	}
 
-	perCore, err := self.cpuProvider.Times(true)
+	perCore, err := self.cpuProvider.Times(ctx, true)
 	if err == nil {
 		cpuInfo.Cores = make([][]float64, len(perCore))
 		for i, c := range perCore {
@@ -91,19 +104,10 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 
 	currentTime := now.UnixMilli()
 
-	var cursorData models.CPUCursorData
-	if cursor != "" {
-		if jsonBytes, err := base64.RawURLEncoding.DecodeString(cursor); err == nil {
-			if err := json.Unmarshal(jsonBytes, &cursorData); err != nil {
-				cursorData = models.CPUCursorData{}
-			}
-		}
-	}
-
 	if len(cursorData.Total) > 0 && len(cpuInfo.Total) > 0 && cursorData.Timestamp > 0 {
 		timeDiff := float64(currentTime-cursorData.Timestamp) / 1000.0
 		if timeDiff > 0 {
-			totalUsage, coreUsages := cpuUsageFromProvider(self.cpuProvider, cursorData.Total, cpuInfo.Total, timeDiff, cpuInfo.Count)
+			totalUsage, coreUsages := cpuUsageFromProvider(ctx, self.cpuProvider, cursorData.Total, cpuInfo.Total, timeDiff, cpuInfo.Count)
 			cpuInfo.Usage = totalUsage
 
 			switch {
@@ -119,12 +123,12 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 	} else {
 		primeCPUPercent()
 
-		cpuPercent, err := self.cpuProvider.Percent(100*time.Millisecond, false)
+		cpuPercent, err := self.cpuProvider.Percent(ctx, 100*time.Millisecond, false)
 		if err == nil && len(cpuPercent) > 0 {
 			cpuInfo.Usage = cpuPercent[0]
 		}
 
-		corePercent, err := self.cpuProvider.Percent(100*time.Millisecond, true)
+		corePercent, err := self.cpuProvider.Percent(ctx, 100*time.Millisecond, true)
 		if err == nil {
 			cpuInfo.CoreUsage = corePercent
 		}
@@ -136,7 +140,7 @@ func (self *GopsUtil) GetCPUInfoWithCursor(cursor string) (*models.CPUInfo, erro
 		Timestamp: currentTime,
 	}
 	cursorBytes, _ := json.Marshal(newCursor)
-	cpuInfo.Cursor = base64.RawURLEncoding.EncodeToString(cursorBytes)
+	cpuInfo.Cursor = self.sealCursor("cpu", base64.RawURLEncoding.EncodeToString(cursorBytes))
 
 	return &cpuInfo, nil
 }
@@ -3,16 +3,17 @@
 package gops
 
 import (
+	"context"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
 )
 
-func getCPUTemperatureCached() float64 {
+func (self *GopsUtil) getCPUTemperatureCached() float64 {
 	return 0
 }
 
-func getCurrentCPUFreq() float64 {
+func (self *GopsUtil) getCurrentCPUFreq() float64 {
 	return 0
 }
 
@@ -21,7 +22,7 @@ func getCurrentCPUFreq() float64 {
 // host_processor_info may not account for parked efficiency cores correctly,
 // inflating the busy/total ratio. gopsutil's Percent(0) uses its own internal
 // delta cache and is tested on macOS.
-func cpuUsageFromProvider(cpuProvider CPUInfoProvider, cursorTotal, currentTotal []float64, timeDiff float64, numCPUs int) (float64, []float64) {
+func cpuUsageFromProvider(ctx context.Context, cpuProvider CPUInfoProvider, cursorTotal, currentTotal []float64, timeDiff float64, numCPUs int) (float64, []float64) {
 	totalPercent := 0.0
 	var corePercents []float64
 
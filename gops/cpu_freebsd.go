@@ -2,7 +2,10 @@
 
 package gops
 
-import "golang.org/x/sys/unix"
+import (
+	"context"
+	"golang.org/x/sys/unix"
+)
 
 // "IK" sysctls store decikelvin: val = celsius*10 + TZ_ZEROC (2731),
 // per sys/dev/coretemp/coretemp.c and sys/dev/acpica/acpi_thermal.c.
@@ -12,7 +15,7 @@ func deciKelvinToCelsius(raw uint32) float64 {
 	return float64(int32(raw)-deciKelvinZeroC) / 10.0
 }
 
-func getCPUTemperatureCached() float64 {
+func (self *GopsUtil) getCPUTemperatureCached() float64 {
 	// dev.cpu.%d.temperature per coretemp(4)/amdtemp(4).
 	if raw, err := unix.SysctlUint32("dev.cpu.0.temperature"); err == nil {
 		return deciKelvinToCelsius(raw)
@@ -31,7 +34,7 @@ func getCPUTemperatureCached() float64 {
 	return temp
 }
 
-func getCurrentCPUFreq() float64 {
+func (self *GopsUtil) getCurrentCPUFreq() float64 {
 	// dev.cpu.%d.freq per cpufreq(4), in MHz.
 	freq, err := unix.SysctlUint32("dev.cpu.0.freq")
 	if err != nil {
@@ -42,7 +45,7 @@ func getCurrentCPUFreq() float64 {
 
 // cpuUsageFromProvider on FreeBSD uses the tick-ratio approach like Linux;
 // gopsutil reads kern.cp_times, which always includes all cores.
-func cpuUsageFromProvider(_ CPUInfoProvider, cursorTotal, currentTotal []float64, _ float64, _ int) (float64, []float64) {
+func cpuUsageFromProvider(_ context.Context, _ CPUInfoProvider, cursorTotal, currentTotal []float64, _ float64, _ int) (float64, []float64) {
 	return calculateCPUPercentage(cursorTotal, currentTotal), nil
 }
 
@@ -1,6 +1,7 @@
 package gops
 
 import (
+	"io/fs"
 	"testing"
 	"time"
 
@@ -11,6 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// expectCPUFiles serves /proc/cpuinfo at mhz and no temperature sensors. The
+// reads are optional since only Linux makes them.
+func expectCPUFiles(mockFS *mocks.MockFileSystem, mhz string) {
+	mockFS.EXPECT().ReadFile("/proc/cpuinfo").Return([]byte("cpu MHz\t\t: "+mhz+"\n"), nil).Maybe()
+	mockFS.EXPECT().ReadFile(mock.Anything).Return(nil, fs.ErrNotExist).Maybe()
+	mockFS.EXPECT().ReadDir(mock.Anything).Return(nil, fs.ErrNotExist).Maybe()
+}
+
 func TestGetCPUInfo_WithMocks(t *testing.T) {
 	mockCPU := mocks.NewMockCPUInfoProvider(t)
 	mockMem := mocks.NewMockMemoryInfoProvider(t)
@@ -35,14 +44,15 @@ func TestGetCPUInfo_WithMocks(t *testing.T) {
 	cpuTracker.modelCached = false
 	cpuTracker.freqLastRead = time.Time{}
 	cpuTracker.tempLastRead = time.Time{}
+	expectCPUFiles(mockFS, "4100.000")
 
 	mockCPU.EXPECT().
-		Counts(true).
+		Counts(mock.Anything, true).
 		Return(8, nil).
 		Once()
 
 	mockCPU.EXPECT().
-		Info().
+		Info(mock.Anything).
 		Return([]cpu.InfoStat{
 			{
 				ModelName: "AMD Ryzen 7 5800X",
@@ -52,7 +62,7 @@ func TestGetCPUInfo_WithMocks(t *testing.T) {
 		Once()
 
 	mockCPU.EXPECT().
-		Times(false).
+		Times(mock.Anything, false).
 		Return([]cpu.TimesStat{
 			{
 				User:    1000.0,
@@ -68,7 +78,7 @@ func TestGetCPUInfo_WithMocks(t *testing.T) {
 		Once()
 
 	mockCPU.EXPECT().
-		Times(true).
+		Times(mock.Anything, true).
 		Return([]cpu.TimesStat{
 			{User: 100.0, Nice: 0.0, System: 50.0, Idle: 850.0, Iowait: 0.0, Irq: 0.0, Softirq: 0.0, Steal: 0.0},
 			{User: 110.0, Nice: 0.0, System: 60.0, Idle: 830.0, Iowait: 0.0, Irq: 0.0, Softirq: 0.0, Steal: 0.0},
@@ -78,16 +88,16 @@ func TestGetCPUInfo_WithMocks(t *testing.T) {
 		Once()
 
 	mockCPU.EXPECT().
-		Percent(100*time.Millisecond, false).
+		Percent(mock.Anything, 100*time.Millisecond, false).
 		Return([]float64{25.5}, nil).
 		Once()
 
 	mockCPU.EXPECT().
-		Percent(100*time.Millisecond, true).
+		Percent(mock.Anything, 100*time.Millisecond, true).
 		Return([]float64{20.0, 25.0, 30.0, 35.0}, nil).
 		Once()
 
-	result, err := gops.GetCPUInfo()
+	result, err := gops.GetCPUInfo(t.Context())
 
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -123,9 +133,10 @@ func TestGetCPUInfoWithCursor_WithMocks(t *testing.T) {
 		mockLoad,
 		mockFS,
 	)
+	expectCPUFiles(mockFS, "4100.000")
 
 	mockCPU.EXPECT().
-		Times(false).
+		Times(mock.Anything, false).
 		Return([]cpu.TimesStat{
 			{
 				User:    2000.0,
@@ -141,7 +152,7 @@ func TestGetCPUInfoWithCursor_WithMocks(t *testing.T) {
 		Once()
 
 	mockCPU.EXPECT().
-		Times(true).
+		Times(mock.Anything, true).
 		Return([]cpu.TimesStat{
 			{User: 200.0, Nice: 0.0, System: 100.0, Idle: 1700.0, Iowait: 0.0, Irq: 0.0, Softirq: 0.0, Steal: 0.0},
 			{User: 210.0, Nice: 0.0, System: 110.0, Idle: 1680.0, Iowait: 0.0, Irq: 0.0, Softirq: 0.0, Steal: 0.0},
@@ -150,7 +161,7 @@ func TestGetCPUInfoWithCursor_WithMocks(t *testing.T) {
 
 	cursor := "eyJUb3RhbCI6WzEwMDAsMCw1MDAsODUwMCwwLDAsMCwwXSwiQ29yZXMiOltbMTAwLDAsNTAsODUwLDAsMCwwLDBdLFsxMTAsMCw2MCw4MzAsMCwwLDAsMF1dLCJUaW1lc3RhbXAiOjE2MzA1MjYyNzAwMDB9"
 
-	result, err := gops.GetCPUInfoWithCursor(cursor)
+	result, err := gops.GetCPUInfoWithCursor(t.Context(), gops.sealCursor("cpu", cursor))
 
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -172,22 +183,22 @@ func TestGetCPUInfo_ErrorHandling(t *testing.T) {
 			name: "handles Info() error gracefully",
 			setupMocks: func(m *mocks.MockCPUInfoProvider) {
 				cpuTracker.modelCached = false
-				m.EXPECT().Counts(true).Return(0, assert.AnError).Once()
-				m.EXPECT().Info().Return(nil, assert.AnError).Once()
-				m.EXPECT().Times(false).Return([]cpu.TimesStat{{User: 100, Idle: 900}}, nil).Once()
-				m.EXPECT().Times(true).Return([]cpu.TimesStat{{User: 100, Idle: 900}}, nil).Once()
-				m.EXPECT().Percent(mock.Anything, false).Return([]float64{10.0}, nil).Once()
-				m.EXPECT().Percent(mock.Anything, true).Return([]float64{10.0}, nil).Once()
+				m.EXPECT().Counts(mock.Anything, true).Return(0, assert.AnError).Once()
+				m.EXPECT().Info(mock.Anything).Return(nil, assert.AnError).Once()
+				m.EXPECT().Times(mock.Anything, false).Return([]cpu.TimesStat{{User: 100, Idle: 900}}, nil).Once()
+				m.EXPECT().Times(mock.Anything, true).Return([]cpu.TimesStat{{User: 100, Idle: 900}}, nil).Once()
+				m.EXPECT().Percent(mock.Anything, mock.Anything, false).Return([]float64{10.0}, nil).Once()
+				m.EXPECT().Percent(mock.Anything, mock.Anything, true).Return([]float64{10.0}, nil).Once()
 			},
 			expectError: false,
 		},
 		{
 			name: "handles Times() error gracefully",
 			setupMocks: func(m *mocks.MockCPUInfoProvider) {
-				m.EXPECT().Times(false).Return(nil, assert.AnError).Once()
-				m.EXPECT().Times(true).Return(nil, assert.AnError).Once()
-				m.EXPECT().Percent(mock.Anything, false).Return([]float64{10.0}, nil).Once()
-				m.EXPECT().Percent(mock.Anything, true).Return([]float64{10.0}, nil).Once()
+				m.EXPECT().Times(mock.Anything, false).Return(nil, assert.AnError).Once()
+				m.EXPECT().Times(mock.Anything, true).Return(nil, assert.AnError).Once()
+				m.EXPECT().Percent(mock.Anything, mock.Anything, false).Return([]float64{10.0}, nil).Once()
+				m.EXPECT().Percent(mock.Anything, mock.Anything, true).Return([]float64{10.0}, nil).Once()
 			},
 			expectError: false,
 		},
@@ -215,9 +226,10 @@ func TestGetCPUInfo_ErrorHandling(t *testing.T) {
 				mockFS,
 			)
 
+			expectCPUFiles(mockFS, "4100.000")
 			tt.setupMocks(mockCPU)
 
-			result, err := gops.GetCPUInfo()
+			result, err := gops.GetCPUInfo(t.Context())
 
 			if tt.expectError {
 				assert.Error(t, err)
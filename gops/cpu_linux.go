@@ -3,40 +3,41 @@
 package gops
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 )
 
-func getCPUTemperatureCached() float64 {
+func (self *GopsUtil) getCPUTemperatureCached() float64 {
 	if cpuTracker.tempPath != "" {
-		tempBytes, err := os.ReadFile(cpuTracker.tempPath)
+		tempBytes, err := self.fs.ReadFile(cpuTracker.tempPath)
 		if err != nil {
 			cpuTracker.tempPath = ""
-			return getCPUTemperatureCached()
+			return self.getCPUTemperatureCached()
 		}
 		temp, err := strconv.Atoi(strings.TrimSpace(string(tempBytes)))
 		if err != nil {
 			cpuTracker.tempPath = ""
-			return getCPUTemperatureCached()
+			return self.getCPUTemperatureCached()
 		}
 		return float64(temp) / 1000.0
 	}
 
 	hwmonPath := "/sys/class/hwmon"
-	entries, err := os.ReadDir(hwmonPath)
+	entries, err := self.fs.ReadDir(hwmonPath)
 	if err != nil {
-		return getACPITZFallback()
+		return self.getACPITZFallback()
 	}
 
 	for _, entry := range entries {
 		// CPU temp sensors are never on i2c; skip to avoid blocking on a held bus (e.g. OpenRGB).
-		if target, err := os.Readlink(filepath.Join(hwmonPath, entry.Name())); err == nil && strings.Contains(target, "/i2c-") {
+		if target, err := self.fs.Readlink(filepath.Join(hwmonPath, entry.Name())); err == nil && strings.Contains(target, "/i2c-") {
 			continue
 		}
 
-		nameBytes, err := os.ReadFile(filepath.Join(hwmonPath, entry.Name(), "name"))
+		nameBytes, err := self.fs.ReadFile(filepath.Join(hwmonPath, entry.Name(), "name"))
 		if err != nil {
 			continue
 		}
@@ -47,7 +48,7 @@ func getCPUTemperatureCached() float64 {
 			continue
 		}
 
-		temp, tempPath, ok := readCoretempInput(filepath.Join(hwmonPath, entry.Name()))
+		temp, tempPath, ok := self.readCoretempInput(filepath.Join(hwmonPath, entry.Name()))
 		if !ok {
 			continue
 		}
@@ -55,23 +56,23 @@ func getCPUTemperatureCached() float64 {
 		return temp
 	}
 
-	return getACPITZFallback()
+	return self.getACPITZFallback()
 }
 
 // readCoretempInput reads a CPU temperature from a coretemp hwmon directory,
 // preferring the package sensor (temp1_input). Older CPUs (e.g. Nehalem i7 9xx)
 // lack it and expose only per-core inputs, so fall back to the hottest core.
-func readCoretempInput(dir string) (float64, string, bool) {
+func (self *GopsUtil) readCoretempInput(dir string) (float64, string, bool) {
 	pkgPath := filepath.Join(dir, "temp1_input")
-	if temp, ok := readMilliCelsius(pkgPath); ok {
+	if temp, ok := self.readMilliCelsius(pkgPath); ok {
 		return temp, pkgPath, true
 	}
 
-	matches, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+	matches, _ := self.fs.Glob(filepath.Join(dir, "temp*_input"))
 	var bestTemp float64
 	var bestPath string
 	for _, path := range matches {
-		temp, ok := readMilliCelsius(path)
+		temp, ok := self.readMilliCelsius(path)
 		if !ok || (bestPath != "" && temp <= bestTemp) {
 			continue
 		}
@@ -83,8 +84,8 @@ func readCoretempInput(dir string) (float64, string, bool) {
 	return bestTemp, bestPath, true
 }
 
-func readMilliCelsius(path string) (float64, bool) {
-	tempBytes, err := os.ReadFile(path)
+func (self *GopsUtil) readMilliCelsius(path string) (float64, bool) {
+	tempBytes, err := self.fs.ReadFile(path)
 	if err != nil {
 		return 0, false
 	}
@@ -95,17 +96,17 @@ func readMilliCelsius(path string) (float64, bool) {
 	return float64(temp) / 1000.0, true
 }
 
-func getACPITZFallback() float64 {
+func (self *GopsUtil) getACPITZFallback() float64 {
 	thermalPath := "/sys/class/thermal"
-	thermalEntries, err := os.ReadDir(thermalPath)
+	thermalEntries, err := self.fs.ReadDir(thermalPath)
 	if err != nil {
 		return 0
 	}
-	return getMaxACPITZTemperature(thermalPath, thermalEntries, 20, 100, true)
+	return self.getMaxACPITZTemperature(thermalPath, thermalEntries, 20, 100, true)
 }
 
-func getCurrentCPUFreq() float64 {
-	cpuinfoBytes, err := os.ReadFile("/proc/cpuinfo")
+func (self *GopsUtil) getCurrentCPUFreq() float64 {
+	cpuinfoBytes, err := self.fs.ReadFile("/proc/cpuinfo")
 	if err != nil {
 		return 0
 	}
@@ -123,7 +124,7 @@ func getCurrentCPUFreq() float64 {
 		}
 	}
 
-	freqBytes, err := os.ReadFile("/sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq")
+	freqBytes, err := self.fs.ReadFile("/sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq")
 	if err == nil {
 		freq, err := strconv.Atoi(strings.TrimSpace(string(freqBytes)))
 		if err == nil {
@@ -134,7 +135,7 @@ func getCurrentCPUFreq() float64 {
 	return 0
 }
 
-func getMaxACPITZTemperature(thermalPath string, thermalEntries []os.DirEntry, minTemp, maxTemp float64, isCPU bool) float64 {
+func (self *GopsUtil) getMaxACPITZTemperature(thermalPath string, thermalEntries []os.DirEntry, minTemp, maxTemp float64, isCPU bool) float64 {
 	var highestTemp float64
 
 	for _, entry := range thermalEntries {
@@ -142,7 +143,7 @@ func getMaxACPITZTemperature(thermalPath string, thermalEntries []os.DirEntry, m
 			continue
 		}
 
-		thermalType, err := readThermalType(thermalPath, entry.Name())
+		thermalType, err := self.readThermalType(thermalPath, entry.Name())
 		if err != nil {
 			continue
 		}
@@ -151,7 +152,7 @@ func getMaxACPITZTemperature(thermalPath string, thermalEntries []os.DirEntry, m
 			continue
 		}
 
-		temp, tempPath, err := readThermalTemp(thermalPath, entry.Name())
+		temp, tempPath, err := self.readThermalTemp(thermalPath, entry.Name())
 		if err != nil {
 			continue
 		}
@@ -171,9 +172,9 @@ func getMaxACPITZTemperature(thermalPath string, thermalEntries []os.DirEntry, m
 	return highestTemp
 }
 
-func readThermalType(thermalPath, entryName string) (string, error) {
+func (self *GopsUtil) readThermalType(thermalPath, entryName string) (string, error) {
 	typePath := filepath.Join(thermalPath, entryName, "type")
-	typeBytes, err := os.ReadFile(typePath)
+	typeBytes, err := self.fs.ReadFile(typePath)
 	if err != nil {
 		return "", err
 	}
@@ -182,15 +183,15 @@ func readThermalType(thermalPath, entryName string) (string, error) {
 
 // cpuUsageFromProvider on Linux uses the tick-ratio approach, which is accurate
 // because /proc/stat always includes all cores (even idle ones).
-func cpuUsageFromProvider(_ CPUInfoProvider, cursorTotal, currentTotal []float64, _ float64, _ int) (float64, []float64) {
+func cpuUsageFromProvider(_ context.Context, _ CPUInfoProvider, cursorTotal, currentTotal []float64, _ float64, _ int) (float64, []float64) {
 	return calculateCPUPercentage(cursorTotal, currentTotal), nil
 }
 
 func primeCPUPercent() {}
 
-func readThermalTemp(thermalPath, entryName string) (float64, string, error) {
+func (self *GopsUtil) readThermalTemp(thermalPath, entryName string) (float64, string, error) {
 	tempPath := filepath.Join(thermalPath, entryName, "temp")
-	tempBytes, err := os.ReadFile(tempPath)
+	tempBytes, err := self.fs.ReadFile(tempPath)
 	if err != nil {
 		return 0, "", err
 	}
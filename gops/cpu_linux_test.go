@@ -11,17 +11,17 @@ import (
 )
 
 func TestReadThermalTemp(t *testing.T) {
-	_, _, err := readThermalTemp("/nonexistent/path", "thermal_zone0")
+	_, _, err := NewGopsUtil().readThermalTemp("/nonexistent/path", "thermal_zone0")
 	assert.Error(t, err, "Should error on nonexistent path")
 }
 
 func TestReadThermalType(t *testing.T) {
-	_, err := readThermalType("/nonexistent/path", "thermal_zone0")
+	_, err := NewGopsUtil().readThermalType("/nonexistent/path", "thermal_zone0")
 	assert.Error(t, err, "Should error on nonexistent path")
 }
 
 func TestGetMaxACPITZTemperature(t *testing.T) {
-	result := getMaxACPITZTemperature("/nonexistent", []os.DirEntry{}, 20, 100, true)
+	result := NewGopsUtil().getMaxACPITZTemperature("/nonexistent", []os.DirEntry{}, 20, 100, true)
 	assert.Equal(t, float64(0), result, "Should return 0 for empty entries")
 }
 
@@ -35,7 +35,7 @@ func TestReadCoretempInputPackage(t *testing.T) {
 	writeTemp(t, dir, "temp1_input", "45000\n")
 	writeTemp(t, dir, "temp2_input", "73000\n")
 
-	temp, path, ok := readCoretempInput(dir)
+	temp, path, ok := NewGopsUtil().readCoretempInput(dir)
 	assert.True(t, ok)
 	assert.Equal(t, 45.0, temp, "Should prefer the package sensor (temp1_input)")
 	assert.Equal(t, filepath.Join(dir, "temp1_input"), path)
@@ -49,13 +49,13 @@ func TestReadCoretempInputPerCoreFallback(t *testing.T) {
 	writeTemp(t, dir, "temp3_input", "76500\n")
 	writeTemp(t, dir, "temp4_input", "74000\n")
 
-	temp, path, ok := readCoretempInput(dir)
+	temp, path, ok := NewGopsUtil().readCoretempInput(dir)
 	assert.True(t, ok)
 	assert.Equal(t, 76.5, temp, "Should use the hottest core when no package sensor exists")
 	assert.Equal(t, filepath.Join(dir, "temp3_input"), path)
 }
 
 func TestReadCoretempInputMissing(t *testing.T) {
-	_, _, ok := readCoretempInput(t.TempDir())
+	_, _, ok := NewGopsUtil().readCoretempInput(t.TempDir())
 	assert.False(t, ok, "Should report no reading when no temp inputs exist")
 }
@@ -0,0 +1,217 @@
+package gops
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// CursorKeySize is the length of the key cursors are signed with.
+const CursorKeySize = 32
+
+// CursorMaxAge bounds how old a cursor may be. Rates over a longer interval
+// say little about now.
+const CursorMaxAge = time.Hour
+
+// cursorMACSize is the truncated HMAC-SHA256 tag length.
+const cursorMACSize = 16
+
+var (
+	ErrCursorMalformed = errors.New("malformed cursor")
+	ErrCursorExpired   = errors.New("cursor expired")
+	ErrCursorWrongHost = errors.New("cursor is from another host")
+	ErrCursorWrongBoot = errors.New("cursor is from a previous boot")
+)
+
+// CursorError is returned when a cursor passed in can't be used. Err is one
+// of the ErrCursor values, possibly wrapped with detail, so callers can
+// check the reason with errors.Is. A caller that gets one should drop the
+// cursor and start again without it.
+type CursorError struct {
+	// Module is the module the cursor was passed for, e.g. cpu or net-rate.
+	Module string
+	Err    error
+}
+
+func (e *CursorError) Error() string {
+	return fmt.Sprintf("invalid %s cursor: %v", e.Module, e.Err)
+}
+
+func (e *CursorError) Unwrap() error {
+	return e.Err
+}
+
+// IsCursorError reports whether err comes from a cursor that was rejected.
+func IsCursorError(err error) bool {
+	var cursorErr *CursorError
+	return errors.As(err, &cursorErr)
+}
+
+// cursorHeader travels in the clear ahead of the payload so the reason a
+// cursor is rejected can be told apart; the MAC covers it too.
+type cursorHeader struct {
+	Module string `json:"m"`
+	Host   string `json:"h"`
+	Boot   string `json:"b,omitempty"`
+	Issued int64  `json:"t"`
+}
+
+// cursorSigner signs the cursors a GopsUtil emits and checks the ones it is
+// given. Each cursor is header.payload.mac, with the header and MAC in
+// unpadded URL-safe base64 and the payload in the module's own encoding.
+type cursorSigner struct {
+	mu  sync.Mutex
+	key []byte
+	// host and boot identify the system being read, loaded on first use.
+	host, boot string
+	loaded     bool
+}
+
+// SetCursorKey sets the key cursors are signed with. Cursors only verify
+// with the key that signed them, so a key that outlives the process, such
+// as the per-install key from the config directory, lets cursors be passed
+// between runs of the CLI. Without one a random key is used.
+func (self *GopsUtil) SetCursorKey(key []byte) error {
+	if len(key) < CursorKeySize {
+		return fmt.Errorf("cursor key must be at least %d bytes", CursorKeySize)
+	}
+	self.signer.mu.Lock()
+	defer self.signer.mu.Unlock()
+	self.signer.key = append([]byte(nil), key...)
+	return nil
+}
+
+// NewCursorKey returns a random key for SetCursorKey.
+func NewCursorKey() []byte {
+	key := make([]byte, CursorKeySize)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// cursorIdentity returns the signing key and the host and boot the cursors
+// belong to.
+func (self *GopsUtil) cursorIdentity() (key []byte, host, boot string) {
+	s := &self.signer
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil {
+		s.key = NewCursorKey()
+	}
+	if !s.loaded {
+		s.host, s.boot = self.readHostIdentity()
+		s.loaded = true
+	}
+	return s.key, s.host, s.boot
+}
+
+// readHostIdentity returns a short hash of the hostname, so cursors don't
+// carry it, and the kernel's boot ID. Where there is no boot_id the boot
+// time stands in.
+func (self *GopsUtil) readHostIdentity() (string, string) {
+	hostname := ""
+	if data, err := self.fs.ReadFile("/proc/sys/kernel/hostname"); err == nil {
+		hostname = strings.TrimSpace(string(data))
+	} else {
+		hostname, _ = os.Hostname()
+	}
+	sum := sha256.Sum256([]byte(hostname))
+
+	boot := ""
+	if data, err := self.fs.ReadFile("/proc/sys/kernel/random/boot_id"); err == nil {
+		boot = strings.TrimSpace(string(data))
+	} else if bootTime, err := host.BootTimeWithContext(self.env.context(context.Background())); err == nil && bootTime > 0 {
+		boot = strconv.FormatUint(bootTime, 10)
+	}
+	return hex.EncodeToString(sum[:8]), boot
+}
+
+// sealCursor signs payload as a cursor for module.
+func (self *GopsUtil) sealCursor(module, payload string) string {
+	key, host, boot := self.cursorIdentity()
+	header, _ := json.Marshal(cursorHeader{
+		Module: module,
+		Host:   host,
+		Boot:   boot,
+		Issued: time.Now().UnixMilli(),
+	})
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + payload
+	return signed + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(key, signed))
+}
+
+// openCursor checks a cursor sealed for module and returns its payload.
+func (self *GopsUtil) openCursor(module, cursor string) (string, error) {
+	fail := func(err error) (string, error) {
+		return "", &CursorError{Module: module, Err: err}
+	}
+
+	first := strings.IndexByte(cursor, '.')
+	last := strings.LastIndexByte(cursor, '.')
+	if first < 0 || first == last {
+		return fail(ErrCursorMalformed)
+	}
+	signed, payload := cursor[:last], cursor[first+1:last]
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(cursor[:first])
+	if err != nil {
+		return fail(ErrCursorMalformed)
+	}
+	var header cursorHeader
+	if json.Unmarshal(headerJSON, &header) != nil {
+		return fail(ErrCursorMalformed)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(cursor[last+1:])
+	if err != nil {
+		return fail(ErrCursorMalformed)
+	}
+
+	key, host, boot := self.cursorIdentity()
+	switch {
+	case header.Module != module:
+		return fail(fmt.Errorf("%w: issued for %s", ErrCursorMalformed, header.Module))
+	case header.Host != host:
+		return fail(ErrCursorWrongHost)
+	case header.Boot != boot:
+		return fail(ErrCursorWrongBoot)
+	case !hmac.Equal(mac, cursorMAC(key, signed)):
+		return fail(fmt.Errorf("%w: signature mismatch", ErrCursorMalformed))
+	case time.Since(time.UnixMilli(header.Issued)) > CursorMaxAge:
+		return fail(ErrCursorExpired)
+	}
+	return payload, nil
+}
+
+// cursorIssued returns when a cursor was sealed, without checking it.
+func cursorIssued(cursor string) (time.Time, bool) {
+	encoded, _, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return time.Time{}, false
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var header cursorHeader
+	if json.Unmarshal(headerJSON, &header) != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(header.Issued), true
+}
+
+func cursorMAC(key []byte, signed string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(signed))
+	return h.Sum(nil)[:cursorMACSize]
+}
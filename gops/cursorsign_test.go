@@ -0,0 +1,102 @@
+package gops
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// signerOn returns a GopsUtil reading a tree with the given hostname and
+// boot ID, signing with key.
+func signerOn(t *testing.T, key []byte, hostname, bootID string) *GopsUtil {
+	t.Helper()
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/sys/kernel/hostname":       hostname + "\n",
+		"proc/sys/kernel/random/boot_id": bootID + "\n",
+	})
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+	require.NoError(t, g.SetCursorKey(key))
+	return g
+}
+
+func TestCursorSealOpen(t *testing.T) {
+	key := NewCursorKey()
+	g := signerOn(t, key, "box", "boot-1")
+
+	cursor := g.sealCursor("cpu", "payload")
+	payload, err := g.openCursor("cpu", cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", payload)
+
+	// Another process with the same key and host accepts it.
+	payload, err = signerOn(t, key, "box", "boot-1").openCursor("cpu", cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", payload)
+}
+
+func TestCursorRejections(t *testing.T) {
+	key := NewCursorKey()
+	g := signerOn(t, key, "box", "boot-1")
+	cursor := g.sealCursor("cpu", "payload")
+
+	header, _, _ := strings.Cut(cursor, ".")
+	stale, _ := json.Marshal(cursorHeader{
+		Module: "cpu",
+		Host:   g.signer.host,
+		Boot:   "boot-1",
+		Issued: time.Now().Add(-2 * CursorMaxAge).UnixMilli(),
+	})
+	staleSigned := base64.RawURLEncoding.EncodeToString(stale) + ".payload"
+	expired := staleSigned + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(key, staleSigned))
+
+	tests := []struct {
+		name   string
+		opener *GopsUtil
+		module string
+		cursor string
+		want   error
+	}{
+		{"unsigned", g, "cpu", "eyJUb3RhbCI6WzEwMDBdfQ", ErrCursorMalformed},
+		{"garbage header", g, "cpu", "!!.payload.mac", ErrCursorMalformed},
+		{"tampered payload", g, "cpu", header + ".payl0ad." + cursor[strings.LastIndexByte(cursor, '.')+1:], ErrCursorMalformed},
+		{"other module", g, "net-rate", cursor, ErrCursorMalformed},
+		{"other key", signerOn(t, NewCursorKey(), "box", "boot-1"), "cpu", cursor, ErrCursorMalformed},
+		{"other host", signerOn(t, key, "elsewhere", "boot-1"), "cpu", cursor, ErrCursorWrongHost},
+		{"previous boot", signerOn(t, key, "box", "boot-2"), "cpu", cursor, ErrCursorWrongBoot},
+		{"expired", g, "cpu", expired, ErrCursorExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.opener.openCursor(tt.module, tt.cursor)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.want)
+			assert.True(t, IsCursorError(err))
+
+			var cursorErr *CursorError
+			require.ErrorAs(t, err, &cursorErr)
+			assert.Equal(t, tt.module, cursorErr.Module)
+		})
+	}
+}
+
+func TestSetCursorKeyRejectsShortKeys(t *testing.T) {
+	assert.Error(t, NewGopsUtil().SetCursorKey([]byte("short")))
+}
@@ -0,0 +1,73 @@
+package gops
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// SetDeviceFilter restricts the network interfaces, disks and mounts that
+// are reported. It is safe to call while other methods are running.
+func (self *GopsUtil) SetDeviceFilter(filter models.DeviceFilter) {
+	self.devices.Store(&filter)
+	self.invalidateCache()
+}
+
+func (self *GopsUtil) deviceFilter() models.DeviceFilter {
+	if f := self.devices.Load(); f != nil {
+		return *f
+	}
+	return models.DeviceFilter{}
+}
+
+func (self *GopsUtil) includeInterface(name string) bool {
+	f := self.deviceFilter()
+	if len(f.Interfaces) > 0 {
+		if !matchAny(f.Interfaces, name) {
+			return false
+		}
+	} else if !matchesNetworkInterface(name) {
+		return false
+	}
+	return !matchAny(f.ExcludeInterfaces, name)
+}
+
+// includeDisk applies the built-in device matching only when builtin is set,
+// since disk rates have always reported every device.
+func (self *GopsUtil) includeDisk(name string, builtin bool) bool {
+	f := self.deviceFilter()
+	if len(f.Disks) > 0 {
+		if !matchAny(f.Disks, name) {
+			return false
+		}
+	} else if builtin && !matchesDiskDevice(name) {
+		return false
+	}
+	return !matchAny(f.ExcludeDisks, name)
+}
+
+func (self *GopsUtil) includeMount(mount string) bool {
+	return !matchAny(self.deviceFilter().ExcludeMounts, mount)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDeviceFilter reports the first malformed glob in filter.
+func ValidateDeviceFilter(filter models.DeviceFilter) error {
+	for _, patterns := range [][]string{filter.Interfaces, filter.ExcludeInterfaces, filter.Disks, filter.ExcludeDisks, filter.ExcludeMounts} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid device pattern %q", p)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build linux
+
+package gops
+
+import (
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceFilter(t *testing.T) {
+	g := NewGopsUtil()
+
+	assert.True(t, g.includeInterface("enp3s0"))
+	assert.False(t, g.includeInterface("docker0"))
+	assert.True(t, g.includeDisk("loop0", false), "disk rates report every device by default")
+
+	g.SetDeviceFilter(models.DeviceFilter{
+		Interfaces:        []string{"docker*", "enp*"},
+		ExcludeInterfaces: []string{"enp0s*"},
+		ExcludeDisks:      []string{"loop*"},
+		ExcludeMounts:     []string{"/mnt/*"},
+	})
+
+	assert.True(t, g.includeInterface("docker0"))
+	assert.True(t, g.includeInterface("enp3s0"))
+	assert.False(t, g.includeInterface("enp0s25"))
+	assert.False(t, g.includeInterface("wlan0"), "an include list replaces the built-in matching")
+	assert.False(t, g.includeDisk("loop0", false))
+	assert.True(t, g.includeDisk("nvme0n1", true))
+	assert.False(t, g.includeMount("/mnt/backup"))
+	assert.True(t, g.includeMount("/home"))
+}
+
+func TestValidateDeviceFilter(t *testing.T) {
+	assert.NoError(t, ValidateDeviceFilter(models.DeviceFilter{Disks: []string{"sd?", "nvme*"}}))
+	assert.Error(t, ValidateDeviceFilter(models.DeviceFilter{ExcludeMounts: []string{"/mnt/[a"}}))
+}
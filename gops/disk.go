@@ -1,31 +1,44 @@
 package gops
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/disk"
 )
 
-func (self *GopsUtil) GetDiskInfo() ([]*models.DiskInfo, error) {
-	diskIO, err := self.diskProvider.IOCounters()
+func (self *GopsUtil) GetDiskInfo(ctx context.Context) ([]*models.DiskInfo, error) {
+	return cachedModule(ctx, self, "disk", "", time.Time{}, self.collectDiskInfo)
+}
+
+func (self *GopsUtil) collectDiskInfo(ctx context.Context) ([]*models.DiskInfo, error) {
+	diskIO, err := self.diskProvider.IOCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
 	res := make([]*models.DiskInfo, 0)
-	if err == nil {
-		for name, d := range diskIO {
-			// Filter to match bash script patterns
-			if matchesDiskDevice(name) {
-				res = append(res, &models.DiskInfo{
-					Name:  name,
-					Read:  d.ReadBytes / 512,  // Convert to sectors
-					Write: d.WriteBytes / 512, // Convert to sectors
-				})
-			}
+	for name, d := range diskIO {
+		// Filter to match bash script patterns
+		if self.includeDisk(name, true) {
+			res = append(res, &models.DiskInfo{
+				Name:  name,
+				Read:  d.ReadBytes / 512,  // Convert to sectors
+				Write: d.WriteBytes / 512, // Convert to sectors
+			})
 		}
 	}
 	return res, nil
 }
 
-func (self *GopsUtil) GetDiskMounts() ([]*models.DiskMountInfo, error) {
-	partitions, err := self.diskProvider.Partitions(true)
+func (self *GopsUtil) GetDiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error) {
+	return cachedModule(ctx, self, "diskmounts", "", time.Time{}, self.collectDiskMounts)
+}
+
+func (self *GopsUtil) collectDiskMounts(ctx context.Context) ([]*models.DiskMountInfo, error) {
+	partitions, err := self.diskProvider.Partitions(ctx, true)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +47,7 @@ func (self *GopsUtil) GetDiskMounts() ([]*models.DiskMountInfo, error) {
 	seen := make(map[string]struct{})
 	for _, p := range partitions {
 		switch {
-		case isVirtualFS(p.Fstype), isVirtualMount(p.Mountpoint):
+		case isVirtualFS(p.Fstype), isVirtualMount(p.Mountpoint), !self.includeMount(p.Mountpoint):
 			continue
 		}
 
@@ -42,7 +55,7 @@ func (self *GopsUtil) GetDiskMounts() ([]*models.DiskMountInfo, error) {
 			continue
 		}
 
-		usage, err := self.diskProvider.Usage(p.Mountpoint)
+		usage, err := self.mountUsage(ctx, p.Mountpoint)
 		if err != nil {
 			continue
 		}
@@ -62,6 +75,50 @@ func (self *GopsUtil) GetDiskMounts() ([]*models.DiskMountInfo, error) {
 	return metrics, nil
 }
 
+// mountUsageTimeout bounds statfs on one mount, so a hung network mount
+// only drops that mount from the list.
+const mountUsageTimeout = 2 * time.Second
+
+var errMountHung = errors.New("statfs from an earlier call has not returned")
+
+// mountUsage returns the usage of mount, giving up when ctx ends or after
+// mountUsageTimeout. statfs can't be interrupted, so a mount whose earlier
+// call is still blocked is skipped until that call returns, and a hung
+// mount ties up one goroutine rather than one per request.
+func (self *GopsUtil) mountUsage(ctx context.Context, mount string) (*disk.UsageStat, error) {
+	if pending, ok := self.hungMounts.Load(mount); ok {
+		select {
+		case <-pending.(chan struct{}):
+			self.hungMounts.CompareAndDelete(mount, pending)
+		default:
+			return nil, errMountHung
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mountUsageTimeout)
+	defer cancel()
+
+	type result struct {
+		usage *disk.UsageStat
+		err   error
+	}
+	done := make(chan struct{})
+	results := make(chan result, 1)
+	go func() {
+		defer close(done)
+		usage, err := self.diskProvider.Usage(ctx, mount)
+		results <- result{usage, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.usage, r.err
+	case <-ctx.Done():
+		self.hungMounts.Store(mount, done)
+		return nil, ctx.Err()
+	}
+}
+
 func formatBytes(bytes uint64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -1,11 +1,15 @@
 package gops
 
 import (
+	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/AvengeMedia/dgop/gops/mocks"
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,26 +67,26 @@ func TestFormatBytesRounding(t *testing.T) {
 func TestGetDiskMountsDedupesSharedDevice(t *testing.T) {
 	mockDisk := mocks.NewMockDiskInfoProvider(t)
 
-	mockDisk.EXPECT().Partitions(true).Return([]disk.PartitionStat{
+	mockDisk.EXPECT().Partitions(mock.Anything, true).Return([]disk.PartitionStat{
 		{Device: "/dev/nvme0n1p3", Mountpoint: "/", Fstype: "btrfs", Opts: []string{"rw", "subvol=/root"}},
 		{Device: "/dev/nvme0n1p3", Mountpoint: "/home", Fstype: "btrfs", Opts: []string{"rw", "subvol=/home"}},
 		{Device: "/dev/nvme0n1p1", Mountpoint: "/boot", Fstype: "vfat", Opts: []string{"rw"}},
 		{Device: "proc", Mountpoint: "/proc", Fstype: "proc", Opts: []string{"rw"}},
 	}, nil)
 
-	mockDisk.EXPECT().Usage("/").Return(&disk.UsageStat{
+	mockDisk.EXPECT().Usage(mock.Anything, "/").Return(&disk.UsageStat{
 		Total: 500 * 1024 * 1024 * 1024,
 		Used:  200 * 1024 * 1024 * 1024,
 		Free:  300 * 1024 * 1024 * 1024,
 	}, nil)
-	mockDisk.EXPECT().Usage("/boot").Return(&disk.UsageStat{
+	mockDisk.EXPECT().Usage(mock.Anything, "/boot").Return(&disk.UsageStat{
 		Total: 1 * 1024 * 1024 * 1024,
 		Used:  256 * 1024 * 1024,
 		Free:  768 * 1024 * 1024,
 	}, nil)
 
 	g := &GopsUtil{diskProvider: mockDisk}
-	mounts, err := g.GetDiskMounts()
+	mounts, err := g.GetDiskMounts(t.Context())
 	require.NoError(t, err)
 
 	require.Len(t, mounts, 2)
@@ -95,22 +99,62 @@ func TestGetDiskMountsDedupesSharedDevice(t *testing.T) {
 func TestGetDiskMountsSkipsFailedUsage(t *testing.T) {
 	mockDisk := mocks.NewMockDiskInfoProvider(t)
 
-	mockDisk.EXPECT().Partitions(true).Return([]disk.PartitionStat{
+	mockDisk.EXPECT().Partitions(mock.Anything, true).Return([]disk.PartitionStat{
 		{Device: "/dev/sda1", Mountpoint: "/mnt/broken", Fstype: "ext4"},
 		{Device: "/dev/sda2", Mountpoint: "/data", Fstype: "ext4"},
 	}, nil)
 
-	mockDisk.EXPECT().Usage("/mnt/broken").Return(nil, assert.AnError)
-	mockDisk.EXPECT().Usage("/data").Return(&disk.UsageStat{
+	mockDisk.EXPECT().Usage(mock.Anything, "/mnt/broken").Return(nil, assert.AnError)
+	mockDisk.EXPECT().Usage(mock.Anything, "/data").Return(&disk.UsageStat{
 		Total: 100 * 1024 * 1024 * 1024,
 		Used:  50 * 1024 * 1024 * 1024,
 		Free:  50 * 1024 * 1024 * 1024,
 	}, nil)
 
 	g := &GopsUtil{diskProvider: mockDisk}
-	mounts, err := g.GetDiskMounts()
+	mounts, err := g.GetDiskMounts(t.Context())
 	require.NoError(t, err)
 
 	require.Len(t, mounts, 1)
 	assert.Equal(t, "/dev/sda2", mounts[0].Device)
 }
+
+func TestGetDiskMountsSkipsHungMount(t *testing.T) {
+	mockDisk := mocks.NewMockDiskInfoProvider(t)
+
+	mockDisk.EXPECT().Partitions(mock.Anything, true).Return([]disk.PartitionStat{
+		{Device: "/dev/sda2", Mountpoint: "/data", Fstype: "ext4"},
+		{Device: "server:/export", Mountpoint: "/mnt/nfs", Fstype: "nfs4"},
+	}, nil)
+	mockDisk.EXPECT().Usage(mock.Anything, "/data").Return(&disk.UsageStat{Total: 1 << 30}, nil)
+
+	release := make(chan struct{})
+	var hung atomic.Bool
+	mockDisk.EXPECT().Usage(mock.Anything, "/mnt/nfs").RunAndReturn(func(context.Context, string) (*disk.UsageStat, error) {
+		if hung.CompareAndSwap(false, true) {
+			<-release
+		}
+		return &disk.UsageStat{Total: 2 << 30}, nil
+	})
+
+	g := &GopsUtil{diskProvider: mockDisk}
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	mounts, err := g.GetDiskMounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, mounts, 1)
+	assert.Equal(t, "/data", mounts[0].Mount)
+
+	// The blocked statfs isn't retried while it's still pending.
+	start := time.Now()
+	mounts, err = g.GetDiskMounts(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, mounts, 1)
+	assert.Less(t, time.Since(start), mountUsageTimeout)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		mounts, err := g.GetDiskMounts(t.Context())
+		return err == nil && len(mounts) == 2
+	}, time.Second, 10*time.Millisecond)
+}
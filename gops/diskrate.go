@@ -1,6 +1,7 @@
 package gops
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"time"
@@ -14,16 +15,29 @@ type DiskRateCursor struct {
 	IOStats   map[string]disk.IOCountersStat `json:"iostats"`
 }
 
-func (self *GopsUtil) GetDiskRates(cursorStr string) (*models.DiskRateResponse, error) {
+func (self *GopsUtil) GetDiskRates(ctx context.Context, cursorStr string) (*models.DiskRateResponse, error) {
+	var cursor DiskRateCursor
+	if cursorStr != "" {
+		payload, err := self.openCursor("disk-rate", cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		if cursor, err = parseDiskRateCursor(payload); err != nil {
+			return nil, &CursorError{Module: "disk-rate", Err: ErrCursorMalformed}
+		}
+	}
+
 	// Get current disk stats
-	diskIO, err := disk.IOCounters()
+	diskIO, err := self.diskProvider.IOCounters(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	currentStats := make(map[string]disk.IOCountersStat)
 	for name, stats := range diskIO {
-		currentStats[name] = stats
+		if self.includeDisk(name, false) {
+			currentStats[name] = stats
+		}
 	}
 
 	currentTime := time.Now()
@@ -31,25 +45,22 @@ func (self *GopsUtil) GetDiskRates(cursorStr string) (*models.DiskRateResponse,
 
 	// If we have a cursor, calculate rates
 	if cursorStr != "" {
-		cursor, err := parseDiskRateCursor(cursorStr)
-		if err == nil {
-			timeDiff := currentTime.Sub(cursor.Timestamp).Seconds()
-			if timeDiff > 0 {
-				for name, current := range currentStats {
-					if prev, exists := cursor.IOStats[name]; exists {
-						readRate := float64(current.ReadBytes-prev.ReadBytes) / timeDiff
-						writeRate := float64(current.WriteBytes-prev.WriteBytes) / timeDiff
-
-						disks = append(disks, &models.DiskRateInfo{
-							Device:     name,
-							ReadRate:   readRate,
-							WriteRate:  writeRate,
-							ReadTotal:  current.ReadBytes,
-							WriteTotal: current.WriteBytes,
-							ReadCount:  current.ReadCount,
-							WriteCount: current.WriteCount,
-						})
-					}
+		timeDiff := currentTime.Sub(cursor.Timestamp).Seconds()
+		if timeDiff > 0 {
+			for name, current := range currentStats {
+				if prev, exists := cursor.IOStats[name]; exists {
+					readRate := float64(current.ReadBytes-prev.ReadBytes) / timeDiff
+					writeRate := float64(current.WriteBytes-prev.WriteBytes) / timeDiff
+
+					disks = append(disks, &models.DiskRateInfo{
+						Device:     name,
+						ReadRate:   readRate,
+						WriteRate:  writeRate,
+						ReadTotal:  current.ReadBytes,
+						WriteTotal: current.WriteBytes,
+						ReadCount:  current.ReadCount,
+						WriteCount: current.WriteCount,
+					})
 				}
 			}
 		}
@@ -83,7 +94,7 @@ func (self *GopsUtil) GetDiskRates(cursorStr string) (*models.DiskRateResponse,
 
 	return &models.DiskRateResponse{
 		Disks:  disks,
-		Cursor: newCursorStr,
+		Cursor: self.sealCursor("disk-rate", newCursorStr),
 	}, nil
 }
 
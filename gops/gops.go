@@ -1,9 +1,13 @@
 package gops
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/AvengeMedia/dankgo/log"
 	"github.com/AvengeMedia/dgop/models"
-	"github.com/shirou/gopsutil/v4/sensors"
 )
 
 type GopsUtil struct {
@@ -15,6 +19,22 @@ type GopsUtil struct {
 	hostProvider HostInfoProvider
 	loadProvider LoadInfoProvider
 	fs           FileSystem
+	cmd          CommandExecutor
+	// env is the gopsutil context for calls made outside the providers,
+	// such as the per-process reads.
+	env     gopsutilEnv
+	devices atomic.Pointer[models.DeviceFilter]
+	signer  cursorSigner
+	cache   atomic.Pointer[moduleCache]
+	// procTable is nil unless EnableProcessTable was called.
+	procTable atomic.Pointer[procTable]
+	// procHistory is nil until the first GetProcessEvents.
+	procHistory atomic.Pointer[procHistory]
+	// hungMounts maps mounts whose statfs timed out to a channel closed
+	// when it returns.
+	hungMounts sync.Map
+	timeouts   atomic.Pointer[map[string]time.Duration]
+	apps       atomic.Pointer[appIndex]
 }
 
 func NewGopsUtil() *GopsUtil {
@@ -27,6 +47,7 @@ func NewGopsUtil() *GopsUtil {
 		hostProvider: &DefaultHostInfoProvider{},
 		loadProvider: &DefaultLoadInfoProvider{},
 		fs:           &DefaultFileSystem{},
+		cmd:          &DefaultCommandExecutor{},
 	}
 }
 
@@ -50,68 +71,92 @@ func NewGopsUtilWithProviders(
 		hostProvider: host,
 		loadProvider: load,
 		fs:           fs,
+		cmd:          &DefaultCommandExecutor{},
 	}
 }
 
-func (self *GopsUtil) GetAllMetrics(procSortBy ProcSortBy, procLimit int, enableProcessCPU bool, mergeChildren bool) (*models.SystemMetrics, error) {
-	return self.GetAllMetricsWithCursors(procSortBy, procLimit, enableProcessCPU, "", "", mergeChildren)
+func (self *GopsUtil) GetAllMetrics(ctx context.Context, procSortBy ProcSortBy, procLimit int, enableProcessCPU bool, mergeChildren bool) (*models.SystemMetrics, error) {
+	return self.GetAllMetricsWithCursors(ctx, procSortBy, procLimit, enableProcessCPU, "", "", mergeChildren)
 }
 
-func (self *GopsUtil) GetAllMetricsWithCursors(procSortBy ProcSortBy, procLimit int, enableProcessCPU bool, cpuCursor string, procCursor string, mergeChildren bool) (*models.SystemMetrics, error) {
-	cpuInfo, err := self.GetCPUInfoWithCursor(cpuCursor)
+func (self *GopsUtil) GetAllMetricsWithCursors(ctx context.Context, procSortBy ProcSortBy, procLimit int, enableProcessCPU bool, cpuCursor string, procCursor string, mergeChildren bool) (*models.SystemMetrics, error) {
+	metrics := &models.SystemMetrics{}
+	failed := func(module string, err error) {
+		if metrics.Errors == nil {
+			metrics.Errors = make(map[string]models.ModuleError)
+		}
+		metrics.Errors[module] = moduleError(err)
+	}
+
+	cpuInfo, err := self.GetCPUInfoWithCursor(ctx, cpuCursor)
+	if IsCursorError(err) {
+		return nil, err
+	}
 	if err != nil {
 		log.Errorf("Failed to get CPU info: %v", err)
+		failed("cpu", err)
 	}
 
-	memInfo, err := self.GetMemoryInfo()
+	memInfo, err := self.GetMemoryInfo(ctx)
 	if err != nil {
 		log.Errorf("Failed to get memory info: %v", err)
+		failed("memory", err)
 	}
 
-	networkInfo, err := self.GetNetworkInfo()
+	networkInfo, err := self.GetNetworkInfo(ctx)
 	if err != nil {
 		log.Errorf("Failed to get network info: %v", err)
+		failed("network", err)
 	}
 
-	diskInfo, err := self.GetDiskInfo()
+	diskInfo, err := self.GetDiskInfo(ctx)
 	if err != nil {
 		log.Errorf("Failed to get disk info: %v", err)
+		failed("disk", err)
 	}
 
-	diskMounts, err := self.GetDiskMounts()
+	diskMounts, err := self.GetDiskMounts(ctx)
 	if err != nil {
 		log.Errorf("Failed to get disk mounts: %v", err)
+		failed("diskmounts", err)
 	}
 
-	processResult, err := self.GetProcessesWithCursor(procSortBy, procLimit, enableProcessCPU, procCursor, mergeChildren)
+	processResult, err := self.GetProcessesWithCursor(ctx, ProcessParams{
+		SortBy:        procSortBy,
+		Limit:         procLimit,
+		EnableCPU:     enableProcessCPU,
+		Cursor:        procCursor,
+		MergeChildren: mergeChildren,
+	})
+	if IsCursorError(err) {
+		return nil, err
+	}
 	if err != nil {
 		log.Errorf("Failed to get processes: %v", err)
+		failed("processes", err)
 	}
 
-	systemInfo, err := self.GetSystemInfo()
+	systemInfo, err := self.GetSystemInfo(ctx)
 	if err != nil {
 		log.Errorf("Failed to get system info: %v", err)
+		failed("system", err)
 	}
 
-	var processes []*models.ProcessInfo
 	if processResult != nil {
-		processes = processResult.Processes
+		metrics.Processes = processResult.Processes
 	}
-
-	return &models.SystemMetrics{
-		Memory:     memInfo,
-		CPU:        cpuInfo,
-		Network:    networkInfo,
-		Disk:       diskInfo,
-		Processes:  processes,
-		System:     systemInfo,
-		DiskMounts: diskMounts,
-	}, nil
+	metrics.Memory = memInfo
+	metrics.CPU = cpuInfo
+	metrics.Network = networkInfo
+	metrics.Disk = diskInfo
+	metrics.System = systemInfo
+	metrics.DiskMounts = diskMounts
+	return metrics, nil
 }
 
 // GetSystemTemperatures returns system temperature sensors
-func (self *GopsUtil) GetSystemTemperatures() ([]models.TemperatureSensor, error) {
-	temps, err := sensors.SensorsTemperatures()
+func (self *GopsUtil) GetSystemTemperatures(ctx context.Context) ([]models.TemperatureSensor, error) {
+	temps, err := self.hostProvider.SensorsTemperatures(ctx)
 	if err != nil {
 		return nil, err
 	}
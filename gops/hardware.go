@@ -1,19 +1,24 @@
 package gops
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/AvengeMedia/dgop/models"
-	"github.com/shirou/gopsutil/v4/host"
 )
 
-func (self *GopsUtil) GetSystemHardware() (*models.SystemHardware, error) {
+func (self *GopsUtil) GetSystemHardware(ctx context.Context) (*models.SystemHardware, error) {
+	return cachedModule(ctx, self, "hardware", "", time.Time{}, self.collectSystemHardware)
+}
+
+func (self *GopsUtil) collectSystemHardware(ctx context.Context) (*models.SystemHardware, error) {
 	info := &models.SystemHardware{}
 
-	cpuInfo, err := self.GetCPUInfo()
+	cpuInfo, err := self.GetCPUInfo(ctx)
 	if err == nil {
 		info.CPU = models.CPUBasic{
 			Count: cpuInfo.Count,
@@ -26,10 +31,10 @@ func (self *GopsUtil) GetSystemHardware() (*models.SystemHardware, error) {
 		}
 	}
 
-	biosInfo := getBIOSInfo()
+	biosInfo := self.getBIOSInfo(ctx)
 	info.BIOS = biosInfo
 
-	hostInfo, err := host.Info()
+	hostInfo, err := self.hostProvider.Info(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -37,13 +42,13 @@ func (self *GopsUtil) GetSystemHardware() (*models.SystemHardware, error) {
 	info.Kernel = hostInfo.KernelVersion
 	info.Hostname = hostInfo.Hostname
 	info.Arch = hostInfo.KernelArch
-	info.Distro = getDistroName()
+	info.Distro = self.getDistroName(ctx)
 
 	return info, nil
 }
 
-func (self *GopsUtil) GetGPUInfo() (*models.GPUInfo, error) {
-	gpus, err := detectGPUs()
+func (self *GopsUtil) GetGPUInfo(ctx context.Context) (*models.GPUInfo, error) {
+	gpus, err := self.detectGPUs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -51,8 +56,14 @@ func (self *GopsUtil) GetGPUInfo() (*models.GPUInfo, error) {
 	return &models.GPUInfo{GPUs: gpus}, nil
 }
 
-func (self *GopsUtil) GetGPUInfoWithTemp(pciIds []string) (*models.GPUInfo, error) {
-	gpus, err := detectGPUs()
+func (self *GopsUtil) GetGPUInfoWithTemp(ctx context.Context, pciIds []string) (*models.GPUInfo, error) {
+	return cachedModule(ctx, self, "gpu", strings.Join(pciIds, ","), time.Time{}, func(ctx context.Context) (*models.GPUInfo, error) {
+		return self.collectGPUInfoWithTemp(ctx, pciIds)
+	})
+}
+
+func (self *GopsUtil) collectGPUInfoWithTemp(ctx context.Context, pciIds []string) (*models.GPUInfo, error) {
+	gpus, err := self.detectGPUs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +72,7 @@ func (self *GopsUtil) GetGPUInfoWithTemp(pciIds []string) (*models.GPUInfo, erro
 		for i, gpu := range gpus {
 			for _, pciId := range pciIds {
 				if gpu.PciId == pciId {
-					if tempInfo, err := self.GetGPUTemp(pciId); err == nil {
+					if tempInfo, err := self.GetGPUTemp(ctx, pciId); err == nil {
 						gpus[i].Temperature = tempInfo.Temperature
 						gpus[i].Hwmon = tempInfo.Hwmon
 					}
@@ -74,12 +85,12 @@ func (self *GopsUtil) GetGPUInfoWithTemp(pciIds []string) (*models.GPUInfo, erro
 	return &models.GPUInfo{GPUs: gpus}, nil
 }
 
-func (self *GopsUtil) GetGPUTemp(pciId string) (*models.GPUTempInfo, error) {
+func (self *GopsUtil) GetGPUTemp(ctx context.Context, pciId string) (*models.GPUTempInfo, error) {
 	if pciId == "" {
 		return nil, fmt.Errorf("pciId is required")
 	}
 
-	gpuEntries, err := detectGPUEntries()
+	gpuEntries, err := self.detectGPUEntries(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -102,9 +113,9 @@ func (self *GopsUtil) GetGPUTemp(pciId string) (*models.GPUTempInfo, error) {
 
 	switch targetGPU.Driver {
 	case "nvidia":
-		temperature, hwmon = getNvidiaTemperature()
+		temperature, hwmon = getNvidiaTemperature(ctx)
 	default:
-		temperature, hwmon = getHwmonTemperature(pciId)
+		temperature, hwmon = self.getHwmonTemperature(pciId)
 	}
 
 	return &models.GPUTempInfo{
@@ -114,6 +125,18 @@ func (self *GopsUtil) GetGPUTemp(pciId string) (*models.GPUTempInfo, error) {
 	}, nil
 }
 
+// commandTimeout bounds the external commands the collectors run, such as
+// nvidia-smi, when the caller's context has no earlier deadline.
+const commandTimeout = 5 * time.Second
+
+// commandOutput runs name and returns its standard output, killing it when
+// ctx ends or commandTimeout passes.
+func commandOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
 type gpuEntry struct {
 	Priority int
 	Driver   string
@@ -134,8 +157,8 @@ func inferVendorFromId(vendorId, driver string) string {
 	}
 }
 
-func detectGPUs() ([]models.GPU, error) {
-	gpuEntries, err := detectGPUEntries()
+func (self *GopsUtil) detectGPUs(ctx context.Context) ([]models.GPU, error) {
+	gpuEntries, err := self.detectGPUEntries(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -282,8 +305,8 @@ func buildFullName(vendor, displayName string) string {
 	}
 }
 
-func readFile(path string) (string, error) {
-	data, err := os.ReadFile(path)
+func (self *GopsUtil) readFile(path string) (string, error) {
+	data, err := self.fs.ReadFile(path)
 	if err != nil {
 		return "", err
 	}
@@ -3,15 +3,15 @@
 package gops
 
 import (
-	"os/exec"
+	"context"
 	"strings"
 
 	"github.com/AvengeMedia/dgop/models"
 )
 
-func getBIOSInfo() models.BIOSInfo {
+func (self *GopsUtil) getBIOSInfo(ctx context.Context) models.BIOSInfo {
 	model := "Unknown"
-	out, err := exec.Command("sysctl", "-n", "hw.model").Output()
+	out, err := commandOutput(ctx, "sysctl", "-n", "hw.model")
 	if err == nil {
 		model = strings.TrimSpace(string(out))
 	}
@@ -23,13 +23,13 @@ func getBIOSInfo() models.BIOSInfo {
 	}
 }
 
-func getDistroName() string {
-	name, err := exec.Command("sw_vers", "-productName").Output()
+func (self *GopsUtil) getDistroName(ctx context.Context) string {
+	name, err := commandOutput(ctx, "sw_vers", "-productName")
 	if err != nil {
 		return "macOS"
 	}
 
-	version, err := exec.Command("sw_vers", "-productVersion").Output()
+	version, err := commandOutput(ctx, "sw_vers", "-productVersion")
 	if err != nil {
 		return strings.TrimSpace(string(name))
 	}
@@ -37,8 +37,8 @@ func getDistroName() string {
 	return strings.TrimSpace(string(name)) + " " + strings.TrimSpace(string(version))
 }
 
-func detectGPUEntries() ([]gpuEntry, error) {
-	out, err := exec.Command("system_profiler", "SPDisplaysDataType").Output()
+func (self *GopsUtil) detectGPUEntries(ctx context.Context) ([]gpuEntry, error) {
+	out, err := commandOutput(ctx, "system_profiler", "SPDisplaysDataType")
 	if err != nil {
 		return nil, err
 	}
@@ -60,10 +60,10 @@ func detectGPUEntries() ([]gpuEntry, error) {
 	return entries, nil
 }
 
-func getNvidiaTemperature() (float64, string) {
+func getNvidiaTemperature(_ context.Context) (float64, string) {
 	return 0, "unknown"
 }
 
-func getHwmonTemperature(_ string) (float64, string) {
+func (self *GopsUtil) getHwmonTemperature(_ string) (float64, string) {
 	return 0, "unknown"
 }
@@ -3,8 +3,8 @@
 package gops
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,8 +14,8 @@ import (
 	"github.com/AvengeMedia/dgop/models"
 )
 
-func kenvString(name string) string {
-	out, err := exec.Command("kenv", "-q", name).Output()
+func kenvString(ctx context.Context, name string) string {
+	out, err := commandOutput(ctx, "kenv", "-q", name)
 	if err != nil {
 		return ""
 	}
@@ -23,11 +23,11 @@ func kenvString(name string) string {
 }
 
 // smbios.* kenv variables are set by the loader; names per stand/libsa/smbios.c.
-func getBIOSInfo() models.BIOSInfo {
+func (self *GopsUtil) getBIOSInfo(ctx context.Context) models.BIOSInfo {
 	biosInfo := models.BIOSInfo{
-		Vendor:  kenvString("smbios.planar.maker"),
-		Version: kenvString("smbios.bios.version"),
-		Date:    kenvString("smbios.bios.reldate"),
+		Vendor:  kenvString(ctx, "smbios.planar.maker"),
+		Version: kenvString(ctx, "smbios.bios.version"),
+		Date:    kenvString(ctx, "smbios.bios.reldate"),
 	}
 
 	if biosInfo.Vendor == "" {
@@ -37,7 +37,7 @@ func getBIOSInfo() models.BIOSInfo {
 		biosInfo.Version = "Unknown"
 	}
 
-	boardName := kenvString("smbios.planar.product")
+	boardName := kenvString(ctx, "smbios.planar.product")
 	switch {
 	case biosInfo.Vendor != "Unknown" && boardName != "":
 		biosInfo.Motherboard = biosInfo.Vendor + " " + boardName
@@ -50,9 +50,9 @@ func getBIOSInfo() models.BIOSInfo {
 	return biosInfo
 }
 
-func getDistroName() string {
+func (self *GopsUtil) getDistroName(_ context.Context) string {
 	// Generated at boot since FreeBSD 13.0; os-release(5).
-	content, err := readFile("/var/run/os-release")
+	content, err := self.readFile("/var/run/os-release")
 	if err != nil {
 		return fallbackDistroName()
 	}
@@ -75,8 +75,8 @@ func fallbackDistroName() string {
 	return "FreeBSD " + release
 }
 
-func detectGPUEntries() ([]gpuEntry, error) {
-	out, err := exec.Command("pciconf", "-lv").Output()
+func (self *GopsUtil) detectGPUEntries(ctx context.Context) ([]gpuEntry, error) {
+	out, err := commandOutput(ctx, "pciconf", "-lv")
 	if err != nil {
 		return nil, err
 	}
@@ -171,9 +171,8 @@ func parsePciconfCaption(line string) (key, value string, ok bool) {
 	return strings.TrimSpace(k), strings.Trim(strings.TrimSpace(v), "'"), true
 }
 
-func getNvidiaTemperature() (float64, string) {
-	cmd := exec.Command("nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
-	output, err := cmd.Output()
+func getNvidiaTemperature(ctx context.Context) (float64, string) {
+	output, err := commandOutput(ctx, "nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
 	if err != nil {
 		return 0, "unknown"
 	}
@@ -189,6 +188,6 @@ func getNvidiaTemperature() (float64, string) {
 	return 0, "unknown"
 }
 
-func getHwmonTemperature(_ string) (float64, string) {
+func (self *GopsUtil) getHwmonTemperature(_ string) (float64, string) {
 	return 0, "unknown"
 }
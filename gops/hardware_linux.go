@@ -3,9 +3,9 @@
 package gops
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -14,22 +14,22 @@ import (
 	"github.com/AvengeMedia/dgop/models"
 )
 
-func getBIOSInfo() models.BIOSInfo {
+func (self *GopsUtil) getBIOSInfo(_ context.Context) models.BIOSInfo {
 	dmip := "/sys/class/dmi/id"
-	if _, err := os.Stat(dmip); os.IsNotExist(err) {
+	if _, err := self.fs.Stat(dmip); os.IsNotExist(err) {
 		dmip = "/sys/devices/virtual/dmi/id"
 	}
 
 	biosInfo := models.BIOSInfo{}
 
-	if vendor, err := readFile(filepath.Join(dmip, "board_vendor")); err == nil {
+	if vendor, err := self.readFile(filepath.Join(dmip, "board_vendor")); err == nil {
 		biosInfo.Vendor = strings.TrimSpace(vendor)
 	} else {
 		biosInfo.Vendor = "Unknown"
 	}
 
 	var boardName string
-	if name, err := readFile(filepath.Join(dmip, "board_name")); err == nil {
+	if name, err := self.readFile(filepath.Join(dmip, "board_name")); err == nil {
 		boardName = strings.TrimSpace(name)
 	}
 
@@ -42,21 +42,21 @@ func getBIOSInfo() models.BIOSInfo {
 		biosInfo.Motherboard = "Unknown"
 	}
 
-	if version, err := readFile(filepath.Join(dmip, "bios_version")); err == nil {
+	if version, err := self.readFile(filepath.Join(dmip, "bios_version")); err == nil {
 		biosInfo.Version = strings.TrimSpace(version)
 	} else {
 		biosInfo.Version = "Unknown"
 	}
 
-	if date, err := readFile(filepath.Join(dmip, "bios_date")); err == nil {
+	if date, err := self.readFile(filepath.Join(dmip, "bios_date")); err == nil {
 		biosInfo.Date = strings.TrimSpace(date)
 	}
 
 	return biosInfo
 }
 
-func getDistroName() string {
-	content, err := readFile("/etc/os-release")
+func (self *GopsUtil) getDistroName(_ context.Context) string {
+	content, err := self.readFile("/etc/os-release")
 	if err != nil {
 		return "Unknown"
 	}
@@ -72,15 +72,20 @@ func getDistroName() string {
 	return "Unknown"
 }
 
-func detectGPUEntries() ([]gpuEntry, error) {
-	devices, err := filepath.Glob("/sys/bus/pci/devices/*")
+func (self *GopsUtil) detectGPUEntries(_ context.Context) ([]gpuEntry, error) {
+	// Glob finds nothing in a missing directory, which would pass for a
+	// machine without GPUs.
+	if _, err := self.fs.Stat("/sys/bus/pci/devices"); err != nil {
+		return nil, err
+	}
+	devices, err := self.fs.Glob("/sys/bus/pci/devices/*")
 	if err != nil {
 		return nil, err
 	}
 
 	var gpuEntries []gpuEntry
 	for _, devicePath := range devices {
-		classBytes, err := os.ReadFile(filepath.Join(devicePath, "class"))
+		classBytes, err := self.fs.ReadFile(filepath.Join(devicePath, "class"))
 		if err != nil {
 			continue
 		}
@@ -90,12 +95,12 @@ func detectGPUEntries() ([]gpuEntry, error) {
 			continue
 		}
 
-		vendorBytes, err := os.ReadFile(filepath.Join(devicePath, "vendor"))
+		vendorBytes, err := self.fs.ReadFile(filepath.Join(devicePath, "vendor"))
 		if err != nil {
 			continue
 		}
 
-		deviceBytes, err := os.ReadFile(filepath.Join(devicePath, "device"))
+		deviceBytes, err := self.fs.ReadFile(filepath.Join(devicePath, "device"))
 		if err != nil {
 			continue
 		}
@@ -103,8 +108,8 @@ func detectGPUEntries() ([]gpuEntry, error) {
 		vendorId := strings.TrimSpace(strings.TrimPrefix(string(vendorBytes), "0x"))
 		deviceId := strings.TrimSpace(strings.TrimPrefix(string(deviceBytes), "0x"))
 		bdf := filepath.Base(devicePath)
-		driver := getGPUDriver(bdf)
-		displayName := lookupPCIDevice(vendorId, deviceId)
+		driver := self.getGPUDriver(bdf)
+		displayName := self.lookupPCIDevice(vendorId, deviceId)
 		vendor := inferVendorFromId(vendorId, driver)
 		priority := getPriority(driver, bdf)
 		pciId := fmt.Sprintf("%s:%s", vendorId, deviceId)
@@ -128,7 +133,7 @@ func detectGPUEntries() ([]gpuEntry, error) {
 	return gpuEntries, nil
 }
 
-func lookupPCIDevice(vendorId, deviceId string) string {
+func (self *GopsUtil) lookupPCIDevice(vendorId, deviceId string) string {
 	pciIdsPaths := []string{
 		"/usr/share/hwdata/pci.ids",
 		"/usr/share/misc/pci.ids",
@@ -137,7 +142,7 @@ func lookupPCIDevice(vendorId, deviceId string) string {
 
 	var pciIdsPath string
 	for _, path := range pciIdsPaths {
-		if _, err := os.Stat(path); err == nil {
+		if _, err := self.fs.Stat(path); err == nil {
 			pciIdsPath = path
 			break
 		}
@@ -147,7 +152,7 @@ func lookupPCIDevice(vendorId, deviceId string) string {
 		return fmt.Sprintf("GPU %s:%s", vendorId, deviceId)
 	}
 
-	content, err := os.ReadFile(pciIdsPath)
+	content, err := self.fs.ReadFile(pciIdsPath)
 	if err != nil {
 		return fmt.Sprintf("GPU %s:%s", vendorId, deviceId)
 	}
@@ -176,17 +181,16 @@ func lookupPCIDevice(vendorId, deviceId string) string {
 	return fmt.Sprintf("GPU %s:%s", vendorId, deviceId)
 }
 
-func getGPUDriver(bdf string) string {
+func (self *GopsUtil) getGPUDriver(bdf string) string {
 	driverPath := filepath.Join("/sys/bus/pci/devices", bdf, "driver")
-	if link, err := os.Readlink(driverPath); err == nil {
+	if link, err := self.fs.Readlink(driverPath); err == nil {
 		return filepath.Base(link)
 	}
 	return ""
 }
 
-func getNvidiaTemperature() (float64, string) {
-	cmd := exec.Command("nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
-	output, err := cmd.Output()
+func getNvidiaTemperature(ctx context.Context) (float64, string) {
+	output, err := commandOutput(ctx, "nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
 	if err != nil {
 		return 0, "unknown"
 	}
@@ -202,8 +206,8 @@ func getNvidiaTemperature() (float64, string) {
 	return 0, "unknown"
 }
 
-func getHwmonTemperature(pciId string) (float64, string) {
-	drmCards, err := filepath.Glob("/sys/class/drm/card*")
+func (self *GopsUtil) getHwmonTemperature(pciId string) (float64, string) {
+	drmCards, err := self.fs.Glob("/sys/class/drm/card*")
 	if err != nil {
 		return 0, "unknown"
 	}
@@ -214,8 +218,8 @@ func getHwmonTemperature(pciId string) (float64, string) {
 		vendorFile := filepath.Join(devicePath, "vendor")
 		deviceFile := filepath.Join(devicePath, "device")
 
-		vendorBytes, err1 := os.ReadFile(vendorFile)
-		deviceBytes, err2 := os.ReadFile(deviceFile)
+		vendorBytes, err1 := self.fs.ReadFile(vendorFile)
+		deviceBytes, err2 := self.fs.ReadFile(deviceFile)
 
 		if err1 != nil || err2 != nil {
 			continue
@@ -234,23 +238,23 @@ func getHwmonTemperature(pciId string) (float64, string) {
 		}
 
 		driverPath := filepath.Join(devicePath, "driver")
-		if _, err := os.Stat(driverPath); os.IsNotExist(err) {
+		if _, err := self.fs.Stat(driverPath); os.IsNotExist(err) {
 			continue
 		}
 
 		hwmonGlob := filepath.Join(devicePath, "hwmon", "hwmon*")
-		hwmonDirs, err := filepath.Glob(hwmonGlob)
+		hwmonDirs, err := self.fs.Glob(hwmonGlob)
 		if err != nil {
 			continue
 		}
 
 		for _, hwmonDir := range hwmonDirs {
 			tempFile := filepath.Join(hwmonDir, "temp1_input")
-			if _, err := os.Stat(tempFile); os.IsNotExist(err) {
+			if _, err := self.fs.Stat(tempFile); os.IsNotExist(err) {
 				continue
 			}
 
-			tempBytes, err := os.ReadFile(tempFile)
+			tempBytes, err := self.fs.ReadFile(tempFile)
 			if err != nil {
 				continue
 			}
@@ -264,12 +268,12 @@ func getHwmonTemperature(pciId string) (float64, string) {
 	}
 
 	thermalPath := "/sys/class/thermal"
-	thermalEntries, err := os.ReadDir(thermalPath)
+	thermalEntries, err := self.fs.ReadDir(thermalPath)
 	if err != nil {
 		return 0, "unknown"
 	}
 
-	maxTemp := getMaxACPITZTemperatureForGPU(thermalPath, thermalEntries, 20, 90)
+	maxTemp := self.getMaxACPITZTemperatureForGPU(thermalPath, thermalEntries, 20, 90)
 	if maxTemp > 0 {
 		return maxTemp, "acpitz"
 	}
@@ -277,7 +281,7 @@ func getHwmonTemperature(pciId string) (float64, string) {
 	return 0, "unknown"
 }
 
-func getMaxACPITZTemperatureForGPU(thermalPath string, thermalEntries []os.DirEntry, minTemp, maxTemp float64) float64 {
+func (self *GopsUtil) getMaxACPITZTemperatureForGPU(thermalPath string, thermalEntries []os.DirEntry, minTemp, maxTemp float64) float64 {
 	var highestTemp float64
 
 	for _, entry := range thermalEntries {
@@ -285,7 +289,7 @@ func getMaxACPITZTemperatureForGPU(thermalPath string, thermalEntries []os.DirEn
 			continue
 		}
 
-		thermalType, err := readThermalTypeForGPU(thermalPath, entry.Name())
+		thermalType, err := self.readThermalTypeForGPU(thermalPath, entry.Name())
 		if err != nil {
 			continue
 		}
@@ -294,7 +298,7 @@ func getMaxACPITZTemperatureForGPU(thermalPath string, thermalEntries []os.DirEn
 			continue
 		}
 
-		temp, err := readThermalTempForGPU(thermalPath, entry.Name())
+		temp, err := self.readThermalTempForGPU(thermalPath, entry.Name())
 		if err != nil {
 			continue
 		}
@@ -311,18 +315,18 @@ func getMaxACPITZTemperatureForGPU(thermalPath string, thermalEntries []os.DirEn
 	return highestTemp
 }
 
-func readThermalTypeForGPU(thermalPath, entryName string) (string, error) {
+func (self *GopsUtil) readThermalTypeForGPU(thermalPath, entryName string) (string, error) {
 	typePath := filepath.Join(thermalPath, entryName, "type")
-	typeBytes, err := os.ReadFile(typePath)
+	typeBytes, err := self.fs.ReadFile(typePath)
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(string(typeBytes)), nil
 }
 
-func readThermalTempForGPU(thermalPath, entryName string) (float64, error) {
+func (self *GopsUtil) readThermalTempForGPU(thermalPath, entryName string) (float64, error) {
 	tempPath := filepath.Join(thermalPath, entryName, "temp")
-	tempBytes, err := os.ReadFile(tempPath)
+	tempBytes, err := self.fs.ReadFile(tempPath)
 	if err != nil {
 		return 0, err
 	}
@@ -153,7 +153,7 @@ func TestBuildFullName(t *testing.T) {
 }
 
 func TestGetDistroName(t *testing.T) {
-	result := getDistroName()
+	result := NewGopsUtil().getDistroName(t.Context())
 	assert.NotEmpty(t, result)
 }
 
@@ -1,10 +1,13 @@
 package gops
 
 import (
+	"context"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/shirou/gopsutil/v4/common"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/host"
@@ -20,56 +23,58 @@ type FileSystem interface {
 	ReadFile(name string) ([]byte, error)
 	ReadDir(name string) ([]fs.DirEntry, error)
 	Stat(name string) (fs.FileInfo, error)
+	Readlink(name string) (string, error)
+	Glob(pattern string) ([]string, error)
 }
 
 // CommandExecutor provides an interface for executing external commands
 type CommandExecutor interface {
-	Execute(name string, args ...string) ([]byte, error)
+	Execute(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
 // CPUInfoProvider provides an interface for CPU information
 type CPUInfoProvider interface {
-	Info() ([]cpu.InfoStat, error)
-	Counts(logical bool) (int, error)
-	Times(perCPU bool) ([]cpu.TimesStat, error)
-	Percent(interval time.Duration, perCPU bool) ([]float64, error)
+	Info(ctx context.Context) ([]cpu.InfoStat, error)
+	Counts(ctx context.Context, logical bool) (int, error)
+	Times(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error)
+	Percent(ctx context.Context, interval time.Duration, perCPU bool) ([]float64, error)
 }
 
 // MemoryInfoProvider provides an interface for memory information
 type MemoryInfoProvider interface {
-	VirtualMemory() (*mem.VirtualMemoryStat, error)
-	SwapMemory() (*mem.SwapMemoryStat, error)
+	VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error)
+	SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error)
 }
 
 // DiskInfoProvider provides an interface for disk information
 type DiskInfoProvider interface {
-	IOCounters() (map[string]disk.IOCountersStat, error)
-	Partitions(all bool) ([]disk.PartitionStat, error)
-	Usage(path string) (*disk.UsageStat, error)
+	IOCounters(ctx context.Context) (map[string]disk.IOCountersStat, error)
+	Partitions(ctx context.Context, all bool) ([]disk.PartitionStat, error)
+	Usage(ctx context.Context, path string) (*disk.UsageStat, error)
 }
 
 // NetworkInfoProvider provides an interface for network information
 type NetworkInfoProvider interface {
-	IOCounters(pernic bool) ([]net.IOCountersStat, error)
-	Interfaces() ([]net.InterfaceStat, error)
+	IOCounters(ctx context.Context, pernic bool) ([]net.IOCountersStat, error)
+	Interfaces(ctx context.Context) ([]net.InterfaceStat, error)
 }
 
 // ProcessInfoProvider provides an interface for process information
 type ProcessInfoProvider interface {
-	Processes() ([]*process.Process, error)
-	NewProcess(pid int32) (*process.Process, error)
+	Processes(ctx context.Context) ([]*process.Process, error)
+	NewProcess(ctx context.Context, pid int32) (*process.Process, error)
 }
 
 // HostInfoProvider provides an interface for host information
 type HostInfoProvider interface {
-	Info() (*host.InfoStat, error)
-	SensorsTemperatures() ([]sensors.TemperatureStat, error)
+	Info(ctx context.Context) (*host.InfoStat, error)
+	SensorsTemperatures(ctx context.Context) ([]sensors.TemperatureStat, error)
 }
 
 // LoadInfoProvider provides an interface for load average information
 type LoadInfoProvider interface {
-	Avg() (*load.AvgStat, error)
-	Misc() (*load.MiscStat, error)
+	Avg(ctx context.Context) (*load.AvgStat, error)
+	Misc(ctx context.Context) (*load.MiscStat, error)
 }
 
 // DefaultFileSystem implements FileSystem using standard os package
@@ -87,91 +92,123 @@ func (d *DefaultFileSystem) Stat(name string) (fs.FileInfo, error) {
 	return os.Stat(name)
 }
 
+func (d *DefaultFileSystem) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+func (d *DefaultFileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+// DefaultCommandExecutor implements CommandExecutor with os/exec, bounded
+// by commandTimeout
+type DefaultCommandExecutor struct{}
+
+func (d *DefaultCommandExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return commandOutput(ctx, name, args...)
+}
+
+// gopsutilEnv carries the HOST_PROC, HOST_SYS and friends gopsutil reads
+// from its context, so the default providers can be pointed at a sysroot.
+// The zero value uses the real system.
+type gopsutilEnv struct {
+	env common.EnvMap
+}
+
+// context returns ctx with the environment attached, keeping ctx's
+// deadline and cancellation.
+func (e gopsutilEnv) context(ctx context.Context) context.Context {
+	if e.env == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, common.EnvKey, e.env)
+}
+
 // DefaultCPUInfoProvider implements CPUInfoProvider using gopsutil
-type DefaultCPUInfoProvider struct{}
+type DefaultCPUInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultCPUInfoProvider) Info() ([]cpu.InfoStat, error) {
-	return cpu.Info()
+func (d *DefaultCPUInfoProvider) Info(ctx context.Context) ([]cpu.InfoStat, error) {
+	return cpu.InfoWithContext(d.context(ctx))
 }
 
-func (d *DefaultCPUInfoProvider) Counts(logical bool) (int, error) {
-	return cpu.Counts(logical)
+func (d *DefaultCPUInfoProvider) Counts(ctx context.Context, logical bool) (int, error) {
+	return cpu.CountsWithContext(d.context(ctx), logical)
 }
 
-func (d *DefaultCPUInfoProvider) Times(perCPU bool) ([]cpu.TimesStat, error) {
-	return cpu.Times(perCPU)
+func (d *DefaultCPUInfoProvider) Times(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error) {
+	return cpuTimes(d.context(ctx), perCPU)
 }
 
-func (d *DefaultCPUInfoProvider) Percent(interval time.Duration, perCPU bool) ([]float64, error) {
-	return cpu.Percent(interval, perCPU)
+func (d *DefaultCPUInfoProvider) Percent(ctx context.Context, interval time.Duration, perCPU bool) ([]float64, error) {
+	return cpu.PercentWithContext(d.context(ctx), interval, perCPU)
 }
 
 // DefaultMemoryInfoProvider implements MemoryInfoProvider using gopsutil
-type DefaultMemoryInfoProvider struct{}
+type DefaultMemoryInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultMemoryInfoProvider) VirtualMemory() (*mem.VirtualMemoryStat, error) {
-	return mem.VirtualMemory()
+func (d *DefaultMemoryInfoProvider) VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	return mem.VirtualMemoryWithContext(d.context(ctx))
 }
 
-func (d *DefaultMemoryInfoProvider) SwapMemory() (*mem.SwapMemoryStat, error) {
-	return mem.SwapMemory()
+func (d *DefaultMemoryInfoProvider) SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error) {
+	return mem.SwapMemoryWithContext(d.context(ctx))
 }
 
 // DefaultDiskInfoProvider implements DiskInfoProvider using gopsutil
-type DefaultDiskInfoProvider struct{}
+type DefaultDiskInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultDiskInfoProvider) IOCounters() (map[string]disk.IOCountersStat, error) {
-	return disk.IOCounters()
+func (d *DefaultDiskInfoProvider) IOCounters(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+	return disk.IOCountersWithContext(d.context(ctx))
 }
 
-func (d *DefaultDiskInfoProvider) Partitions(all bool) ([]disk.PartitionStat, error) {
-	return disk.Partitions(all)
+func (d *DefaultDiskInfoProvider) Partitions(ctx context.Context, all bool) ([]disk.PartitionStat, error) {
+	return disk.PartitionsWithContext(d.context(ctx), all)
 }
 
-func (d *DefaultDiskInfoProvider) Usage(path string) (*disk.UsageStat, error) {
-	return disk.Usage(path)
+func (d *DefaultDiskInfoProvider) Usage(ctx context.Context, path string) (*disk.UsageStat, error) {
+	return disk.UsageWithContext(d.context(ctx), path)
 }
 
 // DefaultNetworkInfoProvider implements NetworkInfoProvider using gopsutil
-type DefaultNetworkInfoProvider struct{}
+type DefaultNetworkInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultNetworkInfoProvider) IOCounters(pernic bool) ([]net.IOCountersStat, error) {
-	return net.IOCounters(pernic)
+func (d *DefaultNetworkInfoProvider) IOCounters(ctx context.Context, pernic bool) ([]net.IOCountersStat, error) {
+	return net.IOCountersWithContext(d.context(ctx), pernic)
 }
 
-func (d *DefaultNetworkInfoProvider) Interfaces() ([]net.InterfaceStat, error) {
-	return net.Interfaces()
+func (d *DefaultNetworkInfoProvider) Interfaces(ctx context.Context) ([]net.InterfaceStat, error) {
+	return net.InterfacesWithContext(d.context(ctx))
 }
 
 // DefaultProcessInfoProvider implements ProcessInfoProvider using gopsutil
-type DefaultProcessInfoProvider struct{}
+type DefaultProcessInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultProcessInfoProvider) Processes() ([]*process.Process, error) {
-	return process.Processes()
+func (d *DefaultProcessInfoProvider) Processes(ctx context.Context) ([]*process.Process, error) {
+	return listProcesses(d.context(ctx))
 }
 
-func (d *DefaultProcessInfoProvider) NewProcess(pid int32) (*process.Process, error) {
-	return process.NewProcess(pid)
+func (d *DefaultProcessInfoProvider) NewProcess(ctx context.Context, pid int32) (*process.Process, error) {
+	return process.NewProcessWithContext(d.context(ctx), pid)
 }
 
 // DefaultHostInfoProvider implements HostInfoProvider using gopsutil
-type DefaultHostInfoProvider struct{}
+type DefaultHostInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultHostInfoProvider) Info() (*host.InfoStat, error) {
-	return host.Info()
+func (d *DefaultHostInfoProvider) Info(ctx context.Context) (*host.InfoStat, error) {
+	return host.InfoWithContext(d.context(ctx))
 }
 
-func (d *DefaultHostInfoProvider) SensorsTemperatures() ([]sensors.TemperatureStat, error) {
-	return sensors.SensorsTemperatures()
+func (d *DefaultHostInfoProvider) SensorsTemperatures(ctx context.Context) ([]sensors.TemperatureStat, error) {
+	return sensors.TemperaturesWithContext(d.context(ctx))
 }
 
 // DefaultLoadInfoProvider implements LoadInfoProvider using gopsutil
-type DefaultLoadInfoProvider struct{}
+type DefaultLoadInfoProvider struct{ gopsutilEnv }
 
-func (d *DefaultLoadInfoProvider) Avg() (*load.AvgStat, error) {
-	return load.Avg()
+func (d *DefaultLoadInfoProvider) Avg(ctx context.Context) (*load.AvgStat, error) {
+	return loadAvg(d.context(ctx))
 }
 
-func (d *DefaultLoadInfoProvider) Misc() (*load.MiscStat, error) {
-	return load.Misc()
+func (d *DefaultLoadInfoProvider) Misc(ctx context.Context) (*load.MiscStat, error) {
+	return loadMisc(d.context(ctx))
 }
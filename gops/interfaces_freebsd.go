@@ -0,0 +1,27 @@
+//go:build freebsd
+
+package gops
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+func cpuTimes(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error) {
+	return cpu.TimesWithContext(ctx, perCPU)
+}
+
+func loadAvg(ctx context.Context) (*load.AvgStat, error) {
+	return load.AvgWithContext(ctx)
+}
+
+func loadMisc(ctx context.Context) (*load.MiscStat, error) {
+	return load.MiscWithContext(ctx)
+}
+
+func listProcesses(ctx context.Context) ([]*process.Process, error) {
+	return process.ProcessesWithContext(ctx)
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package gops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/shirou/gopsutil/v4/common"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// The default providers read the hot /proc files with dgop's own parsers on
+// Linux and leave everything else to gopsutil.
+
+// hostProc joins elems onto the proc directory gopsutil would use for ctx.
+func hostProc(ctx context.Context, elems ...string) string {
+	dir := "/proc"
+	if env, ok := ctx.Value(common.EnvKey).(common.EnvMap); ok && env[common.HostProcEnvKey] != "" {
+		dir = env[common.HostProcEnvKey]
+	} else if v := os.Getenv(string(common.HostProcEnvKey)); v != "" {
+		dir = v
+	}
+	return filepath.Join(append([]string{dir}, elems...)...)
+}
+
+func cpuTimes(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error) {
+	data, err := os.ReadFile(hostProc(ctx, "stat"))
+	if err != nil {
+		// gopsutil reports no times rather than an error.
+		return []cpu.TimesStat{}, nil
+	}
+	times := parseCPUTimes(data, perCPU)
+	if times == nil {
+		times = []cpu.TimesStat{}
+	}
+	return times, nil
+}
+
+func loadAvg(ctx context.Context) (*load.AvgStat, error) {
+	if data, err := os.ReadFile(hostProc(ctx, "loadavg")); err == nil {
+		if avg, err := parseLoadAvg(data); err == nil {
+			return &load.AvgStat{Load1: avg.load1, Load5: avg.load5, Load15: avg.load15}, nil
+		}
+	}
+	// gopsutil falls back to sysinfo(2).
+	return load.AvgWithContext(ctx)
+}
+
+func loadMisc(ctx context.Context) (*load.MiscStat, error) {
+	data, err := os.ReadFile(hostProc(ctx, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	misc := parseMiscStat(data)
+	pids, err := listPIDs(hostProc(ctx))
+	if err != nil {
+		return &misc, err
+	}
+	misc.ProcsTotal = len(pids)
+	return &misc, nil
+}
+
+// listProcesses lists processes without gopsutil's per-PID existence check
+// and start time read. A process that exits before the collectors read it
+// looks like one that exits mid-read.
+func listProcesses(ctx context.Context) ([]*process.Process, error) {
+	pids, err := listPIDs(hostProc(ctx))
+	if err != nil {
+		return nil, err
+	}
+	procs := make([]*process.Process, len(pids))
+	for i, pid := range pids {
+		procs[i] = &process.Process{Pid: pid}
+	}
+	return procs, nil
+}
@@ -0,0 +1,12 @@
+package gops
+
+import (
+	"context"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+func (self *GopsUtil) GetMemoryInfo(ctx context.Context) (*models.MemoryInfo, error) {
+	return cachedModule(ctx, self, "memory", "", time.Time{}, self.collectMemoryInfo)
+}
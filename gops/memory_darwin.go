@@ -2,10 +2,13 @@
 
 package gops
 
-import "github.com/AvengeMedia/dgop/models"
+import (
+	"context"
+	"github.com/AvengeMedia/dgop/models"
+)
 
-func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
-	v, err := self.memProvider.VirtualMemory()
+func (self *GopsUtil) collectMemoryInfo(ctx context.Context) (*models.MemoryInfo, error) {
+	v, err := self.memProvider.VirtualMemory(ctx)
 	if err != nil {
 		return nil, err
 	}
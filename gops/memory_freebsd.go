@@ -3,13 +3,14 @@
 package gops
 
 import (
+	"context"
 	"golang.org/x/sys/unix"
 
 	"github.com/AvengeMedia/dgop/models"
 )
 
-func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
-	v, err := self.memProvider.VirtualMemory()
+func (self *GopsUtil) collectMemoryInfo(ctx context.Context) (*models.MemoryInfo, error) {
+	v, err := self.memProvider.VirtualMemory(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -20,7 +21,7 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 	cached := (v.Cached + v.Inactive + v.Laundry) / 1024
 	available := v.Available / 1024
 
-	arcSize, arcMin := readZfsArcStats()
+	arcSize, arcMin := self.readZfsArcStats()
 	arcSizeKB := arcSize / 1024
 	arcMinKB := arcMin / 1024
 
@@ -48,7 +49,7 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 	}
 
 	var swapTotal, swapFree uint64
-	if s, err := self.memProvider.SwapMemory(); err == nil {
+	if s, err := self.memProvider.SwapMemory(ctx); err == nil {
 		swapTotal = s.Total / 1024
 		swapFree = s.Free / 1024
 	}
@@ -69,7 +70,7 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 
 // OpenZFS exposes kstats as sysctls under kstat.zfs.<module>.<name>,
 // per module/os/freebsd/spl/spl_kstat.c.
-func readZfsArcStats() (size uint64, cMin uint64) {
+func (self *GopsUtil) readZfsArcStats() (size uint64, cMin uint64) {
 	size, err := unix.SysctlUint64("kstat.zfs.misc.arcstats.size")
 	if err != nil {
 		return 0, 0
@@ -4,15 +4,16 @@ package gops
 
 import (
 	"bufio"
-	"os"
+	"bytes"
+	"context"
 	"strconv"
 	"strings"
 
 	"github.com/AvengeMedia/dgop/models"
 )
 
-func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
-	v, err := self.memProvider.VirtualMemory()
+func (self *GopsUtil) collectMemoryInfo(ctx context.Context) (*models.MemoryInfo, error) {
+	v, err := self.memProvider.VirtualMemory(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +28,7 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 
 	rawCached := cached - sreclaimable
 
-	arcSize, arcMin := readZfsArcStats()
+	arcSize, arcMin := self.readZfsArcStats()
 	arcSizeKB := arcSize / 1024
 	arcMinKB := arcMin / 1024
 
@@ -40,7 +41,7 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 	rawCached += arcSizeKB
 	available += freeableArc
 
-	gpuActive, gpuReclaim := readGPUMemStats()
+	gpuActive, gpuReclaim := self.readGPUMemStats()
 	available += gpuReclaim
 
 	usedDiff := free + rawCached + sreclaimable + buffers + gpuReclaim
@@ -75,14 +76,13 @@ func (self *GopsUtil) GetMemoryInfo() (*models.MemoryInfo, error) {
 	}, nil
 }
 
-func readGPUMemStats() (active uint64, reclaim uint64) {
-	f, err := os.Open("/proc/meminfo")
+func (self *GopsUtil) readGPUMemStats() (active uint64, reclaim uint64) {
+	data, err := self.fs.ReadFile("/proc/meminfo")
 	if err != nil {
 		return 0, 0
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		if len(fields) < 2 {
@@ -98,14 +98,13 @@ func readGPUMemStats() (active uint64, reclaim uint64) {
 	return active, reclaim
 }
 
-func readZfsArcStats() (size uint64, cMin uint64) {
-	f, err := os.Open("/proc/spl/kstat/zfs/arcstats")
+func (self *GopsUtil) readZfsArcStats() (size uint64, cMin uint64) {
+	data, err := self.fs.ReadFile("/proc/spl/kstat/zfs/arcstats")
 	if err != nil {
 		return 0, 0
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		if len(fields) != 3 {
@@ -2,7 +2,12 @@ package gops
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 
@@ -24,6 +29,15 @@ var availableModules = []string{
 	"hardware",
 	"gpu",
 	"gpu-temp",
+	"units",
+	"proc-events",
+	"anomalies",
+}
+
+// IsModule reports whether name is a module accepted by GetMeta.
+func IsModule(name string) bool {
+	name = strings.ToLower(name)
+	return name == "all" || slices.Contains(availableModules, name)
 }
 
 func (self *GopsUtil) GetModules() (*models.ModulesInfo, error) {
@@ -33,274 +47,238 @@ func (self *GopsUtil) GetModules() (*models.ModulesInfo, error) {
 }
 
 type MetaParams struct {
-	SortBy         ProcSortBy
-	ProcLimit      int
-	EnableCPU      bool
-	MergeChildren  bool
-	GPUPciIds      []string
-	CPUCursor      string
-	ProcCursor     string
-	NetRateCursor  string
-	DiskRateCursor string
+	SortBy        ProcSortBy
+	ProcLimit     int
+	EnableCPU     bool
+	MergeChildren bool
+	GroupBy       ProcGroupBy
+	// AccurateMemory reads each process's smaps_rollup, as in
+	// ProcessParams.AccurateMemory.
+	AccurateMemory bool
+	// ProcFilter keeps only the processes it matches.
+	ProcFilter       *ProcFilter
+	GPUPciIds        []string
+	CPUCursor        string
+	ProcCursor       string
+	NetRateCursor    string
+	DiskRateCursor   string
+	UnitsCursor      string
+	ProcEventsCursor string
+	AnomaliesCursor  string
+	// MetaCursor is the composite cursor from a previous MetaInfo. It fills
+	// in any of the module cursors above that are left empty.
+	MetaCursor string
 }
 
+// GetMeta collects modules in one call. The returned MetaInfo carries each
+// module's own cursor and a MetaCursor covering all of them, so callers can
+// pass back either.
 func (self *GopsUtil) GetMeta(ctx context.Context, modules []string, params MetaParams) (*models.MetaInfo, error) {
-	meta := &models.MetaInfo{}
+	parts := decodeMetaCursor(params.MetaCursor)
+	params, inherited := parts.apply(params)
 
+	meta, rejected, err := self.collectMeta(ctx, modules, params, inherited)
+	if err != nil {
+		return nil, err
+	}
+	for _, module := range rejected {
+		delete(parts, module)
+	}
+	meta.MetaCursor = parts.update(meta).encode()
+	return meta, nil
+}
+
+// collectMeta collects each module concurrently under its own deadline. A
+// module that fails or runs out of time is left out and listed in
+// MetaInfo.Errors. A rejected cursor fails the whole call only when the
+// caller passed it explicitly; one taken from the composite cursor, named
+// in inherited, is dropped and the module collected without it. Those
+// modules are returned so their parts can be forgotten.
+func (self *GopsUtil) collectMeta(ctx context.Context, modules []string, params MetaParams, inherited map[string]bool) (*models.MetaInfo, []string, error) {
+	var names []string
 	for _, module := range modules {
-		switch strings.ToLower(module) {
-		case "all":
-			return self.loadAllModules(ctx, params)
-		case "cpu":
-			if cpu, err := self.GetCPUInfoWithCursor(params.CPUCursor); err == nil {
-				meta.CPU = cpu
-			}
-		case "memory":
-			if mem, err := self.GetMemoryInfo(); err == nil {
-				meta.Memory = mem
-			}
-		case "network":
-			if net, err := self.GetNetworkInfo(); err == nil {
-				meta.Network = net
-			}
-		case "net-rate":
-			if netRate, err := self.GetNetworkRates(params.NetRateCursor); err == nil {
-				meta.NetRate = netRate
-			}
-		case "disk":
-			if disk, err := self.GetDiskInfo(); err == nil {
-				meta.Disk = disk
-			}
-		case "disk-rate":
-			if diskRate, err := self.GetDiskRates(params.DiskRateCursor); err == nil {
-				meta.DiskRate = diskRate
-			}
-		case "diskmounts":
-			if mounts, err := self.GetDiskMounts(); err == nil {
-				meta.DiskMounts = mounts
-			}
-		case "processes":
-			if result, err := self.GetProcessesWithCursor(params.SortBy, params.ProcLimit, params.EnableCPU, params.ProcCursor, params.MergeChildren); err == nil {
-				meta.Processes = result.Processes
-				meta.Cursor = result.Cursor
-			}
-		case "system":
-			if sys, err := self.GetSystemInfo(); err == nil {
-				meta.System = sys
-			}
-		case "hardware":
-			if hw, err := self.GetSystemHardware(); err == nil {
-				meta.Hardware = hw
-			}
-		case "gpu", "gpu-temp":
-			if gpu, err := self.GetGPUInfoWithTemp(params.GPUPciIds); err == nil {
-				meta.GPU = gpu
-			}
+		module = strings.ToLower(module)
+		switch {
+		case module == "all":
+			names = append(names, availableModules...)
+		case slices.Contains(availableModules, module):
+			names = append(names, module)
 		default:
-			return nil, fmt.Errorf("unknown module: %s", module)
+			return nil, nil, fmt.Errorf("unknown module: %s", module)
 		}
 	}
+	// gpu-temp is the gpu module under another name.
+	for i, name := range names {
+		if name == "gpu-temp" {
+			names[i] = "gpu"
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
 
-	return meta, nil
-}
-
-func (self *GopsUtil) loadAllModules(ctx context.Context, params MetaParams) (*models.MetaInfo, error) {
 	meta := &models.MetaInfo{}
+	var rejected []string
 	var mu sync.Mutex
 
 	g, ctx := errgroup.WithContext(ctx)
-
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		cpu, err := self.GetCPUInfoWithCursor(params.CPUCursor)
-		if err != nil {
-			log.Warn("failed to get CPU info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.CPU = cpu
-		mu.Unlock()
-		return nil
-	})
-
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		mem, err := self.GetMemoryInfo()
-		if err != nil {
-			log.Warn("failed to get memory info", "error", err)
+	for _, module := range names {
+		g.Go(func() error {
+			fill, err := self.runModule(ctx, module, params)
+			if IsCursorError(err) && inherited[module] {
+				log.Debug("dropping rejected meta cursor part", "module", module, "error", err)
+				mu.Lock()
+				rejected = append(rejected, module)
+				mu.Unlock()
+				fill, err = self.runModule(ctx, module, withoutCursor(params, module))
+			}
+			if IsCursorError(err) {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Warn("failed to collect module", "module", module, "error", err)
+				if meta.Errors == nil {
+					meta.Errors = make(map[string]models.ModuleError)
+				}
+				meta.Errors[module] = moduleError(err)
+				return nil
+			}
+			fill(meta)
 			return nil
-		}
-		mu.Lock()
-		meta.Memory = mem
-		mu.Unlock()
-		return nil
-	})
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		net, err := self.GetNetworkInfo()
-		if err != nil {
-			log.Warn("failed to get network info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.Network = net
-		mu.Unlock()
-		return nil
-	})
+	return meta, rejected, nil
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		netRate, err := self.GetNetworkRates(params.NetRateCursor)
-		if err != nil {
-			log.Warn("failed to get network rates", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.NetRate = netRate
-		mu.Unlock()
-		return nil
-	})
+// runModule collects module within its deadline, returning a function that
+// stores the result. It returns once the deadline passes even if the
+// collector ignores ctx, leaving it to finish in the background.
+func (self *GopsUtil) runModule(ctx context.Context, module string, params MetaParams) (func(*models.MetaInfo), error) {
+	ctx, cancel := context.WithTimeout(ctx, self.moduleTimeout(module))
+	defer cancel()
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		disk, err := self.GetDiskInfo()
-		if err != nil {
-			log.Warn("failed to get disk info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.Disk = disk
-		mu.Unlock()
-		return nil
-	})
+	type result struct {
+		fill func(*models.MetaInfo)
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		fill, err := self.collectModule(ctx, module, params)
+		done <- result{fill, err}
+	}()
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		diskRate, err := self.GetDiskRates(params.DiskRateCursor)
-		if err != nil {
-			log.Warn("failed to get disk rates", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.DiskRate = diskRate
-		mu.Unlock()
-		return nil
-	})
+	select {
+	case r := <-done:
+		return r.fill, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		mounts, err := self.GetDiskMounts()
-		if err != nil {
-			log.Warn("failed to get disk mounts", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.DiskMounts = mounts
-		mu.Unlock()
-		return nil
-	})
+func (self *GopsUtil) collectModule(ctx context.Context, module string, params MetaParams) (func(*models.MetaInfo), error) {
+	switch module {
+	case "cpu":
+		cpu, err := self.GetCPUInfoWithCursor(ctx, params.CPUCursor)
+		return func(m *models.MetaInfo) { m.CPU = cpu }, err
+	case "memory":
+		mem, err := self.GetMemoryInfo(ctx)
+		return func(m *models.MetaInfo) { m.Memory = mem }, err
+	case "network":
+		net, err := self.GetNetworkInfo(ctx)
+		return func(m *models.MetaInfo) { m.Network = net }, err
+	case "net-rate":
+		netRate, err := self.GetNetworkRates(ctx, params.NetRateCursor)
+		return func(m *models.MetaInfo) { m.NetRate = netRate }, err
+	case "disk":
+		disk, err := self.GetDiskInfo(ctx)
+		return func(m *models.MetaInfo) { m.Disk = disk }, err
+	case "disk-rate":
+		diskRate, err := self.GetDiskRates(ctx, params.DiskRateCursor)
+		return func(m *models.MetaInfo) { m.DiskRate = diskRate }, err
+	case "diskmounts":
+		mounts, err := self.GetDiskMounts(ctx)
+		return func(m *models.MetaInfo) { m.DiskMounts = mounts }, err
+	case "processes":
+		result, err := self.GetProcessesWithCursor(ctx, ProcessParams{
+			SortBy:         params.SortBy,
+			Limit:          params.ProcLimit,
+			EnableCPU:      params.EnableCPU,
+			Cursor:         params.ProcCursor,
+			MergeChildren:  params.MergeChildren,
+			GroupBy:        params.GroupBy,
+			AccurateMemory: params.AccurateMemory,
+			Filter:         params.ProcFilter,
+		})
+		return func(m *models.MetaInfo) {
+			m.Processes = result.Processes
+			m.Cursor = result.Cursor
+		}, err
+	case "system":
+		sys, err := self.GetSystemInfo(ctx)
+		return func(m *models.MetaInfo) { m.System = sys }, err
+	case "hardware":
+		hw, err := self.GetSystemHardware(ctx)
+		return func(m *models.MetaInfo) { m.Hardware = hw }, err
+	case "gpu":
+		gpu, err := self.GetGPUInfoWithTemp(ctx, params.GPUPciIds)
+		return func(m *models.MetaInfo) { m.GPU = gpu }, err
+	case "units":
+		units, err := self.GetUnits(ctx, UnitSortByMemory, 0, params.UnitsCursor)
+		return func(m *models.MetaInfo) { m.Units = units }, err
+	case "proc-events":
+		events, err := self.GetProcessEvents(ctx, params.ProcEventsCursor)
+		return func(m *models.MetaInfo) { m.ProcEvents = events }, err
+	case "anomalies":
+		anomalies, err := self.GetAnomalies(ctx, params.AnomaliesCursor, AnomalyOptions{})
+		return func(m *models.MetaInfo) { m.Anomalies = anomalies }, err
+	default:
+		return nil, fmt.Errorf("unknown module: %s", module)
+	}
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		procs, err := self.GetProcessesWithCursor(params.SortBy, params.ProcLimit, params.EnableCPU, params.ProcCursor, params.MergeChildren)
-		if err != nil {
-			log.Warn("failed to get processes", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.Processes = procs.Processes
-		meta.Cursor = procs.Cursor
-		mu.Unlock()
-		return nil
-	})
+// moduleError describes why a module is missing from a MetaInfo or
+// SystemMetrics.
+func moduleError(err error) models.ModuleError {
+	code := models.ModuleErrorFailed
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = models.ModuleErrorTimeout
+	case errors.Is(err, context.Canceled):
+		code = models.ModuleErrorCanceled
+	case errors.Is(err, fs.ErrPermission):
+		code = models.ModuleErrorPermissionDenied
+	case errors.Is(err, errors.ErrUnsupported):
+		code = models.ModuleErrorUnsupported
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, exec.ErrNotFound):
+		code = models.ModuleErrorNotFound
+	}
+	return models.ModuleError{Code: code, Message: err.Error()}
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		sys, err := self.GetSystemInfo()
-		if err != nil {
-			log.Warn("failed to get system info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.System = sys
-		mu.Unlock()
-		return nil
-	})
+// PartialError lists the modules missing from a result, for callers that
+// treat any failed module as fatal.
+type PartialError struct {
+	Errors map[string]models.ModuleError
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		hw, err := self.GetSystemHardware()
-		if err != nil {
-			log.Warn("failed to get hardware info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.Hardware = hw
-		mu.Unlock()
-		return nil
-	})
+func (e *PartialError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, module := range slices.Sorted(maps.Keys(e.Errors)) {
+		me := e.Errors[module]
+		parts = append(parts, fmt.Sprintf("%s: %s: %s", module, me.Code, me.Message))
+	}
+	return "modules failed: " + strings.Join(parts, "; ")
+}
 
-	g.Go(func() error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		gpu, err := self.GetGPUInfoWithTemp(params.GPUPciIds)
-		if err != nil {
-			log.Warn("failed to get GPU info", "error", err)
-			return nil
-		}
-		mu.Lock()
-		meta.GPU = gpu
-		mu.Unlock()
+// CheckComplete returns a *PartialError when errs names any module.
+func CheckComplete(errs map[string]models.ModuleError) error {
+	if len(errs) == 0 {
 		return nil
-	})
-
-	if err := g.Wait(); err != nil {
-		return nil, err
 	}
-
-	return meta, nil
+	return &PartialError{Errors: errs}
 }
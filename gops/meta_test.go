@@ -0,0 +1,114 @@
+package gops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/gops/mocks"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetaReportsModulesThatMissTheirDeadline(t *testing.T) {
+	mockDisk := mocks.NewMockDiskInfoProvider(t)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	mockDisk.EXPECT().Partitions(mock.Anything, true).RunAndReturn(func(context.Context, bool) ([]disk.PartitionStat, error) {
+		// Ignores its context, like a statfs on a dead server.
+		<-release
+		return nil, nil
+	})
+	mockDisk.EXPECT().IOCounters(mock.Anything).Return(map[string]disk.IOCountersStat{
+		"sda": {ReadBytes: 512, WriteBytes: 1024},
+	}, nil).Once()
+	mockDisk.EXPECT().IOCounters(mock.Anything).Return(nil, assert.AnError).Once()
+
+	g := &GopsUtil{diskProvider: mockDisk}
+	require.NoError(t, g.SetModuleTimeouts(map[string]time.Duration{"diskmounts": 50 * time.Millisecond}))
+
+	start := time.Now()
+	meta, err := g.GetMeta(t.Context(), []string{"disk", "diskmounts"}, MetaParams{})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, meta.Disk, 1)
+	assert.Nil(t, meta.DiskMounts)
+	assert.Equal(t, map[string]models.ModuleError{
+		"diskmounts": {Code: models.ModuleErrorTimeout, Message: context.DeadlineExceeded.Error()},
+	}, meta.Errors)
+
+	meta, err = g.GetMeta(t.Context(), []string{"disk-rate"}, MetaParams{})
+	require.NoError(t, err)
+	assert.Nil(t, meta.DiskRate)
+	assert.Equal(t, models.ModuleErrorFailed, meta.Errors["disk-rate"].Code)
+}
+
+func TestGetMetaRejectsUnknownModules(t *testing.T) {
+	_, err := NewGopsUtil().GetMeta(t.Context(), []string{"memory", "gpus"}, MetaParams{})
+	assert.ErrorContains(t, err, "unknown module: gpus")
+}
+
+func TestSetModuleTimeoutsValidates(t *testing.T) {
+	g := NewGopsUtil()
+	assert.Error(t, g.SetModuleTimeouts(map[string]time.Duration{"gpus": time.Second}))
+	assert.Error(t, g.SetModuleTimeouts(map[string]time.Duration{"disk": 0}))
+
+	require.NoError(t, g.SetModuleTimeouts(map[string]time.Duration{"disk": time.Minute}))
+	assert.Equal(t, time.Minute, g.moduleTimeout("disk"))
+	assert.Equal(t, DefaultModuleTimeouts["memory"], g.moduleTimeout("memory"))
+}
+
+func TestModuleErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want models.ModuleErrorCode
+	}{
+		{context.DeadlineExceeded, models.ModuleErrorTimeout},
+		{context.Canceled, models.ModuleErrorCanceled},
+		{&fs.PathError{Op: "open", Path: "/sys/kernel/debug", Err: fs.ErrPermission}, models.ModuleErrorPermissionDenied},
+		{&fs.PathError{Op: "stat", Path: "/sys/bus/pci/devices", Err: fs.ErrNotExist}, models.ModuleErrorNotFound},
+		{&exec.Error{Name: "nvidia-smi", Err: exec.ErrNotFound}, models.ModuleErrorNotFound},
+		{fmt.Errorf("sensors: %w", errors.ErrUnsupported), models.ModuleErrorUnsupported},
+		{assert.AnError, models.ModuleErrorFailed},
+	}
+	for _, tt := range tests {
+		got := moduleError(tt.err)
+		assert.Equal(t, tt.want, got.Code, tt.err.Error())
+		assert.Equal(t, tt.err.Error(), got.Message)
+	}
+}
+
+func TestGetAllMetricsReportsFailedModules(t *testing.T) {
+	mockDisk := mocks.NewMockDiskInfoProvider(t)
+	mockDisk.EXPECT().IOCounters(mock.Anything).Return(nil, &fs.PathError{Op: "open", Path: "/proc/diskstats", Err: fs.ErrPermission})
+	mockDisk.EXPECT().Partitions(mock.Anything, true).Return(nil, nil)
+
+	g := NewGopsUtil()
+	g.diskProvider = mockDisk
+	metrics, err := g.GetAllMetrics(t.Context(), SortByPID, 1, false, false)
+	require.NoError(t, err)
+	assert.Nil(t, metrics.Disk)
+	require.Contains(t, metrics.Errors, "disk")
+	assert.Equal(t, models.ModuleErrorPermissionDenied, metrics.Errors["disk"].Code)
+	assert.NotContains(t, metrics.Errors, "diskmounts", "no mounts is not an error")
+}
+
+func TestCheckComplete(t *testing.T) {
+	assert.NoError(t, CheckComplete(nil))
+
+	err := CheckComplete(map[string]models.ModuleError{
+		"gpu":  {Code: models.ModuleErrorNotFound, Message: "no pci bus"},
+		"disk": {Code: models.ModuleErrorTimeout, Message: "context deadline exceeded"},
+	})
+	var partial *PartialError
+	require.ErrorAs(t, err, &partial)
+	assert.Len(t, partial.Errors, 2)
+	assert.EqualError(t, err, "modules failed: disk: timeout: context deadline exceeded; gpu: not_found: no pci bus")
+}
@@ -0,0 +1,197 @@
+package gops
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// metaCursorVersion is the first byte of every composite cursor. Bump it when
+// the layout changes; cursors from another version are dropped and the next
+// call starts a fresh baseline, as if no cursor had been sent.
+const metaCursorVersion byte = 1
+
+// cursorModule ties a stateful module to its own cursor in MetaParams and
+// MetaInfo. Adding a module with a cursor only needs an entry here.
+type cursorModule struct {
+	name string
+	get  func(*models.MetaInfo) string
+	set  func(*MetaParams, string)
+	// current is the cursor the caller passed explicitly, which wins over
+	// the composite one.
+	current func(*MetaParams) string
+}
+
+var cursorModules = []cursorModule{
+	{
+		name: "cpu",
+		get: func(m *models.MetaInfo) string {
+			if m.CPU == nil {
+				return ""
+			}
+			return m.CPU.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.CPUCursor = c },
+		current: func(p *MetaParams) string { return p.CPUCursor },
+	},
+	{
+		name:    "processes",
+		get:     func(m *models.MetaInfo) string { return m.Cursor },
+		set:     func(p *MetaParams, c string) { p.ProcCursor = c },
+		current: func(p *MetaParams) string { return p.ProcCursor },
+	},
+	{
+		name: "net-rate",
+		get: func(m *models.MetaInfo) string {
+			if m.NetRate == nil {
+				return ""
+			}
+			return m.NetRate.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.NetRateCursor = c },
+		current: func(p *MetaParams) string { return p.NetRateCursor },
+	},
+	{
+		name: "disk-rate",
+		get: func(m *models.MetaInfo) string {
+			if m.DiskRate == nil {
+				return ""
+			}
+			return m.DiskRate.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.DiskRateCursor = c },
+		current: func(p *MetaParams) string { return p.DiskRateCursor },
+	},
+	{
+		name: "units",
+		get: func(m *models.MetaInfo) string {
+			if m.Units == nil {
+				return ""
+			}
+			return m.Units.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.UnitsCursor = c },
+		current: func(p *MetaParams) string { return p.UnitsCursor },
+	},
+	{
+		name: "proc-events",
+		get: func(m *models.MetaInfo) string {
+			if m.ProcEvents == nil {
+				return ""
+			}
+			return m.ProcEvents.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.ProcEventsCursor = c },
+		current: func(p *MetaParams) string { return p.ProcEventsCursor },
+	},
+	{
+		name: "anomalies",
+		get: func(m *models.MetaInfo) string {
+			if m.Anomalies == nil {
+				return ""
+			}
+			return m.Anomalies.Cursor
+		},
+		set:     func(p *MetaParams, c string) { p.AnomaliesCursor = c },
+		current: func(p *MetaParams) string { return p.AnomaliesCursor },
+	},
+}
+
+// metaCursor maps module names to their own cursors.
+type metaCursor map[string]string
+
+// decodeMetaCursor returns the parts of a composite cursor, or none when it
+// is empty, corrupt or from another version.
+func decodeMetaCursor(cursor string) metaCursor {
+	out := metaCursor{}
+	if cursor == "" {
+		return out
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) == 0 {
+		log.Debug("ignoring malformed meta cursor")
+		return out
+	}
+	if raw[0] != metaCursorVersion {
+		log.Debug("ignoring meta cursor from another version", "version", raw[0], "want", metaCursorVersion)
+		return out
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(raw[1:]))
+	if err != nil {
+		return out
+	}
+	defer func() { _ = gz.Close() }()
+	data, err := io.ReadAll(io.LimitReader(gz, maxCursorDecodedBytes))
+	if err != nil {
+		return out
+	}
+	if json.Unmarshal(data, &out) != nil {
+		return metaCursor{}
+	}
+	return out
+}
+
+// apply fills the module cursors the caller left empty, returning the
+// modules whose cursor came from the composite one.
+func (c metaCursor) apply(params MetaParams) (MetaParams, map[string]bool) {
+	inherited := map[string]bool{}
+	for _, m := range cursorModules {
+		if part := c[m.name]; part != "" && m.current(&params) == "" {
+			m.set(&params, part)
+			inherited[m.name] = true
+		}
+	}
+	return params, inherited
+}
+
+// update takes the fresh cursors from meta. Modules that weren't collected
+// keep their previous part, so polling a subset doesn't lose the others,
+// until it is older than any module accepts.
+func (c metaCursor) update(meta *models.MetaInfo) metaCursor {
+	for _, m := range cursorModules {
+		if part := m.get(meta); part != "" {
+			c[m.name] = part
+		}
+	}
+	for name, part := range c {
+		if issued, ok := cursorIssued(part); ok && time.Since(issued) > CursorMaxAge {
+			delete(c, name)
+		}
+	}
+	return c
+}
+
+// withoutCursor returns params with module's own cursor emptied.
+func withoutCursor(params MetaParams, module string) MetaParams {
+	for _, m := range cursorModules {
+		if m.name == module {
+			m.set(&params, "")
+		}
+	}
+	return params
+}
+
+func (c metaCursor) encode() string {
+	if len(c) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	buf := bytes.NewBuffer([]byte{metaCursorVersion})
+	gz := cursorWriters.Get().(*gzip.Writer)
+	defer cursorWriters.Put(gz)
+	gz.Reset(buf)
+	_, _ = gz.Write(data)
+	_ = gz.Close()
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
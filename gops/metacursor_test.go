@@ -0,0 +1,102 @@
+package gops
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/gops/mocks"
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaCursorRoundTrip(t *testing.T) {
+	c := metaCursor{}.update(&models.MetaInfo{
+		CPU:     &models.CPUInfo{Cursor: "cpu-state"},
+		Cursor:  "proc-state",
+		NetRate: &models.NetworkRateResponse{Cursor: "net-state"},
+	})
+	encoded := c.encode()
+	assert.NotEmpty(t, encoded)
+
+	params, inherited := decodeMetaCursor(encoded).apply(MetaParams{NetRateCursor: "explicit"})
+	assert.Equal(t, "cpu-state", params.CPUCursor)
+	assert.Equal(t, "proc-state", params.ProcCursor)
+	assert.Equal(t, "explicit", params.NetRateCursor, "an explicit module cursor wins")
+	assert.Empty(t, params.DiskRateCursor)
+	assert.Equal(t, map[string]bool{"cpu": true, "processes": true}, inherited)
+}
+
+func TestMetaCursorKeepsModulesNotCollected(t *testing.T) {
+	first := metaCursor{}.update(&models.MetaInfo{
+		CPU:      &models.CPUInfo{Cursor: "cpu-1"},
+		DiskRate: &models.DiskRateResponse{Cursor: "disk-1"},
+	}).encode()
+
+	second := decodeMetaCursor(first).update(&models.MetaInfo{
+		CPU: &models.CPUInfo{Cursor: "cpu-2"},
+	})
+	assert.Equal(t, metaCursor{"cpu": "cpu-2", "disk-rate": "disk-1"}, second)
+}
+
+func TestMetaCursorRejectsOtherVersions(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteByte(metaCursorVersion + 1)
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(`{"cpu":"cpu-state"}`))
+	_ = gz.Close()
+
+	for name, cursor := range map[string]string{
+		"other version": base64.RawURLEncoding.EncodeToString(buf.Bytes()),
+		"garbage":       "not a cursor!",
+		"truncated":     metaCursor{"cpu": "cpu-state"}.encode()[:6],
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Empty(t, decodeMetaCursor(cursor))
+		})
+	}
+	assert.Empty(t, metaCursor{}.encode())
+}
+
+func TestGetMetaDropsExpiredParts(t *testing.T) {
+	key := NewCursorKey()
+	g := signerOn(t, key, "box", "boot-1")
+	mockDisk := mocks.NewMockDiskInfoProvider(t)
+	mockDisk.EXPECT().IOCounters(mock.Anything).Return(map[string]disk.IOCountersStat{
+		"sda": {ReadBytes: 512, WriteBytes: 1024},
+	}, nil)
+	g.diskProvider = mockDisk
+
+	_, host, boot := g.cursorIdentity()
+	stale := func(module string) string {
+		header, _ := json.Marshal(cursorHeader{
+			Module: module,
+			Host:   host,
+			Boot:   boot,
+			Issued: time.Now().Add(-2 * CursorMaxAge).UnixMilli(),
+		})
+		signed := base64.RawURLEncoding.EncodeToString(header) + ".e30"
+		return signed + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(key, signed))
+	}
+
+	// disk-rate is polled often enough, net-rate was last polled hours ago
+	// and disk-rate's part went stale while the caller was away.
+	composite := metaCursor{"disk-rate": stale("disk-rate"), "net-rate": stale("net-rate")}.encode()
+	meta, err := g.GetMeta(t.Context(), []string{"disk-rate"}, MetaParams{MetaCursor: composite})
+	require.NoError(t, err)
+	require.NotNil(t, meta.DiskRate)
+	assert.Empty(t, meta.Errors)
+
+	parts := decodeMetaCursor(meta.MetaCursor)
+	assert.Equal(t, metaCursor{"disk-rate": meta.DiskRate.Cursor}, parts)
+
+	// A cursor passed for the module itself still fails the call.
+	_, err = g.GetMeta(t.Context(), []string{"disk-rate"}, MetaParams{DiskRateCursor: stale("disk-rate")})
+	assert.ErrorIs(t, err, ErrCursorExpired)
+}
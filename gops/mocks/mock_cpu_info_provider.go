@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	"context"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
@@ -39,8 +40,8 @@ func (_m *MockCPUInfoProvider) EXPECT() *MockCPUInfoProvider_Expecter {
 }
 
 // Counts provides a mock function for the type MockCPUInfoProvider
-func (_mock *MockCPUInfoProvider) Counts(logical bool) (int, error) {
-	ret := _mock.Called(logical)
+func (_mock *MockCPUInfoProvider) Counts(ctx context.Context, logical bool) (int, error) {
+	ret := _mock.Called(ctx, logical)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Counts")
@@ -48,16 +49,16 @@ func (_mock *MockCPUInfoProvider) Counts(logical bool) (int, error) {
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(bool) (int, error)); ok {
-		return returnFunc(logical)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) (int, error)); ok {
+		return returnFunc(ctx, logical)
 	}
-	if returnFunc, ok := ret.Get(0).(func(bool) int); ok {
-		r0 = returnFunc(logical)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) int); ok {
+		r0 = returnFunc(ctx, logical)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(bool) error); ok {
-		r1 = returnFunc(logical)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = returnFunc(ctx, logical)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,19 +71,25 @@ type MockCPUInfoProvider_Counts_Call struct {
 }
 
 // Counts is a helper method to define mock.On call
+//   - ctx context.Context
 //   - logical bool
-func (_e *MockCPUInfoProvider_Expecter) Counts(logical any) *MockCPUInfoProvider_Counts_Call {
-	return &MockCPUInfoProvider_Counts_Call{Call: _e.mock.On("Counts", logical)}
+func (_e *MockCPUInfoProvider_Expecter) Counts(ctx any, logical any) *MockCPUInfoProvider_Counts_Call {
+	return &MockCPUInfoProvider_Counts_Call{Call: _e.mock.On("Counts", ctx, logical)}
 }
 
-func (_c *MockCPUInfoProvider_Counts_Call) Run(run func(logical bool)) *MockCPUInfoProvider_Counts_Call {
+func (_c *MockCPUInfoProvider_Counts_Call) Run(run func(ctx context.Context, logical bool)) *MockCPUInfoProvider_Counts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 bool
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(bool)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -93,14 +100,14 @@ func (_c *MockCPUInfoProvider_Counts_Call) Return(n int, err error) *MockCPUInfo
 	return _c
 }
 
-func (_c *MockCPUInfoProvider_Counts_Call) RunAndReturn(run func(logical bool) (int, error)) *MockCPUInfoProvider_Counts_Call {
+func (_c *MockCPUInfoProvider_Counts_Call) RunAndReturn(run func(ctx context.Context, logical bool) (int, error)) *MockCPUInfoProvider_Counts_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Info provides a mock function for the type MockCPUInfoProvider
-func (_mock *MockCPUInfoProvider) Info() ([]cpu.InfoStat, error) {
-	ret := _mock.Called()
+func (_mock *MockCPUInfoProvider) Info(ctx context.Context) ([]cpu.InfoStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Info")
@@ -108,18 +115,18 @@ func (_mock *MockCPUInfoProvider) Info() ([]cpu.InfoStat, error) {
 
 	var r0 []cpu.InfoStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]cpu.InfoStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]cpu.InfoStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() []cpu.InfoStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []cpu.InfoStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]cpu.InfoStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -132,13 +139,20 @@ type MockCPUInfoProvider_Info_Call struct {
 }
 
 // Info is a helper method to define mock.On call
-func (_e *MockCPUInfoProvider_Expecter) Info() *MockCPUInfoProvider_Info_Call {
-	return &MockCPUInfoProvider_Info_Call{Call: _e.mock.On("Info")}
+//   - ctx context.Context
+func (_e *MockCPUInfoProvider_Expecter) Info(ctx any) *MockCPUInfoProvider_Info_Call {
+	return &MockCPUInfoProvider_Info_Call{Call: _e.mock.On("Info", ctx)}
 }
 
-func (_c *MockCPUInfoProvider_Info_Call) Run(run func()) *MockCPUInfoProvider_Info_Call {
+func (_c *MockCPUInfoProvider_Info_Call) Run(run func(ctx context.Context)) *MockCPUInfoProvider_Info_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -148,14 +162,14 @@ func (_c *MockCPUInfoProvider_Info_Call) Return(infoStats []cpu.InfoStat, err er
 	return _c
 }
 
-func (_c *MockCPUInfoProvider_Info_Call) RunAndReturn(run func() ([]cpu.InfoStat, error)) *MockCPUInfoProvider_Info_Call {
+func (_c *MockCPUInfoProvider_Info_Call) RunAndReturn(run func(ctx context.Context) ([]cpu.InfoStat, error)) *MockCPUInfoProvider_Info_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Percent provides a mock function for the type MockCPUInfoProvider
-func (_mock *MockCPUInfoProvider) Percent(interval time.Duration, perCPU bool) ([]float64, error) {
-	ret := _mock.Called(interval, perCPU)
+func (_mock *MockCPUInfoProvider) Percent(ctx context.Context, interval time.Duration, perCPU bool) ([]float64, error) {
+	ret := _mock.Called(ctx, interval, perCPU)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Percent")
@@ -163,18 +177,18 @@ func (_mock *MockCPUInfoProvider) Percent(interval time.Duration, perCPU bool) (
 
 	var r0 []float64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(time.Duration, bool) ([]float64, error)); ok {
-		return returnFunc(interval, perCPU)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration, bool) ([]float64, error)); ok {
+		return returnFunc(ctx, interval, perCPU)
 	}
-	if returnFunc, ok := ret.Get(0).(func(time.Duration, bool) []float64); ok {
-		r0 = returnFunc(interval, perCPU)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration, bool) []float64); ok {
+		r0 = returnFunc(ctx, interval, perCPU)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]float64)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(time.Duration, bool) error); ok {
-		r1 = returnFunc(interval, perCPU)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Duration, bool) error); ok {
+		r1 = returnFunc(ctx, interval, perCPU)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -187,25 +201,31 @@ type MockCPUInfoProvider_Percent_Call struct {
 }
 
 // Percent is a helper method to define mock.On call
+//   - ctx context.Context
 //   - interval time.Duration
 //   - perCPU bool
-func (_e *MockCPUInfoProvider_Expecter) Percent(interval any, perCPU any) *MockCPUInfoProvider_Percent_Call {
-	return &MockCPUInfoProvider_Percent_Call{Call: _e.mock.On("Percent", interval, perCPU)}
+func (_e *MockCPUInfoProvider_Expecter) Percent(ctx any, interval any, perCPU any) *MockCPUInfoProvider_Percent_Call {
+	return &MockCPUInfoProvider_Percent_Call{Call: _e.mock.On("Percent", ctx, interval, perCPU)}
 }
 
-func (_c *MockCPUInfoProvider_Percent_Call) Run(run func(interval time.Duration, perCPU bool)) *MockCPUInfoProvider_Percent_Call {
+func (_c *MockCPUInfoProvider_Percent_Call) Run(run func(ctx context.Context, interval time.Duration, perCPU bool)) *MockCPUInfoProvider_Percent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 time.Duration
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(time.Duration)
+			arg0 = args[0].(context.Context)
 		}
-		var arg1 bool
+		var arg1 time.Duration
 		if args[1] != nil {
-			arg1 = args[1].(bool)
+			arg1 = args[1].(time.Duration)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -216,14 +236,14 @@ func (_c *MockCPUInfoProvider_Percent_Call) Return(float64s []float64, err error
 	return _c
 }
 
-func (_c *MockCPUInfoProvider_Percent_Call) RunAndReturn(run func(interval time.Duration, perCPU bool) ([]float64, error)) *MockCPUInfoProvider_Percent_Call {
+func (_c *MockCPUInfoProvider_Percent_Call) RunAndReturn(run func(ctx context.Context, interval time.Duration, perCPU bool) ([]float64, error)) *MockCPUInfoProvider_Percent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Times provides a mock function for the type MockCPUInfoProvider
-func (_mock *MockCPUInfoProvider) Times(perCPU bool) ([]cpu.TimesStat, error) {
-	ret := _mock.Called(perCPU)
+func (_mock *MockCPUInfoProvider) Times(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error) {
+	ret := _mock.Called(ctx, perCPU)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Times")
@@ -231,18 +251,18 @@ func (_mock *MockCPUInfoProvider) Times(perCPU bool) ([]cpu.TimesStat, error) {
 
 	var r0 []cpu.TimesStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(bool) ([]cpu.TimesStat, error)); ok {
-		return returnFunc(perCPU)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) ([]cpu.TimesStat, error)); ok {
+		return returnFunc(ctx, perCPU)
 	}
-	if returnFunc, ok := ret.Get(0).(func(bool) []cpu.TimesStat); ok {
-		r0 = returnFunc(perCPU)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) []cpu.TimesStat); ok {
+		r0 = returnFunc(ctx, perCPU)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]cpu.TimesStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(bool) error); ok {
-		r1 = returnFunc(perCPU)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = returnFunc(ctx, perCPU)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -255,19 +275,25 @@ type MockCPUInfoProvider_Times_Call struct {
 }
 
 // Times is a helper method to define mock.On call
+//   - ctx context.Context
 //   - perCPU bool
-func (_e *MockCPUInfoProvider_Expecter) Times(perCPU any) *MockCPUInfoProvider_Times_Call {
-	return &MockCPUInfoProvider_Times_Call{Call: _e.mock.On("Times", perCPU)}
+func (_e *MockCPUInfoProvider_Expecter) Times(ctx any, perCPU any) *MockCPUInfoProvider_Times_Call {
+	return &MockCPUInfoProvider_Times_Call{Call: _e.mock.On("Times", ctx, perCPU)}
 }
 
-func (_c *MockCPUInfoProvider_Times_Call) Run(run func(perCPU bool)) *MockCPUInfoProvider_Times_Call {
+func (_c *MockCPUInfoProvider_Times_Call) Run(run func(ctx context.Context, perCPU bool)) *MockCPUInfoProvider_Times_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 bool
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(bool)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -278,7 +304,7 @@ func (_c *MockCPUInfoProvider_Times_Call) Return(timesStats []cpu.TimesStat, err
 	return _c
 }
 
-func (_c *MockCPUInfoProvider_Times_Call) RunAndReturn(run func(perCPU bool) ([]cpu.TimesStat, error)) *MockCPUInfoProvider_Times_Call {
+func (_c *MockCPUInfoProvider_Times_Call) RunAndReturn(run func(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error)) *MockCPUInfoProvider_Times_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/disk"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockDiskInfoProvider) EXPECT() *MockDiskInfoProvider_Expecter {
 }
 
 // IOCounters provides a mock function for the type MockDiskInfoProvider
-func (_mock *MockDiskInfoProvider) IOCounters() (map[string]disk.IOCountersStat, error) {
-	ret := _mock.Called()
+func (_mock *MockDiskInfoProvider) IOCounters(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IOCounters")
@@ -46,18 +48,18 @@ func (_mock *MockDiskInfoProvider) IOCounters() (map[string]disk.IOCountersStat,
 
 	var r0 map[string]disk.IOCountersStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (map[string]disk.IOCountersStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]disk.IOCountersStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() map[string]disk.IOCountersStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]disk.IOCountersStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]disk.IOCountersStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,13 +72,20 @@ type MockDiskInfoProvider_IOCounters_Call struct {
 }
 
 // IOCounters is a helper method to define mock.On call
-func (_e *MockDiskInfoProvider_Expecter) IOCounters() *MockDiskInfoProvider_IOCounters_Call {
-	return &MockDiskInfoProvider_IOCounters_Call{Call: _e.mock.On("IOCounters")}
+//   - ctx context.Context
+func (_e *MockDiskInfoProvider_Expecter) IOCounters(ctx any) *MockDiskInfoProvider_IOCounters_Call {
+	return &MockDiskInfoProvider_IOCounters_Call{Call: _e.mock.On("IOCounters", ctx)}
 }
 
-func (_c *MockDiskInfoProvider_IOCounters_Call) Run(run func()) *MockDiskInfoProvider_IOCounters_Call {
+func (_c *MockDiskInfoProvider_IOCounters_Call) Run(run func(ctx context.Context)) *MockDiskInfoProvider_IOCounters_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -86,14 +95,14 @@ func (_c *MockDiskInfoProvider_IOCounters_Call) Return(stringToIOCountersStat ma
 	return _c
 }
 
-func (_c *MockDiskInfoProvider_IOCounters_Call) RunAndReturn(run func() (map[string]disk.IOCountersStat, error)) *MockDiskInfoProvider_IOCounters_Call {
+func (_c *MockDiskInfoProvider_IOCounters_Call) RunAndReturn(run func(ctx context.Context) (map[string]disk.IOCountersStat, error)) *MockDiskInfoProvider_IOCounters_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Partitions provides a mock function for the type MockDiskInfoProvider
-func (_mock *MockDiskInfoProvider) Partitions(all bool) ([]disk.PartitionStat, error) {
-	ret := _mock.Called(all)
+func (_mock *MockDiskInfoProvider) Partitions(ctx context.Context, all bool) ([]disk.PartitionStat, error) {
+	ret := _mock.Called(ctx, all)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Partitions")
@@ -101,18 +110,18 @@ func (_mock *MockDiskInfoProvider) Partitions(all bool) ([]disk.PartitionStat, e
 
 	var r0 []disk.PartitionStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(bool) ([]disk.PartitionStat, error)); ok {
-		return returnFunc(all)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) ([]disk.PartitionStat, error)); ok {
+		return returnFunc(ctx, all)
 	}
-	if returnFunc, ok := ret.Get(0).(func(bool) []disk.PartitionStat); ok {
-		r0 = returnFunc(all)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) []disk.PartitionStat); ok {
+		r0 = returnFunc(ctx, all)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]disk.PartitionStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(bool) error); ok {
-		r1 = returnFunc(all)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = returnFunc(ctx, all)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,19 +134,25 @@ type MockDiskInfoProvider_Partitions_Call struct {
 }
 
 // Partitions is a helper method to define mock.On call
+//   - ctx context.Context
 //   - all bool
-func (_e *MockDiskInfoProvider_Expecter) Partitions(all any) *MockDiskInfoProvider_Partitions_Call {
-	return &MockDiskInfoProvider_Partitions_Call{Call: _e.mock.On("Partitions", all)}
+func (_e *MockDiskInfoProvider_Expecter) Partitions(ctx any, all any) *MockDiskInfoProvider_Partitions_Call {
+	return &MockDiskInfoProvider_Partitions_Call{Call: _e.mock.On("Partitions", ctx, all)}
 }
 
-func (_c *MockDiskInfoProvider_Partitions_Call) Run(run func(all bool)) *MockDiskInfoProvider_Partitions_Call {
+func (_c *MockDiskInfoProvider_Partitions_Call) Run(run func(ctx context.Context, all bool)) *MockDiskInfoProvider_Partitions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 bool
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(bool)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -148,14 +163,14 @@ func (_c *MockDiskInfoProvider_Partitions_Call) Return(partitionStats []disk.Par
 	return _c
 }
 
-func (_c *MockDiskInfoProvider_Partitions_Call) RunAndReturn(run func(all bool) ([]disk.PartitionStat, error)) *MockDiskInfoProvider_Partitions_Call {
+func (_c *MockDiskInfoProvider_Partitions_Call) RunAndReturn(run func(ctx context.Context, all bool) ([]disk.PartitionStat, error)) *MockDiskInfoProvider_Partitions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Usage provides a mock function for the type MockDiskInfoProvider
-func (_mock *MockDiskInfoProvider) Usage(path string) (*disk.UsageStat, error) {
-	ret := _mock.Called(path)
+func (_mock *MockDiskInfoProvider) Usage(ctx context.Context, path string) (*disk.UsageStat, error) {
+	ret := _mock.Called(ctx, path)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Usage")
@@ -163,18 +178,18 @@ func (_mock *MockDiskInfoProvider) Usage(path string) (*disk.UsageStat, error) {
 
 	var r0 *disk.UsageStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*disk.UsageStat, error)); ok {
-		return returnFunc(path)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*disk.UsageStat, error)); ok {
+		return returnFunc(ctx, path)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *disk.UsageStat); ok {
-		r0 = returnFunc(path)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *disk.UsageStat); ok {
+		r0 = returnFunc(ctx, path)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*disk.UsageStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(path)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -187,19 +202,25 @@ type MockDiskInfoProvider_Usage_Call struct {
 }
 
 // Usage is a helper method to define mock.On call
+//   - ctx context.Context
 //   - path string
-func (_e *MockDiskInfoProvider_Expecter) Usage(path any) *MockDiskInfoProvider_Usage_Call {
-	return &MockDiskInfoProvider_Usage_Call{Call: _e.mock.On("Usage", path)}
+func (_e *MockDiskInfoProvider_Expecter) Usage(ctx any, path any) *MockDiskInfoProvider_Usage_Call {
+	return &MockDiskInfoProvider_Usage_Call{Call: _e.mock.On("Usage", ctx, path)}
 }
 
-func (_c *MockDiskInfoProvider_Usage_Call) Run(run func(path string)) *MockDiskInfoProvider_Usage_Call {
+func (_c *MockDiskInfoProvider_Usage_Call) Run(run func(ctx context.Context, path string)) *MockDiskInfoProvider_Usage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -210,7 +231,7 @@ func (_c *MockDiskInfoProvider_Usage_Call) Return(usageStat *disk.UsageStat, err
 	return _c
 }
 
-func (_c *MockDiskInfoProvider_Usage_Call) RunAndReturn(run func(path string) (*disk.UsageStat, error)) *MockDiskInfoProvider_Usage_Call {
+func (_c *MockDiskInfoProvider_Usage_Call) RunAndReturn(run func(ctx context.Context, path string) (*disk.UsageStat, error)) *MockDiskInfoProvider_Usage_Call {
 	_c.Call.Return(run)
 	return _c
 }
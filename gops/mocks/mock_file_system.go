@@ -37,6 +37,68 @@ func (_m *MockFileSystem) EXPECT() *MockFileSystem_Expecter {
 	return &MockFileSystem_Expecter{mock: &_m.Mock}
 }
 
+// Glob provides a mock function for the type MockFileSystem
+func (_mock *MockFileSystem) Glob(pattern string) ([]string, error) {
+	ret := _mock.Called(pattern)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Glob")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return returnFunc(pattern)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = returnFunc(pattern)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(pattern)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFileSystem_Glob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Glob'
+type MockFileSystem_Glob_Call struct {
+	*mock.Call
+}
+
+// Glob is a helper method to define mock.On call
+//   - pattern string
+func (_e *MockFileSystem_Expecter) Glob(pattern any) *MockFileSystem_Glob_Call {
+	return &MockFileSystem_Glob_Call{Call: _e.mock.On("Glob", pattern)}
+}
+
+func (_c *MockFileSystem_Glob_Call) Run(run func(pattern string)) *MockFileSystem_Glob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystem_Glob_Call) Return(strings []string, err error) *MockFileSystem_Glob_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockFileSystem_Glob_Call) RunAndReturn(run func(pattern string) ([]string, error)) *MockFileSystem_Glob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReadDir provides a mock function for the type MockFileSystem
 func (_mock *MockFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
 	ret := _mock.Called(name)
@@ -161,6 +223,66 @@ func (_c *MockFileSystem_ReadFile_Call) RunAndReturn(run func(name string) ([]by
 	return _c
 }
 
+// Readlink provides a mock function for the type MockFileSystem
+func (_mock *MockFileSystem) Readlink(name string) (string, error) {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Readlink")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return returnFunc(name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFileSystem_Readlink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Readlink'
+type MockFileSystem_Readlink_Call struct {
+	*mock.Call
+}
+
+// Readlink is a helper method to define mock.On call
+//   - name string
+func (_e *MockFileSystem_Expecter) Readlink(name any) *MockFileSystem_Readlink_Call {
+	return &MockFileSystem_Readlink_Call{Call: _e.mock.On("Readlink", name)}
+}
+
+func (_c *MockFileSystem_Readlink_Call) Run(run func(name string)) *MockFileSystem_Readlink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystem_Readlink_Call) Return(s string, err error) *MockFileSystem_Readlink_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockFileSystem_Readlink_Call) RunAndReturn(run func(name string) (string, error)) *MockFileSystem_Readlink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Stat provides a mock function for the type MockFileSystem
 func (_mock *MockFileSystem) Stat(name string) (fs.FileInfo, error) {
 	ret := _mock.Called(name)
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/host"
 	"github.com/shirou/gopsutil/v4/sensors"
 	mock "github.com/stretchr/testify/mock"
@@ -38,8 +40,8 @@ func (_m *MockHostInfoProvider) EXPECT() *MockHostInfoProvider_Expecter {
 }
 
 // Info provides a mock function for the type MockHostInfoProvider
-func (_mock *MockHostInfoProvider) Info() (*host.InfoStat, error) {
-	ret := _mock.Called()
+func (_mock *MockHostInfoProvider) Info(ctx context.Context) (*host.InfoStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Info")
@@ -47,18 +49,18 @@ func (_mock *MockHostInfoProvider) Info() (*host.InfoStat, error) {
 
 	var r0 *host.InfoStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*host.InfoStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*host.InfoStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *host.InfoStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *host.InfoStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*host.InfoStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,13 +73,20 @@ type MockHostInfoProvider_Info_Call struct {
 }
 
 // Info is a helper method to define mock.On call
-func (_e *MockHostInfoProvider_Expecter) Info() *MockHostInfoProvider_Info_Call {
-	return &MockHostInfoProvider_Info_Call{Call: _e.mock.On("Info")}
+//   - ctx context.Context
+func (_e *MockHostInfoProvider_Expecter) Info(ctx any) *MockHostInfoProvider_Info_Call {
+	return &MockHostInfoProvider_Info_Call{Call: _e.mock.On("Info", ctx)}
 }
 
-func (_c *MockHostInfoProvider_Info_Call) Run(run func()) *MockHostInfoProvider_Info_Call {
+func (_c *MockHostInfoProvider_Info_Call) Run(run func(ctx context.Context)) *MockHostInfoProvider_Info_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -87,14 +96,14 @@ func (_c *MockHostInfoProvider_Info_Call) Return(infoStat *host.InfoStat, err er
 	return _c
 }
 
-func (_c *MockHostInfoProvider_Info_Call) RunAndReturn(run func() (*host.InfoStat, error)) *MockHostInfoProvider_Info_Call {
+func (_c *MockHostInfoProvider_Info_Call) RunAndReturn(run func(ctx context.Context) (*host.InfoStat, error)) *MockHostInfoProvider_Info_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SensorsTemperatures provides a mock function for the type MockHostInfoProvider
-func (_mock *MockHostInfoProvider) SensorsTemperatures() ([]sensors.TemperatureStat, error) {
-	ret := _mock.Called()
+func (_mock *MockHostInfoProvider) SensorsTemperatures(ctx context.Context) ([]sensors.TemperatureStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SensorsTemperatures")
@@ -102,18 +111,18 @@ func (_mock *MockHostInfoProvider) SensorsTemperatures() ([]sensors.TemperatureS
 
 	var r0 []sensors.TemperatureStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]sensors.TemperatureStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]sensors.TemperatureStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() []sensors.TemperatureStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []sensors.TemperatureStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]sensors.TemperatureStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -126,13 +135,20 @@ type MockHostInfoProvider_SensorsTemperatures_Call struct {
 }
 
 // SensorsTemperatures is a helper method to define mock.On call
-func (_e *MockHostInfoProvider_Expecter) SensorsTemperatures() *MockHostInfoProvider_SensorsTemperatures_Call {
-	return &MockHostInfoProvider_SensorsTemperatures_Call{Call: _e.mock.On("SensorsTemperatures")}
+//   - ctx context.Context
+func (_e *MockHostInfoProvider_Expecter) SensorsTemperatures(ctx any) *MockHostInfoProvider_SensorsTemperatures_Call {
+	return &MockHostInfoProvider_SensorsTemperatures_Call{Call: _e.mock.On("SensorsTemperatures", ctx)}
 }
 
-func (_c *MockHostInfoProvider_SensorsTemperatures_Call) Run(run func()) *MockHostInfoProvider_SensorsTemperatures_Call {
+func (_c *MockHostInfoProvider_SensorsTemperatures_Call) Run(run func(ctx context.Context)) *MockHostInfoProvider_SensorsTemperatures_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -142,7 +158,7 @@ func (_c *MockHostInfoProvider_SensorsTemperatures_Call) Return(temperatureStats
 	return _c
 }
 
-func (_c *MockHostInfoProvider_SensorsTemperatures_Call) RunAndReturn(run func() ([]sensors.TemperatureStat, error)) *MockHostInfoProvider_SensorsTemperatures_Call {
+func (_c *MockHostInfoProvider_SensorsTemperatures_Call) RunAndReturn(run func(ctx context.Context) ([]sensors.TemperatureStat, error)) *MockHostInfoProvider_SensorsTemperatures_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/load"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockLoadInfoProvider) EXPECT() *MockLoadInfoProvider_Expecter {
 }
 
 // Avg provides a mock function for the type MockLoadInfoProvider
-func (_mock *MockLoadInfoProvider) Avg() (*load.AvgStat, error) {
-	ret := _mock.Called()
+func (_mock *MockLoadInfoProvider) Avg(ctx context.Context) (*load.AvgStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Avg")
@@ -46,18 +48,18 @@ func (_mock *MockLoadInfoProvider) Avg() (*load.AvgStat, error) {
 
 	var r0 *load.AvgStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*load.AvgStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*load.AvgStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *load.AvgStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *load.AvgStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*load.AvgStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,13 +72,20 @@ type MockLoadInfoProvider_Avg_Call struct {
 }
 
 // Avg is a helper method to define mock.On call
-func (_e *MockLoadInfoProvider_Expecter) Avg() *MockLoadInfoProvider_Avg_Call {
-	return &MockLoadInfoProvider_Avg_Call{Call: _e.mock.On("Avg")}
+//   - ctx context.Context
+func (_e *MockLoadInfoProvider_Expecter) Avg(ctx any) *MockLoadInfoProvider_Avg_Call {
+	return &MockLoadInfoProvider_Avg_Call{Call: _e.mock.On("Avg", ctx)}
 }
 
-func (_c *MockLoadInfoProvider_Avg_Call) Run(run func()) *MockLoadInfoProvider_Avg_Call {
+func (_c *MockLoadInfoProvider_Avg_Call) Run(run func(ctx context.Context)) *MockLoadInfoProvider_Avg_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -86,14 +95,14 @@ func (_c *MockLoadInfoProvider_Avg_Call) Return(avgStat *load.AvgStat, err error
 	return _c
 }
 
-func (_c *MockLoadInfoProvider_Avg_Call) RunAndReturn(run func() (*load.AvgStat, error)) *MockLoadInfoProvider_Avg_Call {
+func (_c *MockLoadInfoProvider_Avg_Call) RunAndReturn(run func(ctx context.Context) (*load.AvgStat, error)) *MockLoadInfoProvider_Avg_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Misc provides a mock function for the type MockLoadInfoProvider
-func (_mock *MockLoadInfoProvider) Misc() (*load.MiscStat, error) {
-	ret := _mock.Called()
+func (_mock *MockLoadInfoProvider) Misc(ctx context.Context) (*load.MiscStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Misc")
@@ -101,18 +110,18 @@ func (_mock *MockLoadInfoProvider) Misc() (*load.MiscStat, error) {
 
 	var r0 *load.MiscStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*load.MiscStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*load.MiscStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *load.MiscStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *load.MiscStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*load.MiscStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,13 +134,20 @@ type MockLoadInfoProvider_Misc_Call struct {
 }
 
 // Misc is a helper method to define mock.On call
-func (_e *MockLoadInfoProvider_Expecter) Misc() *MockLoadInfoProvider_Misc_Call {
-	return &MockLoadInfoProvider_Misc_Call{Call: _e.mock.On("Misc")}
+//   - ctx context.Context
+func (_e *MockLoadInfoProvider_Expecter) Misc(ctx any) *MockLoadInfoProvider_Misc_Call {
+	return &MockLoadInfoProvider_Misc_Call{Call: _e.mock.On("Misc", ctx)}
 }
 
-func (_c *MockLoadInfoProvider_Misc_Call) Run(run func()) *MockLoadInfoProvider_Misc_Call {
+func (_c *MockLoadInfoProvider_Misc_Call) Run(run func(ctx context.Context)) *MockLoadInfoProvider_Misc_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -141,7 +157,7 @@ func (_c *MockLoadInfoProvider_Misc_Call) Return(miscStat *load.MiscStat, err er
 	return _c
 }
 
-func (_c *MockLoadInfoProvider_Misc_Call) RunAndReturn(run func() (*load.MiscStat, error)) *MockLoadInfoProvider_Misc_Call {
+func (_c *MockLoadInfoProvider_Misc_Call) RunAndReturn(run func(ctx context.Context) (*load.MiscStat, error)) *MockLoadInfoProvider_Misc_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/mem"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockMemoryInfoProvider) EXPECT() *MockMemoryInfoProvider_Expecter {
 }
 
 // SwapMemory provides a mock function for the type MockMemoryInfoProvider
-func (_mock *MockMemoryInfoProvider) SwapMemory() (*mem.SwapMemoryStat, error) {
-	ret := _mock.Called()
+func (_mock *MockMemoryInfoProvider) SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SwapMemory")
@@ -46,18 +48,18 @@ func (_mock *MockMemoryInfoProvider) SwapMemory() (*mem.SwapMemoryStat, error) {
 
 	var r0 *mem.SwapMemoryStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*mem.SwapMemoryStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*mem.SwapMemoryStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *mem.SwapMemoryStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *mem.SwapMemoryStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*mem.SwapMemoryStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,13 +72,20 @@ type MockMemoryInfoProvider_SwapMemory_Call struct {
 }
 
 // SwapMemory is a helper method to define mock.On call
-func (_e *MockMemoryInfoProvider_Expecter) SwapMemory() *MockMemoryInfoProvider_SwapMemory_Call {
-	return &MockMemoryInfoProvider_SwapMemory_Call{Call: _e.mock.On("SwapMemory")}
+//   - ctx context.Context
+func (_e *MockMemoryInfoProvider_Expecter) SwapMemory(ctx any) *MockMemoryInfoProvider_SwapMemory_Call {
+	return &MockMemoryInfoProvider_SwapMemory_Call{Call: _e.mock.On("SwapMemory", ctx)}
 }
 
-func (_c *MockMemoryInfoProvider_SwapMemory_Call) Run(run func()) *MockMemoryInfoProvider_SwapMemory_Call {
+func (_c *MockMemoryInfoProvider_SwapMemory_Call) Run(run func(ctx context.Context)) *MockMemoryInfoProvider_SwapMemory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -86,14 +95,14 @@ func (_c *MockMemoryInfoProvider_SwapMemory_Call) Return(swapMemoryStat *mem.Swa
 	return _c
 }
 
-func (_c *MockMemoryInfoProvider_SwapMemory_Call) RunAndReturn(run func() (*mem.SwapMemoryStat, error)) *MockMemoryInfoProvider_SwapMemory_Call {
+func (_c *MockMemoryInfoProvider_SwapMemory_Call) RunAndReturn(run func(ctx context.Context) (*mem.SwapMemoryStat, error)) *MockMemoryInfoProvider_SwapMemory_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // VirtualMemory provides a mock function for the type MockMemoryInfoProvider
-func (_mock *MockMemoryInfoProvider) VirtualMemory() (*mem.VirtualMemoryStat, error) {
-	ret := _mock.Called()
+func (_mock *MockMemoryInfoProvider) VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VirtualMemory")
@@ -101,18 +110,18 @@ func (_mock *MockMemoryInfoProvider) VirtualMemory() (*mem.VirtualMemoryStat, er
 
 	var r0 *mem.VirtualMemoryStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*mem.VirtualMemoryStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*mem.VirtualMemoryStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *mem.VirtualMemoryStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *mem.VirtualMemoryStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*mem.VirtualMemoryStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,13 +134,20 @@ type MockMemoryInfoProvider_VirtualMemory_Call struct {
 }
 
 // VirtualMemory is a helper method to define mock.On call
-func (_e *MockMemoryInfoProvider_Expecter) VirtualMemory() *MockMemoryInfoProvider_VirtualMemory_Call {
-	return &MockMemoryInfoProvider_VirtualMemory_Call{Call: _e.mock.On("VirtualMemory")}
+//   - ctx context.Context
+func (_e *MockMemoryInfoProvider_Expecter) VirtualMemory(ctx any) *MockMemoryInfoProvider_VirtualMemory_Call {
+	return &MockMemoryInfoProvider_VirtualMemory_Call{Call: _e.mock.On("VirtualMemory", ctx)}
 }
 
-func (_c *MockMemoryInfoProvider_VirtualMemory_Call) Run(run func()) *MockMemoryInfoProvider_VirtualMemory_Call {
+func (_c *MockMemoryInfoProvider_VirtualMemory_Call) Run(run func(ctx context.Context)) *MockMemoryInfoProvider_VirtualMemory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -141,7 +157,7 @@ func (_c *MockMemoryInfoProvider_VirtualMemory_Call) Return(virtualMemoryStat *m
 	return _c
 }
 
-func (_c *MockMemoryInfoProvider_VirtualMemory_Call) RunAndReturn(run func() (*mem.VirtualMemoryStat, error)) *MockMemoryInfoProvider_VirtualMemory_Call {
+func (_c *MockMemoryInfoProvider_VirtualMemory_Call) RunAndReturn(run func(ctx context.Context) (*mem.VirtualMemoryStat, error)) *MockMemoryInfoProvider_VirtualMemory_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/net"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockNetworkInfoProvider) EXPECT() *MockNetworkInfoProvider_Expecter {
 }
 
 // IOCounters provides a mock function for the type MockNetworkInfoProvider
-func (_mock *MockNetworkInfoProvider) IOCounters(pernic bool) ([]net.IOCountersStat, error) {
-	ret := _mock.Called(pernic)
+func (_mock *MockNetworkInfoProvider) IOCounters(ctx context.Context, pernic bool) ([]net.IOCountersStat, error) {
+	ret := _mock.Called(ctx, pernic)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IOCounters")
@@ -46,18 +48,18 @@ func (_mock *MockNetworkInfoProvider) IOCounters(pernic bool) ([]net.IOCountersS
 
 	var r0 []net.IOCountersStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(bool) ([]net.IOCountersStat, error)); ok {
-		return returnFunc(pernic)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) ([]net.IOCountersStat, error)); ok {
+		return returnFunc(ctx, pernic)
 	}
-	if returnFunc, ok := ret.Get(0).(func(bool) []net.IOCountersStat); ok {
-		r0 = returnFunc(pernic)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) []net.IOCountersStat); ok {
+		r0 = returnFunc(ctx, pernic)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]net.IOCountersStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(bool) error); ok {
-		r1 = returnFunc(pernic)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = returnFunc(ctx, pernic)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,19 +72,25 @@ type MockNetworkInfoProvider_IOCounters_Call struct {
 }
 
 // IOCounters is a helper method to define mock.On call
+//   - ctx context.Context
 //   - pernic bool
-func (_e *MockNetworkInfoProvider_Expecter) IOCounters(pernic any) *MockNetworkInfoProvider_IOCounters_Call {
-	return &MockNetworkInfoProvider_IOCounters_Call{Call: _e.mock.On("IOCounters", pernic)}
+func (_e *MockNetworkInfoProvider_Expecter) IOCounters(ctx any, pernic any) *MockNetworkInfoProvider_IOCounters_Call {
+	return &MockNetworkInfoProvider_IOCounters_Call{Call: _e.mock.On("IOCounters", ctx, pernic)}
 }
 
-func (_c *MockNetworkInfoProvider_IOCounters_Call) Run(run func(pernic bool)) *MockNetworkInfoProvider_IOCounters_Call {
+func (_c *MockNetworkInfoProvider_IOCounters_Call) Run(run func(ctx context.Context, pernic bool)) *MockNetworkInfoProvider_IOCounters_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 bool
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(bool)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -93,14 +101,14 @@ func (_c *MockNetworkInfoProvider_IOCounters_Call) Return(iOCountersStats []net.
 	return _c
 }
 
-func (_c *MockNetworkInfoProvider_IOCounters_Call) RunAndReturn(run func(pernic bool) ([]net.IOCountersStat, error)) *MockNetworkInfoProvider_IOCounters_Call {
+func (_c *MockNetworkInfoProvider_IOCounters_Call) RunAndReturn(run func(ctx context.Context, pernic bool) ([]net.IOCountersStat, error)) *MockNetworkInfoProvider_IOCounters_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Interfaces provides a mock function for the type MockNetworkInfoProvider
-func (_mock *MockNetworkInfoProvider) Interfaces() ([]net.InterfaceStat, error) {
-	ret := _mock.Called()
+func (_mock *MockNetworkInfoProvider) Interfaces(ctx context.Context) ([]net.InterfaceStat, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Interfaces")
@@ -108,18 +116,18 @@ func (_mock *MockNetworkInfoProvider) Interfaces() ([]net.InterfaceStat, error)
 
 	var r0 []net.InterfaceStat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]net.InterfaceStat, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]net.InterfaceStat, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() []net.InterfaceStat); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []net.InterfaceStat); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]net.InterfaceStat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -132,13 +140,20 @@ type MockNetworkInfoProvider_Interfaces_Call struct {
 }
 
 // Interfaces is a helper method to define mock.On call
-func (_e *MockNetworkInfoProvider_Expecter) Interfaces() *MockNetworkInfoProvider_Interfaces_Call {
-	return &MockNetworkInfoProvider_Interfaces_Call{Call: _e.mock.On("Interfaces")}
+//   - ctx context.Context
+func (_e *MockNetworkInfoProvider_Expecter) Interfaces(ctx any) *MockNetworkInfoProvider_Interfaces_Call {
+	return &MockNetworkInfoProvider_Interfaces_Call{Call: _e.mock.On("Interfaces", ctx)}
 }
 
-func (_c *MockNetworkInfoProvider_Interfaces_Call) Run(run func()) *MockNetworkInfoProvider_Interfaces_Call {
+func (_c *MockNetworkInfoProvider_Interfaces_Call) Run(run func(ctx context.Context)) *MockNetworkInfoProvider_Interfaces_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -148,7 +163,7 @@ func (_c *MockNetworkInfoProvider_Interfaces_Call) Return(interfaceStats []net.I
 	return _c
 }
 
-func (_c *MockNetworkInfoProvider_Interfaces_Call) RunAndReturn(run func() ([]net.InterfaceStat, error)) *MockNetworkInfoProvider_Interfaces_Call {
+func (_c *MockNetworkInfoProvider_Interfaces_Call) RunAndReturn(run func(ctx context.Context) ([]net.InterfaceStat, error)) *MockNetworkInfoProvider_Interfaces_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/v4/process"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockProcessInfoProvider) EXPECT() *MockProcessInfoProvider_Expecter {
 }
 
 // NewProcess provides a mock function for the type MockProcessInfoProvider
-func (_mock *MockProcessInfoProvider) NewProcess(pid int32) (*process.Process, error) {
-	ret := _mock.Called(pid)
+func (_mock *MockProcessInfoProvider) NewProcess(ctx context.Context, pid int32) (*process.Process, error) {
+	ret := _mock.Called(ctx, pid)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewProcess")
@@ -46,18 +48,18 @@ func (_mock *MockProcessInfoProvider) NewProcess(pid int32) (*process.Process, e
 
 	var r0 *process.Process
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(int32) (*process.Process, error)); ok {
-		return returnFunc(pid)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int32) (*process.Process, error)); ok {
+		return returnFunc(ctx, pid)
 	}
-	if returnFunc, ok := ret.Get(0).(func(int32) *process.Process); ok {
-		r0 = returnFunc(pid)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int32) *process.Process); ok {
+		r0 = returnFunc(ctx, pid)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*process.Process)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(int32) error); ok {
-		r1 = returnFunc(pid)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = returnFunc(ctx, pid)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,19 +72,25 @@ type MockProcessInfoProvider_NewProcess_Call struct {
 }
 
 // NewProcess is a helper method to define mock.On call
+//   - ctx context.Context
 //   - pid int32
-func (_e *MockProcessInfoProvider_Expecter) NewProcess(pid any) *MockProcessInfoProvider_NewProcess_Call {
-	return &MockProcessInfoProvider_NewProcess_Call{Call: _e.mock.On("NewProcess", pid)}
+func (_e *MockProcessInfoProvider_Expecter) NewProcess(ctx any, pid any) *MockProcessInfoProvider_NewProcess_Call {
+	return &MockProcessInfoProvider_NewProcess_Call{Call: _e.mock.On("NewProcess", ctx, pid)}
 }
 
-func (_c *MockProcessInfoProvider_NewProcess_Call) Run(run func(pid int32)) *MockProcessInfoProvider_NewProcess_Call {
+func (_c *MockProcessInfoProvider_NewProcess_Call) Run(run func(ctx context.Context, pid int32)) *MockProcessInfoProvider_NewProcess_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 int32
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(int32)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int32
+		if args[1] != nil {
+			arg1 = args[1].(int32)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -93,14 +101,14 @@ func (_c *MockProcessInfoProvider_NewProcess_Call) Return(process1 *process.Proc
 	return _c
 }
 
-func (_c *MockProcessInfoProvider_NewProcess_Call) RunAndReturn(run func(pid int32) (*process.Process, error)) *MockProcessInfoProvider_NewProcess_Call {
+func (_c *MockProcessInfoProvider_NewProcess_Call) RunAndReturn(run func(ctx context.Context, pid int32) (*process.Process, error)) *MockProcessInfoProvider_NewProcess_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Processes provides a mock function for the type MockProcessInfoProvider
-func (_mock *MockProcessInfoProvider) Processes() ([]*process.Process, error) {
-	ret := _mock.Called()
+func (_mock *MockProcessInfoProvider) Processes(ctx context.Context) ([]*process.Process, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Processes")
@@ -108,18 +116,18 @@ func (_mock *MockProcessInfoProvider) Processes() ([]*process.Process, error) {
 
 	var r0 []*process.Process
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]*process.Process, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*process.Process, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func() []*process.Process); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*process.Process); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*process.Process)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -132,13 +140,20 @@ type MockProcessInfoProvider_Processes_Call struct {
 }
 
 // Processes is a helper method to define mock.On call
-func (_e *MockProcessInfoProvider_Expecter) Processes() *MockProcessInfoProvider_Processes_Call {
-	return &MockProcessInfoProvider_Processes_Call{Call: _e.mock.On("Processes")}
+//   - ctx context.Context
+func (_e *MockProcessInfoProvider_Expecter) Processes(ctx any) *MockProcessInfoProvider_Processes_Call {
+	return &MockProcessInfoProvider_Processes_Call{Call: _e.mock.On("Processes", ctx)}
 }
 
-func (_c *MockProcessInfoProvider_Processes_Call) Run(run func()) *MockProcessInfoProvider_Processes_Call {
+func (_c *MockProcessInfoProvider_Processes_Call) Run(run func(ctx context.Context)) *MockProcessInfoProvider_Processes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
@@ -148,7 +163,7 @@ func (_c *MockProcessInfoProvider_Processes_Call) Return(processs []*process.Pro
 	return _c
 }
 
-func (_c *MockProcessInfoProvider_Processes_Call) RunAndReturn(run func() ([]*process.Process, error)) *MockProcessInfoProvider_Processes_Call {
+func (_c *MockProcessInfoProvider_Processes_Call) RunAndReturn(run func(ctx context.Context) ([]*process.Process, error)) *MockProcessInfoProvider_Processes_Call {
 	_c.Call.Return(run)
 	return _c
 }
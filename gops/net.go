@@ -1,20 +1,30 @@
 package gops
 
-import "github.com/AvengeMedia/dgop/models"
+import (
+	"context"
+	"time"
 
-func (self *GopsUtil) GetNetworkInfo() ([]*models.NetworkInfo, error) {
-	netIO, err := self.netProvider.IOCounters(true)
+	"github.com/AvengeMedia/dgop/models"
+)
+
+func (self *GopsUtil) GetNetworkInfo(ctx context.Context) ([]*models.NetworkInfo, error) {
+	return cachedModule(ctx, self, "network", "", time.Time{}, self.collectNetworkInfo)
+}
+
+func (self *GopsUtil) collectNetworkInfo(ctx context.Context) ([]*models.NetworkInfo, error) {
+	netIO, err := self.netProvider.IOCounters(ctx, true)
+	if err != nil {
+		return nil, err
+	}
 	res := make([]*models.NetworkInfo, 0)
-	if err == nil {
-		for _, n := range netIO {
-			// Filter to match bash script (wlan, wlo, wlp, eth, eno, enp, ens, lxc)
-			if matchesNetworkInterface(n.Name) {
-				res = append(res, &models.NetworkInfo{
-					Name: n.Name,
-					Rx:   n.BytesRecv,
-					Tx:   n.BytesSent,
-				})
-			}
+	for _, n := range netIO {
+		// Filter to match bash script (wlan, wlo, wlp, eth, eno, enp, ens, lxc)
+		if self.includeInterface(n.Name) {
+			res = append(res, &models.NetworkInfo{
+				Name: n.Name,
+				Rx:   n.BytesRecv,
+				Tx:   n.BytesSent,
+			})
 		}
 	}
 	return res, nil
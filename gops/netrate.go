@@ -1,6 +1,7 @@
 package gops
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"time"
@@ -14,16 +15,27 @@ type NetworkRateCursor struct {
 	IOStats   map[string]net.IOCountersStat `json:"iostats"`
 }
 
-func (self *GopsUtil) GetNetworkRates(cursorStr string) (*models.NetworkRateResponse, error) {
+func (self *GopsUtil) GetNetworkRates(ctx context.Context, cursorStr string) (*models.NetworkRateResponse, error) {
+	var cursor NetworkRateCursor
+	if cursorStr != "" {
+		payload, err := self.openCursor("net-rate", cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		if cursor, err = parseNetworkRateCursor(payload); err != nil {
+			return nil, &CursorError{Module: "net-rate", Err: ErrCursorMalformed}
+		}
+	}
+
 	// Get current network stats
-	netIO, err := net.IOCounters(true)
+	netIO, err := self.netProvider.IOCounters(ctx, true)
 	if err != nil {
 		return nil, err
 	}
 
 	currentStats := make(map[string]net.IOCountersStat)
 	for _, n := range netIO {
-		if matchesNetworkInterface(n.Name) {
+		if self.includeInterface(n.Name) {
 			currentStats[n.Name] = n
 		}
 	}
@@ -33,23 +45,20 @@ func (self *GopsUtil) GetNetworkRates(cursorStr string) (*models.NetworkRateResp
 
 	// If we have a cursor, calculate rates
 	if cursorStr != "" {
-		cursor, err := parseNetworkRateCursor(cursorStr)
-		if err == nil {
-			timeDiff := currentTime.Sub(cursor.Timestamp).Seconds()
-			if timeDiff > 0 {
-				for name, current := range currentStats {
-					if prev, exists := cursor.IOStats[name]; exists {
-						rxRate := float64(current.BytesRecv-prev.BytesRecv) / timeDiff
-						txRate := float64(current.BytesSent-prev.BytesSent) / timeDiff
-
-						interfaces = append(interfaces, &models.NetworkRateInfo{
-							Interface: name,
-							RxRate:    rxRate,
-							TxRate:    txRate,
-							RxTotal:   current.BytesRecv,
-							TxTotal:   current.BytesSent,
-						})
-					}
+		timeDiff := currentTime.Sub(cursor.Timestamp).Seconds()
+		if timeDiff > 0 {
+			for name, current := range currentStats {
+				if prev, exists := cursor.IOStats[name]; exists {
+					rxRate := float64(current.BytesRecv-prev.BytesRecv) / timeDiff
+					txRate := float64(current.BytesSent-prev.BytesSent) / timeDiff
+
+					interfaces = append(interfaces, &models.NetworkRateInfo{
+						Interface: name,
+						RxRate:    rxRate,
+						TxRate:    txRate,
+						RxTotal:   current.BytesRecv,
+						TxTotal:   current.BytesSent,
+					})
 				}
 			}
 		}
@@ -81,7 +90,7 @@ func (self *GopsUtil) GetNetworkRates(cursorStr string) (*models.NetworkRateResp
 
 	return &models.NetworkRateResponse{
 		Interfaces: interfaces,
-		Cursor:     newCursorStr,
+		Cursor:     self.sealCursor("net-rate", newCursorStr),
 	}, nil
 }
 
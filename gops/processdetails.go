@@ -0,0 +1,19 @@
+package gops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// GetProcessDetails reads everything dgop knows about one process. CPU
+// usage, the process's and each thread's, is measured over a short pause.
+// The environment can hold secrets, so it is only read with includeEnv. A
+// PID that doesn't exist gives an error matching fs.ErrNotExist.
+func (self *GopsUtil) GetProcessDetails(ctx context.Context, pid int32, includeEnv bool) (*models.ProcessDetails, error) {
+	if pid <= 0 {
+		return nil, fmt.Errorf("invalid pid %d", pid)
+	}
+	return self.readProcessDetails(ctx, pid, includeEnv)
+}
@@ -0,0 +1,103 @@
+//go:build freebsd
+
+package gops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// processStateLetters maps gopsutil's status names to the letters ps uses.
+var processStateLetters = map[string]string{
+	process.Running: "R",
+	process.Sleep:   "S",
+	process.Stop:    "T",
+	process.Idle:    "I",
+	process.Zombie:  "Z",
+	process.Wait:    "W",
+	process.Lock:    "L",
+}
+
+func (self *GopsUtil) readProcessDetails(ctx context.Context, pid int32, includeEnv bool) (d *models.ProcessDetails, err error) {
+	p, err := self.procProvider.NewProcess(ctx, pid)
+	if errors.Is(err, process.ErrorProcessNotRunning) {
+		return nil, fmt.Errorf("pid %d: %w", pid, fs.ErrNotExist)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// gopsutil can panic reading system processes or ones that exit
+	// mid-read.
+	defer func() {
+		if r := recover(); r != nil {
+			d, err = nil, fmt.Errorf("pid %d: %v", pid, r)
+		}
+	}()
+
+	ctx = self.env.context(ctx)
+	start := time.Now()
+	before, _ := p.TimesWithContext(ctx)
+
+	d = &models.ProcessDetails{PID: pid}
+	d.PPID, _ = p.PpidWithContext(ctx)
+	d.Command, _ = p.NameWithContext(ctx)
+	d.FullCommand, _ = p.CmdlineWithContext(ctx)
+	d.ExecutablePath, _ = p.ExeWithContext(ctx)
+	d.Username, _ = p.UsernameWithContext(ctx)
+	d.Cwd, _ = p.CwdWithContext(ctx)
+	d.Nice, _ = p.NiceWithContext(ctx)
+	if created, err := p.CreateTimeWithContext(ctx); err == nil && created > 0 {
+		startTime := time.UnixMilli(created)
+		d.StartTime = startTime.Format("2006-01-02 15:04:05")
+		d.AgeSeconds = time.Since(startTime).Seconds()
+	}
+	if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+		d.MemoryKB = mem.RSS / 1024
+	}
+	if n, err := p.NumFDsWithContext(ctx); err == nil {
+		d.NumFDs = int(n)
+	}
+	if ctxt, err := p.NumCtxSwitchesWithContext(ctx); err == nil {
+		d.VoluntaryCtxSwitches = uint64(ctxt.Voluntary)
+		d.InvoluntaryCtxSwitches = uint64(ctxt.Involuntary)
+	}
+	if io, err := p.IOCountersWithContext(ctx); err == nil {
+		d.IO = &models.ProcessIO{
+			ReadSyscalls:  io.ReadCount,
+			WriteSyscalls: io.WriteCount,
+			ReadBytes:     io.ReadBytes,
+			WriteBytes:    io.WriteBytes,
+		}
+	}
+	if includeEnv {
+		d.Environ, _ = p.EnvironWithContext(ctx)
+	}
+
+	select {
+	case <-time.After(cpuBaselineInterval):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if status, err := p.StatusWithContext(ctx); err == nil && len(status) > 0 {
+		d.State = processStateLetters[status[0]]
+	}
+	after, err := p.TimesWithContext(ctx)
+	if err == nil {
+		d.PTicks = after.User + after.System
+		if before != nil {
+			elapsed := time.Since(start).Seconds()
+			used := d.PTicks - (before.User + before.System)
+			if elapsed > 0 && used > 0 {
+				d.CPU = min(used/elapsed/float64(runtime.NumCPU())*100, 100)
+			}
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,266 @@
+//go:build linux
+
+package gops
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// detailsSample is the CPU time of a process and its threads at one moment.
+type detailsSample struct {
+	at      time.Time
+	stat    pidStat
+	threads map[int32]pidStat
+}
+
+func (self *GopsUtil) readDetailsSample(dir string) (detailsSample, error) {
+	sample := detailsSample{at: time.Now()}
+	data, err := self.fs.ReadFile(dir + "/stat")
+	if err != nil {
+		return sample, err
+	}
+	if sample.stat, err = parsePIDStat(data); err != nil {
+		return sample, err
+	}
+	sample.threads, _ = self.readTaskStats(dir)
+	return sample, nil
+}
+
+func (self *GopsUtil) readProcessDetails(ctx context.Context, pid int32, includeEnv bool) (*models.ProcessDetails, error) {
+	dir := "/proc/" + strconv.Itoa(int(pid))
+	first, err := self.readDetailsSample(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &models.ProcessDetails{
+		PID:      pid,
+		PPID:     first.stat.ppid,
+		Command:  first.stat.comm,
+		State:    string(first.stat.state),
+		Nice:     first.stat.nice,
+		Priority: first.stat.priority,
+	}
+
+	cmdline, _ := self.fs.ReadFile(dir + "/cmdline")
+	d.FullCommand = joinCmdline(cmdline)
+	if data, err := self.fs.ReadFile(dir + "/status"); err == nil {
+		status := parsePIDStatus(data)
+		d.Command = procName(status.name, cmdline)
+		if status.hasUID {
+			d.Username = lookupUsername(status.uid)
+		}
+		d.VoluntaryCtxSwitches = status.voluntaryCtxt
+		d.InvoluntaryCtxSwitches = status.nonvoluntaryCtxt
+	}
+	d.ExecutablePath, _ = self.fs.Readlink(dir + "/exe")
+	d.Cwd, _ = self.fs.Readlink(dir + "/cwd")
+
+	if bootTime, err := host.BootTimeWithContext(self.env.context(ctx)); err == nil && bootTime > 0 {
+		start := time.Unix(int64(bootTime), 0).Add(time.Duration(first.stat.starttime) * time.Second / userHZ)
+		d.StartTime = start.Format("2006-01-02 15:04:05")
+		d.AgeSeconds = time.Since(start).Seconds()
+	}
+	if data, err := self.fs.ReadFile(dir + "/statm"); err == nil {
+		if statm, err := parsePIDStatm(data); err == nil {
+			d.MemoryKB = statm.resident * uint64(os.Getpagesize()) / 1024
+		}
+	}
+
+	d.FDs = self.readProcFDs(dir)
+	d.NumFDs = len(d.FDs)
+	if includeEnv {
+		if data, err := self.fs.ReadFile(dir + "/environ"); err == nil {
+			for _, kv := range bytes.Split(bytes.TrimRight(data, "\x00"), []byte{0}) {
+				if len(kv) > 0 {
+					d.Environ = append(d.Environ, string(kv))
+				}
+			}
+		}
+	}
+	if data, err := self.fs.ReadFile(dir + "/limits"); err == nil {
+		d.Limits = parseProcLimits(data)
+	}
+	if entries, err := self.fs.ReadDir(dir + "/ns"); err == nil {
+		d.Namespaces = make(map[string]string, len(entries))
+		for _, e := range entries {
+			if target, err := self.fs.Readlink(dir + "/ns/" + e.Name()); err == nil {
+				d.Namespaces[e.Name()] = target
+			}
+		}
+	}
+	if data, err := self.fs.ReadFile(dir + "/cgroup"); err == nil {
+		d.CGroup = cgroupPath(data)
+	}
+	d.OOMScore = self.readProcInt(dir + "/oom_score")
+	d.OOMScoreAdj = self.readProcInt(dir + "/oom_score_adj")
+	if data, err := self.fs.ReadFile(dir + "/io"); err == nil {
+		io := parseProcIO(data)
+		d.IO = &io
+	}
+	if data, err := self.fs.ReadFile(dir + "/smaps_rollup"); err == nil {
+		if rollup, ok := parseSmapsRollup(data); ok {
+			d.Memory = &rollup
+		}
+	}
+
+	select {
+	case <-time.After(cpuBaselineInterval):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	second, err := self.readDetailsSample(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := second.at.Sub(first.at).Seconds()
+	numCPU := float64(runtime.NumCPU())
+	usage := func(before, after pidStat) float64 {
+		ticks := float64(after.utime+after.stime) - float64(before.utime+before.stime)
+		if elapsed <= 0 || ticks <= 0 {
+			return 0
+		}
+		return min(ticks/userHZ/elapsed/numCPU*100, 100)
+	}
+	d.PTicks = float64(second.stat.utime+second.stat.stime) / userHZ
+	d.CPU = usage(first.stat, second.stat)
+	d.State = string(second.stat.state)
+
+	d.Threads = make([]*models.ThreadInfo, 0, len(second.threads))
+	for _, tid := range slices.Sorted(maps.Keys(second.threads)) {
+		stat := second.threads[tid]
+		thread := &models.ThreadInfo{
+			PID:     pid,
+			TID:     tid,
+			Name:    stat.comm,
+			State:   string(stat.state),
+			PTicks:  float64(stat.utime+stat.stime) / userHZ,
+			LastCPU: stat.processor,
+		}
+		if before, ok := first.threads[tid]; ok {
+			thread.CPU = usage(before, stat)
+		}
+		d.Threads = append(d.Threads, thread)
+	}
+	return d, nil
+}
+
+// readProcFDs lists the open file descriptors under dir/fd in numeric
+// order, with what each one refers to.
+func (self *GopsUtil) readProcFDs(dir string) []*models.FDInfo {
+	entries, err := self.fs.ReadDir(dir + "/fd")
+	if err != nil {
+		return nil
+	}
+	fds := make([]*models.FDInfo, 0, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		target, _ := self.fs.Readlink(path.Join(dir, "fd", e.Name()))
+		fds = append(fds, &models.FDInfo{FD: fd, Target: target})
+	}
+	slices.SortFunc(fds, func(a, b *models.FDInfo) int { return a.FD - b.FD })
+	return fds
+}
+
+func (self *GopsUtil) readProcInt(name string) int {
+	data, err := self.fs.ReadFile(name)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return n
+}
+
+// cgroupPath returns the process's path in the unified hierarchy from
+// /proc/<pid>/cgroup, or on a v1-only system its systemd path.
+func cgroupPath(data []byte) string {
+	var systemd string
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			return parts[2]
+		case parts[1] == "name=systemd":
+			systemd = parts[2]
+		}
+	}
+	return systemd
+}
+
+// parseProcLimits parses /proc/<pid>/limits, whose columns are aligned
+// with spaces and whose names contain spaces too.
+func parseProcLimits(b []byte) []*models.ProcessLimit {
+	var limits []*models.ProcessLimit
+	lines := strings.Split(string(b), "\n")
+	if len(lines) == 0 {
+		return nil
+	}
+	header := lines[0]
+	softAt := strings.Index(header, "Soft Limit")
+	hardAt := strings.Index(header, "Hard Limit")
+	unitAt := strings.Index(header, "Units")
+	if softAt < 0 || hardAt < softAt || unitAt < hardAt {
+		return nil
+	}
+	column := func(line string, from, to int) string {
+		if from >= len(line) {
+			return ""
+		}
+		return strings.TrimSpace(line[from:min(to, len(line))])
+	}
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		limits = append(limits, &models.ProcessLimit{
+			Name: column(line, 0, softAt),
+			Soft: column(line, softAt, hardAt),
+			Hard: column(line, hardAt, unitAt),
+			Unit: column(line, unitAt, len(line)),
+		})
+	}
+	return limits
+}
+
+// parseProcIO parses /proc/<pid>/io.
+func parseProcIO(b []byte) models.ProcessIO {
+	var io models.ProcessIO
+	fields := map[string]*uint64{
+		"rchar": &io.ReadChars, "wchar": &io.WriteChars, "syscr": &io.ReadSyscalls,
+		"syscw": &io.WriteSyscalls, "read_bytes": &io.ReadBytes, "write_bytes": &io.WriteBytes,
+		"cancelled_write_bytes": &io.CancelledWriteBytes,
+	}
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		key, value, found := bytes.Cut(line, []byte{':'})
+		if dst := fields[string(key)]; found && dst != nil {
+			field, _ := cutField(value)
+			*dst, _ = parseDecimal(field)
+		}
+	}
+	return io
+}
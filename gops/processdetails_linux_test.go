@@ -0,0 +1,129 @@
+//go:build linux
+
+package gops
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sampleLimits = `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max open files            1024                 524288               files     
+Max realtime timeout      unlimited            unlimited            us        
+`
+	sampleProcIO       = "rchar: 12345\nwchar: 678\nsyscr: 90\nsyscw: 12\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n"
+	sampleSmapsRollup  = "55d0c6a00000-7ffd2b5fe000 ---p 00000000 00:00 0                          [rollup]\nRss:              304236 kB\nPss:              181234 kB\nPss_Dirty:        150000 kB\nPss_Anon:         140000 kB\nPss_File:          40000 kB\nPss_Shmem:          1234 kB\nShared_Clean:      90000 kB\nShared_Dirty:       2000 kB\nPrivate_Clean:     12000 kB\nPrivate_Dirty:    200236 kB\nAnonymous:        140000 kB\nSwap:               5000 kB\nSwapPss:            4000 kB\nLocked:                0 kB\n"
+	sampleThreadStat   = "4243 (DOM Worker) R 1200 1200 1200 0 -1 4194560 10 0 0 0 300 20 0 0 20 0 31 0 8641200 0 0 18446744073709551615 1 1 0 0 0 0 0 4096 17663 0 0 0 -1 5 0 0 0 0 0 0 0 0 0 0 0 0 0\n"
+	sampleProcCgroupV1 = "12:pids:/user.slice\n1:name=systemd:/user.slice/user-1000.slice/session-2.scope\n"
+)
+
+func TestParseProcLimitsAndIO(t *testing.T) {
+	limits := parseProcLimits([]byte(sampleLimits))
+	require.Len(t, limits, 3)
+	assert.Equal(t, &models.ProcessLimit{Name: "Max open files", Soft: "1024", Hard: "524288", Unit: "files"}, limits[1])
+	assert.Equal(t, "unlimited", limits[2].Hard)
+	assert.Nil(t, parseProcLimits([]byte("garbage\n")))
+
+	assert.Equal(t, models.ProcessIO{
+		ReadChars: 12345, WriteChars: 678, ReadSyscalls: 90, WriteSyscalls: 12, ReadBytes: 4096, WriteBytes: 8192,
+	}, parseProcIO([]byte(sampleProcIO)))
+}
+
+func TestParseSmapsRollup(t *testing.T) {
+	r, ok := parseSmapsRollup([]byte(sampleSmapsRollup))
+	require.True(t, ok)
+	assert.Equal(t, uint64(304236), r.RSS)
+	assert.Equal(t, uint64(181234), r.PSS)
+	assert.Equal(t, uint64(1234), r.PSSShmem)
+	assert.Equal(t, uint64(200236), r.PrivateDirty)
+	assert.Equal(t, uint64(4000), r.SwapPSS)
+
+	_, ok = parseSmapsRollup([]byte("garbage\n"))
+	assert.False(t, ok)
+}
+
+func TestCgroupPath(t *testing.T) {
+	assert.Equal(t, "/user.slice/app.slice/app-foot-1.scope", cgroupPath([]byte("0::/user.slice/app.slice/app-foot-1.scope\n")))
+	assert.Equal(t, "/user.slice/user-1000.slice/session-2.scope", cgroupPath([]byte(sampleProcCgroupV1)))
+	assert.Empty(t, cgroupPath(nil))
+}
+
+func TestGetProcessDetails(t *testing.T) {
+	root := t.TempDir()
+	writeDesktopFiles(t, root, map[string]string{
+		"proc/4242/stat":           samplePIDStat,
+		"proc/4242/statm":          samplePIDStatm,
+		"proc/4242/status":         samplePIDStatus + "voluntary_ctxt_switches:\t150\nnonvoluntary_ctxt_switches:\t7\n",
+		"proc/4242/cmdline":        "/usr/lib/firefox/firefox\x00-contentproc\x00",
+		"proc/4242/environ":        "HOME=/home/user\x00LANG=C.UTF-8\x00",
+		"proc/4242/limits":         sampleLimits,
+		"proc/4242/io":             sampleProcIO,
+		"proc/4242/smaps_rollup":   sampleSmapsRollup,
+		"proc/4242/cgroup":         sampleProcCgroupV1,
+		"proc/4242/oom_score":      "667\n",
+		"proc/4242/oom_score_adj":  "200\n",
+		"proc/4242/task/4242/stat": samplePIDStat,
+		"proc/4242/task/4243/stat": sampleThreadStat,
+		"proc/4242/fd/.keep":       "",
+		"proc/4242/ns/.keep":       "",
+	})
+	dir := filepath.Join(root, "proc/4242")
+	links := map[string]string{
+		"exe": "/usr/lib/firefox/firefox", "cwd": "/home/user",
+		"fd/10": "socket:[123]", "fd/2": "/dev/null", "ns/net": "net:[4026531840]",
+	}
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(dir, name)))
+	}
+
+	g := NewGopsUtil()
+	g.fs = &RootFileSystem{Root: root}
+
+	d, err := g.GetProcessDetails(t.Context(), 4242, false)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1200), d.PPID)
+	assert.Equal(t, "Web Content", d.Command)
+	assert.Equal(t, "/usr/lib/firefox/firefox -contentproc", d.FullCommand)
+	assert.Equal(t, "/usr/lib/firefox/firefox", d.ExecutablePath)
+	assert.Equal(t, "/home/user", d.Cwd)
+	assert.Equal(t, "S", d.State)
+	assert.Equal(t, int32(20), d.Priority)
+	assert.NotEmpty(t, d.StartTime)
+	assert.Equal(t, 104.73, d.PTicks)
+	assert.Equal(t, uint64(150), d.VoluntaryCtxSwitches)
+	assert.Equal(t, uint64(7), d.InvoluntaryCtxSwitches)
+	assert.Equal(t, "/user.slice/user-1000.slice/session-2.scope", d.CGroup)
+	assert.Equal(t, 667, d.OOMScore)
+	assert.Equal(t, 200, d.OOMScoreAdj)
+	assert.Equal(t, map[string]string{"net": "net:[4026531840]"}, d.Namespaces)
+	assert.Nil(t, d.Environ, "the environment is opt-in")
+	require.NotNil(t, d.IO)
+	assert.Equal(t, uint64(8192), d.IO.WriteBytes)
+	require.NotNil(t, d.Memory)
+	assert.Equal(t, uint64(181234), d.Memory.PSS)
+	assert.Len(t, d.Limits, 3)
+
+	require.Equal(t, 2, d.NumFDs)
+	assert.Equal(t, []*models.FDInfo{{FD: 2, Target: "/dev/null"}, {FD: 10, Target: "socket:[123]"}}, d.FDs)
+
+	require.Len(t, d.Threads, 2)
+	assert.Equal(t, int32(4242), d.Threads[0].TID)
+	assert.Equal(t, &models.ThreadInfo{PID: 4242, TID: 4243, Name: "DOM Worker", State: "R", PTicks: 3.2, LastCPU: 5}, d.Threads[1])
+
+	d, err = g.GetProcessDetails(t.Context(), 4242, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HOME=/home/user", "LANG=C.UTF-8"}, d.Environ)
+
+	_, err = g.GetProcessDetails(t.Context(), 999, false)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = g.GetProcessDetails(t.Context(), 0, false)
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package gops
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -16,7 +17,6 @@ import (
 
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/danielgtaylor/huma/v2"
-	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
@@ -58,7 +58,12 @@ func encodeProcessCursor(entries []models.ProcessCursorData) string {
 		wire.OffsetMillis = append(wire.OffsetMillis, e.Timestamp-wire.BaseMillis)
 	}
 
-	raw, _ := json.Marshal(wire)
+	return packCursor(wire)
+}
+
+// packCursor encodes a cursor payload as gzipped JSON in URL-safe base64.
+func packCursor(v any) string {
+	raw, _ := json.Marshal(v)
 	var buf bytes.Buffer
 	gz := cursorWriters.Get().(*gzip.Writer)
 	defer cursorWriters.Put(gz)
@@ -68,28 +73,40 @@ func encodeProcessCursor(entries []models.ProcessCursorData) string {
 	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
 }
 
-func decodeProcessCursor(cursor string) map[int32]*models.ProcessCursorData {
-	out := make(map[int32]*models.ProcessCursorData)
+// unpackCursor decodes a payload from packCursor into v, or returns
+// ErrCursorMalformed.
+func unpackCursor(cursor string, v any) error {
 	raw, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil || len(raw) == 0 {
-		return out
+		return ErrCursorMalformed
 	}
-
 	gz, err := gzip.NewReader(bytes.NewReader(raw))
 	if err != nil {
-		return out
+		return ErrCursorMalformed
 	}
 	defer func() { _ = gz.Close() }()
 	if raw, err = io.ReadAll(io.LimitReader(gz, maxCursorDecodedBytes)); err != nil {
-		return out
+		return ErrCursorMalformed
+	}
+	if json.Unmarshal(raw, v) != nil {
+		return ErrCursorMalformed
 	}
+	return nil
+}
 
+// decodeProcessCursor returns the entries of a process cursor payload, or
+// ErrCursorMalformed with an empty map. An empty payload has no entries.
+func decodeProcessCursor(cursor string) (map[int32]*models.ProcessCursorData, error) {
+	out := make(map[int32]*models.ProcessCursorData)
+	if cursor == "" {
+		return out, nil
+	}
 	var wire processCursorWire
-	if json.Unmarshal(raw, &wire) != nil {
-		return out
+	if err := unpackCursor(cursor, &wire); err != nil {
+		return out, err
 	}
 	if len(wire.CPUMillis) != len(wire.PIDs) || len(wire.OffsetMillis) != len(wire.PIDs) {
-		return out
+		return out, ErrCursorMalformed
 	}
 	entries := make([]models.ProcessCursorData, len(wire.PIDs))
 	for i, pid := range wire.PIDs {
@@ -100,60 +117,234 @@ func decodeProcessCursor(cursor string) map[int32]*models.ProcessCursorData {
 		}
 		out[pid] = &entries[i]
 	}
-	return out
+	return out, nil
 }
 
-// gopsutil can panic on macOS when reading process info for system processes
-// or processes that exit mid-read, and this runs on the request goroutine
-// where a panic would take down the whole call.
-func readProcessTimes(p *process.Process) (times *cpu.TimesStat) {
-	defer func() {
-		if recover() != nil {
-			times = nil
-		}
-	}()
-	times, _ = p.Times()
-	return times
+func (self *GopsUtil) GetProcesses(ctx context.Context, sortBy ProcSortBy, limit int, enableCPU bool, mergeChildren bool) (*models.ProcessListResponse, error) {
+	return self.GetProcessesWithCursor(ctx, ProcessParams{
+		SortBy:        sortBy,
+		Limit:         limit,
+		EnableCPU:     enableCPU,
+		MergeChildren: mergeChildren,
+	})
 }
 
-func (self *GopsUtil) GetProcesses(sortBy ProcSortBy, limit int, enableCPU bool, mergeChildren bool) (*models.ProcessListResponse, error) {
-	return self.GetProcessesWithCursor(sortBy, limit, enableCPU, "", mergeChildren)
+// ProcessParams says which processes GetProcessesWithCursor lists and how.
+type ProcessParams struct {
+	SortBy ProcSortBy
+	// Limit caps the list after sorting; zero lists them all.
+	Limit     int
+	EnableCPU bool
+	// Cursor is the Cursor of a previous result, which CPU usage is
+	// measured since.
+	Cursor        string
+	MergeChildren bool
+	GroupBy       ProcGroupBy
+	// AccurateMemory reads each process's smaps_rollup for its PSS, USS
+	// and swap, and reports memory as PSS; that costs a walk of every
+	// process's page tables, so it's off by default. Sorting by PSS turns
+	// it on.
+	AccurateMemory bool
+	// Filter keeps only the processes it matches, and skips reading more
+	// of the others than it needs to decide; a CPU floor in it turns CPU
+	// sampling on.
+	Filter *ProcFilter
 }
 
-func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enableCPU bool, cursor string, mergeChildren bool) (*models.ProcessListResponse, error) {
-	procs, err := self.procProvider.Processes()
+// GetProcessesWithCursor lists the processes as params says.
+func (self *GopsUtil) GetProcessesWithCursor(ctx context.Context, params ProcessParams) (*models.ProcessListResponse, error) {
+	filter := params.Filter
+	accurateMem := params.AccurateMemory || params.SortBy == SortByPSS
+	enableCPU := params.EnableCPU || filter.needsCPU()
+
+	var payload string
+	if params.Cursor != "" {
+		var err error
+		if payload, err = self.openCursor("processes", params.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	cursorMap, err := decodeProcessCursor(payload)
 	if err != nil {
-		return nil, err
+		return nil, &CursorError{Module: "processes", Err: err}
 	}
 
-	totalMem, _ := self.memProvider.VirtualMemory()
-
-	cursorMap := decodeProcessCursor(cursor)
+	// Rates need a scan taken after the caller's own samples, so with a
+	// cursor the scan it came from is never reused.
+	var notBefore time.Time
+	for _, c := range cursorMap {
+		if t := time.UnixMilli(c.Timestamp + 1); t.After(notBefore) {
+			notBefore = t
+		}
+	}
 
+	scan := func(ctx context.Context) (*processScan, error) { return self.scanProcesses(ctx, accurateMem, filter) }
 	if enableCPU && len(cursorMap) == 0 {
-		for _, p := range procs {
-			times := readProcessTimes(p)
-			if times == nil {
+		procs, err := self.procProvider.Processes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		scan = func(ctx context.Context) (*processScan, error) {
+			return self.scanProcessList(ctx, procs, accurateMem, filter)
+		}
+		envCtx := self.env.context(ctx)
+		for _, p := range self.narrow(ctx, procs, filter) {
+			counters, err := self.readProcCounters(envCtx, p)
+			if err != nil {
 				continue
 			}
 			cursorMap[p.Pid] = &models.ProcessCursorData{
 				PID:       p.Pid,
-				Ticks:     times.User + times.System,
+				Ticks:     counters.cpu,
 				Timestamp: time.Now().UnixMilli(),
 			}
 		}
-		time.Sleep(cpuBaselineInterval)
+		notBefore = time.Now()
+		select {
+		case <-time.After(cpuBaselineInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
 
+	// The two modes read different files, and a filter leaves processes
+	// out, so their scans are cached apart.
+	variant := ""
+	if accurateMem {
+		variant = "smaps"
+	}
+	if filter != nil {
+		variant += "\x00" + filter.String()
+	}
+	result, err := cachedModule(ctx, self, "processes", variant, notBefore, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	// The scan may be shared with other callers, so CPU usage is filled in
+	// on copies.
+	numCPU := float64(runtime.NumCPU())
+	procList := make([]*models.ProcessInfo, 0, len(result.infos))
+	for i, info := range result.infos {
+		proc := *info
+		if enableCPU && result.sampled[i] {
+			if cursorData, hasCursor := cursorMap[proc.PID]; hasCursor {
+				coreRate := calculateProcessCPUPercentageWithCursor(cursorData, proc.PTicks, result.sampledAt[i])
+				proc.CPU = min(coreRate/numCPU, 100)
+			}
+		}
+		if filter.matchUsage(&proc) {
+			procList = append(procList, &proc)
+		}
+	}
+
+	switch {
+	case params.GroupBy == GroupByApp:
+		procList = groupProcessesByApp(procList, params.MergeChildren)
+	case params.MergeChildren:
+		procList = mergeProcessesByExecutable(procList)
+	}
+
+	switch params.SortBy {
+	case SortByCPU:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].CPU > procList[j].CPU
+		})
+	case SortByMemory:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].MemoryPercent > procList[j].MemoryPercent
+		})
+	case SortByPSS:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].PSSKB > procList[j].PSSKB
+		})
+	case SortByName:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].Command < procList[j].Command
+		})
+	case SortByPID:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].PID < procList[j].PID
+		})
+	default:
+		sort.Slice(procList, func(i, j int) bool {
+			return procList[i].CPU > procList[j].CPU
+		})
+	}
+
+	if params.Limit > 0 && len(procList) > params.Limit {
+		procList = procList[:params.Limit]
+	}
+
+	return &models.ProcessListResponse{
+		Processes: procList,
+		Cursor:    self.sealCursor("processes", result.cursor()),
+	}, nil
+}
+
+// processScan is one pass over the process table, without CPU usage, which
+// depends on the caller's cursor. It holds the processes the scan's filter
+// kept, whose cursor entries are all a caller of it can use.
+type processScan struct {
+	infos []*models.ProcessInfo
+	// sampledAt is when each process's CPU time was read, if sampled is set.
+	sampledAt []int64
+	sampled   []bool
+	// starts are the processes' start times, in procCounters' unit, which
+	// tell a reused PID apart.
+	starts []int64
+	// at is when the scan finished, in Unix milliseconds.
+	at int64
+
+	cursorOnce    sync.Once
+	cursorPayload string
+}
+
+// cursor returns the process cursor payload for the scan, encoded once
+// however many callers share it.
+func (s *processScan) cursor() string {
+	s.cursorOnce.Do(func() {
+		entries := make([]models.ProcessCursorData, 0, len(s.infos))
+		for i, info := range s.infos {
+			if s.sampled[i] {
+				entries = append(entries, models.ProcessCursorData{
+					PID:       info.PID,
+					Ticks:     info.PTicks,
+					Timestamp: s.sampledAt[i],
+				})
+			}
+		}
+		s.cursorPayload = encodeProcessCursor(entries)
+	})
+	return s.cursorPayload
+}
+
+func (self *GopsUtil) scanProcesses(ctx context.Context, accurateMem bool, filter *ProcFilter) (*processScan, error) {
+	procs, err := self.procProvider.Processes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return self.scanProcessList(ctx, procs, accurateMem, filter)
+}
+
+// scanProcessList reads the processes of procs that filter matches, or
+// stops early with ctx's error. The workers are capped at 8, which also
+// bounds how many smaps_rollup walks run at once in accurate memory mode.
+// The usage floors of filter are left to the caller, who knows the CPU.
+func (self *GopsUtil) scanProcessList(ctx context.Context, all []*process.Process, accurateMem bool, filter *ProcFilter) (*processScan, error) {
+	began := time.Now().UnixMilli()
+	totalMem, _ := self.memProvider.VirtualMemory(ctx)
+	envCtx := self.env.context(ctx)
+	procs := self.narrow(ctx, all, filter)
+
 	type procResult struct {
 		index     int
 		info      *models.ProcessInfo
 		sampledAt int64
 		sampled   bool
+		start     int64
 	}
 
-	numCPU := float64(runtime.NumCPU())
-
 	numWorkers := runtime.NumCPU()
 	if numWorkers > 8 {
 		numWorkers = 8
@@ -166,6 +357,10 @@ func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enabl
 		go func() {
 			for idx := range jobs {
 				p := procs[idx]
+				if ctx.Err() != nil {
+					results <- procResult{index: idx}
+					continue
+				}
 
 				// gopsutil can panic on macOS when reading process info
 				// for system processes or processes that exit mid-read.
@@ -182,48 +377,49 @@ func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enabl
 						}
 					}()
 
-					name, _ := p.Name()
-					cmdline, _ := p.Cmdline()
-					ppid, _ := p.Ppid()
-					memInfo, _ := p.MemoryInfo()
-					times, _ := p.Times()
+					counters, err := self.readProcCounters(envCtx, p)
 					sampledAt := time.Now().UnixMilli()
-					username, _ := p.Username()
-					exePath, _ := p.Exe()
 
-					currentCPUTime := float64(0)
-					if times != nil {
-						currentCPUTime = times.User + times.System
+					rssKB := uint64(0)
+					rssPercent := float32(0)
+					if counters.rss > 0 {
+						rssKB = counters.rss / 1024
+						rssPercent = float32(counters.rss) / float32(totalMem.Total) * 100
 					}
-
-					cpuPercent := 0.0
-					if enableCPU {
-						if cursorData, hasCursor := cursorMap[p.Pid]; hasCursor {
-							coreRate := calculateProcessCPUPercentageWithCursor(cursorData, currentCPUTime, sampledAt)
-							cpuPercent = coreRate / numCPU
-							if cpuPercent > 100 {
-								cpuPercent = 100
-							}
-						}
+					if !filter.matchCounters(counters, rssPercent) || !self.filterState(envCtx, p, counters, filter) {
+						results <- procResult{index: idx}
+						return
+					}
+					static := self.readProcStatic(envCtx, p, counters, err == nil)
+					if !filter.matchStatic(static) {
+						results <- procResult{index: idx}
+						return
 					}
 
-					rssKB := uint64(0)
-					rssPercent := float32(0)
 					pssKB := uint64(0)
 					pssPercent := float32(0)
 					memKB := uint64(0)
 					memPercent := float32(0)
 					memCalc := "rss"
+					var smaps models.SmapsRollup
 
-					if memInfo != nil {
-						rssKB = memInfo.RSS / 1024
-						rssPercent = float32(memInfo.RSS) / float32(totalMem.Total) * 100
-
+					if counters.rss > 0 {
 						memKB = rssKB
 						memPercent = rssPercent
 
-						if rssKB > 102400 {
-							pssDirty, err := getPssDirty(p.Pid)
+						if accurateMem {
+							// Other users' processes can't be read without
+							// root, and keep their RSS.
+							if rollup, err := self.readSmapsRollup(p.Pid); err == nil && rollup.PSS > 0 {
+								smaps = rollup
+								pssKB = rollup.PSS
+								pssPercent = float32(pssKB*1024) / float32(totalMem.Total) * 100
+								memKB = pssKB
+								memPercent = pssPercent
+								memCalc = "pss"
+							}
+						} else if rssKB > 102400 {
+							pssDirty, err := self.getPssDirty(p.Pid)
 							if err == nil && pssDirty > 0 {
 								memKB = pssDirty
 								memPercent = float32(memKB*1024) / float32(totalMem.Total) * 100
@@ -235,12 +431,13 @@ func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enabl
 					results <- procResult{
 						index:     idx,
 						sampledAt: sampledAt,
-						sampled:   times != nil,
+						sampled:   err == nil,
+						start:     counters.start,
 						info: &models.ProcessInfo{
 							PID:               p.Pid,
-							PPID:              ppid,
-							CPU:               cpuPercent,
-							PTicks:            currentCPUTime,
+							PPID:              counters.ppid,
+							State:             counters.state,
+							PTicks:            counters.cpu,
 							MemoryPercent:     memPercent,
 							MemoryKB:          memKB,
 							MemoryCalculation: memCalc,
@@ -248,10 +445,17 @@ func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enabl
 							RSSPercent:        rssPercent,
 							PSSKB:             pssKB,
 							PSSPercent:        pssPercent,
-							Username:          username,
-							Command:           name,
-							FullCommand:       cmdline,
-							ExecutablePath:    exePath,
+							USSKB:             smaps.PrivateClean + smaps.PrivateDirty,
+							SwapKB:            smaps.Swap,
+							SwapPSSKB:         smaps.SwapPSS,
+							PSSAnonKB:         smaps.PSSAnon,
+							PSSFileKB:         smaps.PSSFile,
+							PSSShmemKB:        smaps.PSSShmem,
+							Username:          static.username,
+							Command:           static.name,
+							FullCommand:       static.cmdline,
+							ExecutablePath:    static.exe,
+							AppID:             static.appID,
 						},
 					}
 				}()
@@ -264,55 +468,100 @@ func (self *GopsUtil) GetProcessesWithCursor(sortBy ProcSortBy, limit int, enabl
 	}
 	close(jobs)
 
-	procList := make([]*models.ProcessInfo, len(procs))
-	cursorList := make([]models.ProcessCursorData, 0, len(procs))
+	ordered := make([]procResult, len(procs))
 	for i := 0; i < len(procs); i++ {
 		r := <-results
-		procList[r.index] = r.info
-		if r.sampled {
-			cursorList = append(cursorList, models.ProcessCursorData{
-				PID:       r.info.PID,
-				Ticks:     r.info.PTicks,
-				Timestamp: r.sampledAt,
-			})
+		ordered[r.index] = r
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	scan := &processScan{at: time.Now().UnixMilli()}
+	for _, r := range ordered {
+		// The filter dropped it.
+		if r.info == nil {
+			continue
 		}
+		scan.infos = append(scan.infos, r.info)
+		scan.sampledAt = append(scan.sampledAt, r.sampledAt)
+		scan.sampled = append(scan.sampled, r.sampled)
+		scan.starts = append(scan.starts, r.start)
 	}
-
-	if mergeChildren {
-		procList = mergeProcessesByExecutable(procList)
+	// Kept processes inherit apps from the whole tree, not just the part
+	// the filter kept.
+	var outside func(int32) *models.ProcessInfo
+	if filter != nil {
+		outside = self.procNodes(envCtx, all)
+	}
+	self.resolveApps(scan.infos, outside)
+	if history := self.procHistory.Load(); history != nil && filter == nil {
+		history.observe(scan, began)
 	}
 
-	switch sortBy {
-	case SortByCPU:
-		sort.Slice(procList, func(i, j int) bool {
-			return procList[i].CPU > procList[j].CPU
-		})
-	case SortByMemory:
-		sort.Slice(procList, func(i, j int) bool {
-			return procList[i].MemoryPercent > procList[j].MemoryPercent
-		})
-	case SortByName:
-		sort.Slice(procList, func(i, j int) bool {
-			return procList[i].Command < procList[j].Command
-		})
-	case SortByPID:
-		sort.Slice(procList, func(i, j int) bool {
-			return procList[i].PID < procList[j].PID
-		})
-	default:
-		sort.Slice(procList, func(i, j int) bool {
-			return procList[i].CPU > procList[j].CPU
-		})
+	if table := self.procTable.Load(); table != nil {
+		live := make(map[int32]struct{}, len(all))
+		for _, p := range all {
+			live[p.Pid] = struct{}{}
+		}
+		table.retain(live)
 	}
+	return scan, nil
+}
 
-	if limit > 0 && len(procList) > limit {
-		procList = procList[:limit]
+// procNodes returns a lookup of the processes in all by PID, reading just
+// enough of each to place it in the tree and name its app. The process
+// table is consulted but not filled, so it keeps to the processes scans
+// kept.
+func (self *GopsUtil) procNodes(ctx context.Context, all []*process.Process) func(int32) *models.ProcessInfo {
+	byPID := make(map[int32]*process.Process, len(all))
+	for _, p := range all {
+		byPID[p.Pid] = p
+	}
+	return func(pid int32) *models.ProcessInfo {
+		p := byPID[pid]
+		if p == nil {
+			return nil
+		}
+		counters, err := self.readProcCounters(ctx, p)
+		if err != nil {
+			return nil
+		}
+		var static procStatic
+		var known bool
+		if table := self.procTable.Load(); table != nil {
+			static, known = table.get(pid, counters)
+		}
+		if !known {
+			static = self.collectProcStatic(ctx, p)
+		}
+		return &models.ProcessInfo{
+			PID:            pid,
+			PPID:           counters.ppid,
+			Command:        static.name,
+			ExecutablePath: static.exe,
+			AppID:          static.appID,
+		}
 	}
+}
 
-	return &models.ProcessListResponse{
-		Processes: procList,
-		Cursor:    encodeProcessCursor(cursorList),
-	}, nil
+// findAncestor walks up the process tree from pid, which is usually a
+// process's parent, and returns the first process found reports true
+// for. parent gives a process's parent, and false for one it doesn't
+// know, which ends the walk; the last process reached is then returned
+// with false. The walk is bounded, in case a PID reused mid-scan makes a
+// loop.
+func findAncestor(pid int32, parent func(int32) (int32, bool), found func(int32) bool) (int32, bool) {
+	for depth := 0; depth < 64; depth++ {
+		if found(pid) {
+			return pid, true
+		}
+		next, ok := parent(pid)
+		if !ok {
+			break
+		}
+		pid = next
+	}
+	return pid, false
 }
 
 type ProcSortBy string
@@ -322,6 +571,9 @@ const (
 	SortByMemory ProcSortBy = "memory"
 	SortByName   ProcSortBy = "name"
 	SortByPID    ProcSortBy = "pid"
+	// SortByPSS sorts by proportional set size, and turns on accurate
+	// memory mode to read it.
+	SortByPSS ProcSortBy = "pss"
 )
 
 // Register enum in OpenAPI specification
@@ -335,12 +587,49 @@ func (u ProcSortBy) Schema(r huma.Registry) *huma.Schema {
 			string(SortByMemory),
 			string(SortByName),
 			string(SortByPID),
+			string(SortByPSS),
 		}...)
 		r.Map()["ProcSortBy"] = schemaRef
 	}
 	return &huma.Schema{Ref: "#/components/schemas/ProcSortBy"}
 }
 
+// ProcGroupBy says how processes are folded together. GroupByProcess lists
+// each process, or each executable when children are merged; GroupByApp
+// lists each desktop app once.
+type ProcGroupBy string
+
+const (
+	GroupByProcess ProcGroupBy = "process"
+	GroupByApp     ProcGroupBy = "app"
+)
+
+// ParseProcGroupBy checks s against the ProcGroupBy values; empty means
+// GroupByProcess.
+func ParseProcGroupBy(s string) (ProcGroupBy, error) {
+	switch ProcGroupBy(s) {
+	case "", GroupByProcess:
+		return GroupByProcess, nil
+	case GroupByApp:
+		return GroupByApp, nil
+	}
+	return "", fmt.Errorf("unknown grouping %q (expected process or app)", s)
+}
+
+// Register enum in OpenAPI specification
+func (u ProcGroupBy) Schema(r huma.Registry) *huma.Schema {
+	if r.Map()["ProcGroupBy"] == nil {
+		schemaRef := r.Schema(reflect.TypeOf(""), true, "ProcGroupBy")
+		schemaRef.Title = "ProcGroupBy"
+		schemaRef.Enum = append(schemaRef.Enum, []any{
+			string(GroupByProcess),
+			string(GroupByApp),
+		}...)
+		r.Map()["ProcGroupBy"] = schemaRef
+	}
+	return &huma.Schema{Ref: "#/components/schemas/ProcGroupBy"}
+}
+
 func calculateProcessCPUPercentageWithCursor(cursor *models.ProcessCursorData, currentCPUTime float64, currentTime int64) float64 {
 	if cursor.Timestamp == 0 || currentCPUTime <= cursor.Ticks {
 		return 0
@@ -369,6 +658,26 @@ func findMergeRoot(p *models.ProcessInfo, pidMap map[int32]*models.ProcessInfo)
 	}
 }
 
+// addProcessUsage counts p's usage towards the merged entry into. RSS
+// double counts shared pages, so for app totals PSS and USS are the sums to
+// trust.
+func addProcessUsage(into, p *models.ProcessInfo) {
+	into.CPU += p.CPU
+	into.MemoryKB += p.MemoryKB
+	into.MemoryPercent += p.MemoryPercent
+	into.RSSKB += p.RSSKB
+	into.RSSPercent += p.RSSPercent
+	into.PSSKB += p.PSSKB
+	into.PSSPercent += p.PSSPercent
+	into.USSKB += p.USSKB
+	into.SwapKB += p.SwapKB
+	into.SwapPSSKB += p.SwapPSSKB
+	into.PSSAnonKB += p.PSSAnonKB
+	into.PSSFileKB += p.PSSFileKB
+	into.PSSShmemKB += p.PSSShmemKB
+	into.ChildCount++
+}
+
 func mergeProcessesByExecutable(procList []*models.ProcessInfo) []*models.ProcessInfo {
 	pidMap := make(map[int32]*models.ProcessInfo)
 	for _, p := range procList {
@@ -393,14 +702,7 @@ func mergeProcessesByExecutable(procList []*models.ProcessInfo) []*models.Proces
 		}
 
 		if p.PID != rootPID {
-			root.CPU += p.CPU
-			root.MemoryKB += p.MemoryKB
-			root.MemoryPercent += p.MemoryPercent
-			root.RSSKB += p.RSSKB
-			root.RSSPercent += p.RSSPercent
-			root.PSSKB += p.PSSKB
-			root.PSSPercent += p.PSSPercent
-			root.ChildCount++
+			addProcessUsage(root, p)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package gops
 
 import (
+	"context"
 	"os"
 	"runtime"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/shirou/gopsutil/v4/process"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,10 +20,17 @@ func newProcessTestUtil(t *testing.T, procs ...*process.Process) *GopsUtil {
 	t.Helper()
 
 	mockProc := mocks.NewMockProcessInfoProvider(t)
-	mockProc.EXPECT().Processes().Return(procs, nil).Once()
+	mockProc.EXPECT().Processes(mock.Anything).Return(procs, nil).Once()
 
 	mockMem := mocks.NewMockMemoryInfoProvider(t)
-	mockMem.EXPECT().VirtualMemory().Return(&mem.VirtualMemoryStat{Total: 16 << 30}, nil).Once()
+	mockMem.EXPECT().VirtualMemory(mock.Anything).Return(&mem.VirtualMemoryStat{Total: 16 << 30}, nil).Once()
+
+	// The processes are real, so their /proc files come from the real /proc.
+	mockFS := mocks.NewMockFileSystem(t)
+	mockFS.EXPECT().ReadFile(mock.Anything).RunAndReturn(os.ReadFile).Maybe()
+	mockFS.EXPECT().Readlink(mock.Anything).RunAndReturn(os.Readlink).Maybe()
+	// No desktop entries, so no process is matched to an app by name.
+	mockFS.EXPECT().ReadDir(mock.Anything).Return(nil, os.ErrNotExist).Maybe()
 
 	return NewGopsUtilWithProviders(
 		mocks.NewMockCPUInfoProvider(t),
@@ -31,10 +40,20 @@ func newProcessTestUtil(t *testing.T, procs ...*process.Process) *GopsUtil {
 		mockProc,
 		mocks.NewMockHostInfoProvider(t),
 		mocks.NewMockLoadInfoProvider(t),
-		mocks.NewMockFileSystem(t),
+		mockFS,
 	)
 }
 
+// openProcessCursor checks and decodes a cursor util emitted.
+func openProcessCursor(t *testing.T, util *GopsUtil, cursor string) map[int32]*models.ProcessCursorData {
+	t.Helper()
+	payload, err := util.openCursor("processes", cursor)
+	require.NoError(t, err)
+	entries, err := decodeProcessCursor(payload)
+	require.NoError(t, err)
+	return entries
+}
+
 func TestGetProcessesWithCursor_UsesCursorForCPU(t *testing.T) {
 	self, err := process.NewProcess(int32(os.Getpid()))
 	require.NoError(t, err)
@@ -42,15 +61,18 @@ func TestGetProcessesWithCursor_UsesCursorForCPU(t *testing.T) {
 	times, err := self.Times()
 	require.NoError(t, err)
 
-	cursor := encodeProcessCursor([]models.ProcessCursorData{{
+	util := newProcessTestUtil(t, self)
+	cursor := util.sealCursor("processes", encodeProcessCursor([]models.ProcessCursorData{{
 		PID:       self.Pid,
 		Ticks:     times.User + times.System - 5.0,
 		Timestamp: time.Now().UnixMilli() - 10_000,
-	}})
-
-	util := newProcessTestUtil(t, self)
+	}}))
 
-	res, err := util.GetProcessesWithCursor(SortByCPU, 0, true, cursor, false)
+	res, err := util.GetProcessesWithCursor(t.Context(), ProcessParams{
+		SortBy:    SortByCPU,
+		EnableCPU: true,
+		Cursor:    cursor,
+	})
 	require.NoError(t, err)
 	require.Len(t, res.Processes, 1)
 
@@ -69,15 +91,18 @@ func TestGetProcessesWithCursor_ClampsToWholeMachine(t *testing.T) {
 
 	// An implausible amount of CPU over a very short interval, as a stale or
 	// hand-crafted cursor would produce.
-	cursor := encodeProcessCursor([]models.ProcessCursorData{{
+	util := newProcessTestUtil(t, self)
+	cursor := util.sealCursor("processes", encodeProcessCursor([]models.ProcessCursorData{{
 		PID:       self.Pid,
 		Ticks:     times.User + times.System - 1000.0,
 		Timestamp: time.Now().UnixMilli() - 10,
-	}})
-
-	util := newProcessTestUtil(t, self)
+	}}))
 
-	res, err := util.GetProcessesWithCursor(SortByCPU, 0, true, cursor, false)
+	res, err := util.GetProcessesWithCursor(t.Context(), ProcessParams{
+		SortBy:    SortByCPU,
+		EnableCPU: true,
+		Cursor:    cursor,
+	})
 	require.NoError(t, err)
 	require.Len(t, res.Processes, 1)
 
@@ -88,10 +113,13 @@ func TestGetProcessesWithCursor_ClampsToWholeMachine(t *testing.T) {
 func TestGetProcessesWithCursor_SkipsCursorEntryWhenTimesUnreadable(t *testing.T) {
 	util := newProcessTestUtil(t, &process.Process{Pid: 999999})
 
-	res, err := util.GetProcessesWithCursor(SortByCPU, 0, true, "", false)
+	res, err := util.GetProcessesWithCursor(t.Context(), ProcessParams{
+		SortBy:    SortByCPU,
+		EnableCPU: true,
+	})
 	require.NoError(t, err)
 
-	assert.Empty(t, decodeProcessCursor(res.Cursor),
+	assert.Empty(t, openProcessCursor(t, util, res.Cursor),
 		"a process whose CPU times could not be read must not enter the cursor as 0 ticks")
 }
 
@@ -103,11 +131,15 @@ func TestGetProcessesWithCursor_CursorCoversAllProcesses(t *testing.T) {
 
 	util := newProcessTestUtil(t, self, parent)
 
-	res, err := util.GetProcessesWithCursor(SortByCPU, 1, false, "", true)
+	res, err := util.GetProcessesWithCursor(t.Context(), ProcessParams{
+		SortBy:        SortByCPU,
+		Limit:         1,
+		MergeChildren: true,
+	})
 	require.NoError(t, err)
 	require.Len(t, res.Processes, 1)
 
-	assert.Len(t, decodeProcessCursor(res.Cursor), 2,
+	assert.Len(t, openProcessCursor(t, util, res.Cursor), 2,
 		"cursor must cover every process, not only the merged and limited page, or excluded processes report 0 forever")
 }
 
@@ -118,14 +150,38 @@ func TestGetProcessesWithCursor_TimestampsTrackEachRead(t *testing.T) {
 	before := time.Now().UnixMilli()
 	util := newProcessTestUtil(t, self)
 
-	res, err := util.GetProcessesWithCursor(SortByCPU, 0, true, "", false)
+	res, err := util.GetProcessesWithCursor(t.Context(), ProcessParams{
+		SortBy:    SortByCPU,
+		EnableCPU: true,
+	})
 	require.NoError(t, err)
 	after := time.Now().UnixMilli()
 
-	entry, ok := decodeProcessCursor(res.Cursor)[self.Pid]
+	entry, ok := openProcessCursor(t, util, res.Cursor)[self.Pid]
 	require.True(t, ok)
 
 	assert.GreaterOrEqual(t, entry.Timestamp, before+cpuBaselineInterval.Milliseconds(),
 		"cursor timestamp must be when times were sampled, not when the request started")
 	assert.LessOrEqual(t, entry.Timestamp, after)
 }
+
+func TestGetProcessesBaselineStopsWhenCanceled(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	mockProc := mocks.NewMockProcessInfoProvider(t)
+	mockProc.EXPECT().Processes(mock.Anything).Return([]*process.Process{self}, nil).Once()
+	mockFS := mocks.NewMockFileSystem(t)
+	mockFS.EXPECT().ReadFile(mock.Anything).RunAndReturn(os.ReadFile).Maybe()
+	util := NewGopsUtilWithProviders(nil, nil, nil, nil, mockProc, nil, nil, mockFS)
+
+	ctx, cancel := context.WithTimeout(t.Context(), cpuBaselineInterval/4)
+	defer cancel()
+	start := time.Now()
+	_, err = util.GetProcessesWithCursor(ctx, ProcessParams{
+		SortBy:    SortByCPU,
+		EnableCPU: true,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), cpuBaselineInterval)
+}
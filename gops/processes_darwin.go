@@ -2,6 +2,81 @@
 
 package gops
 
-func getPssDirty(_ int32) (uint64, error) {
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+func (self *GopsUtil) getPssDirty(_ int32) (uint64, error) {
 	return 0, nil
 }
+
+// readSmapsRollup needs Linux's /proc.
+func (self *GopsUtil) readSmapsRollup(_ int32) (models.SmapsRollup, error) {
+	return models.SmapsRollup{}, errors.ErrUnsupported
+}
+
+// readProcCounters reads the counters of p through gopsutil. When the CPU
+// times can't be read it returns an error with the other counters still
+// set; that's usual for other users' processes without root.
+func (self *GopsUtil) readProcCounters(ctx context.Context, p *process.Process) (c procCounters, err error) {
+	// gopsutil can panic reading system processes or ones that exit
+	// mid-read.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pid %d: %v", p.Pid, r)
+		}
+	}()
+	c.ppid, _ = p.PpidWithContext(ctx)
+	c.comm, _ = p.NameWithContext(ctx)
+	c.start, _ = p.CreateTimeWithContext(ctx)
+	if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+		c.rss = mem.RSS
+	}
+	times, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return c, err
+	}
+	c.cpu = times.User + times.System
+	return c, nil
+}
+
+func (self *GopsUtil) collectProcStatic(ctx context.Context, p *process.Process) procStatic {
+	var static procStatic
+	static.name, _ = p.NameWithContext(ctx)
+	static.cmdline, _ = p.CmdlineWithContext(ctx)
+	static.username, _ = p.UsernameWithContext(ctx)
+	static.exe, _ = p.ExeWithContext(ctx)
+	return static
+}
+
+// startClock returns a function converting start times, which gopsutil
+// gives in Unix milliseconds already.
+func (self *GopsUtil) startClock(_ context.Context) func(int64) int64 {
+	return func(start int64) int64 { return start }
+}
+
+// readWaitChannel returns "": the kernel doesn't say what a process
+// sleeps in.
+func (self *GopsUtil) readWaitChannel(_ int32) string {
+	return ""
+}
+
+// procState asks gopsutil for the state of p, which isn't read with the
+// counters as on macOS it takes a ps run.
+func (self *GopsUtil) procState(ctx context.Context, p *process.Process, _ procCounters) (state string) {
+	defer func() {
+		if recover() != nil {
+			state = ""
+		}
+	}()
+	status, err := p.StatusWithContext(ctx)
+	if err != nil || len(status) == 0 {
+		return ""
+	}
+	return processStateLetters[status[0]]
+}
@@ -3,31 +3,179 @@
 package gops
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/user"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/process"
 )
 
-func getPssDirty(pid int32) (uint64, error) {
-	smapsRollupPath := fmt.Sprintf("/proc/%d/smaps_rollup", pid)
-	contents, err := os.ReadFile(smapsRollupPath)
+func (self *GopsUtil) getPssDirty(pid int32) (uint64, error) {
+	rollup, err := self.readSmapsRollup(pid)
 	if err != nil {
 		return 0, err
 	}
+	return rollup.PSSDirty, nil
+}
+
+// readSmapsRollup reads /proc/<pid>/smaps_rollup. The kernel walks the
+// process's page tables to produce it, so it's much slower than statm.
+func (self *GopsUtil) readSmapsRollup(pid int32) (models.SmapsRollup, error) {
+	contents, err := self.fs.ReadFile(fmt.Sprintf("/proc/%d/smaps_rollup", pid))
+	if err != nil {
+		return models.SmapsRollup{}, err
+	}
+	rollup, ok := parseSmapsRollup(contents)
+	if !ok {
+		return rollup, fmt.Errorf("pid %d: %w", pid, errProcMalformed)
+	}
+	return rollup, nil
+}
+
+// userHZ is the unit of the times in /proc/<pid>/stat. The kernel fixes it
+// at 100 for userspace on every architecture.
+const userHZ = 100
+
+// pfKthread is the flag in /proc/<pid>/stat that marks a kernel thread.
+const pfKthread = 0x00200000
+
+// readProcCounters reads the counters of p from /proc/<pid>/stat and statm.
+func (self *GopsUtil) readProcCounters(_ context.Context, p *process.Process) (procCounters, error) {
+	var c procCounters
+	dir := "/proc/" + strconv.Itoa(int(p.Pid))
+	data, err := self.fs.ReadFile(dir + "/stat")
+	if err != nil {
+		return c, err
+	}
+	stat, err := parsePIDStat(data)
+	if err != nil {
+		return c, fmt.Errorf("%s/stat: %w", dir, err)
+	}
+	c.ppid = stat.ppid
+	c.comm = stat.comm
+	c.start = int64(stat.starttime)
+	c.cpu = float64(stat.utime+stat.stime) / userHZ
+	c.state = string(stat.state)
+	c.kernel = stat.flags&pfKthread != 0
+
+	if data, err := self.fs.ReadFile(dir + "/statm"); err == nil {
+		if statm, err := parsePIDStatm(data); err == nil {
+			c.rss = statm.resident * uint64(os.Getpagesize())
+		}
+	}
+	return c, nil
+}
+
+// collectProcStatic reads the static fields of p from /proc/<pid>.
+func (self *GopsUtil) collectProcStatic(_ context.Context, p *process.Process) procStatic {
+	var static procStatic
+	dir := "/proc/" + strconv.Itoa(int(p.Pid))
+	cmdline, _ := self.fs.ReadFile(dir + "/cmdline")
+	static.cmdline = joinCmdline(cmdline)
+	if data, err := self.fs.ReadFile(dir + "/status"); err == nil {
+		status := parsePIDStatus(data)
+		static.name = procName(status.name, cmdline)
+		if status.hasUID {
+			static.username = lookupUsername(status.uid)
+		}
+	}
+	static.exe, _ = self.fs.Readlink(dir + "/exe")
+	cgroup, _ := self.fs.ReadFile(dir + "/cgroup")
+	static.cgroup = cgroupPath(cgroup)
+	static.appID = self.readProcAppID(dir, cgroup)
+	return static
+}
+
+// startClock returns a function converting start times from
+// /proc/<pid>/stat to Unix milliseconds, which gives zero when the boot time
+// can't be read.
+func (self *GopsUtil) startClock(ctx context.Context) func(int64) int64 {
+	bootTime, err := host.BootTimeWithContext(self.env.context(ctx))
+	if err != nil || bootTime == 0 {
+		return func(int64) int64 { return 0 }
+	}
+	return func(start int64) int64 { return int64(bootTime)*1000 + start*1000/userHZ }
+}
+
+// readWaitChannel returns the kernel function pid sleeps in, or "".
+func (self *GopsUtil) readWaitChannel(pid int32) string {
+	data, err := self.fs.ReadFile(fmt.Sprintf("/proc/%d/wchan", pid))
+	if wchan := strings.TrimSpace(string(data)); err == nil && wchan != "0" {
+		return wchan
+	}
+	return ""
+}
+
+// procState returns the state letter read with the counters.
+func (self *GopsUtil) procState(_ context.Context, _ *process.Process, c procCounters) string {
+	return c.state
+}
+
+// readProcAppID returns the app ID from the unit the process runs in, or
+// failing that from the Flatpak sandbox it runs in. cgroup is the
+// process's /proc/<pid>/cgroup.
+func (self *GopsUtil) readProcAppID(dir string, cgroup []byte) string {
+	if id := appIDFromCgroup(cgroup); id != "" {
+		return id
+	}
+	if data, err := self.fs.ReadFile(dir + "/root/.flatpak-info"); err == nil {
+		return flatpakAppID(data)
+	}
+	return ""
+}
 
-	for _, line := range strings.Split(string(contents), "\n") {
-		if !strings.HasPrefix(line, "Pss_Dirty:") {
+// appIDFromCgroup finds the innermost app unit in /proc/<pid>/cgroup,
+// using the unified hierarchy or systemd's named one.
+func appIDFromCgroup(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 || (parts[0] != "0" && parts[1] != "name=systemd") {
 			continue
 		}
-		fields := strings.Fields(line)
-		if len(fields) >= 2 {
-			val, err := strconv.ParseUint(fields[1], 10, 64)
-			if err != nil {
-				return 0, err
+		units := strings.Split(parts[2], "/")
+		for i := len(units) - 1; i >= 0; i-- {
+			if id := appIDFromUnit(units[i]); id != "" {
+				return id
 			}
-			return val, nil
 		}
 	}
-	return 0, fmt.Errorf("Pss_Dirty not found")
+	return ""
+}
+
+// flatpakAppID returns the name key of the [Application] group in a
+// sandbox's .flatpak-info.
+func flatpakAppID(data []byte) string {
+	inApp := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inApp = line == "[Application]"
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "name="); ok && inApp {
+			return name
+		}
+	}
+	return ""
+}
+
+// usernames caches UID lookups, which read /etc/passwd or ask NSS each time.
+var usernames sync.Map
+
+func lookupUsername(uid uint32) string {
+	if name, ok := usernames.Load(uid); ok {
+		return name.(string)
+	}
+	u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10))
+	if err != nil {
+		return ""
+	}
+	usernames.Store(uid, u.Username)
+	return u.Username
 }
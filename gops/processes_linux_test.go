@@ -0,0 +1,180 @@
+//go:build linux
+
+package gops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProcessesAccurateMemory(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":           fixtureMeminfo,
+		"proc/4242/stat":         samplePIDStat,
+		"proc/4242/statm":        samplePIDStatm,
+		"proc/4242/status":       samplePIDStatus,
+		"proc/4242/cmdline":      "/usr/lib/firefox/firefox\x00-contentproc\x00",
+		"proc/4242/smaps_rollup": sampleSmapsRollup,
+		// Unreadable for another user, so it keeps RSS.
+		"proc/4300/stat":    "4300 (sshd) S 1 4300 4300 0 -1 4194560 0 0 0 0 10 5 0 0 20 0 1 0 500 0 0 0\n",
+		"proc/4300/statm":   "1000 512 100 10 0 200 0\n",
+		"proc/4300/status":  "Name:\tsshd\nUid:\t0\t0\t0\t0\n",
+		"proc/4300/cmdline": "sshd\x00",
+	})
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	plain, err := g.GetProcessesWithCursor(t.Context(), ProcessParams{SortBy: SortByPID})
+	require.NoError(t, err)
+	require.Len(t, plain.Processes, 2)
+	assert.Zero(t, plain.Processes[0].PSSKB, "smaps_rollup is only read on request")
+
+	res, err := g.GetProcessesWithCursor(t.Context(), ProcessParams{SortBy: SortByPSS})
+	require.NoError(t, err)
+	require.Len(t, res.Processes, 2)
+
+	firefox := res.Processes[0]
+	assert.Equal(t, int32(4242), firefox.PID, "sorted by PSS")
+	assert.Equal(t, "pss", firefox.MemoryCalculation)
+	assert.Equal(t, uint64(181234), firefox.PSSKB)
+	assert.Equal(t, firefox.PSSKB, firefox.MemoryKB)
+	assert.Equal(t, uint64(212236), firefox.USSKB)
+	assert.Equal(t, uint64(5000), firefox.SwapKB)
+	assert.Equal(t, uint64(4000), firefox.SwapPSSKB)
+	assert.Equal(t, uint64(140000), firefox.PSSAnonKB)
+	assert.Equal(t, uint64(40000), firefox.PSSFileKB)
+	assert.Equal(t, uint64(1234), firefox.PSSShmemKB)
+
+	sshd := res.Processes[1]
+	assert.Equal(t, "rss", sshd.MemoryCalculation)
+	assert.Zero(t, sshd.PSSKB)
+	assert.NotZero(t, sshd.MemoryKB)
+}
+
+func TestMergeProcessesSumsPSS(t *testing.T) {
+	procs := []*models.ProcessInfo{
+		{PID: 1, ExecutablePath: "/usr/bin/app", RSSKB: 100, PSSKB: 60, USSKB: 40, SwapKB: 5, PSSShmemKB: 2},
+		{PID: 2, PPID: 1, ExecutablePath: "/usr/bin/app", RSSKB: 100, PSSKB: 50, USSKB: 30, SwapKB: 1, PSSShmemKB: 2},
+	}
+	merged := mergeProcessesByExecutable(procs)
+	require.Len(t, merged, 1)
+	assert.Equal(t, uint64(110), merged[0].PSSKB)
+	assert.Equal(t, uint64(70), merged[0].USSKB)
+	assert.Equal(t, uint64(6), merged[0].SwapKB)
+	assert.Equal(t, uint64(4), merged[0].PSSShmemKB)
+	assert.Equal(t, 1, merged[0].ChildCount)
+	assert.Equal(t, uint64(60), procs[0].PSSKB, "merging leaves the scan alone")
+}
+
+func fixtureStat(pid, ppid int32, comm string, state byte, flags uint64) string {
+	return fmt.Sprintf("%d (%s) %c %d 1 1 0 -1 %d 0 0 0 0 10 5 0 0 20 0 1 0 500 0 0 0\n", pid, comm, state, ppid, flags)
+}
+
+func TestGetProcessesFiltered(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":      fixtureMeminfo,
+		"proc/1/stat":       fixtureStat(1, 0, "systemd", 'S', 4194560),
+		"proc/1/statm":      "5000 3000 0 0 0 0 0\n",
+		"proc/1/status":     "Name:\tsystemd\nUid:\t0\t0\t0\t0\n",
+		"proc/1/cmdline":    "/sbin/init\x00",
+		"proc/1/cgroup":     "0::/init.scope\n",
+		"proc/2/stat":       fixtureStat(2, 0, "kthreadd", 'S', 2129984),
+		"proc/2/status":     "Name:\tkthreadd\nUid:\t0\t0\t0\t0\n",
+		"proc/100/stat":     fixtureStat(100, 1, "bash", 'S', 4194560),
+		"proc/100/statm":    "2000 1000 0 0 0 0 0\n",
+		"proc/100/status":   "Name:\tbash\nUid:\t0\t0\t0\t0\n",
+		"proc/100/cmdline":  "-bash\x00",
+		"proc/100/cgroup":   "0::/user.slice/session-1.scope\n",
+		"proc/101/stat":     fixtureStat(101, 100, "sleep", 'D', 4194560),
+		"proc/101/statm":    "2000 100 0 0 0 0 0\n",
+		"proc/101/status":   "Name:\tsleep\nUid:\t0\t0\t0\t0\n",
+		"proc/101/cmdline":  "sleep\x00600\x00",
+		"proc/101/cgroup":   "0::/user.slice/session-1.scope\n",
+		"proc/4300/stat":    fixtureStat(4300, 1, "sshd", 'R', 4194560),
+		"proc/4300/statm":   "2000000 1000000 0 0 0 0 0\n",
+		"proc/4300/status":  "Name:\tsshd\nUid:\t0\t0\t0\t0\n",
+		"proc/4300/cmdline": "sshd:\x00alice\x00",
+		"proc/4300/cgroup":  "0::/system.slice/sshd.service\n",
+	})
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	pids := func(expr string) []int32 {
+		t.Helper()
+		filter, err := ParseProcFilter(expr)
+		require.NoError(t, err)
+		res, err := g.GetProcessesWithCursor(t.Context(), ProcessParams{
+			SortBy: SortByPID,
+			Filter: filter,
+		})
+		require.NoError(t, err)
+		var out []int32
+		for _, p := range res.Processes {
+			out = append(out, p.PID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int32{1, 2, 100, 101, 4300}, pids(""))
+	assert.Equal(t, []int32{1, 100, 101, 4300}, pids("kthreads=false"))
+	assert.Equal(t, []int32{100, 101}, pids("tree=100"))
+	assert.Equal(t, []int32{101}, pids("tree=1 state=D"))
+	assert.Equal(t, []int32{1, 4300}, pids("user=root name~^s cmd~^/|sshd"))
+	assert.Equal(t, []int32{100, 101}, pids("cgroup~session-"))
+	assert.Equal(t, []int32{1}, pids("pid=1,2 kthreads=false"))
+	assert.Equal(t, []int32{4300}, pids("mem>=10"))
+	assert.Empty(t, pids("user=nobody"))
+
+	// Processes ruled out by their counters never have their static
+	// fields read.
+	g.EnableProcessTable()
+	assert.Equal(t, []int32{101}, pids("state=D"))
+	assert.Equal(t, 1, g.procTable.Load().len())
+}
+
+func TestGetProcessesFilteredKeepsApps(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":                         fixtureMeminfo,
+		"proc/1/stat":                          fixtureStat(1, 0, "systemd", 'S', 4194560),
+		"proc/1/status":                        "Name:\tsystemd\nUid:\t0\t0\t0\t0\n",
+		"proc/200/stat":                        fixtureStat(200, 1, "kitty", 'S', 4194560),
+		"proc/200/statm":                       "2000 1000 0 0 0 0 0\n",
+		"proc/200/status":                      "Name:\tkitty\nUid:\t0\t0\t0\t0\n",
+		"proc/201/stat":                        fixtureStat(201, 200, "bash", 'S', 4194560),
+		"proc/201/statm":                       "2000 100 0 0 0 0 0\n",
+		"proc/201/status":                      "Name:\tbash\nUid:\t0\t0\t0\t0\n",
+		"proc/202/stat":                        fixtureStat(202, 201, "make", 'R', 4194560),
+		"proc/202/statm":                       "2000 100 0 0 0 0 0\n",
+		"proc/202/status":                      "Name:\tmake\nUid:\t0\t0\t0\t0\n",
+		"usr/share/applications/kitty.desktop": "[Desktop Entry]\nType=Application\nName=kitty\nIcon=kitty\nExec=kitty\n",
+	})
+	t.Setenv("XDG_DATA_HOME", "/nonexistent")
+	t.Setenv("XDG_DATA_DIRS", "/usr/share")
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	for _, expr := range []string{"name~^(bash|make)$", "tree=201", "pid=202"} {
+		filter, err := ParseProcFilter(expr)
+		require.NoError(t, err)
+		res, err := g.GetProcessesWithCursor(t.Context(), ProcessParams{
+			SortBy:  SortByPID,
+			GroupBy: GroupByApp,
+			Filter:  filter,
+		})
+		require.NoError(t, err, expr)
+		require.Len(t, res.Processes, 1, expr)
+		app := res.Processes[0]
+		assert.Equal(t, "kitty", app.AppID, "%s: the terminal the filter left out still names the app", expr)
+		assert.Equal(t, "kitty", app.AppName, expr)
+	}
+}
@@ -212,7 +212,7 @@ func TestCalculateProcessCPUPercentageRealWorld(t *testing.T) {
 }
 
 func TestGetPssDirty(t *testing.T) {
-	_, err := getPssDirty(999999)
+	_, err := NewGopsUtil().getPssDirty(999999)
 	assert.Error(t, err, "Should error for non-existent PID")
 }
 
@@ -224,7 +224,8 @@ func TestProcessCursorRoundTrip(t *testing.T) {
 		{PID: 999999, Ticks: 0, Timestamp: base - 12},
 	}
 
-	decoded := decodeProcessCursor(encodeProcessCursor(entries))
+	decoded, err := decodeProcessCursor(encodeProcessCursor(entries))
+	require.NoError(t, err)
 
 	require.Len(t, decoded, 3)
 	for _, want := range entries {
@@ -246,7 +247,9 @@ func TestProcessCursorFitsInAQueryParam(t *testing.T) {
 	encoded := encodeProcessCursor(entries)
 
 	assert.Less(t, len(encoded), 6000)
-	assert.Len(t, decodeProcessCursor(encoded), 500)
+	decoded, err := decodeProcessCursor(encoded)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 500)
 }
 
 func gzipB64(t *testing.T, payload string) string {
@@ -270,7 +273,12 @@ func TestProcessCursorRejectsBadInput(t *testing.T) {
 		"no arrays at all": gzipB64(t, `{"t":1}`),
 	} {
 		t.Run(name, func(t *testing.T) {
-			assert.Empty(t, decodeProcessCursor(cursor))
+			entries, err := decodeProcessCursor(cursor)
+			assert.Empty(t, entries)
+			// An empty cursor, or one listing no processes, is well formed.
+			if name != "empty" && name != "no arrays at all" {
+				assert.ErrorIs(t, err, ErrCursorMalformed)
+			}
 		})
 	}
 }
@@ -289,7 +297,9 @@ func TestProcessCursorEncodesConcurrently(t *testing.T) {
 	}
 
 	for range 16 {
-		assert.Len(t, decodeProcessCursor(<-encoded), 300,
+		decoded, err := decodeProcessCursor(<-encoded)
+		require.NoError(t, err)
+		assert.Len(t, decoded, 300,
 			"encoders share a pooled gzip writer and must not interleave output")
 	}
 }
@@ -301,7 +311,9 @@ func TestProcessCursorCapsDecompression(t *testing.T) {
 		bomb = append(bomb, models.ProcessCursorData{PID: int32(i), Ticks: 1, Timestamp: 1})
 	}
 
-	assert.Empty(t, decodeProcessCursor(encodeProcessCursor(bomb)),
+	decoded, err := decodeProcessCursor(encodeProcessCursor(bomb))
+	assert.ErrorIs(t, err, ErrCursorMalformed)
+	assert.Empty(t, decoded,
 		"a cursor that inflates past the cap must be discarded, not decoded")
 }
 
@@ -0,0 +1,293 @@
+package gops
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// exitRetention is how long the last sighting of an exited process is
+// kept, long enough for a poller's next call to still say what it was.
+const exitRetention = time.Minute
+
+// procEventsCursor is the set of processes running at the previous call.
+type procEventsCursor struct {
+	At     int64   `json:"t"`
+	PIDs   []int32 `json:"pid"`
+	Starts []int64 `json:"start"`
+}
+
+// procSighting is the last look a scan had at a process.
+type procSighting struct {
+	start    int64
+	ppid     int32
+	name     string
+	cmdline  string
+	username string
+	ticks    float64
+	memKB    uint64
+	// at is when the CPU time was read, in Unix milliseconds.
+	at int64
+	// cpu is the usage between the last two sightings, as a percentage of
+	// the whole machine, or zero after only one.
+	cpu float64
+}
+
+type exitedProc struct {
+	pid    int32
+	goneAt int64
+	procSighting
+}
+
+// procHistory follows processes across the unfiltered scans, so the usage
+// of one that exits is still known for exitRetention afterwards. It also
+// holds the proc connector, when the kernel let dgop join it.
+type procHistory struct {
+	conn *procConnector
+
+	mu     sync.Mutex
+	live   map[int32]procSighting
+	exited []exitedProc
+}
+
+// startProcHistory turns on process history for GetProcessEvents, and on
+// the live system tries the proc connector.
+func (self *GopsUtil) startProcHistory() *procHistory {
+	if h := self.procHistory.Load(); h != nil {
+		return h
+	}
+	h := &procHistory{live: make(map[int32]procSighting)}
+	if !self.procHistory.CompareAndSwap(nil, h) {
+		return self.procHistory.Load()
+	}
+	if _, host := self.fs.(*DefaultFileSystem); host && self.env.env == nil {
+		h.conn = startProcConnector(self.fs, self.startClock(context.Background()))
+	}
+	return h
+}
+
+// observe records scan, which began at the given Unix milliseconds. A
+// process seen before the scan began that the scan didn't find has exited.
+func (h *procHistory) observe(scan *processScan, began int64) {
+	numCPU := float64(runtime.NumCPU())
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[int32]bool, len(scan.infos))
+	for i, info := range scan.infos {
+		seen[info.PID] = true
+		s := procSighting{
+			start:    scan.starts[i],
+			ppid:     info.PPID,
+			name:     info.Command,
+			cmdline:  info.FullCommand,
+			username: info.Username,
+			ticks:    info.PTicks,
+			memKB:    info.MemoryKB,
+			at:       scan.sampledAt[i],
+		}
+		prev, ok := h.live[info.PID]
+		switch {
+		case !scan.sampled[i]:
+			s.at = scan.at
+		case ok && prev.start == s.start && s.at <= prev.at:
+			// A scan that overlapped a newer one.
+			continue
+		case ok && prev.start == s.start:
+			s.cpu = prev.cpu
+			if elapsed := float64(s.at-prev.at) / 1000; elapsed > 0 && s.ticks >= prev.ticks {
+				s.cpu = min((s.ticks-prev.ticks)/elapsed/numCPU*100, 100)
+			}
+		case ok:
+			// The PID was reused.
+			h.exited = append(h.exited, exitedProc{pid: info.PID, goneAt: scan.at, procSighting: prev})
+		}
+		h.live[info.PID] = s
+	}
+	for pid, prev := range h.live {
+		if !seen[pid] && prev.at < began {
+			h.exited = append(h.exited, exitedProc{pid: pid, goneAt: scan.at, procSighting: prev})
+			delete(h.live, pid)
+		}
+	}
+
+	cutoff := scan.at - exitRetention.Milliseconds()
+	drop := 0
+	for drop < len(h.exited) && h.exited[drop].goneAt < cutoff {
+		drop++
+	}
+	h.exited = h.exited[drop:]
+}
+
+// lookupExited returns the last sighting of the process pid that started
+// at start, or with anyStart of whichever had pid last, and when it was
+// found gone.
+func (h *procHistory) lookupExited(pid int32, start int64, anyStart bool) (procSighting, int64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(h.exited) - 1; i >= 0; i-- {
+		e := h.exited[i]
+		if e.pid == pid && (anyStart || e.start == start) {
+			return e.procSighting, e.goneAt, true
+		}
+	}
+	return procSighting{}, 0, false
+}
+
+// GetProcessEvents reports the processes that started and exited since
+// cursor. Without the proc connector, which needs CAP_NET_ADMIN, events
+// come from comparing the processes running now with those in the cursor,
+// so a process that lived between two calls goes unseen unless another
+// scan, such as a processes call, caught it. The first call only returns a
+// cursor.
+func (self *GopsUtil) GetProcessEvents(ctx context.Context, cursorStr string) (*models.ProcessEventsResponse, error) {
+	var prev procEventsCursor
+	if cursorStr != "" {
+		payload, err := self.openCursor("proc-events", cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		if err := unpackCursor(payload, &prev); err != nil || len(prev.Starts) != len(prev.PIDs) {
+			return nil, &CursorError{Module: "proc-events", Err: ErrCursorMalformed}
+		}
+	}
+	history := self.startProcHistory()
+
+	var notBefore time.Time
+	if prev.At != 0 {
+		notBefore = time.UnixMilli(prev.At + 1)
+	}
+	scan, err := cachedModule(ctx, self, "processes", "", notBefore, func(ctx context.Context) (*processScan, error) {
+		return self.scanProcesses(ctx, false, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	next := procEventsCursor{
+		At:     scan.at,
+		PIDs:   make([]int32, len(scan.infos)),
+		Starts: scan.starts,
+	}
+	current := make(map[int32]int, len(scan.infos))
+	for i, info := range scan.infos {
+		next.PIDs[i] = info.PID
+		current[info.PID] = i
+	}
+
+	resp := &models.ProcessEventsResponse{
+		Events: []*models.ProcessEvent{},
+		Source: models.ProcessEventsScan,
+		Cursor: self.sealCursor("proc-events", packCursor(next)),
+	}
+	if prev.At == 0 {
+		return resp, nil
+	}
+
+	startWall := self.startClock(ctx)
+	numCPU := float64(runtime.NumCPU())
+
+	type eventKey struct {
+		typ models.ProcessEventType
+		pid int32
+	}
+	have := make(map[eventKey]bool)
+	if events, covered := history.conn.eventsBetween(prev.At, scan.at); covered {
+		resp.Source = models.ProcessEventsNetlink
+		for _, e := range events {
+			if i, alive := current[e.PID]; alive && e.Type == models.ProcessSpawned {
+				info := scan.infos[i]
+				e.Command, e.FullCommand, e.Username = info.Command, info.FullCommand, info.Username
+			}
+			if e.Type == models.ProcessExited {
+				if s, _, ok := history.lookupExited(e.PID, 0, true); ok && s.at >= prev.At {
+					fillExit(e, s, startWall, numCPU)
+				}
+			}
+			have[eventKey{e.Type, e.PID}] = true
+			resp.Events = append(resp.Events, e)
+		}
+	}
+
+	previous := make(map[int32]int64, len(prev.PIDs))
+	for i, pid := range prev.PIDs {
+		previous[pid] = prev.Starts[i]
+	}
+	for i, info := range scan.infos {
+		if start, ok := previous[info.PID]; ok && start == scan.starts[i] {
+			continue
+		}
+		if have[eventKey{models.ProcessSpawned, info.PID}] {
+			continue
+		}
+		resp.Events = append(resp.Events, &models.ProcessEvent{
+			Type:        models.ProcessSpawned,
+			PID:         info.PID,
+			PPID:        info.PPID,
+			Command:     info.Command,
+			FullCommand: info.FullCommand,
+			Username:    info.Username,
+			Time:        max(startWall(scan.starts[i]), prev.At),
+			MemoryKB:    info.MemoryKB,
+			Source:      models.ProcessEventsScan,
+		})
+	}
+	for pid, start := range previous {
+		if i, ok := current[pid]; ok && scan.starts[i] == start {
+			continue
+		}
+		if have[eventKey{models.ProcessExited, pid}] {
+			continue
+		}
+		e := &models.ProcessEvent{
+			Type:   models.ProcessExited,
+			PID:    pid,
+			Time:   scan.at,
+			Source: models.ProcessEventsScan,
+		}
+		if s, goneAt, ok := history.lookupExited(pid, start, false); ok {
+			e.Time = goneAt
+			fillExit(e, s, startWall, numCPU)
+		}
+		resp.Events = append(resp.Events, e)
+	}
+
+	sort.Slice(resp.Events, func(i, j int) bool {
+		a, b := resp.Events[i], resp.Events[j]
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		return a.PID < b.PID
+	})
+	return resp, nil
+}
+
+// fillExit adds what the last sighting of a process knew to its exit
+// event. A process seen only once has no last interval to measure, so its
+// CPU is the average over its life.
+func fillExit(e *models.ProcessEvent, s procSighting, startWall func(int64) int64, numCPU float64) {
+	if e.Command == "" {
+		e.Command, e.FullCommand = s.name, s.cmdline
+	}
+	if e.Username == "" {
+		e.Username = s.username
+	}
+	if e.PPID == 0 {
+		e.PPID = s.ppid
+	}
+	e.MemoryKB = s.memKB
+	started := startWall(s.start)
+	if e.Runtime == 0 && started > 0 && s.at > started {
+		e.Runtime = float64(s.at-started) / 1000
+	}
+	switch {
+	case s.cpu > 0:
+		e.CPU = s.cpu
+	case e.CPU == 0 && e.Runtime > 0:
+		e.CPU = min(s.ticks/e.Runtime/numCPU*100, 100)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package gops
+
+import "github.com/AvengeMedia/dgop/models"
+
+// procConnector stands in for Linux's proc connector, which this platform
+// lacks, so process events always come from scans.
+type procConnector struct{}
+
+func startProcConnector(FileSystem, func(int64) int64) *procConnector {
+	return nil
+}
+
+func (c *procConnector) eventsBetween(_, _ int64) ([]*models.ProcessEvent, bool) {
+	return nil, false
+}
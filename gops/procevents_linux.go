@@ -0,0 +1,370 @@
+//go:build linux
+
+package gops
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AvengeMedia/dankgo/log"
+	"github.com/AvengeMedia/dgop/models"
+	"golang.org/x/sys/unix"
+)
+
+// The proc connector's netlink protocol, from linux/connector.h and
+// linux/cn_proc.h.
+const (
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+
+	procEventNone = 0x00000000
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventComm = 0x00000200
+	procEventExit = 0x80000000
+
+	// cnMsgLen is the size of struct cn_msg, and procEventHeaderLen of the
+	// fields of struct proc_event before its event_data.
+	cnMsgLen           = 20
+	procEventHeaderLen = 16
+)
+
+const (
+	// connectorRetention is how long connector events wait for a
+	// GetProcessEvents call to pick them up.
+	connectorRetention = 2 * time.Minute
+	maxConnectorEvents = 4096
+	// connectorAckTimeout bounds the wait for the kernel to accept the
+	// subscription. Without CAP_NET_ADMIN it may never answer.
+	connectorAckTimeout = 2 * time.Second
+)
+
+// procConnector listens to the kernel's proc connector, which reports every
+// fork, exec and exit as it happens, so it catches processes too short-lived
+// for any scan to see.
+type procConnector struct {
+	fs        FileSystem
+	startWall func(int64) int64
+
+	mu sync.Mutex
+	// from is when the kernel confirmed the subscription, in Unix
+	// milliseconds; zero until then, and after the connector fails.
+	from   int64
+	events []*models.ProcessEvent
+}
+
+// startProcConnector subscribes to the proc connector, or returns nil when
+// netlink isn't available. The kernel's answer arrives asynchronously, so
+// events are only trusted once it has accepted.
+func startProcConnector(fs FileSystem, startWall func(int64) int64) *procConnector {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		log.Debug("proc connector unavailable", "error", err)
+		return nil
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		log.Debug("proc connector unavailable", "error", err)
+		_ = unix.Close(fd)
+		return nil
+	}
+	timeout := unix.NsecToTimeval(connectorAckTimeout.Nanoseconds())
+	_ = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout)
+	if _, err := unix.Write(fd, connectorSubscribe()); err != nil {
+		log.Debug("proc connector unavailable", "error", err)
+		_ = unix.Close(fd)
+		return nil
+	}
+
+	c := &procConnector{fs: fs, startWall: startWall}
+	go c.run(fd)
+	return c
+}
+
+// connectorSubscribe builds the netlink message asking for proc events.
+func connectorSubscribe() []byte {
+	msg := make([]byte, unix.NLMSG_HDRLEN+cnMsgLen+4)
+	ne := binary.NativeEndian
+	ne.PutUint32(msg[0:], uint32(len(msg)))
+	ne.PutUint16(msg[4:], unix.NLMSG_DONE)
+	ne.PutUint32(msg[12:], uint32(unix.Getpid()))
+	cn := msg[unix.NLMSG_HDRLEN:]
+	ne.PutUint32(cn[0:], cnIdxProc)
+	ne.PutUint32(cn[4:], cnValProc)
+	ne.PutUint16(cn[16:], 4)
+	ne.PutUint32(cn[cnMsgLen:], procCnMcastListen)
+	return msg
+}
+
+func (c *procConnector) run(fd int) {
+	defer func() { _ = unix.Close(fd) }()
+	buf := make([]byte, 1<<16)
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		switch {
+		case errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.ENOBUFS):
+			// Events were dropped; the scan diff covers for them.
+			continue
+		case err != nil:
+			log.Debug("proc connector stopped", "error", err)
+			c.fail()
+			return
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, msg := range msgs {
+			ev, ok := parseProcEvent(msg.Data)
+			if !ok {
+				continue
+			}
+			if ev.what == procEventNone {
+				if ev.ackErr != 0 {
+					log.Debug("proc connector refused", "error", unix.Errno(ev.ackErr))
+					c.fail()
+					return
+				}
+				c.accept(fd)
+				continue
+			}
+			c.handle(ev)
+		}
+	}
+}
+
+func (c *procConnector) accept(fd int) {
+	var none unix.Timeval
+	_ = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &none)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.from == 0 {
+		c.from = time.Now().UnixMilli()
+	}
+}
+
+func (c *procConnector) fail() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.from = 0
+	c.events = nil
+}
+
+// procEvent is the part of a struct proc_event dgop uses. pid and tgid are
+// the child's for a fork, and ppid is the parent's tgid.
+type procEvent struct {
+	what       uint32
+	pid, tgid  int32
+	ppid       int32
+	exitStatus uint32
+	ackErr     uint32
+	comm       string
+}
+
+// parseProcEvent parses the cn_msg carrying a struct proc_event.
+func parseProcEvent(data []byte) (procEvent, bool) {
+	var ev procEvent
+	const body = cnMsgLen + procEventHeaderLen
+	if len(data) < body+4 {
+		return ev, false
+	}
+	ne := binary.NativeEndian
+	if ne.Uint32(data[0:]) != cnIdxProc || ne.Uint32(data[4:]) != cnValProc {
+		return ev, false
+	}
+	ev.what = ne.Uint32(data[cnMsgLen:])
+	field := func(i int) (uint32, bool) {
+		off := body + 4*i
+		if len(data) < off+4 {
+			return 0, false
+		}
+		return ne.Uint32(data[off:]), true
+	}
+	switch ev.what {
+	case procEventNone:
+		ev.ackErr, _ = field(0)
+		return ev, true
+	case procEventFork:
+		parent, ok1 := field(1)
+		pid, ok2 := field(2)
+		tgid, ok3 := field(3)
+		ev.ppid, ev.pid, ev.tgid = int32(parent), int32(pid), int32(tgid)
+		return ev, ok1 && ok2 && ok3
+	case procEventExec, procEventComm, procEventExit:
+		pid, ok1 := field(0)
+		tgid, ok2 := field(1)
+		ev.pid, ev.tgid = int32(pid), int32(tgid)
+		switch ev.what {
+		case procEventExec:
+			return ev, ok1 && ok2
+		case procEventComm:
+			const commLen = 16
+			if len(data) < body+8+commLen {
+				return ev, false
+			}
+			comm := data[body+8 : body+8+commLen]
+			if i := bytes.IndexByte(comm, 0); i >= 0 {
+				comm = comm[:i]
+			}
+			ev.comm = string(comm)
+			return ev, true
+		}
+		status, ok3 := field(2)
+		ev.exitStatus = status
+		// The parent arrived in 4.18.
+		if parent, ok := field(5); ok {
+			ev.ppid = int32(parent)
+		}
+		return ev, ok1 && ok2 && ok3
+	}
+	return ev, false
+}
+
+// handle records an event of a process; the other threads' are skipped.
+// The details are read from /proc straight away: at the exit event the
+// process is a zombie, whose stat still has its CPU time. A process its
+// parent reaps first keeps the name from the comm event exec sends.
+func (c *procConnector) handle(ev procEvent) {
+	if ev.pid != ev.tgid {
+		return
+	}
+	now := time.Now().UnixMilli()
+	dir := "/proc/" + strconv.Itoa(int(ev.pid))
+
+	switch ev.what {
+	case procEventFork:
+		c.add(&models.ProcessEvent{
+			Type:   models.ProcessSpawned,
+			PID:    ev.pid,
+			PPID:   ev.ppid,
+			Time:   now,
+			Source: models.ProcessEventsNetlink,
+		}, now)
+	case procEventComm:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if e := c.lastSpawned(ev.pid); e != nil {
+			e.Command = ev.comm
+		}
+	case procEventExec:
+		cmdline, _ := c.fs.ReadFile(dir + "/cmdline")
+		comm, _ := c.fs.ReadFile(dir + "/comm")
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		e := c.lastSpawned(ev.pid)
+		if e == nil || len(cmdline) == 0 {
+			return
+		}
+		if len(comm) == 0 {
+			comm = []byte(e.Command)
+		}
+		e.Command, e.FullCommand = procName(string(trimNewline(comm)), cmdline), joinCmdline(cmdline)
+	case procEventExit:
+		e := &models.ProcessEvent{
+			Type:   models.ProcessExited,
+			PID:    ev.pid,
+			PPID:   ev.ppid,
+			Time:   now,
+			Source: models.ProcessEventsNetlink,
+		}
+		if sig := int(ev.exitStatus & 0x7f); sig != 0 {
+			e.Signal = sig
+		} else {
+			code := int(ev.exitStatus>>8) & 0xff
+			e.ExitCode = &code
+		}
+		// The fork event times a process seen from its start better than
+		// its stat, which is only as precise as the boot time.
+		var ticks float64
+		if data, err := c.fs.ReadFile(dir + "/stat"); err == nil {
+			if stat, err := parsePIDStat(data); err == nil {
+				e.Command = stat.comm
+				ticks = float64(stat.utime+stat.stime) / userHZ
+				if started := c.startWall(int64(stat.starttime)); started > 0 && now > started {
+					e.Runtime = float64(now-started) / 1000
+				}
+			}
+		}
+		c.mu.Lock()
+		if s := c.lastSpawned(ev.pid); s != nil {
+			if s.Command != "" {
+				e.Command, e.FullCommand = s.Command, s.FullCommand
+			}
+			e.Runtime = float64(now-s.Time) / 1000
+		}
+		c.mu.Unlock()
+		if e.Runtime > 0 {
+			e.CPU = min(ticks/e.Runtime/float64(runtime.NumCPU())*100, 100)
+		}
+		c.add(e, now)
+	}
+}
+
+// lastSpawned returns the latest spawned event of pid, or nil. c.mu must
+// be held.
+func (c *procConnector) lastSpawned(pid int32) *models.ProcessEvent {
+	for i := len(c.events) - 1; i >= 0; i-- {
+		if e := c.events[i]; e.PID == pid && e.Type == models.ProcessSpawned {
+			return e
+		}
+	}
+	return nil
+}
+
+func trimNewline(b []byte) []byte {
+	if n := len(b); n > 0 && b[n-1] == '\n' {
+		return b[:n-1]
+	}
+	return b
+}
+
+// add appends e, dropping events older than connectorRetention or beyond
+// maxConnectorEvents.
+func (c *procConnector) add(e *models.ProcessEvent, now int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.from == 0 {
+		return
+	}
+	c.events = append(c.events, e)
+	cutoff := now - connectorRetention.Milliseconds()
+	drop := max(len(c.events)-maxConnectorEvents, 0)
+	for drop < len(c.events) && c.events[drop].Time < cutoff {
+		drop++
+	}
+	if drop > 0 {
+		// Calls from before the dropped events can't be covered any more.
+		c.from = max(c.from, c.events[drop-1].Time)
+		// A shrinking prefix would keep the array from ever being freed.
+		c.events = append([]*models.ProcessEvent(nil), c.events[drop:]...)
+	}
+}
+
+// eventsBetween returns copies of the events after since and up to until,
+// and whether the connector was listening for all of that time.
+func (c *procConnector) eventsBetween(since, until int64) ([]*models.ProcessEvent, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.from == 0 || c.from > since {
+		return nil, false
+	}
+	var out []*models.ProcessEvent
+	for _, e := range c.events {
+		if e.Time > since && e.Time <= until {
+			copied := *e
+			out = append(out, &copied)
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,167 @@
+//go:build linux
+
+package gops
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestGetProcessEvents(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":     fixtureMeminfo,
+		"proc/stat":        "cpu  100 0 100 1000 0 0 0 0 0 0\nbtime 1754900000\n",
+		"proc/uptime":      "1000.00 500.00\n",
+		"proc/1/stat":      fixtureStat(1, 0, "systemd", 'S', 4194560),
+		"proc/1/statm":     "5000 3000 0 0 0 0 0\n",
+		"proc/1/status":    "Name:\tsystemd\nUid:\t0\t0\t0\t0\n",
+		"proc/1/cmdline":   "/sbin/init\x00",
+		"proc/100/stat":    fixtureStat(100, 1, "make", 'R', 4194560),
+		"proc/100/statm":   "2000 1000 0 0 0 0 0\n",
+		"proc/100/status":  "Name:\tmake\nUid:\t0\t0\t0\t0\n",
+		"proc/100/cmdline": "make\x00-j8\x00",
+	})
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	first, err := g.GetProcessEvents(t.Context(), "")
+	require.NoError(t, err)
+	assert.Empty(t, first.Events, "the first call only takes a baseline")
+	assert.Equal(t, models.ProcessEventsScan, first.Source)
+	require.NotEmpty(t, first.Cursor)
+
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "proc/100")))
+	writeFixture(t, root, map[string]string{
+		"proc/200/stat":    fixtureStat(200, 1, "cc1", 'R', 4194560),
+		"proc/200/statm":   "4000 2000 0 0 0 0 0\n",
+		"proc/200/status":  "Name:\tcc1\nUid:\t0\t0\t0\t0\n",
+		"proc/200/cmdline": "cc1\x00main.c\x00",
+	})
+	time.Sleep(5 * time.Millisecond)
+
+	second, err := g.GetProcessEvents(t.Context(), first.Cursor)
+	require.NoError(t, err)
+	require.Len(t, second.Events, 2)
+	byType := make(map[models.ProcessEventType]*models.ProcessEvent)
+	for _, e := range second.Events {
+		byType[e.Type] = e
+	}
+
+	spawned := byType[models.ProcessSpawned]
+	require.NotNil(t, spawned)
+	assert.Equal(t, int32(200), spawned.PID)
+	assert.Equal(t, int32(1), spawned.PPID)
+	assert.Equal(t, "cc1", spawned.Command)
+	assert.Equal(t, "cc1 main.c", spawned.FullCommand)
+	assert.Equal(t, uint64(8000), spawned.MemoryKB)
+
+	exited := byType[models.ProcessExited]
+	require.NotNil(t, exited)
+	assert.Equal(t, int32(100), exited.PID)
+	assert.Equal(t, int32(1), exited.PPID, "kept from the last sighting")
+	assert.Equal(t, "make", exited.Command)
+	assert.Equal(t, "make -j8", exited.FullCommand)
+	assert.Equal(t, "root", exited.Username)
+	assert.Equal(t, uint64(4000), exited.MemoryKB)
+	assert.Positive(t, exited.Runtime)
+	assert.Nil(t, exited.ExitCode, "only the proc connector sees exit codes")
+
+	third, err := g.GetProcessEvents(t.Context(), second.Cursor)
+	require.NoError(t, err)
+	assert.Empty(t, third.Events)
+
+	_, err = g.GetProcessEvents(t.Context(), "garbage")
+	var cursorErr *CursorError
+	assert.ErrorAs(t, err, &cursorErr)
+}
+
+func TestParseProcEvent(t *testing.T) {
+	ne := binary.NativeEndian
+	msg := func(what uint32, fields ...uint32) []byte {
+		data := make([]byte, cnMsgLen+procEventHeaderLen+4*len(fields))
+		ne.PutUint32(data[0:], cnIdxProc)
+		ne.PutUint32(data[4:], cnValProc)
+		ne.PutUint32(data[cnMsgLen:], what)
+		for i, f := range fields {
+			ne.PutUint32(data[cnMsgLen+procEventHeaderLen+4*i:], f)
+		}
+		return data
+	}
+
+	ev, ok := parseProcEvent(msg(procEventFork, 10, 10, 42, 42))
+	require.True(t, ok)
+	assert.Equal(t, procEvent{what: procEventFork, pid: 42, tgid: 42, ppid: 10}, ev)
+
+	// Killed by SIGKILL, with the parent fields of 4.18 and later.
+	ev, ok = parseProcEvent(msg(procEventExit, 42, 42, 9, 9, 10, 10))
+	require.True(t, ok)
+	assert.Equal(t, procEvent{what: procEventExit, pid: 42, tgid: 42, ppid: 10, exitStatus: 9}, ev)
+
+	// Older kernels leave the parent out.
+	ev, ok = parseProcEvent(msg(procEventExit, 42, 42, 1<<8, 0))
+	require.True(t, ok)
+	assert.Equal(t, int32(0), ev.ppid)
+
+	comm := msg(procEventComm, 42, 42, 0, 0, 0, 0)
+	copy(comm[cnMsgLen+procEventHeaderLen+8:], "cc1")
+	ev, ok = parseProcEvent(comm)
+	require.True(t, ok)
+	assert.Equal(t, procEvent{what: procEventComm, pid: 42, tgid: 42, comm: "cc1"}, ev)
+
+	ev, ok = parseProcEvent(msg(procEventNone, uint32(unix.EPERM)))
+	require.True(t, ok)
+	assert.Equal(t, uint32(unix.EPERM), ev.ackErr)
+
+	_, ok = parseProcEvent(msg(procEventFork, 10, 10))
+	assert.False(t, ok, "truncated")
+	_, ok = parseProcEvent([]byte{1, 2, 3})
+	assert.False(t, ok)
+}
+
+func TestConnectorEventsBetween(t *testing.T) {
+	var missing *procConnector
+	_, covered := missing.eventsBetween(0, 100)
+	assert.False(t, covered)
+
+	c := &procConnector{}
+	c.add(&models.ProcessEvent{Type: models.ProcessSpawned, PID: 1, Time: 50}, 50)
+	_, covered = c.eventsBetween(0, 100)
+	assert.False(t, covered, "nothing is kept before the kernel accepts")
+
+	c.from = 10
+	c.add(&models.ProcessEvent{Type: models.ProcessSpawned, PID: 2, Time: 50}, 50)
+	c.add(&models.ProcessEvent{Type: models.ProcessExited, PID: 2, Time: 80}, 80)
+	_, covered = c.eventsBetween(5, 100)
+	assert.False(t, covered, "the interval began before the subscription")
+
+	events, covered := c.eventsBetween(50, 100)
+	require.True(t, covered)
+	require.Len(t, events, 1)
+	events[0].Command = "changed"
+	assert.Empty(t, c.events[1].Command, "callers get copies")
+
+	later := connectorRetention.Milliseconds() + 60
+	c.add(&models.ProcessEvent{Type: models.ProcessSpawned, PID: 3, Time: later}, later)
+	require.Len(t, c.events, 2)
+	_, covered = c.eventsBetween(20, later)
+	assert.False(t, covered, "dropped events can't be covered")
+	_, covered = c.eventsBetween(50, later)
+	assert.True(t, covered)
+}
+
+func TestConnectorSubscribe(t *testing.T) {
+	msg := connectorSubscribe()
+	require.Len(t, msg, unix.NLMSG_HDRLEN+cnMsgLen+4)
+	assert.Equal(t, uint32(len(msg)), binary.NativeEndian.Uint32(msg))
+	assert.Equal(t, uint32(procCnMcastListen), binary.NativeEndian.Uint32(msg[unix.NLMSG_HDRLEN+cnMsgLen:]))
+}
@@ -0,0 +1,248 @@
+package gops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ProcFilter picks the processes a scan returns. Its terms are checked as
+// early as their fields are read, so a process the filter drops costs as
+// few reads as possible: PIDs and subtrees before anything is read, kernel
+// threads, state and a memory floor after the counters, and names, users
+// and cgroups before the usage that's only known once CPU is sampled.
+type ProcFilter struct {
+	expr       string
+	pids       map[int32]bool
+	trees      []int32
+	users      map[string]bool
+	states     map[string]bool
+	name       *regexp.Regexp
+	cmdline    *regexp.Regexp
+	cgroup     *regexp.Regexp
+	minCPU     float64
+	minMem     float64
+	hideKernel bool
+}
+
+// ParseProcFilter parses a filter expression: space-separated terms that
+// must all hold, each a field, an operator and a value.
+//
+//	user=alice,bob     owned by one of the users
+//	name~regexp        the command name matches
+//	cmd~regexp         the full command line matches
+//	pid=1,2,3          one of the PIDs
+//	tree=PID[,PID]     the PID or one of its descendants
+//	cgroup~regexp      the cgroup path matches (Linux)
+//	state=R,D          in one of the ps state letters
+//	cpu>=N             using at least N% CPU, which turns CPU sampling on
+//	mem>=N             using at least N% of memory
+//	kthreads=false     hide kernel threads
+//
+// Regular expressions use Go's syntax and can't hold spaces; \s stands in.
+// An empty expression gives a nil filter, which keeps everything.
+func ParseProcFilter(expr string) (*ProcFilter, error) {
+	terms := strings.Fields(expr)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	f := &ProcFilter{expr: strings.Join(terms, " ")}
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		i := strings.IndexAny(term, "=~>")
+		if i <= 0 {
+			return nil, fmt.Errorf("filter term %q: expected field, operator and value", term)
+		}
+		field, op, value := term[:i], term[i:i+1], term[i+1:]
+		if op == ">" {
+			if !strings.HasPrefix(value, "=") {
+				return nil, fmt.Errorf("filter term %q: use >= for a minimum", term)
+			}
+			op, value = ">=", value[1:]
+		}
+		if value == "" {
+			return nil, fmt.Errorf("filter term %q: missing value", term)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("filter field %q given twice", field)
+		}
+		seen[field] = true
+		if err := f.addTerm(field, op, value); err != nil {
+			return nil, fmt.Errorf("filter term %q: %w", term, err)
+		}
+	}
+	return f, nil
+}
+
+func (f *ProcFilter) addTerm(field, op, value string) error {
+	want := map[string]string{
+		"user": "=", "pid": "=", "tree": "=", "state": "=", "kthreads": "=",
+		"name": "~", "cmd": "~", "cgroup": "~",
+		"cpu": ">=", "mem": ">=",
+	}[field]
+	if want == "" {
+		return fmt.Errorf("unknown field %q (expected user, name, cmd, pid, tree, cgroup, state, cpu, mem or kthreads)", field)
+	}
+	if op != want {
+		return fmt.Errorf("%s takes %s", field, want)
+	}
+
+	var err error
+	switch field {
+	case "user":
+		f.users = make(map[string]bool)
+		for _, u := range strings.Split(value, ",") {
+			f.users[u] = true
+		}
+	case "pid":
+		f.pids = make(map[int32]bool)
+		var pids []int32
+		pids, err = parsePIDList(value)
+		for _, pid := range pids {
+			f.pids[pid] = true
+		}
+	case "tree":
+		f.trees, err = parsePIDList(value)
+	case "state":
+		f.states = make(map[string]bool)
+		for _, s := range strings.Split(value, ",") {
+			if len(s) != 1 {
+				return fmt.Errorf("state %q isn't a single letter", s)
+			}
+			f.states[s] = true
+		}
+	case "kthreads":
+		var show bool
+		show, err = strconv.ParseBool(value)
+		f.hideKernel = !show
+	case "name":
+		f.name, err = regexp.Compile(value)
+	case "cmd":
+		f.cmdline, err = regexp.Compile(value)
+	case "cgroup":
+		f.cgroup, err = regexp.Compile(value)
+	case "cpu":
+		f.minCPU, err = parsePercent(value)
+	case "mem":
+		f.minMem, err = parsePercent(value)
+	}
+	return err
+}
+
+func parsePIDList(s string) ([]int32, error) {
+	var pids []int32
+	for _, part := range strings.Split(s, ",") {
+		pid, err := strconv.ParseInt(part, 10, 32)
+		if err != nil || pid <= 0 {
+			return nil, fmt.Errorf("bad PID %q", part)
+		}
+		pids = append(pids, int32(pid))
+	}
+	return pids, nil
+}
+
+func parsePercent(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || v < 0 || v > 100 {
+		return 0, fmt.Errorf("bad percentage %q", s)
+	}
+	return v, nil
+}
+
+// String returns the filter in canonical form, or "" for a nil filter.
+func (f *ProcFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// needsCPU says whether the filter has a CPU floor.
+func (f *ProcFilter) needsCPU() bool {
+	return f != nil && f.minCPU > 0
+}
+
+// narrow drops the processes the filter's PID and subtree terms rule out,
+// before anything else is read about them. A subtree takes the parent of
+// every process, so it costs a counters read of each.
+func (self *GopsUtil) narrow(ctx context.Context, procs []*process.Process, f *ProcFilter) []*process.Process {
+	if f == nil || (f.pids == nil && f.trees == nil) {
+		return procs
+	}
+	var inTree map[int32]bool
+	if f.trees != nil {
+		children := make(map[int32][]int32)
+		envCtx := self.env.context(ctx)
+		for _, p := range procs {
+			if c, err := self.readProcCounters(envCtx, p); err == nil || c.ppid != 0 {
+				children[c.ppid] = append(children[c.ppid], p.Pid)
+			}
+		}
+		inTree = make(map[int32]bool)
+		queue := slices.Clone(f.trees)
+		for len(queue) > 0 {
+			pid := queue[0]
+			queue = queue[1:]
+			if inTree[pid] {
+				continue
+			}
+			inTree[pid] = true
+			queue = append(queue, children[pid]...)
+		}
+	}
+
+	kept := make([]*process.Process, 0, len(procs))
+	for _, p := range procs {
+		if (f.pids == nil || f.pids[p.Pid]) && (inTree == nil || inTree[p.Pid]) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// matchCounters checks the terms decided by a process's counters. The
+// memory floor is checked against RSS, which no other measure exceeds, and
+// again on the final figure by matchUsage.
+func (f *ProcFilter) matchCounters(c procCounters, rssPercent float32) bool {
+	if f == nil {
+		return true
+	}
+	if f.hideKernel && c.kernel {
+		return false
+	}
+	return float64(rssPercent) >= f.minMem
+}
+
+// filterState checks the state term, reading the state of p only when
+// there is one.
+func (self *GopsUtil) filterState(ctx context.Context, p *process.Process, c procCounters, f *ProcFilter) bool {
+	return f == nil || f.states == nil || f.states[self.procState(ctx, p, c)]
+}
+
+// matchStatic checks the terms decided by a process's static fields.
+func (f *ProcFilter) matchStatic(s procStatic) bool {
+	switch {
+	case f == nil:
+		return true
+	case f.users != nil && !f.users[s.username]:
+		return false
+	case f.name != nil && !f.name.MatchString(s.name):
+		return false
+	case f.cmdline != nil && !f.cmdline.MatchString(s.cmdline):
+		return false
+	case f.cgroup != nil && !f.cgroup.MatchString(s.cgroup):
+		return false
+	}
+	return true
+}
+
+// matchUsage checks the usage floors once a process's CPU is known.
+func (f *ProcFilter) matchUsage(p *models.ProcessInfo) bool {
+	return f == nil || (p.CPU >= f.minCPU && float64(p.MemoryPercent) >= f.minMem)
+}
@@ -0,0 +1,42 @@
+package gops
+
+import (
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcFilter(t *testing.T) {
+	f, err := ParseProcFilter("  ")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+	assert.Empty(t, f.String())
+	assert.True(t, f.matchStatic(procStatic{}), "a nil filter keeps everything")
+
+	f, err = ParseProcFilter("user=alice,bob  name~^fire cpu>=2.5 mem>=1% kthreads=false pid=3,4 tree=1 state=R,D cgroup~app- cmd~--flag")
+	require.NoError(t, err)
+	assert.Equal(t, "user=alice,bob name~^fire cpu>=2.5 mem>=1% kthreads=false pid=3,4 tree=1 state=R,D cgroup~app- cmd~--flag", f.String())
+	assert.True(t, f.needsCPU())
+	assert.Equal(t, map[int32]bool{3: true, 4: true}, f.pids)
+	assert.Equal(t, []int32{1}, f.trees)
+	assert.True(t, f.hideKernel)
+
+	assert.True(t, f.matchStatic(procStatic{username: "bob", name: "firefox", cmdline: "firefox --flag", cgroup: "/app-1.scope"}))
+	assert.False(t, f.matchStatic(procStatic{username: "carol", name: "firefox", cmdline: "firefox --flag", cgroup: "/app-1.scope"}))
+	assert.False(t, f.matchStatic(procStatic{username: "bob", name: "chromium", cmdline: "firefox --flag", cgroup: "/app-1.scope"}))
+	assert.False(t, f.matchCounters(procCounters{kernel: true}, 5))
+	assert.False(t, f.matchCounters(procCounters{}, 0.5))
+	assert.True(t, f.matchCounters(procCounters{}, 1))
+	assert.True(t, f.matchUsage(&models.ProcessInfo{CPU: 3, MemoryPercent: 1}))
+	assert.False(t, f.matchUsage(&models.ProcessInfo{CPU: 2, MemoryPercent: 1}))
+
+	for _, bad := range []string{
+		"user", "user=", "color=red", "name=firefox", "cpu>2", "cpu=2", "cpu>=abc", "mem>=200",
+		"pid=1,x", "pid=0", "tree=-1", "state=RD", "kthreads=maybe", "name~(", "user=a user=b",
+	} {
+		_, err := ParseProcFilter(bad)
+		assert.Error(t, err, bad)
+	}
+}
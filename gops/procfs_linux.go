@@ -0,0 +1,488 @@
+//go:build linux
+
+package gops
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+// Parsers for the /proc files read on every refresh. They work on the raw
+// file contents without splitting it into strings, and report the same
+// values gopsutil does for the same file.
+
+var errProcMalformed = errors.New("malformed proc file")
+
+func isProcSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// cutField returns the first whitespace-separated field of b and the rest.
+func cutField(b []byte) (field, rest []byte) {
+	start := 0
+	for start < len(b) && isProcSpace(b[start]) {
+		start++
+	}
+	end := start
+	for end < len(b) && !isProcSpace(b[end]) {
+		end++
+	}
+	return b[start:end], b[end:]
+}
+
+// parseDecimal parses an unsigned decimal integer.
+func parseDecimal(b []byte) (uint64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	var n uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if n > (math.MaxUint64-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, true
+}
+
+// parseSigned parses a decimal integer with an optional minus sign.
+func parseSigned(b []byte) (int64, bool) {
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+	n, ok := parseDecimal(b)
+	if !ok || n > math.MaxInt64 {
+		return 0, false
+	}
+	if neg {
+		return -int64(n), true
+	}
+	return int64(n), true
+}
+
+// parseFixed parses a non-negative decimal fraction such as a load average.
+func parseFixed(b []byte) (float64, bool) {
+	var mantissa uint64
+	digits, scale := 0, 0
+	dot := false
+	for _, c := range b {
+		switch {
+		case c == '.' && !dot:
+			dot = true
+		case c >= '0' && c <= '9':
+			mantissa = mantissa*10 + uint64(c-'0')
+			digits++
+			if dot {
+				scale++
+			}
+		default:
+			return 0, false
+		}
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	if digits > 15 {
+		// Past float64's exact integers; rare enough to take the slow path.
+		v, err := strconv.ParseFloat(string(b), 64)
+		return v, err == nil
+	}
+	// Both operands are exact, so the division rounds once, like ParseFloat.
+	return float64(mantissa) / math.Pow10(scale), true
+}
+
+// pidStat holds the fields of /proc/<pid>/stat dgop uses.
+type pidStat struct {
+	comm       string
+	state      byte
+	ppid       int32
+	flags      uint64 // PF_* bits
+	utime      uint64 // clock ticks
+	stime      uint64 // clock ticks
+	priority   int32
+	nice       int32
+	numThreads int32
+	starttime  uint64 // clock ticks since boot
+	processor  int32  // CPU last run on
+}
+
+// parsePIDStat parses /proc/<pid>/stat.
+func parsePIDStat(b []byte) (pidStat, error) {
+	var s pidStat
+	// comm can hold spaces and parentheses, so it ends at the last ')'.
+	open := bytes.IndexByte(b, '(')
+	end := bytes.LastIndexByte(b, ')')
+	if open < 0 || end < open {
+		return s, errProcMalformed
+	}
+	s.comm = string(b[open+1 : end])
+
+	rest := b[end+1:]
+	var field []byte
+	for i := 0; i <= 36; i++ {
+		field, rest = cutField(rest)
+		if len(field) == 0 {
+			// processor arrived with 2.2; the rest dgop needs is older.
+			if i > 19 {
+				break
+			}
+			return s, errProcMalformed
+		}
+		var ok bool
+		switch i {
+		case 0:
+			s.state, ok = field[0], len(field) == 1
+		case 1:
+			var v int64
+			v, ok = parseSigned(field)
+			s.ppid = int32(v)
+		case 6:
+			s.flags, ok = parseDecimal(field)
+		case 11:
+			s.utime, ok = parseDecimal(field)
+		case 12:
+			s.stime, ok = parseDecimal(field)
+		case 15:
+			var v int64
+			v, ok = parseSigned(field)
+			s.priority = int32(v)
+		case 16:
+			var v int64
+			v, ok = parseSigned(field)
+			s.nice = int32(v)
+		case 17:
+			var v int64
+			v, ok = parseSigned(field)
+			s.numThreads = int32(v)
+		case 19:
+			s.starttime, ok = parseDecimal(field)
+		case 36:
+			var v int64
+			v, ok = parseSigned(field)
+			s.processor = int32(v)
+		default:
+			ok = true
+		}
+		if !ok {
+			return s, errProcMalformed
+		}
+	}
+	return s, nil
+}
+
+// pidStatm holds /proc/<pid>/statm, in pages.
+type pidStatm struct {
+	size     uint64
+	resident uint64
+	shared   uint64
+	text     uint64
+	data     uint64
+}
+
+// parsePIDStatm parses /proc/<pid>/statm.
+func parsePIDStatm(b []byte) (pidStatm, error) {
+	var s pidStatm
+	// size resident shared text lib data dt; lib and dt are always 0.
+	dst := [...]*uint64{&s.size, &s.resident, &s.shared, &s.text, nil, &s.data}
+	for _, p := range dst {
+		var field []byte
+		field, b = cutField(b)
+		v, ok := parseDecimal(field)
+		if !ok {
+			return s, errProcMalformed
+		}
+		if p != nil {
+			*p = v
+		}
+	}
+	return s, nil
+}
+
+// pidStatus holds the fields of /proc/<pid>/status dgop uses.
+type pidStatus struct {
+	name    string
+	uid     uint32 // real UID
+	hasUID  bool
+	threads int32
+	// voluntaryCtxt and nonvoluntaryCtxt count context switches.
+	voluntaryCtxt    uint64
+	nonvoluntaryCtxt uint64
+}
+
+// parsePIDStatus parses /proc/<pid>/status.
+func parsePIDStatus(b []byte) pidStatus {
+	var s pidStatus
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		key, value, ok := bytes.Cut(line, []byte{':'})
+		if !ok {
+			continue
+		}
+		switch string(key) {
+		case "Name":
+			s.name = string(bytes.Trim(value, " \t"))
+		case "Uid":
+			field, _ := cutField(value)
+			if v, ok := parseDecimal(field); ok && v <= math.MaxUint32 {
+				s.uid, s.hasUID = uint32(v), true
+			}
+		case "Threads":
+			field, _ := cutField(value)
+			if v, ok := parseSigned(field); ok {
+				s.threads = int32(v)
+			}
+		case "voluntary_ctxt_switches":
+			field, _ := cutField(value)
+			s.voluntaryCtxt, _ = parseDecimal(field)
+		case "nonvoluntary_ctxt_switches":
+			field, _ := cutField(value)
+			s.nonvoluntaryCtxt, _ = parseDecimal(field)
+		}
+	}
+	return s
+}
+
+// parseSmapsRollup parses /proc/<pid>/smaps_rollup. ok is false when none
+// of its fields were found.
+func parseSmapsRollup(b []byte) (r models.SmapsRollup, ok bool) {
+	fields := map[string]*uint64{
+		"Rss": &r.RSS, "Pss": &r.PSS, "Pss_Dirty": &r.PSSDirty, "Pss_Anon": &r.PSSAnon,
+		"Pss_File": &r.PSSFile, "Pss_Shmem": &r.PSSShmem, "Shared_Clean": &r.SharedClean,
+		"Shared_Dirty": &r.SharedDirty, "Private_Clean": &r.PrivateClean,
+		"Private_Dirty": &r.PrivateDirty, "Referenced": &r.Referenced,
+		"Anonymous": &r.Anonymous, "LazyFree": &r.LazyFree, "AnonHugePages": &r.AnonHugePages,
+		"ShmemPmdMapped": &r.ShmemPmdMapped, "FilePmdMapped": &r.FilePmdMapped,
+		"Shared_Hugetlb": &r.SharedHugetlb, "Private_Hugetlb": &r.PrivateHugetlb,
+		"Swap": &r.Swap, "SwapPss": &r.SwapPSS, "Locked": &r.Locked,
+	}
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		key, value, found := bytes.Cut(line, []byte{':'})
+		if !found {
+			continue
+		}
+		dst := fields[string(key)]
+		if dst == nil {
+			continue
+		}
+		field, _ := cutField(value)
+		if v, valid := parseDecimal(field); valid {
+			*dst = v
+			ok = true
+		}
+	}
+	return r, ok
+}
+
+// procName extends a name that status truncated to 15 bytes from the first
+// argument of cmdline, as gopsutil does.
+func procName(name string, cmdline []byte) string {
+	if len(name) < 15 {
+		return name
+	}
+	arg0, _, _ := bytes.Cut(bytes.TrimRight(cmdline, "\x00"), []byte{0})
+	if len(arg0) == 0 {
+		return name
+	}
+	if base := filepath.Base(string(arg0)); len(base) >= len(name) && base[:len(name)] == name {
+		return base
+	}
+	return name
+}
+
+// joinCmdline turns the NUL-separated /proc/<pid>/cmdline into one line,
+// dropping empty arguments as gopsutil does.
+func joinCmdline(b []byte) string {
+	out := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		var arg []byte
+		arg, b, _ = bytes.Cut(b, []byte{0})
+		if len(arg) == 0 {
+			continue
+		}
+		if len(out) > 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, arg...)
+	}
+	return string(out)
+}
+
+// procLoadAvg holds /proc/loadavg.
+type procLoadAvg struct {
+	load1, load5, load15 float64
+	// running and total count scheduling entities, so threads.
+	running, total int
+	lastPID        int
+}
+
+// parseLoadAvg parses /proc/loadavg.
+func parseLoadAvg(b []byte) (procLoadAvg, error) {
+	var s procLoadAvg
+	for _, p := range [...]*float64{&s.load1, &s.load5, &s.load15} {
+		var field []byte
+		field, b = cutField(b)
+		v, ok := parseFixed(field)
+		if !ok {
+			return s, errProcMalformed
+		}
+		*p = v
+	}
+
+	field, b := cutField(b)
+	running, total, ok := bytes.Cut(field, []byte{'/'})
+	r, okRunning := parseDecimal(running)
+	t, okTotal := parseDecimal(total)
+	if !ok || !okRunning || !okTotal {
+		return s, errProcMalformed
+	}
+	s.running, s.total = int(r), int(t)
+
+	field, _ = cutField(b)
+	last, ok := parseDecimal(field)
+	if !ok {
+		return s, errProcMalformed
+	}
+	s.lastPID = int(last)
+	return s, nil
+}
+
+// parseCPUTimes parses the cpu lines of /proc/stat: the first line for the
+// total, or the per-CPU lines that follow it. Lines gopsutil would reject
+// are skipped.
+func parseCPUTimes(b []byte, perCPU bool) []cpu.TimesStat {
+	var out []cpu.TimesStat
+	for first := true; len(b) > 0; first = false {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		if first {
+			if !perCPU {
+				if t, ok := parseCPULine(line); ok {
+					out = append(out, t)
+				}
+				return out
+			}
+			continue
+		}
+		if !bytes.HasPrefix(line, []byte("cpu")) {
+			break
+		}
+		if t, ok := parseCPULine(line); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func parseCPULine(line []byte) (cpu.TimesStat, bool) {
+	var t cpu.TimesStat
+	name, rest := cutField(line)
+	if !bytes.HasPrefix(name, []byte("cpu")) {
+		return t, false
+	}
+	t.CPU = string(name)
+	if t.CPU == "cpu" {
+		t.CPU = "cpu-total"
+	}
+
+	// user nice system idle iowait irq softirq are always there; steal,
+	// guest and guest_nice arrived with later kernels.
+	dst := [...]*float64{&t.User, &t.Nice, &t.System, &t.Idle, &t.Iowait, &t.Irq, &t.Softirq, &t.Steal, &t.Guest, &t.GuestNice}
+	for i, p := range dst {
+		var field []byte
+		field, rest = cutField(rest)
+		if len(field) == 0 {
+			return t, i >= 7
+		}
+		v, ok := parseDecimal(field)
+		if !ok {
+			return t, false
+		}
+		*p = float64(v) / userHZ
+	}
+	return t, true
+}
+
+// parseMiscStat parses the counters at the end of /proc/stat.
+func parseMiscStat(b []byte) load.MiscStat {
+	var s load.MiscStat
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		key, rest := cutField(line)
+		value, rest := cutField(rest)
+		if extra, _ := cutField(rest); len(extra) > 0 {
+			continue
+		}
+		v, ok := parseSigned(value)
+		if !ok {
+			continue
+		}
+		switch string(key) {
+		case "processes":
+			s.ProcsCreated = int(v)
+		case "procs_running":
+			s.ProcsRunning = int(v)
+		case "procs_blocked":
+			s.ProcsBlocked = int(v)
+		case "ctxt":
+			s.Ctxt = int(v)
+		}
+	}
+	return s
+}
+
+// listPIDs returns the numeric entries of the proc directory.
+func listPIDs(procDir string) ([]int32, error) {
+	dir, err := os.Open(procDir)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int32, 0, len(names))
+	for _, name := range names {
+		if pid, err := strconv.ParseInt(name, 10, 32); err == nil {
+			pids = append(pids, int32(pid))
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,332 @@
+//go:build linux
+
+package gops
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	samplePIDStat   = "4242 (Web Content) S 1200 1200 1200 0 -1 4194560 51236 0 12 0 9071 1402 0 0 20 0 31 0 8641130 3058847744 76032 18446744073709551615 1 1 0 0 0 0 0 4096 17663 0 0 0 17 3 0 0 0 0 0 0 0 0 0 0 0 0 0\n"
+	samplePIDStatm  = "746789 76032 23553 162 0 105620 0\n"
+	samplePIDStatus = "Name:\tWeb Content\nUmask:\t0022\nState:\tS (sleeping)\nTgid:\t4242\nPid:\t4242\nPPid:\t1200\nUid:\t1000\t1000\t1000\t1000\nGid:\t1000\t1000\t1000\t1000\nThreads:\t31\n"
+	sampleLoadAvg   = "0.52 1.04 12.30 3/1234 56789\n"
+	sampleStat      = `cpu  117450 5582 35190 3489120 4020 0 1620 0 0 0
+cpu0 29021 1290 8898 871843 1057 0 1120 0 0 0
+cpu1 29480 1416 8769 872608 984 0 233 0 0 0
+intr 3910125 9 0 0 0 0 0 0 0 1 0 0 0 0 0 0 0 0 0 0 0 0
+ctxt 10462870
+btime 1754900000
+processes 56123
+procs_running 3
+procs_blocked 1
+softirq 2080120 4 527118 28 21290 96441 0 1296 771020 0 662923
+`
+)
+
+func TestParsePIDStat(t *testing.T) {
+	s, err := parsePIDStat([]byte(samplePIDStat))
+	require.NoError(t, err)
+	assert.Equal(t, pidStat{
+		comm:       "Web Content",
+		state:      'S',
+		ppid:       1200,
+		flags:      4194560,
+		utime:      9071,
+		stime:      1402,
+		priority:   20,
+		numThreads: 31,
+		starttime:  8641130,
+		processor:  3,
+	}, s)
+
+	odd, err := parsePIDStat([]byte(strings.Replace(samplePIDStat, "(Web Content)", "(a) (b)", 1)))
+	require.NoError(t, err)
+	assert.Equal(t, "a) (b", odd.comm)
+	assert.Equal(t, int32(1200), odd.ppid)
+
+	for _, bad := range []string{"", "4242 (x", "4242 (x) S 1", "4242 (x) S one 1200"} {
+		_, err := parsePIDStat([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParsePIDStatmAndStatus(t *testing.T) {
+	statm, err := parsePIDStatm([]byte(samplePIDStatm))
+	require.NoError(t, err)
+	assert.Equal(t, pidStatm{size: 746789, resident: 76032, shared: 23553, text: 162, data: 105620}, statm)
+	_, err = parsePIDStatm([]byte("1 2 3\n"))
+	assert.Error(t, err)
+
+	status := parsePIDStatus([]byte(samplePIDStatus))
+	assert.Equal(t, pidStatus{name: "Web Content", uid: 1000, hasUID: true, threads: 31}, status)
+	assert.Equal(t, pidStatus{}, parsePIDStatus([]byte("garbage")))
+}
+
+func TestProcNameAndCmdline(t *testing.T) {
+	cmdline := []byte("/usr/lib/firefox/firefox-bin\x00-contentproc\x00\x00-isForBrowser\x00")
+	assert.Equal(t, "/usr/lib/firefox/firefox-bin -contentproc -isForBrowser", joinCmdline(cmdline))
+	assert.Equal(t, "", joinCmdline(nil))
+
+	assert.Equal(t, "firefox-bin", procName("firefox-bin", cmdline), "short names are whole")
+	assert.Equal(t, "gnome-shell-calendar-server",
+		procName("gnome-shell-cal", []byte("/usr/libexec/gnome-shell-calendar-server\x00")))
+	assert.Equal(t, "kworker/u16:3-ev", procName("kworker/u16:3-ev", nil))
+	assert.Equal(t, "systemd-journal", procName("systemd-journal", []byte("/bin/other\x00")))
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	avg, err := parseLoadAvg([]byte(sampleLoadAvg))
+	require.NoError(t, err)
+	assert.Equal(t, procLoadAvg{load1: 0.52, load5: 1.04, load15: 12.30, running: 3, total: 1234, lastPID: 56789}, avg)
+
+	for _, bad := range []string{"", "0.52 1.04", "0.52 1.04 12.30 3 56789", "0.52 x 12.30 3/1234 56789"} {
+		_, err := parseLoadAvg([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseCPUTimes(t *testing.T) {
+	total := parseCPUTimes([]byte(sampleStat), false)
+	require.Len(t, total, 1)
+	assert.Equal(t, "cpu-total", total[0].CPU)
+	assert.Equal(t, 1174.5, total[0].User)
+	assert.Equal(t, 34891.2, total[0].Idle)
+
+	perCPU := parseCPUTimes([]byte(sampleStat), true)
+	require.Len(t, perCPU, 2)
+	assert.Equal(t, "cpu1", perCPU[1].CPU)
+	assert.Equal(t, 2.33, perCPU[1].Softirq)
+
+	old := parseCPUTimes([]byte("cpu  1 2 3 4 5 6 7\n"), false)
+	require.Len(t, old, 1, "kernels before 2.6.11 have seven columns")
+	assert.Empty(t, parseCPUTimes([]byte("cpu  1 2 3\n"), false))
+
+	misc := parseMiscStat([]byte(sampleStat))
+	assert.Equal(t, load.MiscStat{ProcsCreated: 56123, ProcsRunning: 3, ProcsBlocked: 1, Ctxt: 10462870}, misc)
+}
+
+// The parsers must agree with gopsutil on the live system.
+func TestProcParsersMatchGopsutil(t *testing.T) {
+	pid := int32(os.Getpid())
+	self, err := process.NewProcess(pid)
+	require.NoError(t, err)
+	dir := "/proc/" + strconv.Itoa(int(pid))
+
+	data, err := os.ReadFile(dir + "/stat")
+	require.NoError(t, err)
+	stat, err := parsePIDStat(data)
+	require.NoError(t, err)
+	ppid, err := self.Ppid()
+	require.NoError(t, err)
+	assert.Equal(t, ppid, stat.ppid)
+	times, err := self.Times()
+	require.NoError(t, err)
+	assert.InDelta(t, times.User, float64(stat.utime)/userHZ, 0.5)
+
+	data, err = os.ReadFile(dir + "/status")
+	require.NoError(t, err)
+	cmdline, err := os.ReadFile(dir + "/cmdline")
+	require.NoError(t, err)
+	status := parsePIDStatus(data)
+	name, err := self.Name()
+	require.NoError(t, err)
+	assert.Equal(t, name, procName(status.name, cmdline))
+	uids, err := self.Uids()
+	require.NoError(t, err)
+	assert.Equal(t, uids[0], status.uid)
+	fullCommand, err := self.Cmdline()
+	require.NoError(t, err)
+	assert.Equal(t, fullCommand, joinCmdline(cmdline))
+	username, err := self.Username()
+	require.NoError(t, err)
+	assert.Equal(t, username, lookupUsername(status.uid))
+
+	data, err = os.ReadFile(dir + "/statm")
+	require.NoError(t, err)
+	statm, err := parsePIDStatm(data)
+	require.NoError(t, err)
+	mem, err := self.MemoryInfo()
+	require.NoError(t, err)
+	assert.Equal(t, mem.VMS, statm.size*uint64(os.Getpagesize()))
+
+	want, err := cpu.Times(true)
+	require.NoError(t, err)
+	got, err := cpuTimes(t.Context(), true)
+	require.NoError(t, err)
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.Equal(t, want[i].CPU, got[i].CPU)
+		assert.InDelta(t, want[i].Idle, got[i].Idle, 5)
+	}
+
+	wantAvg, err := load.Avg()
+	require.NoError(t, err)
+	gotAvg, err := loadAvg(t.Context())
+	require.NoError(t, err)
+	assert.InDelta(t, wantAvg.Load15, gotAvg.Load15, 0.05)
+
+	wantMisc, err := load.Misc()
+	require.NoError(t, err)
+	gotMisc, err := loadMisc(t.Context())
+	require.NoError(t, err)
+	assert.InDelta(t, wantMisc.ProcsCreated, gotMisc.ProcsCreated, 100)
+	assert.InDelta(t, wantMisc.ProcsTotal, gotMisc.ProcsTotal, 20)
+}
+
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseUint(t *testing.T, s string) uint64 {
+	t.Helper()
+	v, err := strconv.ParseUint(s, 10, 64)
+	require.NoError(t, err)
+	return v
+}
+
+func FuzzParsePIDStat(f *testing.F) {
+	f.Add([]byte(samplePIDStat))
+	f.Add([]byte("1 ((sd-pam)) S 0 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 3 0 0"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := parsePIDStat(data)
+		if err != nil {
+			return
+		}
+		// A parse that succeeds agrees with splitting on whitespace, which
+		// also splits on Unicode spaces the kernel never writes.
+		rest := string(data[bytes.LastIndexByte(data, ')')+1:])
+		if !isASCII(rest) {
+			return
+		}
+		fields := strings.Fields(rest)
+		assert.Equal(t, mustParseUint(t, fields[11]), s.utime)
+		assert.Equal(t, mustParseUint(t, fields[19]), s.starttime)
+	})
+}
+
+func FuzzParsePIDStatm(f *testing.F) {
+	f.Add([]byte(samplePIDStatm))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := parsePIDStatm(data)
+		if err != nil {
+			return
+		}
+		if !isASCII(string(data)) {
+			return
+		}
+		fields := strings.Fields(string(data))
+		assert.Equal(t, mustParseUint(t, fields[1]), s.resident)
+	})
+}
+
+func FuzzParsePIDStatus(f *testing.F) {
+	f.Add([]byte(samplePIDStatus))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = parsePIDStatus(data)
+		_ = procName(string(data), data)
+		_ = joinCmdline(data)
+	})
+}
+
+func FuzzParseLoadAvg(f *testing.F) {
+	f.Add([]byte(sampleLoadAvg))
+	f.Add([]byte("123456789012345678.5 0 0 0/0 0"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		avg, err := parseLoadAvg(data)
+		if err != nil {
+			return
+		}
+		field, _ := cutField(data)
+		want, err := strconv.ParseFloat(string(field), 64)
+		require.NoError(t, err)
+		assert.Equal(t, want, avg.load1)
+	})
+}
+
+func FuzzParseCPUTimes(f *testing.F) {
+	f.Add([]byte(sampleStat))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = parseCPUTimes(data, false)
+		_ = parseCPUTimes(data, true)
+		_ = parseMiscStat(data)
+	})
+}
+
+func FuzzParseDecimal(f *testing.F) {
+	f.Add([]byte("18446744073709551615"))
+	f.Add([]byte("-12"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got, ok := parseDecimal(data)
+		want, err := strconv.ParseUint(string(data), 10, 64)
+		require.Equal(t, err == nil, ok, "%q", data)
+		if ok {
+			assert.Equal(t, want, got)
+		}
+	})
+}
+
+func BenchmarkParsePIDStat(b *testing.B) {
+	data := []byte(samplePIDStat)
+	b.ReportAllocs()
+	for range b.N {
+		_, _ = parsePIDStat(data)
+	}
+}
+
+func BenchmarkParsePIDStatus(b *testing.B) {
+	data := []byte(samplePIDStatus)
+	b.ReportAllocs()
+	for range b.N {
+		_ = parsePIDStatus(data)
+	}
+}
+
+func BenchmarkParseCPUTimes(b *testing.B) {
+	data := []byte(sampleStat)
+	b.ReportAllocs()
+	for range b.N {
+		_ = parseCPUTimes(data, true)
+	}
+}
+
+// BenchmarkCPUTimes compares reading /proc/stat against gopsutil.
+func BenchmarkCPUTimes(b *testing.B) {
+	b.Run("dgop", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_, _ = cpuTimes(b.Context(), true)
+		}
+	})
+	b.Run("gopsutil", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_, _ = cpu.Times(true)
+		}
+	})
+}
+
+// BenchmarkSystemInfo is dominated by counting threads, which used to open
+// every process.
+func BenchmarkSystemInfo(b *testing.B) {
+	g := NewGopsUtil()
+	b.ReportAllocs()
+	for range b.N {
+		_, _ = g.collectSystemInfo(b.Context())
+	}
+}
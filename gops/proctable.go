@@ -0,0 +1,115 @@
+package gops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// procCounters are the fields of a process that change while it runs,
+// reread on every scan.
+type procCounters struct {
+	ppid int32
+	// comm is the short command name, which changes when the process
+	// execs another program.
+	comm string
+	// start is when the process started, in a platform-specific unit. It
+	// only tells a reused PID apart from the process that had it before.
+	start int64
+	// cpu is user plus system time in seconds.
+	cpu float64
+	// rss is the resident set in bytes.
+	rss uint64
+	// state is the ps state letter, where the platform reads it with the
+	// counters; see procState.
+	state string
+	// kernel marks a kernel thread.
+	kernel bool
+}
+
+// procStatic holds the fields of a process that don't change while it runs.
+type procStatic struct {
+	name     string
+	cmdline  string
+	username string
+	exe      string
+	// appID is the desktop app the process was launched as, from its
+	// cgroup or sandbox, where the platform records it.
+	appID string
+	// cgroup is the process's cgroup path, where the platform has them.
+	cgroup string
+}
+
+type procTableEntry struct {
+	start  int64
+	comm   string
+	static procStatic
+}
+
+// procTable remembers the static fields of each process between scans, so
+// a long-running caller only rereads the counters. Entries are keyed by PID
+// and start time, so a reused PID is read afresh, and a changed comm marks
+// a process that has exec'd since.
+type procTable struct {
+	mu      sync.Mutex
+	entries map[int32]procTableEntry
+}
+
+// EnableProcessTable keeps a process table between process scans. It suits
+// callers that scan repeatedly, such as the server, watch mode and the TUI;
+// a one-off scan gains nothing from it.
+func (self *GopsUtil) EnableProcessTable() {
+	self.procTable.CompareAndSwap(nil, &procTable{entries: make(map[int32]procTableEntry)})
+}
+
+// readProcStatic returns the static fields of p, from the process table
+// when it has them. counters come from the same scan, and known is false
+// when they couldn't be read, so there's nothing to key the entry on.
+func (self *GopsUtil) readProcStatic(ctx context.Context, p *process.Process, counters procCounters, known bool) procStatic {
+	table := self.procTable.Load()
+	if table != nil && known {
+		if static, ok := table.get(p.Pid, counters); ok {
+			return static
+		}
+	}
+
+	static := self.collectProcStatic(ctx, p)
+	if table != nil && known {
+		table.put(p.Pid, counters, static)
+	}
+	return static
+}
+
+func (t *procTable) get(pid int32, c procCounters) (procStatic, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[pid]
+	if !ok || e.start != c.start || e.comm != c.comm {
+		return procStatic{}, false
+	}
+	return e.static, true
+}
+
+func (t *procTable) put(pid int32, c procCounters, static procStatic) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[pid] = procTableEntry{start: c.start, comm: c.comm, static: static}
+}
+
+// retain drops the entries of processes that are gone.
+func (t *procTable) retain(live map[int32]struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for pid := range t.entries {
+		if _, ok := live[pid]; !ok {
+			delete(t.entries, pid)
+		}
+	}
+}
+
+func (t *procTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
@@ -0,0 +1,87 @@
+//go:build linux
+
+package gops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func procStatLine(comm string, start string) string {
+	return "4242 (" + comm + ") S 1 4242 4242 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 " + start + " 1000000 300\n"
+}
+
+func TestProcessTableRereadsOnlyChangedProcesses(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":      fixtureMeminfo,
+		"proc/4242/stat":    procStatLine("worker", "12345"),
+		"proc/4242/statm":   "2000 300 100 1 0 200 0\n",
+		"proc/4242/status":  "Name:\tworker\nUid:\t0\t0\t0\t0\n",
+		"proc/4242/cmdline": "worker\x00--first\x00",
+	})
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+	g.EnableProcessTable()
+
+	scan := func() *processScan {
+		t.Helper()
+		procs, err := g.procProvider.Processes(t.Context())
+		require.NoError(t, err)
+		require.Len(t, procs, 1)
+		s, err := g.scanProcessList(t.Context(), procs, false, nil)
+		require.NoError(t, err)
+		return s
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "proc/4242", name), []byte(content), 0o644))
+	}
+
+	first := scan().infos[0]
+	assert.Equal(t, "worker --first", first.FullCommand)
+	assert.Equal(t, 3.0, first.PTicks)
+	assert.Equal(t, uint64(300*os.Getpagesize()/1024), first.RSSKB)
+
+	// Counters are reread, static fields come from the table.
+	write("cmdline", "worker\x00--second\x00")
+	write("stat", strings.Replace(procStatLine("worker", "12345"), " 250 50 ", " 350 50 ", 1))
+	next := scan().infos[0]
+	assert.Equal(t, "worker --first", next.FullCommand)
+	assert.Equal(t, 4.0, next.PTicks)
+
+	// A reused PID has a new start time.
+	write("stat", procStatLine("worker", "99999"))
+	assert.Equal(t, "worker --second", scan().infos[0].FullCommand)
+
+	// An exec changes comm.
+	write("cmdline", "other\x00")
+	write("stat", procStatLine("other", "99999"))
+	assert.Equal(t, "other", scan().infos[0].FullCommand)
+
+	_, err := g.scanProcessList(t.Context(), nil, false, nil)
+	require.NoError(t, err)
+	assert.Zero(t, g.procTable.Load().len(), "exited processes leave the table")
+}
+
+func TestReadProcCountersHandlesOddComm(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":   fixtureMeminfo,
+		"proc/4242/stat": procStatLine("a) (b", "7"),
+	})
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	c, err := g.readProcCounters(g.env.context(t.Context()), &process.Process{Pid: 4242})
+	require.NoError(t, err)
+	assert.Equal(t, "a) (b", c.comm)
+	assert.Equal(t, int32(1), c.ppid)
+	assert.Equal(t, int64(7), c.start)
+	assert.Zero(t, c.rss, "statm is missing")
+}
@@ -0,0 +1,69 @@
+package gops
+
+import (
+	"os"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcTableKeysOnStartAndComm(t *testing.T) {
+	table := &procTable{entries: make(map[int32]procTableEntry)}
+	counters := procCounters{start: 100, comm: "bash"}
+	table.put(42, counters, procStatic{name: "bash"})
+
+	_, ok := table.get(42, counters)
+	assert.True(t, ok)
+	_, ok = table.get(42, procCounters{start: 200, comm: "bash"})
+	assert.False(t, ok, "a reused PID starts later")
+	_, ok = table.get(42, procCounters{start: 100, comm: "vim"})
+	assert.False(t, ok, "an exec changes comm")
+
+	table.retain(map[int32]struct{}{7: {}})
+	assert.Zero(t, table.len())
+}
+
+func TestReadProcCountersMatchesGopsutil(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	g := NewGopsUtil()
+	counters, err := g.readProcCounters(g.env.context(t.Context()), self)
+	require.NoError(t, err)
+
+	ppid, err := self.Ppid()
+	require.NoError(t, err)
+	times, err := self.Times()
+	require.NoError(t, err)
+	mem, err := self.MemoryInfo()
+	require.NoError(t, err)
+
+	assert.Equal(t, ppid, counters.ppid)
+	assert.InDelta(t, times.User+times.System, counters.cpu, 0.5)
+	assert.InDelta(t, float64(mem.RSS), float64(counters.rss), float64(mem.RSS)/10)
+}
+
+func benchmarkScanProcesses(b *testing.B, table bool) {
+	g := NewGopsUtil()
+	if table {
+		g.EnableProcessTable()
+	}
+	procs, err := g.procProvider.Processes(b.Context())
+	require.NoError(b, err)
+	_, err = g.scanProcessList(b.Context(), procs, false, nil)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_, _ = g.scanProcessList(b.Context(), procs, false, nil)
+	}
+	b.ReportMetric(float64(len(procs)), "procs")
+}
+
+func BenchmarkScanProcesses(b *testing.B) {
+	b.Run("rescan", func(b *testing.B) { benchmarkScanProcesses(b, false) })
+	b.Run("table", func(b *testing.B) { benchmarkScanProcesses(b, true) })
+}
@@ -0,0 +1,57 @@
+package gops
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+var signalsByName = map[string]syscall.Signal{
+	"TERM": syscall.SIGTERM,
+	"KILL": syscall.SIGKILL,
+	"INT":  syscall.SIGINT,
+	"HUP":  syscall.SIGHUP,
+	"STOP": syscall.SIGSTOP,
+	"CONT": syscall.SIGCONT,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+}
+
+// SignalNames lists the signals accepted by ParseSignal.
+func SignalNames() []string {
+	names := make([]string, 0, len(signalsByName))
+	for name := range signalsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSignal accepts a signal name with or without the SIG prefix, e.g.
+// "TERM" or "SIGKILL".
+func ParseSignal(name string) (syscall.Signal, error) {
+	name = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "SIG")
+	sig, ok := signalsByName[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown signal %q (expected one of %s)", name, strings.Join(SignalNames(), ", "))
+	}
+	return sig, nil
+}
+
+// SignalName returns the short name of sig as used by ParseSignal.
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signalsByName {
+		if s == sig {
+			return name
+		}
+	}
+	return sig.String()
+}
+
+func (self *GopsUtil) SignalProcess(pid int32, sig syscall.Signal) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid pid %d", pid)
+	}
+	return syscall.Kill(int(pid), sig)
+}
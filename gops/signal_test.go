@@ -0,0 +1,24 @@
+package gops
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"TERM", "term", "SIGTERM", " sigterm "} {
+		sig, err := ParseSignal(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, syscall.SIGTERM, sig)
+	}
+
+	sig, err := ParseSignal("KILL")
+	require.NoError(t, err)
+	assert.Equal(t, "KILL", SignalName(sig))
+
+	_, err = ParseSignal("SIGSEGV")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package gops
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+// RootFileSystem reads a system tree captured under Root as if it were
+// mounted at /. Collectors keep using absolute paths such as /proc/meminfo.
+type RootFileSystem struct {
+	Root string
+}
+
+func (r *RootFileSystem) path(name string) string {
+	return filepath.Join(r.Root, name)
+}
+
+func (r *RootFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(r.path(name))
+}
+
+func (r *RootFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(r.path(name))
+}
+
+func (r *RootFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(r.path(name))
+}
+
+// Readlink returns the link target unchanged; sysfs links are relative, and
+// callers only look at their last elements.
+func (r *RootFileSystem) Readlink(name string) (string, error) {
+	return os.Readlink(r.path(name))
+}
+
+// Glob returns matches as paths inside the tree, without Root.
+func (r *RootFileSystem) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(r.path(pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i, match := range matches {
+		rel, err := filepath.Rel(r.Root, match)
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = "/" + filepath.ToSlash(rel)
+	}
+	return matches, nil
+}
+
+// sysrootEnv points gopsutil's HOST_* environment into root.
+func sysrootEnv(root string) common.EnvMap {
+	return common.EnvMap{
+		common.HostProcEnvKey: filepath.Join(root, "proc"),
+		common.HostSysEnvKey:  filepath.Join(root, "sys"),
+		common.HostEtcEnvKey:  filepath.Join(root, "etc"),
+		common.HostVarEnvKey:  filepath.Join(root, "var"),
+		common.HostRunEnvKey:  filepath.Join(root, "run"),
+		common.HostDevEnvKey:  filepath.Join(root, "dev"),
+		common.HostRootEnvKey: root,
+	}
+}
+
+// UseSysroot makes every collector read /proc, /sys, /etc and the other
+// system trees under root, such as a fixture captured from another machine.
+// It replaces the providers and file system, so call it before collecting
+// anything. An empty root or "/" keeps the real system.
+//
+// Filesystem usage (statfs) and external commands such as nvidia-smi still
+// come from the running system.
+func (self *GopsUtil) UseSysroot(root string) error {
+	if root == "" || root == "/" {
+		return nil
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(filepath.Join(abs, "proc")); err != nil || !info.IsDir() {
+		return fmt.Errorf("sysroot %s has no proc directory", root)
+	}
+
+	env := gopsutilEnv{env: sysrootEnv(abs)}
+	self.cpuProvider = &DefaultCPUInfoProvider{env}
+	self.memProvider = &DefaultMemoryInfoProvider{env}
+	self.diskProvider = &DefaultDiskInfoProvider{env}
+	self.netProvider = &DefaultNetworkInfoProvider{env}
+	self.procProvider = &DefaultProcessInfoProvider{env}
+	self.hostProvider = &DefaultHostInfoProvider{env}
+	self.loadProvider = &DefaultLoadInfoProvider{env}
+	self.fs = &RootFileSystem{Root: abs}
+	self.env = env
+
+	// Cursors belong to the tree's host and boot, not this machine's.
+	self.signer.mu.Lock()
+	self.signer.loaded = false
+	self.signer.mu.Unlock()
+	return nil
+}
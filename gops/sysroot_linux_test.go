@@ -0,0 +1,80 @@
+//go:build linux
+
+package gops
+
+import (
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureMeminfo = `MemTotal:       16384000 kB
+MemFree:         4096000 kB
+MemAvailable:    8192000 kB
+Buffers:          512000 kB
+Cached:          2048000 kB
+SwapCached:            0 kB
+Shmem:            128000 kB
+SReclaimable:     256000 kB
+SwapTotal:       2048000 kB
+SwapFree:        1024000 kB
+GPUActive:         64000 kB
+GPUReclaim:        32000 kB
+`
+
+func TestSysroot(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/meminfo":                            fixtureMeminfo,
+		"proc/cpuinfo":                            "processor\t: 0\ncpu MHz\t\t: 1234.500\n",
+		"sys/class/hwmon/hwmon3/name":             "k10temp\n",
+		"sys/class/hwmon/hwmon3/temp1_input":      "61500\n",
+		"sys/bus/pci/devices/0000:03:00.0/class":  "0x030000\n",
+		"sys/bus/pci/devices/0000:03:00.0/vendor": "0x1002\n",
+		"sys/bus/pci/devices/0000:03:00.0/device": "0x73bf\n",
+		"sys/bus/pci/devices/0000:00:1f.0/class":  "0x060100\n",
+		"etc/os-release":                          "NAME=Fixture\nPRETTY_NAME=\"Fixture Linux\"\n",
+	})
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	mem, err := g.GetMemoryInfo(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(16384000), mem.Total)
+	assert.Equal(t, uint64(64000), mem.GPUActive)
+	assert.Equal(t, uint64(2048000), mem.SwapTotal)
+
+	cpuTracker.tempPath = ""
+	assert.Equal(t, 61.5, g.getCPUTemperatureCached())
+	assert.Equal(t, "/sys/class/hwmon/hwmon3/temp1_input", cpuTracker.tempPath, "paths stay inside the tree")
+	cpuTracker.tempPath = ""
+	assert.Equal(t, 1234.5, g.getCurrentCPUFreq())
+
+	gpus, err := g.detectGPUEntries(t.Context())
+	require.NoError(t, err)
+	require.Len(t, gpus, 1)
+	assert.Equal(t, "AMD", gpus[0].Vendor)
+	assert.Contains(t, gpus[0].RawLine, "0000:03:00.0")
+
+	assert.Equal(t, "Fixture Linux", g.getDistroName(t.Context()))
+}
+
+func TestSysrootWithoutPCIBusIsNotFound(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{"proc/meminfo": fixtureMeminfo})
+
+	g := NewGopsUtil()
+	require.NoError(t, g.UseSysroot(root))
+
+	_, err := g.GetGPUInfo(t.Context())
+	require.Error(t, err)
+	assert.Equal(t, models.ModuleErrorNotFound, moduleError(err).Code)
+}
+
+func TestUseSysrootRequiresProc(t *testing.T) {
+	assert.Error(t, NewGopsUtil().UseSysroot(t.TempDir()))
+	assert.NoError(t, NewGopsUtil().UseSysroot("/"))
+}
@@ -1,35 +1,33 @@
 package gops
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/AvengeMedia/dgop/models"
 	"github.com/shirou/gopsutil/v4/host"
-	"github.com/shirou/gopsutil/v4/load"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
-func (self *GopsUtil) GetSystemInfo() (*models.SystemInfo, error) {
-	// System info
-	loadAvg, _ := load.Avg()
-	procs, _ := process.Pids()
-	bootTime, _ := host.BootTime()
+func (self *GopsUtil) GetSystemInfo(ctx context.Context) (*models.SystemInfo, error) {
+	return cachedModule(ctx, self, "system", "", time.Time{}, self.collectSystemInfo)
+}
 
-	// Count threads (approximation - gopsutil doesn't expose this directly)
-	threadCount := 0
-	for _, p := range procs {
-		proc, err := process.NewProcess(p)
-		if err == nil {
-			threads, _ := proc.NumThreads()
-			threadCount += int(threads)
-		}
+func (self *GopsUtil) collectSystemInfo(ctx context.Context) (*models.SystemInfo, error) {
+	// System info
+	loadAvg, err := self.loadProvider.Avg(ctx)
+	if err != nil {
+		return nil, err
 	}
+	envCtx := self.env.context(ctx)
+	procs, _ := process.PidsWithContext(envCtx)
+	bootTime, _ := host.BootTimeWithContext(envCtx)
 
 	return &models.SystemInfo{
 		LoadAvg:   fmt.Sprintf("%.2f %.2f %.2f", loadAvg.Load1, loadAvg.Load5, loadAvg.Load15),
 		Processes: len(procs),
-		Threads:   threadCount,
+		Threads:   self.countThreads(ctx, procs),
 		BootTime:  time.Unix(int64(bootTime), 0).Format("2006-01-02 15:04:05"),
 	}, nil
 }
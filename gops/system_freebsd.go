@@ -0,0 +1,22 @@
+//go:build freebsd
+
+package gops
+
+import (
+	"context"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// countThreads sums the thread counts of pids.
+func (self *GopsUtil) countThreads(ctx context.Context, pids []int32) int {
+	ctx = self.env.context(ctx)
+	threadCount := 0
+	for _, pid := range pids {
+		proc, err := process.NewProcessWithContext(ctx, pid)
+		if err == nil {
+			threads, _ := proc.NumThreadsWithContext(ctx)
+			threadCount += int(threads)
+		}
+	}
+	return threadCount
+}
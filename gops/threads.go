@@ -0,0 +1,93 @@
+package gops
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// threadSample is one thread's counters, read at a millisecond timestamp.
+type threadSample struct {
+	pid     int32
+	tid     int32
+	name    string
+	state   string
+	ticks   float64 // CPU seconds
+	lastCPU int32
+	at      int64
+}
+
+// GetThreads lists the threads of pid, or of every process when pid is 0,
+// busiest first. CPU usage is measured since cursor, which uses the process
+// cursor's format keyed by TID; without one it's measured over a short
+// pause. A PID that doesn't exist gives an error matching fs.ErrNotExist.
+func (self *GopsUtil) GetThreads(ctx context.Context, pid int32, limit int, cursor string) (*models.ThreadListResponse, error) {
+	var payload string
+	if cursor != "" {
+		var err error
+		if payload, err = self.openCursor("threads", cursor); err != nil {
+			return nil, err
+		}
+	}
+	cursorMap, err := decodeProcessCursor(payload)
+	if err != nil {
+		return nil, &CursorError{Module: "threads", Err: err}
+	}
+
+	samples, err := self.readThreads(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	if len(cursorMap) == 0 {
+		for _, s := range samples {
+			cursorMap[s.tid] = &models.ProcessCursorData{PID: s.tid, Ticks: s.ticks, Timestamp: s.at}
+		}
+		select {
+		case <-time.After(cpuBaselineInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if samples, err = self.readThreads(ctx, pid); err != nil {
+			return nil, err
+		}
+	}
+
+	numCPU := float64(runtime.NumCPU())
+	threads := make([]*models.ThreadInfo, 0, len(samples))
+	entries := make([]models.ProcessCursorData, 0, len(samples))
+	for _, s := range samples {
+		t := &models.ThreadInfo{
+			PID:     s.pid,
+			TID:     s.tid,
+			Name:    s.name,
+			State:   s.state,
+			PTicks:  s.ticks,
+			LastCPU: s.lastCPU,
+		}
+		if c, ok := cursorMap[s.tid]; ok {
+			t.CPU = min(calculateProcessCPUPercentageWithCursor(c, s.ticks, s.at)/numCPU, 100)
+		}
+		threads = append(threads, t)
+		entries = append(entries, models.ProcessCursorData{PID: s.tid, Ticks: s.ticks, Timestamp: s.at})
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		if threads[i].CPU != threads[j].CPU {
+			return threads[i].CPU > threads[j].CPU
+		}
+		return threads[i].TID < threads[j].TID
+	})
+	// The cursor covers every thread, so ones below the limit still get a
+	// rate next time.
+	if limit > 0 && len(threads) > limit {
+		threads = threads[:limit]
+	}
+
+	return &models.ThreadListResponse{
+		Threads: threads,
+		Cursor:  self.sealCursor("threads", encodeProcessCursor(entries)),
+	}, nil
+}
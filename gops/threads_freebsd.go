@@ -0,0 +1,13 @@
+//go:build freebsd
+
+package gops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (self *GopsUtil) readThreads(_ context.Context, _ int32) ([]threadSample, error) {
+	return nil, fmt.Errorf("per-thread counters need Linux's /proc: %w", errors.ErrUnsupported)
+}
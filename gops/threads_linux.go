@@ -0,0 +1,77 @@
+//go:build linux
+
+package gops
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// readTaskStats reads the stat file of each thread under dir/task. Threads
+// that exit mid-read are left out.
+func (self *GopsUtil) readTaskStats(dir string) (map[int32]pidStat, error) {
+	tasks, err := self.fs.ReadDir(dir + "/task")
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[int32]pidStat, len(tasks))
+	for _, task := range tasks {
+		tid, err := strconv.ParseInt(task.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		data, err := self.fs.ReadFile(dir + "/task/" + task.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		if stat, err := parsePIDStat(data); err == nil {
+			stats[int32(tid)] = stat
+		}
+	}
+	return stats, nil
+}
+
+func (self *GopsUtil) readThreads(ctx context.Context, pid int32) ([]threadSample, error) {
+	pids := []int32{pid}
+	if pid == 0 {
+		entries, err := self.fs.ReadDir("/proc")
+		if err != nil {
+			return nil, err
+		}
+		pids = pids[:0]
+		for _, e := range entries {
+			if n, err := strconv.ParseInt(e.Name(), 10, 32); err == nil {
+				pids = append(pids, int32(n))
+			}
+		}
+	}
+
+	var samples []threadSample
+	for _, p := range pids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stats, err := self.readTaskStats("/proc/" + strconv.Itoa(int(p)))
+		if err != nil {
+			// Processes that exit mid-scan only matter when asked for.
+			if pid != 0 {
+				return nil, err
+			}
+			continue
+		}
+		at := time.Now().UnixMilli()
+		for tid, stat := range stats {
+			samples = append(samples, threadSample{
+				pid:     p,
+				tid:     tid,
+				name:    stat.comm,
+				state:   string(stat.state),
+				ticks:   float64(stat.utime+stat.stime) / userHZ,
+				lastCPU: stat.processor,
+				at:      at,
+			})
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package gops
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func threadStat(tid int, name string, state byte, ticks, processor int) string {
+	return fmt.Sprintf("%d (%s) %c 1 1 1 0 -1 0 0 0 0 0 %d 0 0 0 20 0 2 0 100 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 17 %d 0\n",
+		tid, name, state, ticks, processor)
+}
+
+func TestGetThreads(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"proc/4242/task/4242/stat": threadStat(4242, "java", 'S', 100, 0),
+		"proc/4242/task/4250/stat": threadStat(4250, "GC Thread#0", 'R', 500, 3),
+		"proc/77/task/77/stat":     threadStat(77, "sshd", 'S', 10, 1),
+	})
+	g := NewGopsUtil()
+	g.fs = &RootFileSystem{Root: root}
+
+	first, err := g.GetThreads(t.Context(), 4242, 0, "")
+	require.NoError(t, err)
+	require.Len(t, first.Threads, 2)
+	assert.Equal(t, int32(4242), first.Threads[0].TID, "idle threads are ordered by TID")
+	gc := first.Threads[1]
+	assert.Equal(t, int32(4242), gc.PID)
+	assert.Equal(t, "GC Thread#0", gc.Name)
+	assert.Equal(t, "R", gc.State)
+	assert.Equal(t, int32(3), gc.LastCPU)
+	assert.Equal(t, 5.0, gc.PTicks)
+	assert.Zero(t, gc.CPU)
+
+	// The GC thread burns CPU until the next poll.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "proc/4242/task/4250/stat"), []byte(threadStat(4250, "GC Thread#0", 'R', 520, 2)), 0o644))
+	// Rates need the clock to move on from the cursor's millisecond.
+	time.Sleep(2 * time.Millisecond)
+	next, err := g.GetThreads(t.Context(), 4242, 1, first.Cursor)
+	require.NoError(t, err)
+	require.Len(t, next.Threads, 1)
+	assert.Equal(t, int32(4250), next.Threads[0].TID, "the busiest thread comes first")
+	assert.Positive(t, next.Threads[0].CPU)
+	payload, err := g.openCursor("threads", next.Cursor)
+	require.NoError(t, err)
+	entries, err := decodeProcessCursor(payload)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "the cursor covers threads past the limit")
+
+	all, err := g.GetThreads(t.Context(), 0, 0, next.Cursor)
+	require.NoError(t, err)
+	assert.Len(t, all.Threads, 3)
+
+	_, err = g.GetThreads(t.Context(), 999, 0, "")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = g.GetThreads(t.Context(), 4242, 0, "bogus")
+	assert.True(t, IsCursorError(err))
+}
@@ -0,0 +1,67 @@
+package gops
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultModuleTimeouts are how long GetMeta waits for each module before
+// reporting it as timed out. processes and cpu include the sampling pause
+// taken when the caller has no cursor.
+var DefaultModuleTimeouts = map[string]time.Duration{
+	"cpu":         2 * time.Second,
+	"memory":      2 * time.Second,
+	"network":     2 * time.Second,
+	"net-rate":    2 * time.Second,
+	"disk":        2 * time.Second,
+	"disk-rate":   2 * time.Second,
+	"diskmounts":  5 * time.Second,
+	"processes":   5 * time.Second,
+	"system":      2 * time.Second,
+	"hardware":    5 * time.Second,
+	"gpu":         5 * time.Second,
+	"gpu-temp":    5 * time.Second,
+	"units":       5 * time.Second,
+	"proc-events": 5 * time.Second,
+	"anomalies":   5 * time.Second,
+}
+
+// ValidateModuleTimeouts checks that timeouts only names modules and that
+// every timeout is positive.
+func ValidateModuleTimeouts(timeouts map[string]time.Duration) error {
+	for module, timeout := range timeouts {
+		if _, ok := DefaultModuleTimeouts[module]; !ok {
+			return fmt.Errorf("unknown module %q (expected one of %s)", module, strings.Join(slices.Sorted(maps.Keys(DefaultModuleTimeouts)), ", "))
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("%s: timeout must be positive", module)
+		}
+	}
+	return nil
+}
+
+// SetModuleTimeouts overrides DefaultModuleTimeouts for the modules in
+// timeouts. It can be called again at any time.
+func (self *GopsUtil) SetModuleTimeouts(timeouts map[string]time.Duration) error {
+	if err := ValidateModuleTimeouts(timeouts); err != nil {
+		return err
+	}
+	merged := maps.Clone(DefaultModuleTimeouts)
+	maps.Copy(merged, timeouts)
+	self.timeouts.Store(&merged)
+	return nil
+}
+
+func (self *GopsUtil) moduleTimeout(module string) time.Duration {
+	timeouts := DefaultModuleTimeouts
+	if t := self.timeouts.Load(); t != nil {
+		timeouts = *t
+	}
+	if timeout, ok := timeouts[module]; ok {
+		return timeout
+	}
+	return 5 * time.Second
+}
@@ -0,0 +1,252 @@
+package gops
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// unitProperties are the unit metadata read with systemctl show.
+const unitProperties = "Id,Description,ActiveState,SubState,ControlGroup,NRestarts,MainPID"
+
+type UnitSortBy string
+
+const (
+	UnitSortByCPU    UnitSortBy = "cpu"
+	UnitSortByMemory UnitSortBy = "memory"
+	UnitSortByIO     UnitSortBy = "io"
+	UnitSortByName   UnitSortBy = "name"
+)
+
+// ParseUnitSortBy checks s against the UnitSortBy values; empty means
+// UnitSortByMemory.
+func ParseUnitSortBy(s string) (UnitSortBy, error) {
+	switch UnitSortBy(s) {
+	case "":
+		return UnitSortByMemory, nil
+	case UnitSortByCPU, UnitSortByMemory, UnitSortByIO, UnitSortByName:
+		return UnitSortBy(s), nil
+	}
+	return "", fmt.Errorf("unknown sort %q (expected cpu, memory, io or name)", s)
+}
+
+// Register enum in OpenAPI specification
+func (u UnitSortBy) Schema(r huma.Registry) *huma.Schema {
+	if r.Map()["UnitSortBy"] == nil {
+		schemaRef := r.Schema(reflect.TypeOf(""), true, "UnitSortBy")
+		schemaRef.Title = "UnitSortBy"
+		schemaRef.Enum = append(schemaRef.Enum, []any{
+			string(UnitSortByCPU),
+			string(UnitSortByMemory),
+			string(UnitSortByIO),
+			string(UnitSortByName),
+		}...)
+		r.Map()["UnitSortBy"] = schemaRef
+	}
+	return &huma.Schema{Ref: "#/components/schemas/UnitSortBy"}
+}
+
+// UnitsCursor holds each unit's counters from the previous call, keyed by
+// unit name.
+type UnitsCursor struct {
+	Timestamp time.Time               `json:"timestamp"`
+	Units     map[string]UnitCounters `json:"units"`
+}
+
+type UnitCounters struct {
+	CPUSeconds float64 `json:"cpu"`
+	ReadBytes  uint64  `json:"rbytes"`
+	WriteBytes uint64  `json:"wbytes"`
+}
+
+// GetUnits lists the running systemd services and scopes with the usage of
+// their cgroups. CPU and I/O rates are measured from cursor; a unit that
+// restarted since has its counters reset, so it reports zero until the
+// next call.
+func (self *GopsUtil) GetUnits(ctx context.Context, sortBy UnitSortBy, limit int, cursorStr string) (*models.UnitsResponse, error) {
+	var cursor UnitsCursor
+	if cursorStr != "" {
+		payload, err := self.openCursor("units", cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		if cursor, err = parseUnitsCursor(payload); err != nil {
+			return nil, &CursorError{Module: "units", Err: ErrCursorMalformed}
+		}
+	}
+
+	// The scan may be shared with other callers, so the rates go on copies.
+	scan, err := cachedModule(ctx, self, "units", "", cursor.Timestamp, self.scanUnits)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]*models.UnitInfo, len(scan.units))
+	for i, u := range scan.units {
+		unit := *u
+		units[i] = &unit
+	}
+
+	elapsed := scan.at.Sub(cursor.Timestamp).Seconds()
+	numCPU := float64(runtime.NumCPU())
+	next := UnitsCursor{
+		Timestamp: scan.at,
+		Units:     make(map[string]UnitCounters, len(units)),
+	}
+	for _, u := range units {
+		next.Units[u.Name] = UnitCounters{
+			CPUSeconds: u.CPUSeconds,
+			ReadBytes:  u.IOReadBytes,
+			WriteBytes: u.IOWriteBytes,
+		}
+		prev, ok := cursor.Units[u.Name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		if u.CPUSeconds >= prev.CPUSeconds {
+			u.CPU = min((u.CPUSeconds-prev.CPUSeconds)/elapsed/numCPU*100, 100)
+		}
+		if u.IOReadBytes >= prev.ReadBytes {
+			u.IOReadRate = float64(u.IOReadBytes-prev.ReadBytes) / elapsed
+		}
+		if u.IOWriteBytes >= prev.WriteBytes {
+			u.IOWriteRate = float64(u.IOWriteBytes-prev.WriteBytes) / elapsed
+		}
+	}
+
+	sortUnits(units, sortBy)
+	if limit > 0 && len(units) > limit {
+		units = units[:limit]
+	}
+
+	newCursor, err := encodeUnitsCursor(next)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UnitsResponse{
+		Units:  units,
+		Cursor: self.sealCursor("units", newCursor),
+	}, nil
+}
+
+// unitScan is the units as read at one moment, without rates.
+type unitScan struct {
+	at    time.Time
+	units []*models.UnitInfo
+}
+
+func (self *GopsUtil) scanUnits(ctx context.Context) (*unitScan, error) {
+	at := time.Now()
+	units, err := self.readUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &unitScan{at: at, units: units}, nil
+}
+
+func sortUnits(units []*models.UnitInfo, sortBy UnitSortBy) {
+	switch sortBy {
+	case UnitSortByCPU:
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].CPU > units[j].CPU
+		})
+	case UnitSortByIO:
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].IOReadRate+units[i].IOWriteRate > units[j].IOReadRate+units[j].IOWriteRate
+		})
+	case UnitSortByName:
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].Name < units[j].Name
+		})
+	default:
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].MemoryBytes > units[j].MemoryBytes
+		})
+	}
+}
+
+// parseUnitList returns the unit names in the output of
+// systemctl list-units --plain --no-legend.
+func parseUnitList(out []byte) []string {
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+// parseUnitShow reads the unitProperties of each unit from the output of
+// systemctl show, which separates units with a blank line.
+func parseUnitShow(out []byte) []*models.UnitInfo {
+	var units []*models.UnitInfo
+	var u *models.UnitInfo
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			u = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if u == nil {
+			u = &models.UnitInfo{}
+			units = append(units, u)
+		}
+		switch key {
+		case "Id":
+			u.Name = value
+		case "Description":
+			u.Description = value
+		case "ActiveState":
+			u.ActiveState = value
+		case "SubState":
+			u.SubState = value
+		case "ControlGroup":
+			u.CGroup = value
+		case "NRestarts":
+			u.Restarts, _ = strconv.Atoi(value)
+		case "MainPID":
+			pid, _ := strconv.ParseInt(value, 10, 32)
+			u.MainPID = int32(pid)
+		}
+	}
+	return units
+}
+
+func encodeUnitsCursor(cursor UnitsCursor) (string, error) {
+	jsonData, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(jsonData), nil
+}
+
+func parseUnitsCursor(cursorStr string) (UnitsCursor, error) {
+	var cursor UnitsCursor
+
+	jsonData, err := base64.StdEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return cursor, err
+	}
+
+	err = json.Unmarshal(jsonData, &cursor)
+	return cursor, err
+}
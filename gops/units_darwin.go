@@ -0,0 +1,15 @@
+//go:build darwin
+
+package gops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+func (self *GopsUtil) readUnits(ctx context.Context) ([]*models.UnitInfo, error) {
+	return nil, fmt.Errorf("units need systemd: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,100 @@
+//go:build linux
+
+package gops
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/AvengeMedia/dgop/models"
+)
+
+// cgroupRoot is where the unified (v2) cgroup hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// readUnits asks systemd for the loaded services and scopes that are
+// running or failed, then reads the usage of each from its cgroup.
+func (self *GopsUtil) readUnits(ctx context.Context) ([]*models.UnitInfo, error) {
+	if _, err := self.fs.Stat(path.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("units need the unified cgroup hierarchy: %w", errors.ErrUnsupported)
+		}
+		return nil, err
+	}
+
+	out, err := self.cmd.Execute(ctx, "systemctl", "list-units", "--type=service,scope",
+		"--state=active,activating,deactivating,reloading,failed", "--plain", "--no-legend", "--full")
+	if err != nil {
+		return nil, fmt.Errorf("systemctl list-units: %w", err)
+	}
+	names := parseUnitList(out)
+	if len(names) == 0 {
+		return []*models.UnitInfo{}, nil
+	}
+
+	args := append([]string{"show", "--property=" + unitProperties, "--"}, names...)
+	out, err = self.cmd.Execute(ctx, "systemctl", args...)
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show: %w", err)
+	}
+	units := parseUnitShow(out)
+	for _, u := range units {
+		if u.CGroup != "" {
+			self.readUnitCgroup(u)
+		}
+	}
+	return units, nil
+}
+
+// readUnitCgroup fills in the usage of u from its cgroup. Files for
+// controllers that aren't enabled on the cgroup are missing, leaving those
+// fields zero.
+func (self *GopsUtil) readUnitCgroup(u *models.UnitInfo) {
+	dir := path.Join(cgroupRoot, u.CGroup)
+
+	if data, err := self.fs.ReadFile(path.Join(dir, "cpu.stat")); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if value, ok := strings.CutPrefix(scanner.Text(), "usage_usec "); ok {
+				usec, _ := strconv.ParseUint(value, 10, 64)
+				u.CPUSeconds = float64(usec) / 1e6
+				break
+			}
+		}
+	}
+	u.MemoryBytes = self.readCgroupUint(path.Join(dir, "memory.current"))
+	u.Tasks = self.readCgroupUint(path.Join(dir, "pids.current"))
+
+	// io.stat has a line per device: "8:0 rbytes=1 wbytes=2 rios=3 ...".
+	if data, err := self.fs.ReadFile(path.Join(dir, "io.stat")); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			for _, field := range strings.Fields(scanner.Text()) {
+				key, value, _ := strings.Cut(field, "=")
+				n, _ := strconv.ParseUint(value, 10, 64)
+				switch key {
+				case "rbytes":
+					u.IOReadBytes += n
+				case "wbytes":
+					u.IOWriteBytes += n
+				}
+			}
+		}
+	}
+}
+
+func (self *GopsUtil) readCgroupUint(name string) uint64 {
+	data, err := self.fs.ReadFile(name)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return n
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package gops
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/dgop/gops/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newUnitsTestUtil(t *testing.T) (*GopsUtil, *mocks.MockCommandExecutor) {
+	t.Helper()
+	root := t.TempDir()
+	writeDesktopFiles(t, root, map[string]string{
+		"sys/fs/cgroup/cgroup.controllers":                           "cpu io memory pids\n",
+		"sys/fs/cgroup/system.slice/sshd.service/cpu.stat":           "usage_usec 3000000\nuser_usec 2000000\nsystem_usec 1000000\n",
+		"sys/fs/cgroup/system.slice/sshd.service/memory.current":     "1048576\n",
+		"sys/fs/cgroup/system.slice/sshd.service/pids.current":       "3\n",
+		"sys/fs/cgroup/system.slice/sshd.service/io.stat":            "8:0 rbytes=4096 wbytes=1024 rios=1 wios=1\n259:0 rbytes=4096 wbytes=0 rios=1 wios=0\n",
+		"sys/fs/cgroup/system.slice/postgres.service/cpu.stat":       "usage_usec 1000000\n",
+		"sys/fs/cgroup/system.slice/postgres.service/memory.current": "8388608\n",
+	})
+
+	cmd := mocks.NewMockCommandExecutor(t)
+	cmd.EXPECT().Execute(mock.Anything, "systemctl", "list-units", "--type=service,scope",
+		"--state=active,activating,deactivating,reloading,failed", "--plain", "--no-legend", "--full").
+		Return([]byte("sshd.service loaded active running OpenSSH Daemon\npostgres.service loaded active running PostgreSQL\n"), nil).Maybe()
+	cmd.EXPECT().Execute(mock.Anything, "systemctl", "show", "--property="+unitProperties, "--", "sshd.service", "postgres.service").
+		Return([]byte("Id=sshd.service\nActiveState=active\nControlGroup=/system.slice/sshd.service\nNRestarts=1\n\n"+
+			"Id=postgres.service\nActiveState=active\nControlGroup=/system.slice/postgres.service\nNRestarts=0\n"), nil).Maybe()
+
+	g := NewGopsUtil()
+	g.fs = &RootFileSystem{Root: root}
+	g.cmd = cmd
+	return g, cmd
+}
+
+func TestGetUnitsReadsCgroups(t *testing.T) {
+	g, _ := newUnitsTestUtil(t)
+
+	result, err := g.GetUnits(t.Context(), UnitSortByMemory, 0, "")
+	require.NoError(t, err)
+	require.Len(t, result.Units, 2)
+	assert.NotEmpty(t, result.Cursor)
+
+	postgres, sshd := result.Units[0], result.Units[1]
+	assert.Equal(t, "postgres.service", postgres.Name)
+	assert.Equal(t, uint64(8388608), postgres.MemoryBytes)
+	assert.Zero(t, postgres.Tasks, "pids controller not enabled")
+
+	assert.Equal(t, 3.0, sshd.CPUSeconds)
+	assert.Equal(t, uint64(1048576), sshd.MemoryBytes)
+	assert.Equal(t, uint64(8192), sshd.IOReadBytes)
+	assert.Equal(t, uint64(1024), sshd.IOWriteBytes)
+	assert.Equal(t, uint64(3), sshd.Tasks)
+	assert.Equal(t, 1, sshd.Restarts)
+	assert.Zero(t, sshd.CPU, "no cursor yet")
+
+	limited, err := g.GetUnits(t.Context(), UnitSortByName, 1, "")
+	require.NoError(t, err)
+	require.Len(t, limited.Units, 1)
+	assert.Equal(t, "postgres.service", limited.Units[0].Name)
+}
+
+func TestGetUnitsRatesFromCursor(t *testing.T) {
+	g, _ := newUnitsTestUtil(t)
+
+	encoded, err := encodeUnitsCursor(UnitsCursor{
+		Timestamp: time.Now().Add(-2 * time.Second),
+		Units: map[string]UnitCounters{
+			"sshd.service": {CPUSeconds: 2, ReadBytes: 4096, WriteBytes: 0},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := g.GetUnits(t.Context(), UnitSortByCPU, 0, g.sealCursor("units", encoded))
+	require.NoError(t, err)
+	sshd := result.Units[0]
+	require.Equal(t, "sshd.service", sshd.Name)
+	assert.InDelta(t, 50.0/float64(runtime.NumCPU()), sshd.CPU, 1)
+	assert.InDelta(t, 2048, sshd.IOReadRate, 100)
+	assert.InDelta(t, 512, sshd.IOWriteRate, 25)
+	assert.Zero(t, result.Units[1].CPU, "postgres wasn't in the cursor")
+
+	_, err = g.GetUnits(t.Context(), UnitSortByCPU, 0, "garbage")
+	assert.True(t, IsCursorError(err))
+}
+
+func TestGetUnitsNeedsUnifiedHierarchy(t *testing.T) {
+	g := NewGopsUtil()
+	g.fs = &RootFileSystem{Root: filepath.Join(t.TempDir(), "empty")}
+	g.cmd = mocks.NewMockCommandExecutor(t)
+
+	_, err := g.GetUnits(t.Context(), UnitSortByMemory, 0, "")
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
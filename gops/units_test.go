@@ -0,0 +1,61 @@
+package gops
+
+import (
+	"testing"
+
+	"github.com/AvengeMedia/dgop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnitList(t *testing.T) {
+	out := []byte("sshd.service loaded active running OpenSSH Daemon\n" +
+		"session-2.scope loaded active running Session 2 of User alice\n" +
+		"\n" +
+		"nginx.service loaded failed failed A high performance web server\n")
+	assert.Equal(t, []string{"sshd.service", "session-2.scope", "nginx.service"}, parseUnitList(out))
+	assert.Empty(t, parseUnitList(nil))
+}
+
+func TestParseUnitShow(t *testing.T) {
+	out := []byte(`Id=sshd.service
+Description=OpenSSH Daemon
+ActiveState=active
+SubState=running
+ControlGroup=/system.slice/sshd.service
+NRestarts=2
+MainPID=812
+
+Id=nginx.service
+Description=A high performance web server = fast
+ActiveState=failed
+SubState=failed
+ControlGroup=
+NRestarts=0
+MainPID=0
+`)
+	units := parseUnitShow(out)
+	require.Len(t, units, 2)
+	assert.Equal(t, &models.UnitInfo{
+		Name:        "sshd.service",
+		Description: "OpenSSH Daemon",
+		ActiveState: "active",
+		SubState:    "running",
+		CGroup:      "/system.slice/sshd.service",
+		MainPID:     812,
+		Restarts:    2,
+	}, units[0])
+	assert.Equal(t, "A high performance web server = fast", units[1].Description)
+	assert.Empty(t, units[1].CGroup)
+}
+
+func TestParseUnitSortBy(t *testing.T) {
+	sortBy, err := ParseUnitSortBy("")
+	require.NoError(t, err)
+	assert.Equal(t, UnitSortByMemory, sortBy)
+	sortBy, err = ParseUnitSortBy("io")
+	require.NoError(t, err)
+	assert.Equal(t, UnitSortByIO, sortBy)
+	_, err = ParseUnitSortBy("pid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package models
+
+type AlertState string
+
+const (
+	AlertInactive AlertState = "inactive"
+	AlertPending  AlertState = "pending"
+	AlertFiring   AlertState = "firing"
+	AlertResolved AlertState = "resolved"
+)
+
+type AlertsConfig struct {
+	Interval string                       `toml:"interval"`
+	Rules    []AlertRuleConfig            `toml:"rules"`
+	Actions  map[string]AlertActionConfig `toml:"actions"`
+}
+
+type AlertRuleConfig struct {
+	Name       string   `toml:"name"`
+	When       string   `toml:"when"`
+	Hysteresis float64  `toml:"hysteresis"`
+	Severity   string   `toml:"severity"`
+	Actions    []string `toml:"actions"`
+}
+
+type AlertActionConfig struct {
+	Type    string            `toml:"type"`
+	Command string            `toml:"command"`
+	URL     string            `toml:"url"`
+	Headers map[string]string `toml:"headers"`
+	Timeout string            `toml:"timeout"`
+}
+
+type Alert struct {
+	Name       string     `json:"name"`
+	Expr       string     `json:"expr"`
+	Severity   string     `json:"severity"`
+	State      AlertState `json:"state"`
+	Value      float64    `json:"value"`
+	Threshold  float64    `json:"threshold"`
+	ActiveAt   int64      `json:"activeAt,omitempty" doc:"Unix millis when the condition first became true"`
+	FiredAt    int64      `json:"firedAt,omitempty" doc:"Unix millis when the alert started firing"`
+	ResolvedAt int64      `json:"resolvedAt,omitempty" doc:"Unix millis when the alert last resolved"`
+}
+
+type AlertsInfo struct {
+	Alerts      []Alert `json:"alerts"`
+	LastEvalAt  int64   `json:"lastEvalAt,omitempty"`
+	FiringCount int     `json:"firingCount"`
+}
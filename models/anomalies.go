@@ -0,0 +1,41 @@
+package models
+
+type AnomalyKind string
+
+const (
+	AnomalyForkBomb     AnomalyKind = "fork_bomb"
+	AnomalyStuck        AnomalyKind = "uninterruptible"
+	AnomalyZombies      AnomalyKind = "zombies"
+	AnomalyMemoryGrowth AnomalyKind = "memory_growth"
+)
+
+// Anomaly is a process behaving in a way worth a look. PID and Command are
+// the process responsible: the parent of the zombies, the stuck process,
+// the nearest ancestor of a fork bomb's new processes that was already
+// running, or the process whose memory keeps growing.
+type Anomaly struct {
+	Kind     AnomalyKind `json:"kind"`
+	PID      int32       `json:"pid"`
+	Command  string      `json:"command"`
+	Username string      `json:"username,omitempty"`
+	Summary  string      `json:"summary" doc:"One line describing the anomaly, for display."`
+	Count    int         `json:"count,omitempty" doc:"Zombies: how many the parent has. Fork bomb: processes started since the cursor."`
+	PIDs     []int32     `json:"pids,omitempty" doc:"Zombies: the zombies' PIDs. Fork bomb: some of the new processes."`
+	// ChildCommand names the processes a fork bomb is starting.
+	ChildCommand  string  `json:"childCommand,omitempty"`
+	Samples       int     `json:"samples,omitempty" doc:"Consecutive samples the zombies or the stuck process were seen."`
+	Since         int64   `json:"since,omitempty" doc:"Unix milliseconds the zombies or the stuck process were first seen."`
+	WaitChannel   string  `json:"waitChannel,omitempty" doc:"Uninterruptible: the kernel function the process sleeps in, e.g. rpc_wait_bit_killable for a hung NFS server (Linux)."`
+	Rate          float64 `json:"rate,omitempty" doc:"Fork bomb: new processes per second. Memory growth: KB per minute."`
+	MemoryKB      uint64  `json:"memoryKB,omitempty"`
+	GrowthKB      uint64  `json:"growthKB,omitempty" doc:"Memory growth: how much the process grew over the window."`
+	WindowSeconds float64 `json:"windowSeconds,omitempty" doc:"Memory growth: the span of the samples the trend was found in."`
+}
+
+type AnomaliesResponse struct {
+	Anomalies []*Anomaly `json:"anomalies"`
+	// Samples is how many memory samples the cursor holds; memory growth
+	// is only judged once they span half the window.
+	Samples int    `json:"samples"`
+	Cursor  string `json:"cursor"`
+}
@@ -0,0 +1,21 @@
+package models
+
+type AuthConfig struct {
+	Tokens  []TokenConfig      `toml:"tokens"`
+	Clients []ClientCertConfig `toml:"clients"`
+}
+
+// TokenConfig is a bearer token. Either Token or its hex SHA256 digest may
+// be given, so the file doesn't have to hold the secret itself.
+type TokenConfig struct {
+	Name   string   `toml:"name"`
+	Token  string   `toml:"token"`
+	SHA256 string   `toml:"sha256"`
+	Scopes []string `toml:"scopes"`
+}
+
+// ClientCertConfig grants scopes to a TLS client certificate by common name.
+type ClientCertConfig struct {
+	CommonName string   `toml:"cn"`
+	Scopes     []string `toml:"scopes"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// CacheStats reports how often the server reused module results.
+type CacheStats struct {
+	Modules map[string]ModuleCacheStats `json:"modules"`
+}
+
+type ModuleCacheStats struct {
+	TTL string `json:"ttl"`
+	// Hits were served from the cache, Misses collected afresh and
+	// Coalesced waited on another caller's collection.
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+}
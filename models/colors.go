@@ -112,3 +112,64 @@ func DefaultColorPalette() *ColorPalette {
 		},
 	}
 }
+
+// Level is how close a reading is to its limit. The TUI picks progress bar
+// colors by it and dgop bar reports it as the module's class.
+type Level string
+
+const (
+	LevelLow    Level = "low"
+	LevelMedium Level = "medium"
+	LevelHigh   Level = "high"
+)
+
+// UsageLevel classifies a cpu, memory or disk percentage. Disks are allowed
+// to run fuller than cpu and memory before they are flagged.
+func UsageLevel(kind string, usage float64) Level {
+	medium, high := 60.0, 80.0
+	if kind == "disk" {
+		medium, high = 70, 90
+	}
+	switch {
+	case usage > high:
+		return LevelHigh
+	case usage > medium:
+		return LevelMedium
+	default:
+		return LevelLow
+	}
+}
+
+// UsageColor returns the progress bar color for kind at level. Unknown kinds
+// use the memory colors.
+func (p *ColorPalette) UsageColor(kind string, level Level) string {
+	low, medium, high := p.ProgressBars.MemoryLow, p.ProgressBars.MemoryMedium, p.ProgressBars.MemoryHigh
+	switch kind {
+	case "cpu":
+		low, medium, high = p.ProgressBars.CPULow, p.ProgressBars.CPUMedium, p.ProgressBars.CPUHigh
+	case "disk":
+		low, medium, high = p.ProgressBars.DiskLow, p.ProgressBars.DiskMedium, p.ProgressBars.DiskHigh
+	}
+	switch level {
+	case LevelHigh:
+		return high
+	case LevelMedium:
+		return medium
+	default:
+		return low
+	}
+}
+
+// TemperatureColor returns the color for a temperature in °C.
+func (p *ColorPalette) TemperatureColor(temp float64) string {
+	switch {
+	case temp > 85:
+		return p.Temperature.Danger
+	case temp > 70:
+		return p.Temperature.Hot
+	case temp > 50:
+		return p.Temperature.Warm
+	default:
+		return p.Temperature.Cold
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+// DeviceFilter narrows which network interfaces, disks and mounts are
+// reported. Entries are shell globs such as "enp*" or "nvme?n1". An include
+// list replaces the built-in device matching; excludes apply afterwards.
+type DeviceFilter struct {
+	Interfaces        []string `toml:"interfaces" env:"DGOP_INTERFACES"`
+	ExcludeInterfaces []string `toml:"exclude_interfaces" env:"DGOP_EXCLUDE_INTERFACES"`
+	Disks             []string `toml:"disks" env:"DGOP_DISKS"`
+	ExcludeDisks      []string `toml:"exclude_disks" env:"DGOP_EXCLUDE_DISKS"`
+	ExcludeMounts     []string `toml:"exclude_mounts" env:"DGOP_EXCLUDE_MOUNTS"`
+}
@@ -0,0 +1,49 @@
+package models
+
+type PeersConfig struct {
+	Interval string   `toml:"interval"`
+	Peers    []string `toml:"peers"`
+}
+
+type PeerHealth struct {
+	Host      string `json:"host"`
+	URL       string `json:"url"`
+	Healthy   bool   `json:"healthy"`
+	LastSeen  int64  `json:"lastSeen" doc:"Unix millis of the last successful poll, 0 if never"`
+	LatencyMs int64  `json:"latencyMs"`
+	Failures  int    `json:"failures" doc:"Consecutive failed polls"`
+	Error     string `json:"error,omitempty"`
+}
+
+type FleetProcess struct {
+	Host string `json:"host"`
+	*ProcessInfo
+}
+
+type FleetCPU struct {
+	Host        string  `json:"host"`
+	Usage       float64 `json:"usage"`
+	Temperature float64 `json:"temperature"`
+}
+
+type FleetDisk struct {
+	Host    string  `json:"host"`
+	Mount   string  `json:"mount"`
+	Device  string  `json:"device"`
+	Size    string  `json:"size"`
+	Used    string  `json:"used"`
+	Percent float64 `json:"percent"`
+}
+
+type FleetSummary struct {
+	TopProcesses []FleetProcess `json:"topProcesses"`
+	HottestCPUs  []FleetCPU     `json:"hottestCpus"`
+	FullestDisks []FleetDisk    `json:"fullestDisks"`
+}
+
+type FleetInfo struct {
+	Hosts     map[string]*MetaInfo `json:"hosts" doc:"Latest metrics of every healthy host, keyed by host name"`
+	Peers     []PeerHealth         `json:"peers"`
+	Summary   FleetSummary         `json:"summary"`
+	UpdatedAt int64                `json:"updatedAt"`
+}
@@ -13,6 +13,10 @@ const (
 	ActionSortPID     KeyAction = "sortPID"
 	ActionGroup       KeyAction = "group"
 	ActionSearch      KeyAction = "search"
+	ActionFleet       KeyAction = "fleet"
+	ActionUnits       KeyAction = "units"
+	ActionThreads     KeyAction = "threads"
+	ActionEvents      KeyAction = "events"
 	ActionNavUp       KeyAction = "navUp"
 	ActionNavDown     KeyAction = "navDown"
 	ActionSelectLeft  KeyAction = "selectLeft"
@@ -35,6 +39,10 @@ func DefaultKeybinds() Keybinds {
 		ActionSortPID:     {"p"},
 		ActionGroup:       {"g"},
 		ActionSearch:      {"/"},
+		ActionFleet:       {"f"},
+		ActionUnits:       {"u"},
+		ActionThreads:     {"H"},
+		ActionEvents:      {"e"},
 		ActionNavUp:       {"up", "k"},
 		ActionNavDown:     {"down", "j"},
 		ActionSelectLeft:  {"left", "h"},
@@ -8,6 +8,8 @@ type SystemMetrics struct {
 	Processes  []*ProcessInfo   `json:"processes"`
 	System     *SystemInfo      `json:"system"`
 	DiskMounts []*DiskMountInfo `json:"diskmounts"`
+	// Errors maps the modules that couldn't be collected to the reason.
+	Errors map[string]ModuleError `json:"errors,omitempty"`
 }
 
 type SystemInfo struct {
@@ -18,18 +20,44 @@ type SystemInfo struct {
 }
 
 type MetaInfo struct {
-	CPU        *CPUInfo             `json:"cpu,omitempty"`
-	Memory     *MemoryInfo          `json:"memory,omitempty"`
-	Network    []*NetworkInfo       `json:"network,omitempty"`
-	NetRate    *NetworkRateResponse `json:"netrate,omitempty"`
-	Disk       []*DiskInfo          `json:"disk,omitempty"`
-	DiskRate   *DiskRateResponse    `json:"diskrate,omitempty"`
-	DiskMounts []*DiskMountInfo     `json:"diskmounts,omitempty"`
-	Processes  []*ProcessInfo       `json:"processes,omitempty"`
-	System     *SystemInfo          `json:"system,omitempty"`
-	Hardware   *SystemHardware      `json:"hardware,omitempty"`
-	GPU        *GPUInfo             `json:"gpu,omitempty"`
-	Cursor     string               `json:"cursor,omitempty"`
+	CPU        *CPUInfo               `json:"cpu,omitempty"`
+	Memory     *MemoryInfo            `json:"memory,omitempty"`
+	Network    []*NetworkInfo         `json:"network,omitempty"`
+	NetRate    *NetworkRateResponse   `json:"netrate,omitempty"`
+	Disk       []*DiskInfo            `json:"disk,omitempty"`
+	DiskRate   *DiskRateResponse      `json:"diskrate,omitempty"`
+	DiskMounts []*DiskMountInfo       `json:"diskmounts,omitempty"`
+	Processes  []*ProcessInfo         `json:"processes,omitempty"`
+	System     *SystemInfo            `json:"system,omitempty"`
+	Hardware   *SystemHardware        `json:"hardware,omitempty"`
+	GPU        *GPUInfo               `json:"gpu,omitempty"`
+	Units      *UnitsResponse         `json:"units,omitempty"`
+	ProcEvents *ProcessEventsResponse `json:"procEvents,omitempty"`
+	Anomalies  *AnomaliesResponse     `json:"anomalies,omitempty"`
+	Cursor     string                 `json:"cursor,omitempty"`
+	MetaCursor string                 `json:"metaCursor,omitempty"`
+	// Session replaces the cursors when the request asked for a server-side
+	// cursor session.
+	Session string `json:"session,omitempty"`
+	// Errors maps the requested modules that are missing to the reason.
+	Errors map[string]ModuleError `json:"errors,omitempty"`
+}
+
+type ModuleErrorCode string
+
+const (
+	ModuleErrorTimeout          ModuleErrorCode = "timeout"
+	ModuleErrorCanceled         ModuleErrorCode = "canceled"
+	ModuleErrorPermissionDenied ModuleErrorCode = "permission_denied"
+	ModuleErrorUnsupported      ModuleErrorCode = "unsupported"
+	ModuleErrorNotFound         ModuleErrorCode = "not_found"
+	ModuleErrorFailed           ModuleErrorCode = "failed"
+)
+
+// ModuleError says why a module was left out of a MetaInfo or SystemMetrics.
+type ModuleError struct {
+	Code    ModuleErrorCode `json:"code" enum:"timeout,canceled,permission_denied,unsupported,not_found,failed" doc:"timeout: the module missed its deadline; canceled: the request ended first; permission_denied: dgop may not read the source; unsupported: not available on this platform; not_found: the source is missing, such as an absent sysfs tree or tool; failed: any other error"`
+	Message string          `json:"message"`
 }
 
 type ModulesInfo struct {
@@ -3,6 +3,7 @@ package models
 type ProcessInfo struct {
 	PID               int32   `json:"pid"`
 	PPID              int32   `json:"ppid"`
+	State             string  `json:"state,omitempty" doc:"Scheduler state as in ps: R running, S sleeping, D uninterruptible sleep, Z zombie, T stopped, t traced, I idle. Linux only; elsewhere reading it costs a call per process."`
 	CPU               float64 `json:"cpu" doc:"Percentage of total machine CPU capacity used since the cursor, in the range 0-100."`
 	PTicks            float64 `json:"pticks" doc:"Cumulative CPU seconds (user + system) consumed by this process."`
 	MemoryPercent     float32 `json:"memoryPercent"`
@@ -10,13 +11,22 @@ type ProcessInfo struct {
 	MemoryCalculation string  `json:"memoryCalculation"`
 	RSSKB             uint64  `json:"rssKB"`
 	RSSPercent        float32 `json:"rssPercent"`
-	PSSKB             uint64  `json:"pssKB"`
+	PSSKB             uint64  `json:"pssKB" doc:"Proportional set size: resident memory with each shared page split between the processes mapping it. Only filled in accurate memory mode."`
 	PSSPercent        float32 `json:"pssPercent"`
+	USSKB             uint64  `json:"ussKB,omitempty" doc:"Unique set size: private clean and dirty pages, what exiting would free. Accurate memory mode only."`
+	SwapKB            uint64  `json:"swapKB,omitempty"`
+	SwapPSSKB         uint64  `json:"swapPssKB,omitempty" doc:"Swapped-out memory with shared pages split, like PSS."`
+	PSSAnonKB         uint64  `json:"pssAnonKB,omitempty"`
+	PSSFileKB         uint64  `json:"pssFileKB,omitempty"`
+	PSSShmemKB        uint64  `json:"pssShmemKB,omitempty"`
 	Username          string  `json:"username"`
 	Command           string  `json:"command"`
 	FullCommand       string  `json:"fullCommand"`
 	ExecutablePath    string  `json:"executablePath,omitempty"`
 	ChildCount        int     `json:"childCount,omitempty"`
+	AppID             string  `json:"appId,omitempty" doc:"Desktop file ID of the application the process belongs to."`
+	AppName           string  `json:"appName,omitempty"`
+	IconName          string  `json:"iconName,omitempty" doc:"Icon theme name from the application's desktop file."`
 }
 
 type ProcessCursorData struct {
@@ -29,3 +39,107 @@ type ProcessListResponse struct {
 	Processes []*ProcessInfo `json:"processes"`
 	Cursor    string         `json:"cursor,omitempty"`
 }
+
+// ProcessDetails is everything dgop reads about a single process. Fields
+// the platform doesn't expose, or that dgop may not read for another user's
+// process, are left empty.
+type ProcessDetails struct {
+	PID            int32   `json:"pid"`
+	PPID           int32   `json:"ppid"`
+	Command        string  `json:"command"`
+	FullCommand    string  `json:"fullCommand"`
+	ExecutablePath string  `json:"executablePath,omitempty"`
+	Username       string  `json:"username"`
+	State          string  `json:"state" doc:"Scheduler state as in ps: R running, S sleeping, D uninterruptible sleep, Z zombie, T stopped, t traced, I idle, X dead."`
+	Nice           int32   `json:"nice"`
+	Priority       int32   `json:"priority"`
+	StartTime      string  `json:"startTime"`
+	AgeSeconds     float64 `json:"ageSeconds"`
+	CPU            float64 `json:"cpu" doc:"Percentage of total machine CPU capacity used over a short sample, in the range 0-100."`
+	PTicks         float64 `json:"pticks" doc:"Cumulative CPU seconds (user + system) consumed by this process."`
+	MemoryKB       uint64  `json:"memoryKB"`
+	Cwd            string  `json:"cwd,omitempty"`
+	CGroup         string  `json:"cgroup,omitempty"`
+	OOMScore       int     `json:"oomScore"`
+	OOMScoreAdj    int     `json:"oomScoreAdj"`
+	// VoluntaryCtxSwitches counts waits for a resource, InvoluntaryCtxSwitches
+	// preemptions by the scheduler.
+	VoluntaryCtxSwitches   uint64            `json:"voluntaryCtxSwitches"`
+	InvoluntaryCtxSwitches uint64            `json:"involuntaryCtxSwitches"`
+	Threads                []*ThreadInfo     `json:"threads"`
+	NumFDs                 int               `json:"numFds"`
+	FDs                    []*FDInfo         `json:"fds,omitempty"`
+	Environ                []string          `json:"environ,omitempty" doc:"Only filled when asked for."`
+	Limits                 []*ProcessLimit   `json:"limits,omitempty"`
+	Namespaces             map[string]string `json:"namespaces,omitempty" doc:"Namespace type to its identifier, e.g. net to net:[4026531840]."`
+	IO                     *ProcessIO        `json:"io,omitempty"`
+	Memory                 *SmapsRollup      `json:"memory,omitempty"`
+}
+
+type ThreadInfo struct {
+	PID     int32   `json:"pid" doc:"The process the thread belongs to."`
+	TID     int32   `json:"tid"`
+	Name    string  `json:"name"`
+	State   string  `json:"state"`
+	CPU     float64 `json:"cpu" doc:"Percentage of total machine CPU capacity, over a short sample or since the cursor."`
+	PTicks  float64 `json:"pticks"`
+	LastCPU int32   `json:"lastCpu" doc:"The CPU the thread last ran on."`
+}
+
+type FDInfo struct {
+	FD     int    `json:"fd"`
+	Target string `json:"target"`
+}
+
+// ProcessLimit is a resource limit; Soft and Hard are "unlimited" or a
+// number in Unit.
+type ProcessLimit struct {
+	Name string `json:"name"`
+	Soft string `json:"soft"`
+	Hard string `json:"hard"`
+	Unit string `json:"unit,omitempty"`
+}
+
+// ProcessIO counts a process's I/O. ReadBytes and WriteBytes are what
+// reached the storage layer; ReadChars and WriteChars include reads served
+// from the page cache.
+type ProcessIO struct {
+	ReadChars           uint64 `json:"readChars"`
+	WriteChars          uint64 `json:"writeChars"`
+	ReadSyscalls        uint64 `json:"readSyscalls"`
+	WriteSyscalls       uint64 `json:"writeSyscalls"`
+	ReadBytes           uint64 `json:"readBytes"`
+	WriteBytes          uint64 `json:"writeBytes"`
+	CancelledWriteBytes uint64 `json:"cancelledWriteBytes"`
+}
+
+// SmapsRollup is /proc/<pid>/smaps_rollup, the process's mappings added
+// together, in kB.
+type SmapsRollup struct {
+	RSS            uint64 `json:"rss"`
+	PSS            uint64 `json:"pss"`
+	PSSDirty       uint64 `json:"pssDirty"`
+	PSSAnon        uint64 `json:"pssAnon"`
+	PSSFile        uint64 `json:"pssFile"`
+	PSSShmem       uint64 `json:"pssShmem"`
+	SharedClean    uint64 `json:"sharedClean"`
+	SharedDirty    uint64 `json:"sharedDirty"`
+	PrivateClean   uint64 `json:"privateClean"`
+	PrivateDirty   uint64 `json:"privateDirty"`
+	Referenced     uint64 `json:"referenced"`
+	Anonymous      uint64 `json:"anonymous"`
+	LazyFree       uint64 `json:"lazyFree"`
+	AnonHugePages  uint64 `json:"anonHugePages"`
+	ShmemPmdMapped uint64 `json:"shmemPmdMapped"`
+	FilePmdMapped  uint64 `json:"filePmdMapped"`
+	SharedHugetlb  uint64 `json:"sharedHugetlb"`
+	PrivateHugetlb uint64 `json:"privateHugetlb"`
+	Swap           uint64 `json:"swap"`
+	SwapPSS        uint64 `json:"swapPss"`
+	Locked         uint64 `json:"locked"`
+}
+
+type ThreadListResponse struct {
+	Threads []*ThreadInfo `json:"threads"`
+	Cursor  string        `json:"cursor,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+type ProcessEventType string
+
+const (
+	ProcessSpawned ProcessEventType = "spawned"
+	ProcessExited  ProcessEventType = "exited"
+)
+
+// ProcessEventSource says how an event was seen. The proc connector
+// reports every process as it starts and exits; a scan only compares the
+// processes running at two calls, so it misses those that came and went in
+// between.
+type ProcessEventSource string
+
+const (
+	ProcessEventsNetlink ProcessEventSource = "netlink"
+	ProcessEventsScan    ProcessEventSource = "scan"
+)
+
+// ProcessEvent is a process that started or exited between two calls.
+type ProcessEvent struct {
+	Type        ProcessEventType   `json:"type"`
+	PID         int32              `json:"pid"`
+	PPID        int32              `json:"ppid,omitempty"`
+	Command     string             `json:"command,omitempty"`
+	FullCommand string             `json:"fullCommand,omitempty"`
+	Username    string             `json:"username,omitempty"`
+	Time        int64              `json:"time" doc:"Unix milliseconds the process started, or exited. Without the proc connector an exit is timed by the scan that no longer found the process."`
+	Runtime     float64            `json:"runtime,omitempty" doc:"Seconds the process ran, for exits."`
+	CPU         float64            `json:"cpu,omitempty" doc:"For exits, the last CPU usage seen, or the average over its life for a process too short-lived to be sampled; percent of the whole machine."`
+	MemoryKB    uint64             `json:"memoryKB,omitempty" doc:"For exits, the last memory seen."`
+	ExitCode    *int               `json:"exitCode,omitempty" doc:"Exit status, from the proc connector."`
+	Signal      int                `json:"signal,omitempty" doc:"Signal that killed the process, from the proc connector."`
+	Source      ProcessEventSource `json:"source"`
+}
+
+type ProcessEventsResponse struct {
+	// Events are oldest first.
+	Events []*ProcessEvent `json:"events"`
+	// Source is netlink when the proc connector covered the whole interval
+	// since the cursor.
+	Source ProcessEventSource `json:"source"`
+	Cursor string             `json:"cursor"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// UnitInfo is a systemd service or scope with the usage of its cgroup.
+type UnitInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ActiveState string `json:"activeState"`
+	SubState    string `json:"subState"`
+	CGroup      string `json:"cgroup"`
+	MainPID     int32  `json:"mainPid,omitempty"`
+	// CPU is zero on the first call, before there is a cursor to measure
+	// from.
+	CPU          float64 `json:"cpu" doc:"Percentage of total machine CPU capacity used since the cursor, in the range 0-100."`
+	CPUSeconds   float64 `json:"cpuSeconds" doc:"Cumulative CPU seconds consumed by the unit's cgroup."`
+	MemoryBytes  uint64  `json:"memoryBytes"`
+	IOReadBytes  uint64  `json:"ioReadBytes"`
+	IOWriteBytes uint64  `json:"ioWriteBytes"`
+	IOReadRate   float64 `json:"ioReadRate" doc:"Bytes read per second since the cursor."`
+	IOWriteRate  float64 `json:"ioWriteRate" doc:"Bytes written per second since the cursor."`
+	Tasks        uint64  `json:"tasks"`
+	Restarts     int     `json:"restarts" doc:"Times systemd restarted the service automatically (NRestarts)."`
+}
+
+type UnitsResponse struct {
+	Units  []*UnitInfo `json:"units"`
+	Cursor string      `json:"cursor"`
+}